@@ -4,6 +4,7 @@ import { DataQuery, DataQueryRequest, DataQueryResponse } from '@grafana/data';
 
 import { config } from '../config';
 import { getBackendSrv } from '../services/backendSrv';
+import { getTemplateSrv } from '../services/templateSrv';
 
 import { BackendDataSourceResponse, toDataQueryResponse } from './queryResponse';
 
@@ -30,6 +31,7 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       to: toRange.valueOf().toString(),
       timezone: request.timezone,
     },
+    variables: getSelectedVariables(),
   };
 
   return getBackendSrv()
@@ -48,3 +50,20 @@ export function publicDashboardQueryHandler(request: DataQueryRequest<DataQuery>
       })
     );
 }
+
+// Returns the values of the template variables selected by the viewer. Only constant, custom and query variables can
+// be selected on public dashboards, the others use the values saved with the dashboard.
+function getSelectedVariables(): Record<string, string[]> {
+  const variables: Record<string, string[]> = {};
+  for (const variable of getTemplateSrv().getVariables()) {
+    if (variable.type !== 'constant' && variable.type !== 'custom' && variable.type !== 'query') {
+      continue;
+    }
+    const value = variable.current?.value;
+    if (value === undefined) {
+      continue;
+    }
+    variables[variable.name] = Array.isArray(value) ? value : [value];
+  }
+  return variables;
+}
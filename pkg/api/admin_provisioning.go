@@ -3,11 +3,19 @@ package api
 import (
 	"context"
 	"errors"
+	"io"
+	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
 )
 
+// maxWebhookBodySize is the largest webhook payload accepted, which is the limit of GitHub webhooks.
+const maxWebhookBodySize = 25 << 20
+
 // swagger:route POST /admin/provisioning/dashboards/reload admin_provisioning adminProvisioningReloadDashboards
 //
 // Reload dashboard provisioning configurations.
@@ -31,6 +39,24 @@ func (hs *HTTPServer) AdminProvisioningReloadDashboards(c *contextmodel.ReqConte
 	return response.Success("Dashboards config reloaded")
 }
 
+// swagger:route GET /admin/provisioning/dashboards/failed-write-backs admin_provisioning adminProvisioningGetDashboardsFailedWriteBacks
+//
+// Get the dashboards that git dashboard providers failed to push.
+//
+// Returns the dashboards saved from the UI that the git dashboard providers with `writeBack` enabled couldn't commit and push to their repository. They are pushed again on every sync of the provider, until it succeeds or the dashboard is saved again.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:dashboards`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminProvisioningGetDashboardsFailedWriteBacksResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+func (hs *HTTPServer) AdminProvisioningGetDashboardsFailedWriteBacks(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, hs.ProvisioningService.GetDashboardsFailedWriteBacks())
+}
+
 // swagger:route POST /admin/provisioning/datasources/reload admin_provisioning adminProvisioningReloadDatasources
 //
 // Reload datasource provisioning configurations.
@@ -107,3 +133,38 @@ func (hs *HTTPServer) AdminProvisioningReloadAlerting(c *contextmodel.ReqContext
 	}
 	return response.Success("Alerting config reloaded")
 }
+
+// swagger:route POST /provisioning/dashboards/git/{name}/webhook admin_provisioning dashboardsGitWebhook
+//
+// Sync a git dashboard provider.
+//
+// Makes the git dashboard provider with the name fetch its repository and provision the changed dashboards, without waiting for the next poll. It's meant to be called by the push webhooks of GitHub, GitLab or Gitea, and requires the request to be signed with the `webhookSecret` of the provider.
+//
+// Responses:
+// 202: okResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DashboardsGitWebhook(c *contextmodel.ReqContext) response.Response {
+	body, err := io.ReadAll(io.LimitReader(c.Req.Body, maxWebhookBodySize))
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to read the request body", err)
+	}
+
+	err = hs.ProvisioningService.HandleDashboardsGitWebhook(web.Params(c.Req)[":name"], c.Req.Header, body)
+	switch {
+	case errors.Is(err, dashboards.ErrGitProviderNotFound):
+		return response.Error(http.StatusNotFound, "Git dashboard provider not found", err)
+	case errors.Is(err, dashboards.ErrInvalidWebhookSignature):
+		return response.Error(http.StatusUnauthorized, "Invalid webhook signature", err)
+	case err != nil:
+		return response.Error(http.StatusInternalServerError, "Failed to sync git dashboard provider", err)
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "Git dashboard provider sync requested"})
+}
+
+// swagger:response adminProvisioningGetDashboardsFailedWriteBacksResponse
+type AdminProvisioningGetDashboardsFailedWriteBacksResponse struct {
+	// in: body
+	Body []dashboards.FailedWriteBack `json:"body"`
+}
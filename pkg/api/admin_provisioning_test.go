@@ -4,13 +4,16 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web/webtest"
 )
@@ -185,3 +188,61 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 		})
 	}
 }
+
+func TestAPI_DashboardsGitWebhook(t *testing.T) {
+	for _, tc := range []struct {
+		desc         string
+		err          error
+		expectedCode int
+	}{
+		{desc: "should request a sync", expectedCode: http.StatusAccepted},
+		{desc: "should fail for unknown providers", err: dashboards.ErrGitProviderNotFound, expectedCode: http.StatusNotFound},
+		{desc: "should fail for invalid signatures", err: dashboards.ErrInvalidWebhookSignature, expectedCode: http.StatusUnauthorized},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			pService := provisioning.NewProvisioningServiceMock(context.Background())
+			pService.HandleDashboardsGitWebhookFunc = func(name string, header http.Header, body []byte) error {
+				return tc.err
+			}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.ProvisioningService = pService
+			})
+
+			res, err := server.Send(server.NewPostRequest("/api/provisioning/dashboards/git/team/webhook", strings.NewReader(`{}`)))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+			assert.Equal(t, []any{"team"}, pService.Calls.HandleDashboardsGitWebhook)
+		})
+	}
+}
+
+func TestAPI_AdminProvisioningGetDashboardsFailedWriteBacks(t *testing.T) {
+	failed := time.Date(2023, 10, 17, 10, 12, 32, 0, time.UTC)
+	pService := provisioning.NewProvisioningServiceMock(context.Background())
+	pService.GetDashboardsFailedWriteBacksFunc = func() []dashboards.FailedWriteBack {
+		return []dashboards.FailedWriteBack{{Provider: "git", File: "team.json", Login: "jdoe", Message: "Update team", Error: "push failed", Failed: failed}}
+	}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.ProvisioningService = pService
+	})
+
+	t.Run("should return the dashboards that failed to be pushed", func(t *testing.T) {
+		permissions := []accesscontrol.Permission{{Action: ActionProvisioningReload, Scope: ScopeProvisionersDashboards}}
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/admin/provisioning/dashboards/failed-write-backs"), userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.JSONEq(t, `[{"provider":"git","file":"team.json","login":"jdoe","message":"Update team","error":"push failed","failed":"2023-10-17T10:12:32Z"}]`, string(body))
+	})
+
+	t.Run("should fail without permission", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/admin/provisioning/dashboards/failed-write-backs"), userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -203,6 +203,9 @@ func (hs *HTTPServer) registerRoutes() {
 	r.Post("/api/user/password/send-reset-email", routing.Wrap(hs.SendResetPasswordEmail))
 	r.Post("/api/user/password/reset", routing.Wrap(hs.ResetPassword))
 
+	// git dashboard provisioning webhooks, authenticated by their signature
+	r.Post("/api/provisioning/dashboards/git/:name/webhook", routing.Wrap(hs.DashboardsGitWebhook))
+
 	// dashboard snapshots
 	r.Get("/dashboard/snapshot/*", reqNoAuth, hs.Index)
 	r.Get("/dashboard/snapshots/", reqSignedIn, hs.Index)
@@ -477,6 +480,15 @@ func (hs *HTTPServer) registerRoutes() {
 			dashboardRoute.Get("/home", routing.Wrap(hs.GetHomeDashboard))
 			dashboardRoute.Get("/tags", hs.GetDashboardTags)
 
+			dashboardRoute.Group("/trash", func(trashRoute routing.RouteRegister) {
+				trashRoute.Get("/", routing.Wrap(hs.GetDashboardTrash))
+				trashRoute.Post("/:uid/restore", routing.Wrap(hs.RestoreDashboardTrash))
+				trashRoute.Delete("/:uid", routing.Wrap(hs.DeleteDashboardTrash))
+			}, authorize(ac.EvalAll(
+				ac.EvalPermission(dashboards.ActionDashboardsDelete, dashboards.ScopeDashboardsAll),
+				ac.EvalPermission(dashboards.ActionFoldersDelete, dashboards.ScopeFoldersAll),
+			)))
+
 			// Deprecated: used to convert internal IDs to UIDs
 			dashboardRoute.Get("/ids/:ids", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead)), hs.GetDashboardUIDs)
 
@@ -595,6 +607,7 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/encryption/delete-secretsmanagerplugin-secrets", reqGrafanaAdmin, routing.Wrap(hs.AdminDeleteAllSecretsManagerPluginSecrets))
 
 		adminRoute.Post("/provisioning/dashboards/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningReloadDashboards))
+		adminRoute.Get("/provisioning/dashboards/failed-write-backs", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningGetDashboardsFailedWriteBacks))
 		adminRoute.Post("/provisioning/plugins/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersPlugins)), routing.Wrap(hs.AdminProvisioningReloadPlugins))
 		adminRoute.Post("/provisioning/datasources/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/notifications/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersNotifications)), routing.Wrap(hs.AdminProvisioningReloadNotifications))
@@ -326,24 +326,15 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 
 	namespaceID, userIDStr := c.SignedInUser.GetNamespacedID()
 
-	// disconnect all library elements for this dashboard
-	err = hs.LibraryElementService.DisconnectElementsFromDashboard(c.Req.Context(), dash.ID)
-	if err != nil {
-		hs.log.Error(
-			"Failed to disconnect library elements",
-			"dashboard", dash.ID,
-			"namespaceID", namespaceID,
-			"user", userIDStr,
-			"error", err)
-	}
-
 	// deletes all related public dashboard entities
 	err = hs.PublicDashboardsApi.PublicDashboardService.DeleteByDashboard(c.Req.Context(), dash)
 	if err != nil {
 		hs.log.Error("Failed to delete public dashboard")
 	}
 
-	err = hs.DashboardService.DeleteDashboard(c.Req.Context(), dash.ID, c.SignedInUser.GetOrgID())
+	// the dashboard is moved to the trash along with its library element connections,
+	// so they are only disconnected once it's deleted
+	err = hs.DashboardService.TrashDashboard(c.Req.Context(), dash.ID, c.SignedInUser.GetOrgID(), c.SignedInUser)
 	if err != nil {
 		var dashboardErr dashboards.DashboardErr
 		if ok := errors.As(err, &dashboardErr); ok {
@@ -354,6 +345,17 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 		return response.Error(http.StatusInternalServerError, "Failed to delete dashboard", err)
 	}
 
+	// disconnect all library elements for this dashboard
+	err = hs.LibraryElementService.DisconnectElementsFromDashboard(c.Req.Context(), dash.ID)
+	if err != nil {
+		hs.log.Error(
+			"Failed to disconnect library elements",
+			"dashboard", dash.ID,
+			"namespaceID", namespaceID,
+			"user", userIDStr,
+			"error", err)
+	}
+
 	userDTODisplay, err := user.NewUserDisplayDTOFromRequester(c.SignedInUser)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Error while parsing the user DTO model", err)
@@ -507,6 +509,24 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
 	}
 
+	result := util.DynMap{
+		"status":  "success",
+		"slug":    dashboard.Slug,
+		"version": dashboard.Version,
+		"id":      dashboard.ID,
+		"uid":     dashboard.UID,
+		"url":     dashboard.GetURL(),
+	}
+
+	// Providers with write back enabled keep their source in sync with the dashboards saved from the UI.
+	// The dashboard is saved already, so failing to write it back is reported as a warning.
+	if provisioningData != nil && hs.ProvisioningService.GetWriteBackFromConfig(provisioningData.Name) {
+		if err := hs.ProvisioningService.WriteBackDashboard(ctx, provisioningData, dashboard, c.SignedInUser, cmd.Message); err != nil {
+			hs.log.Warn("Failed to write back dashboard to its provisioning source", "uid", dashboard.UID, "provider", provisioningData.Name, "error", err)
+			result["warning"] = "Dashboard saved, but failed to write it back to its provisioning source: " + err.Error()
+		}
+	}
+
 	// Clear permission cache for the user who's created the dashboard, so that new permissions are fetched for their next call
 	// Required for cases when caller wants to immediately interact with the newly created object
 	if newDashboard {
@@ -520,14 +540,7 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 	}
 
 	c.TimeRequest(metrics.MApiDashboardSave)
-	return response.JSON(http.StatusOK, util.DynMap{
-		"status":  "success",
-		"slug":    dashboard.Slug,
-		"version": dashboard.Version,
-		"id":      dashboard.ID,
-		"uid":     dashboard.UID,
-		"url":     dashboard.GetURL(),
-	})
+	return response.JSON(http.StatusOK, result)
 }
 
 // swagger:route GET /dashboards/home dashboards getHomeDashboard
@@ -1301,6 +1314,10 @@ type PostDashboardResponse struct {
 		// required: true
 		// example: /d/nHz3SXiiz/my-dashboard
 		URL string `json:"url"`
+
+		// Warning The reason the dashboard couldn't be written back to its provisioning source, if it failed.
+		// example: Dashboard saved, but failed to write it back to its provisioning source
+		Warning string `json:"warning,omitempty"`
 	} `json:"body"`
 }
 
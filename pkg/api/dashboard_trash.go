@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /dashboards/trash dashboards getDashboardTrash
+//
+// Get the deleted dashboards and folders.
+//
+// Returns the dashboards and folders of the organization that have been deleted and can still be restored, most recently deleted first.
+//
+// Responses:
+// 200: getDashboardTrashResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardTrash(c *contextmodel.ReqContext) response.Response {
+	trash, err := hs.DashboardService.FindTrash(c.Req.Context(), &dashboards.FindDashboardTrashQuery{OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get deleted dashboards", err)
+	}
+	return response.JSON(http.StatusOK, trash)
+}
+
+// swagger:route POST /dashboards/trash/{uid}/restore dashboards restoreDashboardTrash
+//
+// Restore a deleted dashboard or folder.
+//
+// Restores the dashboard or folder along with its versions and permissions. Folders are restored with
+// their subfolders, dashboards, library panels and alert rules. If the folder the dashboard or folder
+// was in no longer exists, it's restored to the root folder.
+//
+// Responses:
+// 200: restoreDashboardTrashResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (hs *HTTPServer) RestoreDashboardTrash(c *contextmodel.ReqContext) response.Response {
+	trash, err := hs.DashboardService.RestoreTrash(c.Req.Context(), &dashboards.RestoreDashboardTrashCommand{
+		OrgID: c.SignedInUser.GetOrgID(),
+		UID:   web.Params(c.Req)[":uid"],
+	})
+	if err != nil {
+		return dashboardTrashErrResponse(err, "Failed to restore dashboard")
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"uid":     trash.DashboardUID,
+		"title":   trash.Title,
+		"message": fmt.Sprintf("%s restored", trash.Title),
+	})
+}
+
+// swagger:route DELETE /dashboards/trash/{uid} dashboards deleteDashboardTrash
+//
+// Permanently delete a deleted dashboard or folder.
+//
+// Removes the dashboard or folder from the trash, after which it can no longer be restored.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteDashboardTrash(c *contextmodel.ReqContext) response.Response {
+	err := hs.DashboardService.DeleteTrash(c.Req.Context(), &dashboards.DeleteDashboardTrashCommand{
+		OrgID: c.SignedInUser.GetOrgID(),
+		UID:   web.Params(c.Req)[":uid"],
+	})
+	if err != nil {
+		return dashboardTrashErrResponse(err, "Failed to delete dashboard from the trash")
+	}
+	return response.Success("Dashboard deleted permanently")
+}
+
+func dashboardTrashErrResponse(err error, message string) response.Response {
+	var dashboardErr dashboards.DashboardErr
+	if errors.As(err, &dashboardErr) {
+		return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
+	}
+	return response.Error(http.StatusInternalServerError, message, err)
+}
+
+// swagger:parameters restoreDashboardTrash
+type RestoreDashboardTrashParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters deleteDashboardTrash
+type DeleteDashboardTrashParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response getDashboardTrashResponse
+type GetDashboardTrashResponse struct {
+	// in: body
+	Body []*dashboards.DashboardTrash `json:"body"`
+}
+
+// swagger:response restoreDashboardTrashResponse
+type RestoreDashboardTrashResponse struct {
+	// The response message
+	// in: body
+	Body struct {
+		// UID Identifier of the restored dashboard or folder.
+		// required: true
+		// example: cIBgcSjkk
+		UID string `json:"uid"`
+
+		// Title Title of the restored dashboard or folder.
+		// required: true
+		// example: My Dashboard
+		Title string `json:"title"`
+
+		// Message Message of the restored dashboard or folder.
+		// required: true
+		// example: My Dashboard restored
+		Message string `json:"message"`
+	} `json:"body"`
+}
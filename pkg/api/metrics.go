@@ -14,6 +14,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -25,6 +26,11 @@ func (hs *HTTPServer) handleQueryMetricsError(err error) *response.NormalRespons
 		return response.Error(http.StatusNotFound, "Data source not found", err)
 	}
 
+	var rateLimited *quota.RateLimitedError
+	if errors.As(err, &rateLimited) {
+		return response.Err(err).SetHeader("Retry-After", rateLimited.RetryAfterHeader())
+	}
+
 	var secretsPlugin datasources.ErrDatasourceSecretsPluginUserFriendly
 	if errors.As(err, &secretsPlugin) {
 		return response.Error(http.StatusInternalServerError, fmt.Sprint("Secrets Plugin error: ", err.Error()), err)
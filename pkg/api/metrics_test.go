@@ -79,6 +79,8 @@ func TestAPIEndpoint_Metrics_QueryMetricsV2(t *testing.T) {
 			},
 		}, &fakeDatasources.FakeDataSourceService{}, pluginSettings.ProvideService(dbtest.NewFakeDB(),
 			secretstest.NewFakeSecretsService()), pluginFakes.NewFakeLicensingService(), &config.Cfg{}),
+		nil,
+		quotatest.New(false, nil),
 	)
 	serverFeatureEnabled := SetupAPITestServer(t, func(hs *HTTPServer) {
 		hs.queryDataService = qds
@@ -142,6 +144,8 @@ func TestAPIEndpoint_Metrics_PluginDecryptionFailure(t *testing.T) {
 			},
 		},
 		pcp,
+		nil,
+		quotatest.New(false, nil),
 	)
 	httpServer := SetupAPITestServer(t, func(hs *HTTPServer) {
 		hs.queryDataService = qds
@@ -302,6 +306,8 @@ func TestDataSourceQueryError(t *testing.T) {
 					},
 						ds, pluginSettings.ProvideService(dbtest.NewFakeDB(),
 							secretstest.NewFakeSecretsService()), pluginFakes.NewFakeLicensingService(), &config.Cfg{}),
+					nil,
+					quotatest.New(false, nil),
 				)
 				hs.QuotaService = quotatest.New(false, nil)
 			})
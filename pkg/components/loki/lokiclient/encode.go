@@ -1,4 +1,4 @@
-package historian
+package lokiclient
 
 import (
 	"encoding/json"
@@ -15,9 +15,9 @@ import (
 
 type JsonEncoder struct{}
 
-func (e JsonEncoder) encode(s []stream) ([]byte, error) {
+func (e JsonEncoder) encode(s []Stream) ([]byte, error) {
 	body := struct {
-		Streams []stream `json:"streams"`
+		Streams []Stream `json:"streams"`
 	}{Streams: s}
 	enc, err := json.Marshal(body)
 	if err != nil {
@@ -34,7 +34,7 @@ func (e JsonEncoder) headers() map[string]string {
 
 type SnappyProtoEncoder struct{}
 
-func (e SnappyProtoEncoder) encode(s []stream) ([]byte, error) {
+func (e SnappyProtoEncoder) encode(s []Stream) ([]byte, error) {
 	body := logproto.PushRequest{
 		Streams: make([]logproto.Stream, 0, len(s)),
 	}
@@ -4,6 +4,8 @@ import (
 	"math"
 	"sort"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
 
@@ -15,6 +17,8 @@ func (cr reducer) ValidReduceFunc() bool {
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "range", "stddev", "p50", "p90", "p95", "p99":
+		return true
 	}
 	return false
 }
@@ -124,6 +128,17 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 				value = (values[(length/2)-1] + values[length/2]) / 2
 			}
 		}
+	case "first":
+		for i := 0; i < ff.Len(); i++ {
+			f := ff.GetValue(i)
+			if !nilOrNaN(f) {
+				value = *f
+				allNull = false
+				break
+			}
+		}
+	case "range", "stddev", "p50", "p90", "p95", "p99":
+		allNull, value = reduceNonNull(ff, string(cr))
 	case "diff":
 		allNull, value = calculateDiff(ff, allNull, value, diff)
 	case "diff_abs":
@@ -154,6 +169,25 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 	return num
 }
 
+// reduceNonNull reduces the values that are neither null nor NaN using the reducer of the same name in mathexp.
+func reduceNonNull(ff mathexp.Float64Field, rFunc string) (bool, float64) {
+	values := make([]*float64, 0, ff.Len())
+	for i := 0; i < ff.Len(); i++ {
+		if f := ff.GetValue(i); !nilOrNaN(f) {
+			values = append(values, f)
+		}
+	}
+	if len(values) == 0 {
+		return true, 0
+	}
+	reduceFunc, err := mathexp.GetReduceFunc(rFunc)
+	if err != nil {
+		return true, 0
+	}
+	nonNull := mathexp.Float64Field(*data.NewField("", nil, values))
+	return false, *reduceFunc(&nonNull)
+}
+
 func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func(float64, float64) float64) (bool, float64) {
 	var (
 		first float64
@@ -115,6 +115,36 @@ func TestReducer(t *testing.T) {
 			inputSeries:    newSeries(nil, nil),
 			expectedNumber: newNumber(nil),
 		},
+		{
+			name:           "first should ignore null values",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, util.Pointer(math.NaN()), util.Pointer(3.0), util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(3.0)),
+		},
+		{
+			name:           "range",
+			reducer:        reducer("range"),
+			inputSeries:    newSeries(util.Pointer(3.0), nil, util.Pointer(-1.0), util.Pointer(4.0)),
+			expectedNumber: newNumber(util.Pointer(5.0)),
+		},
+		{
+			name:           "stddev",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(util.Pointer(2.0), util.Pointer(4.0), nil, util.Pointer(4.0), util.Pointer(4.0), util.Pointer(5.0), util.Pointer(5.0), util.Pointer(7.0), util.Pointer(9.0)),
+			expectedNumber: newNumber(util.Pointer(2.0)),
+		},
+		{
+			name:           "p90 should ignore null values",
+			reducer:        reducer("p90"),
+			inputSeries:    newSeries(util.Pointer(1.0), util.Pointer(2.0), nil, util.Pointer(3.0), util.Pointer(4.0), util.Pointer(5.0), util.Pointer(6.0), util.Pointer(7.0), util.Pointer(8.0), util.Pointer(9.0), util.Pointer(11.0)),
+			expectedNumber: newNumber(util.Pointer(9.2)),
+		},
+		{
+			name:           "p99 with only nulls",
+			reducer:        reducer("p99"),
+			inputSeries:    newSeries(nil, util.Pointer(math.NaN())),
+			expectedNumber: newNumber(nil),
+		},
 	}
 
 	for _, tt := range tests {
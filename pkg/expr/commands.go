@@ -323,6 +323,16 @@ const (
 	TypeClassicConditions
 	// TypeThreshold is the CMDType for checking if a threshold has been crossed
 	TypeThreshold
+	// TypeTimeShift is the CMDType for shifting a timeseries in time.
+	TypeTimeShift
+	// TypePredictLinear is the CMDType for predicting the value of a timeseries using linear regression.
+	TypePredictLinear
+	// TypeRate is the CMDType for the per-second rate of increase of a counter.
+	TypeRate
+	// TypeDerivative is the CMDType for the per-second rate of change of a timeseries.
+	TypeDerivative
+	// TypeSQL is the CMDType for a SQL query over the results of other queries and expressions.
+	TypeSQL
 )
 
 func (gt CommandType) String() string {
@@ -335,6 +345,16 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeTimeShift:
+		return "timeshift"
+	case TypePredictLinear:
+		return "predict_linear"
+	case TypeRate:
+		return "rate"
+	case TypeDerivative:
+		return "derivative"
+	case TypeSQL:
+		return "sql"
 	default:
 		return "unknown"
 	}
@@ -353,6 +373,16 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeClassicConditions, nil
 	case "threshold":
 		return TypeThreshold, nil
+	case "timeshift":
+		return TypeTimeShift, nil
+	case "predict_linear":
+		return TypePredictLinear, nil
+	case "rate":
+		return TypeRate, nil
+	case "derivative":
+		return TypeDerivative, nil
+	case "sql":
+		return TypeSQL, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
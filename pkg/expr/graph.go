@@ -285,10 +285,32 @@ func buildGraphEdges(dp *simple.DirectedGraph, registry map[string]Node) error {
 				}
 			}
 
+			if cmdNode.CMDType == TypeSQL {
+				if dsNode, ok := neededNode.(*DSNode); ok {
+					dsNode.isInputToSQLExpr = true
+				}
+			}
+
 			edge := dp.NewEdge(neededNode, cmdNode)
 
 			dp.SetEdge(edge)
 		}
 	}
+
+	// The results of queries used by SQL expressions are not converted to numbers or series,
+	// so they can't be the input of other expressions.
+	nodeIt = dp.Nodes()
+	for nodeIt.Next() {
+		dsNode, ok := nodeIt.Node().(*DSNode)
+		if !ok || !dsNode.isInputToSQLExpr {
+			continue
+		}
+		toIt := dp.From(dsNode.ID())
+		for toIt.Next() {
+			if cmdNode := toIt.Node().(*CMDNode); cmdNode.CMDType != TypeSQL {
+				return fmt.Errorf("queries used by SQL expressions may not be the input for other expressions, but %v is the input for %v", dsNode.RefID(), cmdNode.RefID())
+			}
+		}
+	}
 	return nil
 }
@@ -0,0 +1,57 @@
+package mathexp
+
+import (
+	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// PredictLinear turns the Series into a Number with the value the series is predicted to have at the given time,
+// based on a simple linear regression of its points. This is the equivalent of predict_linear in PromQL.
+// The value is nil if the series does not have at least two numeric points at different times.
+func (s Series) PredictLinear(refID string, at time.Time) Number {
+	var l data.Labels
+	if s.GetLabels() != nil {
+		l = s.GetLabels().Copy()
+	}
+	number := NewNumber(refID, l)
+
+	// Times are taken relative to the predicted time, so the intercept is the predicted value.
+	_, intercept, ok := linearRegression(s, at)
+	if !ok {
+		number.SetValue(nil)
+		return number
+	}
+	number.SetValue(&intercept)
+	return number
+}
+
+// linearRegression calculates the slope, per second, and the intercept at the origin time of the least squares
+// regression line of the numeric points of the series. It returns false if the line can't be calculated.
+func linearRegression(s Series, origin time.Time) (slope, intercept float64, ok bool) {
+	var n, sumX, sumY, sumXY, sumX2 float64
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		if v == nil || math.IsNaN(*v) || math.IsInf(*v, 0) {
+			continue
+		}
+		x := t.Sub(origin).Seconds()
+		n++
+		sumX += x
+		sumY += *v
+		sumXY += x * *v
+		sumX2 += x * x
+	}
+	if n < 2 {
+		return 0, 0, false
+	}
+	covXY := sumXY - sumX*sumY/n
+	varX := sumX2 - sumX*sumX/n
+	if varX == 0 {
+		return 0, 0, false
+	}
+	slope = covXY / varX
+	intercept = sumY/n - slope*sumX/n
+	return slope, intercept, true
+}
@@ -0,0 +1,90 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPredictLinearSeries(t *testing.T) {
+	tests := []struct {
+		name     string
+		series   Series
+		at       time.Time
+		expected *float64
+	}{
+		{
+			name: "predicts the value of a linear series",
+			series: makeSeries("A", nil,
+				tp{time.Unix(0, 0), float64Pointer(100)},
+				tp{time.Unix(10, 0), float64Pointer(90)},
+				tp{time.Unix(20, 0), float64Pointer(80)},
+			),
+			at:       time.Unix(100, 0),
+			expected: float64Pointer(0),
+		},
+		{
+			name: "uses the least squares regression line",
+			series: makeSeries("A", nil,
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(1, 0), float64Pointer(3)},
+				tp{time.Unix(2, 0), float64Pointer(2)},
+				tp{time.Unix(3, 0), float64Pointer(4)},
+			),
+			// slope = 0.8, intercept at 0 = 1.3
+			at:       time.Unix(10, 0),
+			expected: float64Pointer(9.3),
+		},
+		{
+			name: "ignores null and non-numeric values",
+			series: makeSeries("A", nil,
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(5, 0), nil},
+				tp{time.Unix(7, 0), float64Pointer(math.NaN())},
+				tp{time.Unix(10, 0), float64Pointer(10)},
+			),
+			at:       time.Unix(20, 0),
+			expected: float64Pointer(20),
+		},
+		{
+			name: "is null for a single point",
+			series: makeSeries("A", nil,
+				tp{time.Unix(0, 0), float64Pointer(1)},
+			),
+			at: time.Unix(20, 0),
+		},
+		{
+			name: "is null if all points have the same time",
+			series: makeSeries("A", nil,
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(0, 0), float64Pointer(2)},
+			),
+			at: time.Unix(20, 0),
+		},
+		{
+			name:   "is null for an empty series",
+			series: makeSeries("A", nil),
+			at:     time.Unix(20, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := tt.series.PredictLinear("B", tt.at)
+			if tt.expected == nil {
+				require.Nil(t, n.GetFloat64Value())
+				return
+			}
+			require.InDelta(t, *tt.expected, *n.GetFloat64Value(), 1e-9)
+		})
+	}
+
+	t.Run("keeps the labels of the series", func(t *testing.T) {
+		s := makeSeries("A", data.Labels{"host": "a"}, tp{time.Unix(0, 0), float64Pointer(0)}, tp{time.Unix(1, 0), float64Pointer(1)})
+		n := s.PredictLinear("B", time.Unix(2, 0))
+		require.Equal(t, data.Labels{"host": "a"}, n.GetLabels())
+	})
+}
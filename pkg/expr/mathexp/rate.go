@@ -0,0 +1,53 @@
+package mathexp
+
+import (
+	"math"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Derivative returns a Series with the per-second change between each pair of consecutive numeric points.
+// Each point of the result has the time of the later point of the pair, so the result has one point less.
+func (s Series) Derivative(refID string) Series {
+	return s.perSecond(refID, false)
+}
+
+// Rate is like Derivative, but treats the Series as a counter. A decrease in value is considered a counter reset,
+// and the change is then calculated from zero, so the rate is never negative.
+func (s Series) Rate(refID string) Series {
+	return s.perSecond(refID, true)
+}
+
+func (s Series) perSecond(refID string, counter bool) Series {
+	var l data.Labels
+	if s.GetLabels() != nil {
+		l = s.GetLabels().Copy()
+	}
+	result := NewSeries(refID, l, 0)
+
+	prevIdx := -1
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		if v == nil || math.IsNaN(*v) || math.IsInf(*v, 0) {
+			continue
+		}
+		if prevIdx == -1 {
+			prevIdx = i
+			continue
+		}
+		prevT, prevV := s.GetPoint(prevIdx)
+		seconds := t.Sub(prevT).Seconds()
+		if seconds <= 0 {
+			// Points out of order or with duplicate times can't have a rate.
+			continue
+		}
+		delta := *v - *prevV
+		if counter && delta < 0 {
+			delta = *v
+		}
+		perSecond := delta / seconds
+		result.AppendPoint(t, &perSecond)
+		prevIdx = i
+	}
+	return result
+}
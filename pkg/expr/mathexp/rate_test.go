@@ -0,0 +1,57 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesPerSecond(t *testing.T) {
+	counter := makeSeries("A", data.Labels{"host": "a"},
+		tp{time.Unix(0, 0), float64Pointer(10)},
+		tp{time.Unix(10, 0), float64Pointer(30)},
+		tp{time.Unix(20, 0), nil},
+		tp{time.Unix(30, 0), float64Pointer(70)},
+		tp{time.Unix(40, 0), float64Pointer(math.NaN())},
+		tp{time.Unix(50, 0), float64Pointer(20)},
+	)
+
+	t.Run("derivative", func(t *testing.T) {
+		expected := makeSeries("B", data.Labels{"host": "a"},
+			tp{time.Unix(10, 0), float64Pointer(2)},
+			tp{time.Unix(30, 0), float64Pointer(2)},
+			tp{time.Unix(50, 0), float64Pointer(-2.5)},
+		)
+		require.Equal(t, expected, counter.Derivative("B"))
+	})
+
+	t.Run("rate handles counter resets", func(t *testing.T) {
+		expected := makeSeries("B", data.Labels{"host": "a"},
+			tp{time.Unix(10, 0), float64Pointer(2)},
+			tp{time.Unix(30, 0), float64Pointer(2)},
+			tp{time.Unix(50, 0), float64Pointer(1)},
+		)
+		require.Equal(t, expected, counter.Rate("B"))
+	})
+
+	t.Run("skips points that are not after the previous one", func(t *testing.T) {
+		s := makeSeries("A", nil,
+			tp{time.Unix(10, 0), float64Pointer(1)},
+			tp{time.Unix(10, 0), float64Pointer(5)},
+			tp{time.Unix(5, 0), float64Pointer(5)},
+			tp{time.Unix(20, 0), float64Pointer(11)},
+		)
+		expected := makeSeries("B", nil,
+			tp{time.Unix(20, 0), float64Pointer(1)},
+		)
+		require.Equal(t, expected, s.Derivative("B"))
+	})
+
+	t.Run("empty for less than two points", func(t *testing.T) {
+		s := makeSeries("A", nil, tp{time.Unix(10, 0), float64Pointer(1)})
+		require.Equal(t, 0, s.Rate("B").Len())
+	})
+}
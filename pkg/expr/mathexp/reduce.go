@@ -3,6 +3,7 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -81,6 +82,78 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+func Range(fv *Float64Field) *float64 {
+	minV, maxV := Min(fv), Max(fv)
+	f := *maxV - *minV
+	return &f
+}
+
+// StdDev returns the population standard deviation of the values.
+func StdDev(fv *Float64Field) *float64 {
+	avg := Avg(fv)
+	if math.IsNaN(*avg) {
+		return avg
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *avg
+		sum += d * d
+	}
+	f := math.Sqrt(sum / float64(fv.Len()))
+	return &f
+}
+
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a reducer that calculates the p-th percentile of the values,
+// interpolating linearly between the two closest values.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		nan := math.NaN()
+		if fv.Len() == 0 {
+			return &nan
+		}
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				return &nan
+			}
+			values = append(values, *v)
+		}
+		sort.Float64s(values)
+
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
 func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 	switch strings.ToLower(rFunc) {
 	case "sum":
@@ -95,6 +168,24 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "first":
+		return First, nil
+	case "count_non_null":
+		return CountNonNull, nil
+	case "range":
+		return Range, nil
+	case "stddev":
+		return StdDev, nil
+	case "median":
+		return Median, nil
+	case "p50":
+		return Percentile(50), nil
+	case "p90":
+		return Percentile(90), nil
+	case "p95":
+		return Percentile(95), nil
+	case "p99":
+		return Percentile(99), nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
@@ -102,7 +193,7 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 
 // GetSupportedReduceFuncs returns collection of supported function names
 func GetSupportedReduceFuncs() []string {
-	return []string{"sum", "mean", "min", "max", "count", "last"}
+	return []string{"sum", "mean", "min", "max", "count", "last", "first", "count_non_null", "range", "stddev", "median", "p50", "p90", "p95", "p99"}
 }
 
 // Reduce turns the Series into a Number based on the given reduction function
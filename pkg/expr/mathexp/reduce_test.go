@@ -179,6 +179,105 @@ func TestSeriesReduce(t *testing.T) {
 			resultsIs:   require.Equal,
 			results:     resultValuesNoErr(makeNumber("", nil, nil)),
 		},
+		{
+			name:        "first series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "first empty series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "count_non_null series with a nil value",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "range series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
+		},
+		{
+			name:        "range series with a nil value",
+			red:         "range",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "stddev series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(0.5))),
+		},
+		{
+			name:        "stddev series with a nil value",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "median series",
+			red:         "median",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1.5))),
+		},
+		{
+			name:        "median series with a nil value",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
+		{
+			name:        "p90 series",
+			red:         "p90",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1.9))),
+		},
+		{
+			name:        "p50 empty series",
+			red:         "p50",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results:     resultValuesNoErr(makeNumber("", nil, NaN)),
+		},
 	}
 
 	for _, tt := range tests {
@@ -278,6 +377,27 @@ func TestSeriesReduceDropNN(t *testing.T) {
 			vars:        seriesWithNil,
 			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(1))),
 		},
+		{
+			name:        "DropNN: median series with nil and value should only use real numbers",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
+		},
+		{
+			name:        "DropNN: stddev series that becomes empty after filtering non-number",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesNonNumbers,
+			results:     resultValuesNoErr(makeNumber("", nil, nil)),
+		},
+		{
+			name:        "DropNN: p99 empty series",
+			red:         "p99",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			results:     resultValuesNoErr(makeNumber("", nil, nil)),
+		},
 	}
 
 	for _, tt := range tests {
@@ -365,6 +485,20 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 			vars:        seriesWithNil,
 			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2))),
 		},
+		{
+			name:        "replaceNN: range series with a nil value",
+			red:         "range",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(2-replaceWith))),
+		},
+		{
+			name:        "replaceNN: first series that only has non-numbers",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesNonNumbers,
+			results:     resultValuesNoErr(makeNumber("", nil, float64Pointer(replaceWith))),
+		},
 	}
 
 	for _, tt := range tests {
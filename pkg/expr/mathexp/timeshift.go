@@ -0,0 +1,26 @@
+package mathexp
+
+import (
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// TimeShift returns a copy of the Series with all of its points moved in time by the given duration.
+// A positive duration moves the points forward, so values from that long ago line up with the current ones.
+func (s Series) TimeShift(refID string, shift time.Duration) Series {
+	var l data.Labels
+	if s.GetLabels() != nil {
+		l = s.GetLabels().Copy()
+	}
+	shifted := NewSeries(refID, l, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		if v != nil {
+			f := *v
+			v = &f
+		}
+		shifted.SetPoint(i, t.Add(shift), v)
+	}
+	return shifted
+}
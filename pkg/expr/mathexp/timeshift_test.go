@@ -0,0 +1,34 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeShiftSeries(t *testing.T) {
+	series := makeSeries("A", data.Labels{"host": "a"},
+		tp{time.Unix(10, 0), float64Pointer(1)},
+		tp{time.Unix(20, 0), nil},
+		tp{time.Unix(30, 0), float64Pointer(3)},
+	)
+
+	shifted := series.TimeShift("B", time.Hour)
+
+	expected := makeSeries("B", data.Labels{"host": "a"},
+		tp{time.Unix(3610, 0), float64Pointer(1)},
+		tp{time.Unix(3620, 0), nil},
+		tp{time.Unix(3630, 0), float64Pointer(3)},
+	)
+	require.Equal(t, expected, shifted)
+
+	t.Run("does not modify the original series", func(t *testing.T) {
+		*shifted.GetValue(0) = 10
+		shifted.GetLabels()["host"] = "b"
+		require.Equal(t, float64(1), *series.GetValue(0))
+		require.Equal(t, time.Unix(10, 0), series.GetTime(0))
+		require.Equal(t, "a", series.GetLabels()["host"])
+	})
+}
@@ -121,6 +121,19 @@ func buildCMDNode(rn *rawNode, toggles featuremgmt.FeatureToggles) (*CMDNode, er
 		node.Command, err = classic.UnmarshalConditionsCmd(rn.Query, rn.RefID)
 	case TypeThreshold:
 		node.Command, err = UnmarshalThresholdCommand(rn, toggles)
+	case TypeTimeShift:
+		node.Command, err = UnmarshalTimeShiftCommand(rn)
+	case TypePredictLinear:
+		node.Command, err = UnmarshalPredictLinearCommand(rn)
+	case TypeRate:
+		node.Command, err = UnmarshalRateCommand(rn, true)
+	case TypeDerivative:
+		node.Command, err = UnmarshalRateCommand(rn, false)
+	case TypeSQL:
+		if !toggles.IsEnabled(featuremgmt.FlagSqlExpressions) {
+			return nil, fmt.Errorf("failed to parse expression '%v': %w", rn.RefID, errSQLExpressionsDisabled)
+		}
+		node.Command, err = UnmarshalSQLCommand(rn)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
@@ -148,6 +161,10 @@ type DSNode struct {
 	intervalMS int64
 	maxDP      int64
 	request    Request
+
+	// isInputToSQLExpr is set when the results of the query are used by a SQL expression,
+	// which reads the frames as they are returned by the data source.
+	isInputToSQLExpr bool
 }
 
 // NodeType returns the data pipeline node type.
@@ -288,6 +305,12 @@ func executeDSNodesGrouped(ctx context.Context, now time.Time, vars mathexp.Vars
 					return
 				}
 
+				if dn.isInputToSQLExpr {
+					instrument(nil, "table data")
+					vars[dn.refID] = tableDataResults(dataFrames)
+					continue
+				}
+
 				var result mathexp.Results
 				responseType, result, err := convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
 				if err != nil {
@@ -356,6 +379,11 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		return mathexp.Results{}, MakeQueryError(dn.refID, dn.datasource.UID, err)
 	}
 
+	if dn.isInputToSQLExpr {
+		responseType = "table data"
+		return tableDataResults(dataFrames), nil
+	}
+
 	var result mathexp.Results
 	responseType, result, err = convertDataFramesToResults(ctx, dataFrames, dn.datasource.Type, s, logger)
 	if err != nil {
@@ -0,0 +1,236 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// TimeShiftCommand is an expression command that moves a timeseries in time, so its values can be compared
+// with the values of another series from a different point in time, e.g. "$A - $B" where B is A shifted by a day.
+type TimeShiftCommand struct {
+	Shift       time.Duration
+	VarToShift  string
+	refID       string
+	rawDuration string
+}
+
+// TimeShiftCommandConfig is the JSON model of the time shift command.
+type TimeShiftCommandConfig struct {
+	Expression string `json:"expression"`
+	Shift      string `json:"shift"`
+}
+
+// NewTimeShiftCommand creates a new TimeShiftCommand.
+func NewTimeShiftCommand(refID, rawShift, varToShift string) (*TimeShiftCommand, error) {
+	shift, err := parseCommandDuration(rawShift)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse time shift "shift" duration field %q: %w`, rawShift, err)
+	}
+	return &TimeShiftCommand{
+		Shift:       shift,
+		VarToShift:  varToShift,
+		refID:       refID,
+		rawDuration: rawShift,
+	}, nil
+}
+
+// UnmarshalTimeShiftCommand creates a TimeShiftCommand from Grafana's frontend query.
+func UnmarshalTimeShiftCommand(rn *rawNode) (*TimeShiftCommand, error) {
+	cmdConfig := TimeShiftCommandConfig{}
+	if err := json.Unmarshal(rn.QueryRaw, &cmdConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the time shift command: %w", err)
+	}
+	varToShift, err := seriesCommandVar(rn.RefID, cmdConfig.Expression)
+	if err != nil {
+		return nil, err
+	}
+	if cmdConfig.Shift == "" {
+		return nil, fmt.Errorf("no duration specified for the shift in time shift command for refId %v", rn.RefID)
+	}
+	return NewTimeShiftCommand(rn.RefID, cmdConfig.Shift, varToShift)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (c *TimeShiftCommand) NeedsVars() []string {
+	return []string{c.VarToShift}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (c *TimeShiftCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteTimeShift")
+	defer span.End()
+	span.SetAttributes(attribute.String("shift", c.rawDuration))
+
+	return mapSeries(vars[c.VarToShift], "time shift", func(s mathexp.Series) mathexp.Value {
+		return s.TimeShift(c.refID, c.Shift)
+	})
+}
+
+// PredictLinearCommand is an expression command that predicts the value of each timeseries at some time
+// in the future using linear regression, the equivalent of predict_linear in PromQL.
+type PredictLinearCommand struct {
+	Predict      time.Duration
+	VarToPredict string
+	refID        string
+	rawDuration  string
+}
+
+// PredictLinearCommandConfig is the JSON model of the predict linear command.
+type PredictLinearCommandConfig struct {
+	Expression string `json:"expression"`
+	Predict    string `json:"predict"`
+}
+
+// NewPredictLinearCommand creates a new PredictLinearCommand.
+func NewPredictLinearCommand(refID, rawPredict, varToPredict string) (*PredictLinearCommand, error) {
+	predict, err := parseCommandDuration(rawPredict)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse predict linear "predict" duration field %q: %w`, rawPredict, err)
+	}
+	return &PredictLinearCommand{
+		Predict:      predict,
+		VarToPredict: varToPredict,
+		refID:        refID,
+		rawDuration:  rawPredict,
+	}, nil
+}
+
+// UnmarshalPredictLinearCommand creates a PredictLinearCommand from Grafana's frontend query.
+func UnmarshalPredictLinearCommand(rn *rawNode) (*PredictLinearCommand, error) {
+	cmdConfig := PredictLinearCommandConfig{}
+	if err := json.Unmarshal(rn.QueryRaw, &cmdConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the predict linear command: %w", err)
+	}
+	varToPredict, err := seriesCommandVar(rn.RefID, cmdConfig.Expression)
+	if err != nil {
+		return nil, err
+	}
+	// Predicting the value at the time of the evaluation is a valid use, so the duration is optional.
+	if cmdConfig.Predict == "" {
+		cmdConfig.Predict = "0s"
+	}
+	return NewPredictLinearCommand(rn.RefID, cmdConfig.Predict, varToPredict)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (c *PredictLinearCommand) NeedsVars() []string {
+	return []string{c.VarToPredict}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute. The prediction is made relative to the time of the execution.
+func (c *PredictLinearCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecutePredictLinear")
+	defer span.End()
+	span.SetAttributes(attribute.String("predict", c.rawDuration))
+
+	at := now.Add(c.Predict)
+	return mapSeries(vars[c.VarToPredict], "predict", func(s mathexp.Series) mathexp.Value {
+		return s.PredictLinear(c.refID, at)
+	})
+}
+
+// RateCommand is an expression command that calculates the per-second rate of change of a timeseries.
+// If Counter is true the series is treated as a counter, and decreases are considered counter resets.
+type RateCommand struct {
+	Counter   bool
+	VarToRate string
+	refID     string
+}
+
+// RateCommandConfig is the JSON model of the rate and derivative commands.
+type RateCommandConfig struct {
+	Expression string `json:"expression"`
+}
+
+// NewRateCommand creates a new RateCommand.
+func NewRateCommand(refID, varToRate string, counter bool) *RateCommand {
+	return &RateCommand{
+		Counter:   counter,
+		VarToRate: varToRate,
+		refID:     refID,
+	}
+}
+
+// UnmarshalRateCommand creates a RateCommand from Grafana's frontend query.
+func UnmarshalRateCommand(rn *rawNode, counter bool) (*RateCommand, error) {
+	cmdConfig := RateCommandConfig{}
+	if err := json.Unmarshal(rn.QueryRaw, &cmdConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the rate command: %w", err)
+	}
+	varToRate, err := seriesCommandVar(rn.RefID, cmdConfig.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return NewRateCommand(rn.RefID, varToRate, counter), nil
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (c *RateCommand) NeedsVars() []string {
+	return []string{c.VarToRate}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (c *RateCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteRate")
+	defer span.End()
+	span.SetAttributes(attribute.Bool("counter", c.Counter))
+
+	return mapSeries(vars[c.VarToRate], "calculate the rate of", func(s mathexp.Series) mathexp.Value {
+		if c.Counter {
+			return s.Rate(c.refID)
+		}
+		return s.Derivative(c.refID)
+	})
+}
+
+// mapSeries applies fn to every series of the results. NoData is passed through, any other type is an error.
+func mapSeries(res mathexp.Results, op string, fn func(s mathexp.Series) mathexp.Value) (mathexp.Results, error) {
+	newRes := mathexp.Results{}
+	for _, val := range res.Values {
+		if val == nil {
+			continue
+		}
+		switch v := val.(type) {
+		case mathexp.Series:
+			newRes.Values = append(newRes.Values, fn(v))
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only %s type series, got type %v", op, val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// seriesCommandVar returns the name of the variable referenced by the expression field of a command.
+func seriesCommandVar(refID, expression string) (string, error) {
+	v := strings.TrimPrefix(expression, "$")
+	if v == "" {
+		return "", fmt.Errorf("no expression ID is specified for refId %v. Must be a reference to an existing query or expression", refID)
+	}
+	return v, nil
+}
+
+// parseCommandDuration parses a duration such as "4h" or "1d". Negative durations are allowed.
+func parseCommandDuration(raw string) (time.Duration, error) {
+	if strings.HasPrefix(raw, "-") {
+		d, err := gtime.ParseDuration(strings.TrimPrefix(raw, "-"))
+		return -d, err
+	}
+	return gtime.ParseDuration(raw)
+}
@@ -0,0 +1,146 @@
+package expr
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestUnmarshalSeriesCommands(t *testing.T) {
+	t.Run("time shift", func(t *testing.T) {
+		cmd, err := UnmarshalTimeShiftCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{"expression": "$A", "shift": "1d"}`)})
+		require.NoError(t, err)
+		require.Equal(t, "A", cmd.VarToShift)
+		require.Equal(t, 24*time.Hour, cmd.Shift)
+		require.Equal(t, []string{"A"}, cmd.NeedsVars())
+
+		cmd, err = UnmarshalTimeShiftCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{"expression": "$A", "shift": "-1h"}`)})
+		require.NoError(t, err)
+		require.Equal(t, -time.Hour, cmd.Shift)
+
+		_, err = UnmarshalTimeShiftCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{"expression": "$A"}`)})
+		require.Error(t, err)
+		_, err = UnmarshalTimeShiftCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{"expression": "$A", "shift": "test"}`)})
+		require.Error(t, err)
+		_, err = UnmarshalTimeShiftCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{"shift": "1h"}`)})
+		require.Error(t, err)
+	})
+
+	t.Run("predict linear", func(t *testing.T) {
+		cmd, err := UnmarshalPredictLinearCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{"expression": "$A", "predict": "4h"}`)})
+		require.NoError(t, err)
+		require.Equal(t, "A", cmd.VarToPredict)
+		require.Equal(t, 4*time.Hour, cmd.Predict)
+
+		cmd, err = UnmarshalPredictLinearCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{"expression": "$A"}`)})
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), cmd.Predict)
+
+		_, err = UnmarshalPredictLinearCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{"expression": ""}`)})
+		require.Error(t, err)
+	})
+
+	t.Run("rate and derivative", func(t *testing.T) {
+		for _, typ := range []string{"rate", "derivative"} {
+			cmdType, err := ParseCommandType(typ)
+			require.NoError(t, err)
+			require.Equal(t, typ, cmdType.String())
+		}
+
+		cmd, err := UnmarshalRateCommand(&rawNode{RefID: "B", QueryRaw: []byte(`{"expression": "A"}`)}, true)
+		require.NoError(t, err)
+		require.Equal(t, "A", cmd.VarToRate)
+		require.True(t, cmd.Counter)
+	})
+}
+
+func TestSeriesCommands_Execute(t *testing.T) {
+	varToUse := util.GenerateShortUID()
+	now := time.Unix(1000, 0)
+
+	timeShift, err := NewTimeShiftCommand("B", "1m", varToUse)
+	require.NoError(t, err)
+	predict, err := NewPredictLinearCommand("B", "10s", varToUse)
+	require.NoError(t, err)
+	commands := map[string]Command{
+		"time shift":     timeShift,
+		"predict linear": predict,
+		"rate":           NewRateCommand("B", varToUse, true),
+		"derivative":     NewRateCommand("B", varToUse, false),
+	}
+
+	for name, cmd := range commands {
+		t.Run(name, func(t *testing.T) {
+			t.Run("should return NoData when input NoData", func(t *testing.T) {
+				result, err := cmd.Execute(context.Background(), now, mathexp.Vars{
+					varToUse: mathexp.Results{Values: mathexp.Values{mathexp.NoData{}}},
+				}, tracing.InitializeTracerForTest())
+				require.NoError(t, err)
+				require.Len(t, result.Values, 1)
+				require.Equal(t, parse.TypeNoData, result.Values[0].Type())
+			})
+
+			t.Run("should return error when input Number", func(t *testing.T) {
+				_, err := cmd.Execute(context.Background(), now, mathexp.Vars{
+					varToUse: mathexp.Results{Values: mathexp.Values{mathexp.NewNumber("test", nil)}},
+				}, tracing.InitializeTracerForTest())
+				require.Error(t, err)
+			})
+
+			t.Run("should return error when input Scalar", func(t *testing.T) {
+				_, err := cmd.Execute(context.Background(), now, mathexp.Vars{
+					varToUse: mathexp.Results{Values: mathexp.Values{mathexp.NewScalar("test", util.Pointer(rand.Float64()))}},
+				}, tracing.InitializeTracerForTest())
+				require.Error(t, err)
+			})
+		})
+	}
+
+	// A series growing by 2 every 10 seconds, up to the time of the evaluation.
+	series := mathexp.NewSeries(varToUse, data.Labels{"host": "a"}, 0)
+	for i := 0; i < 5; i++ {
+		series.AppendPoint(now.Add(time.Duration(i-4)*10*time.Second), util.Pointer(float64(2*i)))
+	}
+	vars := mathexp.Vars{varToUse: mathexp.Results{Values: mathexp.Values{series}}}
+
+	t.Run("time shift moves the series", func(t *testing.T) {
+		result, err := timeShift.Execute(context.Background(), now, vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, result.Values, 1)
+		s := result.Values[0].(mathexp.Series)
+		require.Equal(t, data.Labels{"host": "a"}, s.GetLabels())
+		tm, v := s.GetPoint(s.Len() - 1)
+		require.Equal(t, now.Add(time.Minute), tm)
+		require.Equal(t, 8.0, *v)
+	})
+
+	t.Run("predict linear returns the value in the future", func(t *testing.T) {
+		result, err := predict.Execute(context.Background(), now, vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, result.Values, 1)
+		n := result.Values[0].(mathexp.Number)
+		require.Equal(t, data.Labels{"host": "a"}, n.GetLabels())
+		require.InDelta(t, 10.0, *n.GetFloat64Value(), 1e-9)
+	})
+
+	t.Run("rate returns the per-second increase", func(t *testing.T) {
+		result, err := commands["rate"].Execute(context.Background(), now, vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, result.Values, 1)
+		s := result.Values[0].(mathexp.Series)
+		require.Equal(t, 4, s.Len())
+		for i := 0; i < s.Len(); i++ {
+			_, v := s.GetPoint(i)
+			require.InDelta(t, 0.2, *v, 1e-9)
+		}
+	})
+}
@@ -0,0 +1,375 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteRecursive is the authorizer action code of recursive common table expressions,
+// which isn't exported by the driver.
+const sqliteRecursive = 33
+
+// Query loads the frames of each table in an in-memory SQLite database, runs the query and
+// returns its result as a frame with the name. The database only exists for the query, and
+// the query can only read from it.
+func Query(ctx context.Context, name string, query string, tables map[string]data.Frames) (*data.Frame, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = db.Close() }()
+
+	// Each connection to an in-memory database has its own database.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	for table, frames := range tables {
+		if err := loadTable(ctx, conn, table, frames); err != nil {
+			return nil, fmt.Errorf("failed to load table %s: %w", table, err)
+		}
+	}
+
+	err = conn.Raw(func(driverConn any) error {
+		sqliteConn, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("unexpected connection type %T", driverConn)
+		}
+		sqliteConn.RegisterAuthorizer(authorizeRead)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return readFrame(name, rows)
+}
+
+// authorizeRead only authorizes the statements reading the tables, so that queries can't
+// attach databases, change pragmas or the tables.
+func authorizeRead(action int, _, _, _ string) int {
+	switch action {
+	case sqlite3.SQLITE_SELECT, sqlite3.SQLITE_READ, sqlite3.SQLITE_FUNCTION, sqliteRecursive:
+		return sqlite3.SQLITE_OK
+	default:
+		return sqlite3.SQLITE_DENY
+	}
+}
+
+// column is a column of a table, holding the values of a field or of a label of the fields.
+type column struct {
+	name     string
+	declType string
+	field    *data.Field
+	label    string
+}
+
+// loadTable creates the table with the columns of all the frames, and inserts their rows.
+func loadTable(ctx context.Context, conn *sql.Conn, table string, frames data.Frames) error {
+	var columns []string
+	declTypes := map[string]string{}
+	frameColumns := make([][]column, 0, len(frames))
+	for _, frame := range frames {
+		fc, err := tableColumns(frame)
+		if err != nil {
+			return err
+		}
+		for _, c := range fc {
+			if _, ok := declTypes[c.name]; !ok {
+				columns = append(columns, c.name)
+				declTypes[c.name] = c.declType
+			}
+		}
+		frameColumns = append(frameColumns, fc)
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("no columns")
+	}
+
+	definitions := make([]string, 0, len(columns))
+	for _, c := range columns {
+		definitions = append(definitions, quoteIdentifier(c)+" "+declTypes[c])
+	}
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdentifier(table), strings.Join(definitions, ", "))); err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, fc := range frameColumns {
+		if len(fc) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(fc))
+		for _, c := range fc {
+			names = append(names, quoteIdentifier(c.name))
+		}
+		stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			quoteIdentifier(table), strings.Join(names, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(fc)), ", ")))
+		if err != nil {
+			return err
+		}
+
+		rows := 0
+		for _, c := range fc {
+			if c.field != nil {
+				rows = c.field.Len()
+				break
+			}
+		}
+		values := make([]any, len(fc))
+		for row := 0; row < rows; row++ {
+			for i, c := range fc {
+				if c.field == nil {
+					values[i] = c.label
+					continue
+				}
+				values[i] = columnValue(c.field, row)
+			}
+			if _, err := stmt.ExecContext(ctx, values...); err != nil {
+				_ = stmt.Close()
+				return err
+			}
+		}
+		if err := stmt.Close(); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// tableColumns returns the columns of the frame in a table. Time series with several value
+// fields are converted to the long format, and the labels of the fields are columns.
+func tableColumns(frame *data.Frame) ([]column, error) {
+	if schema := frame.TimeSeriesSchema(); schema.Type == data.TimeSeriesTypeWide && len(schema.ValueIndices) > 1 {
+		long, err := data.WideToLong(frame)
+		if err != nil {
+			return nil, err
+		}
+		frame = long
+	}
+
+	var columns []column
+	seen := map[string]bool{}
+	for _, field := range frame.Fields {
+		name := field.Name
+		if name == "" {
+			name = "value"
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column %s", name)
+		}
+		seen[name] = true
+		columns = append(columns, column{name: name, declType: declType(field.Type()), field: field})
+	}
+
+	for _, field := range frame.Fields {
+		keys := make([]string, 0, len(field.Labels))
+		for key := range field.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			columns = append(columns, column{name: key, declType: "TEXT", label: field.Labels[key]})
+		}
+	}
+	return columns, nil
+}
+
+// declType returns the SQLite type of the column of a field. The driver converts the values of
+// TIMESTAMP and BOOLEAN columns back to times and booleans.
+func declType(fieldType data.FieldType) string {
+	switch fieldType.NonNullableType() {
+	case data.FieldTypeTime:
+		return "TIMESTAMP"
+	case data.FieldTypeFloat32, data.FieldTypeFloat64:
+		return "REAL"
+	case data.FieldTypeInt8, data.FieldTypeInt16, data.FieldTypeInt32, data.FieldTypeInt64,
+		data.FieldTypeUint8, data.FieldTypeUint16, data.FieldTypeUint32, data.FieldTypeUint64:
+		return "INTEGER"
+	case data.FieldTypeBool:
+		return "BOOLEAN"
+	default:
+		return "TEXT"
+	}
+}
+
+func columnValue(field *data.Field, row int) any {
+	value, ok := field.ConcreteAt(row)
+	if !ok {
+		return nil
+	}
+	switch v := value.(type) {
+	case float32:
+		return float64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		if v > math.MaxInt64 {
+			return float64(v)
+		}
+		return int64(v)
+	case json.RawMessage:
+		return string(v)
+	case float64, int64, bool, string, time.Time:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// readFrame returns the rows as a frame. The type of each field is the type of the values of
+// its column, or of the column itself when the values are null.
+func readFrame(name string, rows *sql.Rows) (*data.Frame, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]any, len(columnTypes))
+	dest := make([]any, len(columnTypes))
+	for rows.Next() {
+		row := make([]any, len(columnTypes))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range row {
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			values[i] = append(values[i], v)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	frame := data.NewFrame(name)
+	for i, columnType := range columnTypes {
+		field := newField(columnType, values[i])
+		field.Name = columnType.Name()
+		frame.Fields = append(frame.Fields, field)
+	}
+	return frame, nil
+}
+
+func newField(columnType *sql.ColumnType, values []any) *data.Field {
+	fieldType := data.FieldTypeUnknown
+	for _, v := range values {
+		var t data.FieldType
+		switch v.(type) {
+		case nil:
+			continue
+		case time.Time:
+			t = data.FieldTypeNullableTime
+		case int64:
+			t = data.FieldTypeNullableInt64
+		case float64:
+			t = data.FieldTypeNullableFloat64
+		case bool:
+			t = data.FieldTypeNullableBool
+		default:
+			t = data.FieldTypeNullableString
+		}
+		switch {
+		case fieldType == data.FieldTypeUnknown || fieldType == t:
+			fieldType = t
+		case fieldType.Numeric() && t.Numeric():
+			fieldType = data.FieldTypeNullableFloat64
+		default:
+			fieldType = data.FieldTypeNullableString
+		}
+	}
+	if fieldType == data.FieldTypeUnknown {
+		fieldType = columnFieldType(columnType.DatabaseTypeName())
+	}
+
+	field := data.NewFieldFromFieldType(fieldType, len(values))
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		switch fieldType {
+		case data.FieldTypeNullableTime:
+			t := v.(time.Time)
+			field.Set(i, &t)
+		case data.FieldTypeNullableInt64:
+			n := v.(int64)
+			field.Set(i, &n)
+		case data.FieldTypeNullableFloat64:
+			var f float64
+			switch n := v.(type) {
+			case int64:
+				f = float64(n)
+			case float64:
+				f = n
+			}
+			field.Set(i, &f)
+		case data.FieldTypeNullableBool:
+			b := v.(bool)
+			field.Set(i, &b)
+		default:
+			s := fmt.Sprint(v)
+			field.Set(i, &s)
+		}
+	}
+	return field
+}
+
+// columnFieldType returns the type of the field of a column without values.
+func columnFieldType(databaseType string) data.FieldType {
+	switch strings.ToUpper(databaseType) {
+	case "TIMESTAMP", "DATETIME", "DATE":
+		return data.FieldTypeNullableTime
+	case "INTEGER":
+		return data.FieldTypeNullableInt64
+	case "REAL":
+		return data.FieldTypeNullableFloat64
+	case "BOOLEAN":
+		return data.FieldTypeNullableBool
+	default:
+		return data.FieldTypeNullableString
+	}
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,78 @@
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	series := func(host string, values ...float64) *data.Frame {
+		times := make([]time.Time, len(values))
+		for i := range values {
+			times[i] = now.Add(time.Duration(i) * time.Minute)
+		}
+		return data.NewFrame("",
+			data.NewField("time", nil, times),
+			data.NewField("cpu", data.Labels{"host": host}, values),
+		)
+	}
+	tables := map[string]data.Frames{
+		"A": {series("a", 1, 2, 3), series("b", 4, 5, 6)},
+		"B": {data.NewFrame("",
+			data.NewField("host", nil, []string{"a", "b"}),
+			data.NewField("team", nil, []string{"infra", "web"}),
+		)},
+	}
+
+	t.Run("joins and groups the tables", func(t *testing.T) {
+		frame, err := Query(context.Background(), "C", "SELECT B.team, avg(A.cpu) AS cpu FROM A JOIN B ON A.host = B.host GROUP BY B.team ORDER BY B.team", tables)
+		require.NoError(t, err)
+		require.Equal(t, "C", frame.Name)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, "team", frame.Fields[0].Name)
+		require.Equal(t, "cpu", frame.Fields[1].Name)
+		team, _ := frame.ConcreteAt(0, 1)
+		require.Equal(t, "web", team)
+		cpu, _ := frame.FloatAt(1, 1)
+		require.Equal(t, 5.0, cpu)
+	})
+
+	t.Run("keeps the types of the columns", func(t *testing.T) {
+		frame, err := Query(context.Background(), "C", "SELECT time, cpu FROM A WHERE host = 'b' ORDER BY time", tables)
+		require.NoError(t, err)
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		require.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[1].Type())
+		require.Equal(t, 3, frame.Rows())
+		ts, _ := frame.ConcreteAt(0, 0)
+		require.True(t, now.Equal(ts.(time.Time)))
+	})
+
+	t.Run("returns the columns of empty results", func(t *testing.T) {
+		frame, err := Query(context.Background(), "C", "SELECT time, cpu FROM A WHERE cpu > 10", tables)
+		require.NoError(t, err)
+		require.Equal(t, 0, frame.Rows())
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+	})
+
+	t.Run("can only read the tables", func(t *testing.T) {
+		for _, query := range []string{
+			"DELETE FROM A",
+			"ATTACH DATABASE 'test.db' AS test",
+			"PRAGMA table_info(A)",
+			"CREATE TABLE D (x INTEGER)",
+		} {
+			_, err := Query(context.Background(), "C", query, tables)
+			require.Error(t, err, query)
+		}
+	})
+
+	t.Run("fails for unknown tables", func(t *testing.T) {
+		_, err := Query(context.Background(), "C", "SELECT * FROM D", tables)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,208 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenQuotedIdentifier
+	tokenString
+	tokenPunctuation
+	tokenOther
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func (t token) isKeyword(keywords ...string) bool {
+	if t.kind != tokenWord {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(t.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t token) isIdentifier() bool {
+	return t.kind == tokenWord || t.kind == tokenQuotedIdentifier
+}
+
+func (t token) is(punctuation string) bool {
+	return t.kind == tokenPunctuation && t.text == punctuation
+}
+
+// keywords ending the list of tables of a FROM clause.
+var fromListEnd = []string{"WHERE", "GROUP", "HAVING", "WINDOW", "ORDER", "LIMIT", "UNION", "EXCEPT", "INTERSECT"}
+
+// TablesList returns the names of the tables a query reads from: the tables of its FROM and JOIN
+// clauses, excluding the common table expressions defined by the query. Subqueries are included.
+func TablesList(query string) ([]string, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []string
+	seen := map[string]bool{}
+	ctes := map[string]bool{}
+	// inFromList tracks the depth of parentheses at which a list of tables is being read.
+	inFromList := map[int]bool{}
+	depth := 0
+	expectTable := false
+
+	next := func(i int) token {
+		if i+1 < len(tokens) {
+			return tokens[i+1]
+		}
+		return token{kind: tokenOther}
+	}
+
+	for i, tok := range tokens {
+		switch {
+		case tok.isKeyword("FROM", "JOIN"):
+			inFromList[depth] = true
+			expectTable = true
+			continue
+		case tok.is("("):
+			depth++
+		case tok.is(")"):
+			inFromList[depth] = false
+			depth--
+		case tok.is(";"):
+			inFromList = map[int]bool{}
+			depth = 0
+		case tok.is(","):
+			if inFromList[depth] {
+				expectTable = true
+				continue
+			}
+		case tok.isKeyword(fromListEnd...):
+			inFromList[depth] = false
+		case tok.isIdentifier():
+			name := identifier(tok)
+			// The name of a common table expression is followed by its definition: name AS (...)
+			if next(i).isKeyword("AS") && i+2 < len(tokens) && (tokens[i+2].is("(") || tokens[i+2].isKeyword("MATERIALIZED", "NOT")) {
+				ctes[strings.ToLower(name)] = true
+			}
+			// Table valued functions and schema qualified names aren't tables of the query.
+			if expectTable && !next(i).is("(") && !next(i).is(".") && !seen[name] {
+				seen[name] = true
+				tables = append(tables, name)
+			}
+		}
+		expectTable = false
+	}
+
+	result := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if !ctes[strings.ToLower(table)] {
+			result = append(result, table)
+		}
+	}
+	return result, nil
+}
+
+// identifier returns the name of an identifier token, without its quotes.
+func identifier(tok token) string {
+	if tok.kind != tokenQuotedIdentifier {
+		return tok.text
+	}
+	quote := tok.text[0]
+	name := tok.text[1 : len(tok.text)-1]
+	switch quote {
+	case '"':
+		return strings.ReplaceAll(name, `""`, `"`)
+	case '`':
+		return strings.ReplaceAll(name, "``", "`")
+	default:
+		return name
+	}
+}
+
+// tokenize splits a SQLite query in tokens, skipping whitespace and comments.
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			j := i + 2
+			for j+1 < len(runes) && !(runes[j] == '*' && runes[j+1] == '/') {
+				j++
+			}
+			if j+1 >= len(runes) {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i = j + 2
+		case r == '\'' || r == '"' || r == '`' || r == '[':
+			closing := r
+			if r == '[' {
+				closing = ']'
+			}
+			j := i + 1
+			for {
+				if j >= len(runes) {
+					return nil, fmt.Errorf("unterminated quote %c", r)
+				}
+				if runes[j] == closing {
+					// Quotes are escaped by doubling them, except in bracket identifiers.
+					if r != '[' && j+1 < len(runes) && runes[j+1] == closing {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			kind := tokenQuotedIdentifier
+			if r == '\'' {
+				kind = tokenString
+			}
+			tokens = append(tokens, token{kind: kind, text: string(runes[i : j+1])})
+			i = j + 1
+		case isWordRune(r):
+			j := i
+			for j < len(runes) && (isWordRune(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[i:j])})
+			i = j
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (isWordRune(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenOther, text: string(runes[i:j])})
+			i = j
+		case strings.ContainsRune("(),;.", r):
+			tokens = append(tokens, token{kind: tokenPunctuation, text: string(r)})
+			i++
+		default:
+			tokens = append(tokens, token{kind: tokenOther, text: string(r)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || r > unicode.MaxASCII
+}
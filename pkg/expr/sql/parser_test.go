@@ -0,0 +1,38 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTablesList(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{name: "single table", query: "SELECT * FROM A", expected: []string{"A"}},
+		{name: "joins", query: "SELECT * FROM A LEFT JOIN B ON A.host = B.host JOIN C USING (host)", expected: []string{"A", "B", "C"}},
+		{name: "list of tables", query: "SELECT * FROM A a, B AS b WHERE a.host = b.host", expected: []string{"A", "B"}},
+		{name: "subqueries", query: "SELECT * FROM (SELECT * FROM A) x, B WHERE x.v IN (SELECT v FROM C)", expected: []string{"A", "B", "C"}},
+		{name: "quoted identifiers", query: "SELECT * FROM \"my query\" JOIN `B` JOIN [C]", expected: []string{"my query", "B", "C"}},
+		{name: "common table expressions", query: "WITH x AS (SELECT * FROM A), y AS (SELECT * FROM x) SELECT * FROM y JOIN B", expected: []string{"A", "B"}},
+		{name: "strings and comments", query: "SELECT 'FROM D' -- FROM E\n /* FROM F */ FROM A", expected: []string{"A"}},
+		{name: "duplicates", query: "SELECT * FROM A UNION SELECT * FROM A", expected: []string{"A"}},
+		{name: "table valued functions", query: "SELECT * FROM A, json_each(A.tags)", expected: []string{"A"}},
+		{name: "no tables", query: "SELECT 1", expected: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := TablesList(tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, tables)
+		})
+	}
+
+	t.Run("fails for unterminated quotes", func(t *testing.T) {
+		_, err := TablesList("SELECT * FROM 'A")
+		require.Error(t, err)
+	})
+}
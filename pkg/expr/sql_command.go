@@ -0,0 +1,137 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/sql"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+var errSQLExpressionsDisabled = errors.New("SQL expressions are disabled, enable the sqlExpressions feature toggle to use them")
+
+// SQLCommand is an expression command that runs a SQL query over the results of other queries and
+// expressions. The results of each refId used by the query are loaded as a table with the refId as
+// name, in an in-memory SQLite database.
+type SQLCommand struct {
+	Query       string
+	varsToQuery []string
+	refID       string
+}
+
+// SQLCommandConfig is the JSON model of the SQL command.
+type SQLCommandConfig struct {
+	Expression string `json:"expression"`
+}
+
+// NewSQLCommand creates a new SQLCommand.
+func NewSQLCommand(refID, query string) (*SQLCommand, error) {
+	if query == "" {
+		return nil, fmt.Errorf("no SQL query specified in SQL command for refId %v", refID)
+	}
+	tables, err := sql.TablesList(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the SQL query of refId %v: %w", refID, err)
+	}
+	return &SQLCommand{
+		Query:       query,
+		varsToQuery: tables,
+		refID:       refID,
+	}, nil
+}
+
+// UnmarshalSQLCommand creates a SQLCommand from Grafana's frontend query.
+func UnmarshalSQLCommand(rn *rawNode) (*SQLCommand, error) {
+	cmdConfig := SQLCommandConfig{}
+	if err := json.Unmarshal(rn.QueryRaw, &cmdConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the SQL command: %w", err)
+	}
+	return NewSQLCommand(rn.RefID, cmdConfig.Expression)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (c *SQLCommand) NeedsVars() []string {
+	return c.varsToQuery
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute. The result is no data if one of the tables has no data.
+func (c *SQLCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	ctx, span := tracer.Start(ctx, "SSE.ExecuteSQL")
+	defer span.End()
+	span.SetAttributes(attribute.StringSlice("tables", c.varsToQuery))
+
+	tables := make(map[string]data.Frames, len(c.varsToQuery))
+	for _, refID := range c.varsToQuery {
+		results := vars[refID]
+		if results.IsNoData() {
+			return mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}, nil
+		}
+		frames := make(data.Frames, 0, len(results.Values))
+		for _, v := range results.Values {
+			frames = append(frames, v.AsDataFrame())
+		}
+		tables[refID] = frames
+	}
+
+	frame, err := sql.Query(ctx, c.refID, c.Query, tables)
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to execute the SQL query of refId %v: %w", c.refID, err)
+	}
+	frame.RefID = c.refID
+
+	if frame.Rows() == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NoData{Frame: frame}}}, nil
+	}
+
+	// Results with a single numeric column are a number per row, labeled by the string columns,
+	// so that they can be the condition of alert rules and the input of other expressions.
+	if isNumberTable(frame) && !hasNullLabels(frame) {
+		numbers, err := extractNumberSet(frame)
+		if err != nil {
+			return mathexp.Results{}, err
+		}
+		vals := make([]mathexp.Value, 0, len(numbers))
+		for _, n := range numbers {
+			vals = append(vals, n)
+		}
+		return mathexp.Results{Values: vals}, nil
+	}
+	return mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: frame}}}, nil
+}
+
+// hasNullLabels returns true if the string fields of the frame, which are the labels of its
+// numbers, have null values.
+func hasNullLabels(frame *data.Frame) bool {
+	for _, field := range frame.Fields {
+		if field.Type() != data.FieldTypeNullableString {
+			continue
+		}
+		for i := 0; i < field.Len(); i++ {
+			if _, ok := field.ConcreteAt(i); !ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tableDataResults returns the frames of a query used by SQL expressions as they are.
+func tableDataResults(frames data.Frames) mathexp.Results {
+	if len(frames) == 0 {
+		return mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}
+	}
+	vals := make([]mathexp.Value, 0, len(frames))
+	for _, frame := range frames {
+		vals = append(vals, mathexp.TableData{Frame: frame})
+	}
+	return mathexp.Results{Values: vals}
+}
@@ -0,0 +1,125 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+func TestUnmarshalSQLCommand(t *testing.T) {
+	cmd, err := UnmarshalSQLCommand(&rawNode{RefID: "C", QueryRaw: []byte(`{"expression": "SELECT * FROM A JOIN B ON A.host = B.host", "type": "sql"}`)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, cmd.NeedsVars())
+
+	_, err = UnmarshalSQLCommand(&rawNode{RefID: "C", QueryRaw: []byte(`{"type": "sql"}`)})
+	require.Error(t, err)
+
+	cmdType, err := ParseCommandType("sql")
+	require.NoError(t, err)
+	require.Equal(t, TypeSQL, cmdType)
+}
+
+func TestSQLCommand_Execute(t *testing.T) {
+	table := data.NewFrame("",
+		data.NewField("host", nil, []string{"a", "b", "c"}),
+		data.NewField("cpu", nil, []float64{1, 5, 10}),
+		data.NewField("up", nil, []bool{true, true, false}),
+	)
+	vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: table}}}}
+
+	t.Run("returns numbers for results with a single numeric column", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", "SELECT host, cpu * 2 AS cpu FROM A WHERE cpu > 2 ORDER BY host")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 2)
+		for i, host := range []string{"b", "c"} {
+			n, ok := res.Values[i].(mathexp.Number)
+			require.True(t, ok)
+			require.Equal(t, data.Labels{"host": host}, n.GetLabels())
+		}
+		require.Equal(t, 10.0, *res.Values[0].(mathexp.Number).GetFloat64Value())
+	})
+
+	t.Run("returns a table for other results", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", "SELECT host, up FROM A")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		frame := res.Values[0].AsDataFrame()
+		require.Equal(t, "B", frame.RefID)
+		require.Equal(t, 3, frame.Rows())
+		require.Len(t, frame.Fields, 2)
+	})
+
+	t.Run("returns no data when the query has no rows", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", "SELECT host, cpu FROM A WHERE cpu > 100")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.True(t, res.IsNoData())
+	})
+
+	t.Run("returns no data when a table has no data", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", "SELECT * FROM A")
+		require.NoError(t, err)
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.True(t, res.IsNoData())
+	})
+
+	t.Run("fails for queries writing to the database", func(t *testing.T) {
+		cmd, err := NewSQLCommand("B", "DELETE FROM A")
+		require.NoError(t, err)
+		_, err = cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
+
+func TestSQLCommand_Pipeline(t *testing.T) {
+	sqlQuery := Query{
+		RefID:      "B",
+		DataSource: dataSourceModel(),
+		JSON:       json.RawMessage(`{"expression": "SELECT * FROM A", "type": "sql"}`),
+	}
+	dsQuery := Query{
+		RefID:      "A",
+		DataSource: &datasources.DataSource{UID: "Fake"},
+		TimeRange:  AbsoluteTimeRange{},
+	}
+
+	t.Run("fails when the feature toggle is disabled", func(t *testing.T) {
+		s := Service{features: featuremgmt.WithFeatures()}
+		_, err := s.buildPipeline(&Request{Queries: []Query{sqlQuery, dsQuery}})
+		require.ErrorIs(t, err, errSQLExpressionsDisabled)
+	})
+
+	s := Service{features: featuremgmt.WithFeatures(featuremgmt.FlagSqlExpressions)}
+
+	t.Run("marks the queries used by the expression", func(t *testing.T) {
+		nodes, err := s.buildPipeline(&Request{Queries: []Query{sqlQuery, dsQuery}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"A", "B"}, getRefIDOrder(nodes))
+		require.True(t, nodes[0].(*DSNode).isInputToSQLExpr)
+	})
+
+	t.Run("fails when the queries used by the expression are used by other expressions", func(t *testing.T) {
+		mathQuery := Query{
+			RefID:      "C",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{"expression": "$A * 2", "type": "math"}`),
+		}
+		_, err := s.buildPipeline(&Request{Queries: []Query{sqlQuery, dsQuery, mathQuery}})
+		require.ErrorContains(t, err, "queries used by SQL expressions may not be the input for other expressions")
+	})
+}
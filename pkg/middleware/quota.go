@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -28,3 +30,24 @@ func Quota(quotaService quota.Service) func(string) web.Handler {
 		}
 	}
 }
+
+// RateQuota returns a handler that enforces the rate-based quota of API requests of each service account
+// token. Requests over the quota get a 429 response with a Retry-After header.
+func RateQuota(quotaService quota.Service) web.Handler {
+	return func(c *contextmodel.ReqContext) {
+		if c.SignedInUser == nil || c.SignedInUser.TokenID == 0 {
+			return
+		}
+
+		err := quotaService.CheckRateReached(c.Req.Context(), quota.RateTargetAPIRequests, quota.TokenScope, c.SignedInUser.TokenID)
+		var rateLimited *quota.RateLimitedError
+		if errors.As(err, &rateLimited) {
+			c.Resp.Header().Set("Retry-After", rateLimited.RetryAfterHeader())
+			c.JsonApiErr(http.StatusTooManyRequests, "API request rate quota reached", err)
+			return
+		}
+		if err != nil {
+			c.JsonApiErr(http.StatusInternalServerError, "Failed to check rate quota", err)
+		}
+	}
+}
@@ -2,11 +2,14 @@ package middleware
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
@@ -175,6 +178,35 @@ func TestMiddlewareQuota(t *testing.T) {
 	})
 }
 
+func TestMiddlewareRateQuota(t *testing.T) {
+	rateLimited := quota.NewRateLimitedError(quota.RateTargetAPIRequests, quota.TokenScope, 1500*time.Millisecond)
+
+	middlewareScenario(t, "service account token over the rate quota", func(t *testing.T, sc *scenarioContext) {
+		sc.withIdentity(&authn.Identity{ID: "service-account:2", AuthenticatedBy: login.APIKeyAuthModule, TokenID: 5})
+		sc.m.Get("/api", RateQuota(&quotatest.FakeQuotaService{ExpectedRateError: rateLimited}), sc.defaultHandler)
+		sc.fakeReq("GET", "/api").exec()
+		assert.Equal(t, 429, sc.resp.Code)
+		assert.Equal(t, "2", sc.resp.Header().Get("Retry-After"))
+	}, configure)
+
+	middlewareScenario(t, "service account token within the rate quota", func(t *testing.T, sc *scenarioContext) {
+		qs := &quotatest.FakeQuotaService{}
+		sc.withIdentity(&authn.Identity{ID: "service-account:2", AuthenticatedBy: login.APIKeyAuthModule, TokenID: 5})
+		sc.m.Get("/api", RateQuota(qs), sc.defaultHandler)
+		sc.fakeReq("GET", "/api").exec()
+		assert.Equal(t, 200, sc.resp.Code)
+		// each token of a service account has its own quota
+		assert.Equal(t, []quotatest.RateCheck{{Target: quota.RateTargetAPIRequests, Scope: quota.TokenScope, ID: 5}}, qs.RateChecks)
+	}, configure)
+
+	middlewareScenario(t, "user session is not rate limited", func(t *testing.T, sc *scenarioContext) {
+		sc.withIdentity(&authn.Identity{ID: "user:1", SessionToken: &auth.UserToken{UserId: 1}})
+		sc.m.Get("/api", RateQuota(&quotatest.FakeQuotaService{ExpectedRateError: rateLimited}), sc.defaultHandler)
+		sc.fakeReq("GET", "/api").exec()
+		assert.Equal(t, 200, sc.resp.Code)
+	}, configure)
+}
+
 func getQuotaHandler(reached bool, target string) web.Handler {
 	qs := quotatest.New(reached, nil)
 	return Quota(qs)(target)
@@ -3,6 +3,10 @@ package annotationsimpl
 import (
 	"context"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/weaveworks/common/instrument"
+
+	"github.com/grafana/grafana/pkg/components/loki/lokiclient"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/annotations"
@@ -15,16 +19,72 @@ type RepositoryImpl struct {
 	store store
 }
 
-func ProvideService(db db.DB, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tagService tag.Service) *RepositoryImpl {
-	return &RepositoryImpl{
-		store: &xormRepositoryImpl{
-			cfg:               cfg,
-			features:          features,
-			db:                db,
-			log:               log.New("annotations"),
-			tagService:        tagService,
-			maximumTagsLength: cfg.AnnotationMaximumTagsLength,
-		},
+func ProvideService(db db.DB, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tagService tag.Service, registerer prometheus.Registerer) *RepositoryImpl {
+	logger := log.New("annotations")
+	sqlStore := &xormRepositoryImpl{
+		cfg:               cfg,
+		features:          features,
+		db:                db,
+		log:               logger,
+		tagService:        tagService,
+		maximumTagsLength: cfg.AnnotationMaximumTagsLength,
+	}
+
+	backend := cfg.AnnotationStore.Backend
+	if backend == "" || backend == "sql" {
+		return &RepositoryImpl{store: sqlStore}
+	}
+
+	client, err := newLokiClient(cfg.AnnotationStore, registerer, logger)
+	if err != nil {
+		logger.Error("Failed to configure Loki for annotations, storing annotations in the database", "error", err)
+		return &RepositoryImpl{store: sqlStore}
+	}
+	lokiStore := newLokiRepositoryImpl(client, db, features, logger)
+
+	switch backend {
+	case "loki":
+		return &RepositoryImpl{store: lokiStore}
+	case "composite":
+		return &RepositoryImpl{store: newCompositeStore(sqlStore, lokiStore, cfg.AnnotationStore.LokiSources, logger)}
+	default:
+		logger.Error("Unknown annotation store, storing annotations in the database", "store", backend)
+		return &RepositoryImpl{store: sqlStore}
+	}
+}
+
+// newLokiClient returns a client for the Loki instance annotations are stored in.
+func newLokiClient(cfg setting.AnnotationStoreSettings, registerer prometheus.Registerer, logger log.Logger) (*lokiclient.HttpLokiClient, error) {
+	lokiCfg, err := lokiclient.NewLokiConfig(cfg.LokiRemoteURL, cfg.LokiReadURL, cfg.LokiWriteURL)
+	if err != nil {
+		return nil, err
+	}
+	lokiCfg.TenantID = cfg.LokiTenantID
+	lokiCfg.BasicAuthUser = cfg.LokiBasicAuthUsername
+	lokiCfg.BasicAuthPassword = cfg.LokiBasicAuthPassword
+	return lokiclient.NewLokiClient(lokiCfg, lokiclient.NewRequester(), newLokiClientMetrics(registerer), logger), nil
+}
+
+func newLokiClientMetrics(registerer prometheus.Registerer) lokiclient.Metrics {
+	writeDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "grafana",
+		Subsystem: "annotations",
+		Name:      "loki_request_duration_seconds",
+		Help:      "Histogram of request durations to the Loki instance annotations are stored in.",
+		Buckets:   instrument.DefBuckets,
+	}, instrument.HistogramCollectorBuckets)
+	bytesWritten := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "annotations",
+		Name:      "loki_writes_bytes_total",
+		Help:      "The total number of bytes of annotations sent to Loki.",
+	})
+	if registerer != nil {
+		registerer.MustRegister(writeDuration, bytesWritten)
+	}
+	return lokiclient.Metrics{
+		WriteDuration: instrument.NewHistogramCollector(writeDuration),
+		BytesWritten:  bytesWritten,
 	}
 }
 
@@ -0,0 +1,162 @@
+package annotationsimpl
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Sources of annotations, which decide where the composite store writes them.
+const (
+	sourceAlert     = "alert"
+	sourceDashboard = "dashboard"
+	sourceAPI       = "api"
+)
+
+// annotationSource returns the source of an annotation, using the same types as the cleanup job.
+func annotationSource(item *annotations.Item) string {
+	switch {
+	case item.AlertID != 0:
+		return sourceAlert
+	case item.DashboardID != 0:
+		return sourceDashboard
+	default:
+		return sourceAPI
+	}
+}
+
+// compositeStore writes annotations either to the database or to Loki depending
+// on their source, and reads annotations and tags from both.
+//
+// Annotations stored in Loki can't be updated or deleted, so the cleanup job
+// only applies to the database, and updates and deletes of annotations stored
+// in Loki, which are told apart by their IDs, fail.
+type compositeStore struct {
+	sql         store
+	loki        store
+	lokiSources map[string]struct{}
+	log         log.Logger
+}
+
+func newCompositeStore(sql store, loki store, lokiSources []string, logger log.Logger) *compositeStore {
+	sources := make(map[string]struct{}, len(lokiSources))
+	for _, source := range lokiSources {
+		sources[source] = struct{}{}
+	}
+	return &compositeStore{
+		sql:         sql,
+		loki:        loki,
+		lokiSources: sources,
+		log:         logger.New("store", "composite"),
+	}
+}
+
+func (c *compositeStore) storeFor(item *annotations.Item) store {
+	if _, ok := c.lokiSources[annotationSource(item)]; ok {
+		return c.loki
+	}
+	return c.sql
+}
+
+func (c *compositeStore) Add(ctx context.Context, item *annotations.Item) error {
+	return c.storeFor(item).Add(ctx, item)
+}
+
+func (c *compositeStore) AddMany(ctx context.Context, items []annotations.Item) error {
+	var sqlItems, lokiItems []annotations.Item
+	for i := range items {
+		if c.storeFor(&items[i]) == c.loki {
+			lokiItems = append(lokiItems, items[i])
+		} else {
+			sqlItems = append(sqlItems, items[i])
+		}
+	}
+
+	if err := c.sql.AddMany(ctx, sqlItems); err != nil {
+		return err
+	}
+	return c.loki.AddMany(ctx, lokiItems)
+}
+
+func (c *compositeStore) Update(ctx context.Context, item *annotations.Item) error {
+	if isLokiID(item.ID) {
+		return c.loki.Update(ctx, item)
+	}
+	return c.sql.Update(ctx, item)
+}
+
+// Delete deletes an annotation by ID from the store it is in. Annotations
+// deleted by dashboard and panel are only deleted from the database.
+func (c *compositeStore) Delete(ctx context.Context, params *annotations.DeleteParams) error {
+	if isLokiID(params.ID) {
+		return c.loki.Delete(ctx, params)
+	}
+	return c.sql.Delete(ctx, params)
+}
+
+// Get returns the annotations from both stores, most recent first. Annotations
+// from Loki are left out if Loki can't be read, so that an outage of Loki
+// doesn't hide the annotations stored in the database.
+func (c *compositeStore) Get(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	if query.Limit == 0 {
+		query.Limit = 100
+	}
+
+	var sqlItems, lokiItems []*annotations.ItemDTO
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		sqlItems, err = c.sql.Get(gctx, query)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		if lokiItems, err = c.loki.Get(gctx, query); err != nil {
+			c.log.Warn("Failed to get annotations from Loki", "error", err)
+			lokiItems = nil
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	items := append(sqlItems, lokiItems...)
+	sortItems(items)
+	if int64(len(items)) > query.Limit {
+		items = items[:query.Limit]
+	}
+	return items, nil
+}
+
+// GetTags returns the tags from both stores, with the counts of tags found in
+// both added up.
+func (c *compositeStore) GetTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
+	sqlTags, err := c.sql.GetTags(ctx, query)
+	if err != nil {
+		return sqlTags, err
+	}
+	lokiTags, err := c.loki.GetTags(ctx, query)
+	if err != nil {
+		c.log.Warn("Failed to get annotation tags from Loki", "error", err)
+		return sqlTags, nil
+	}
+
+	counts := make(map[string]int64, len(sqlTags.Tags)+len(lokiTags.Tags))
+	for _, t := range append(sqlTags.Tags, lokiTags.Tags...) {
+		counts[t.Tag] += t.Count
+	}
+	return tagsResult(counts, query.Limit), nil
+}
+
+func (c *compositeStore) CleanAnnotations(ctx context.Context, cfg setting.AnnotationCleanupSettings, annotationType string) (int64, error) {
+	return c.sql.CleanAnnotations(ctx, cfg, annotationType)
+}
+
+func (c *compositeStore) CleanOrphanedAnnotationTags(ctx context.Context) (int64, error) {
+	return c.sql.CleanOrphanedAnnotationTags(ctx)
+}
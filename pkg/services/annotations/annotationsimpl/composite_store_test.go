@@ -0,0 +1,136 @@
+package annotationsimpl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeStore struct {
+	items []annotations.Item
+	dtos  []*annotations.ItemDTO
+	tags  []*annotations.TagsDTO
+	err   error
+}
+
+func (s *fakeStore) Add(ctx context.Context, item *annotations.Item) error {
+	s.items = append(s.items, *item)
+	return nil
+}
+
+func (s *fakeStore) AddMany(ctx context.Context, items []annotations.Item) error {
+	s.items = append(s.items, items...)
+	return nil
+}
+
+func (s *fakeStore) Update(ctx context.Context, item *annotations.Item) error {
+	return s.err
+}
+
+func (s *fakeStore) Get(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	return s.dtos, s.err
+}
+
+func (s *fakeStore) Delete(ctx context.Context, params *annotations.DeleteParams) error {
+	return s.err
+}
+
+func (s *fakeStore) GetTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
+	return annotations.FindTagsResult{Tags: s.tags}, s.err
+}
+
+func (s *fakeStore) CleanAnnotations(ctx context.Context, cfg setting.AnnotationCleanupSettings, annotationType string) (int64, error) {
+	return 0, s.err
+}
+
+func (s *fakeStore) CleanOrphanedAnnotationTags(ctx context.Context) (int64, error) {
+	return 0, s.err
+}
+
+func TestCompositeStore(t *testing.T) {
+	setup := func() (*compositeStore, *fakeStore, *fakeStore) {
+		sqlStore, lokiStore := &fakeStore{}, &fakeStore{}
+		return newCompositeStore(sqlStore, lokiStore, []string{sourceAPI}, log.New("annotation.test")), sqlStore, lokiStore
+	}
+
+	t.Run("writes annotations by source", func(t *testing.T) {
+		store, sqlStore, lokiStore := setup()
+
+		require.NoError(t, store.Add(context.Background(), &annotations.Item{Text: "api"}))
+		require.NoError(t, store.Add(context.Background(), &annotations.Item{DashboardID: 1, Text: "dashboard"}))
+		require.NoError(t, store.AddMany(context.Background(), []annotations.Item{
+			{Text: "api"},
+			{AlertID: 1, Text: "alert"},
+		}))
+
+		require.Len(t, lokiStore.items, 2)
+		assert.Equal(t, "api", lokiStore.items[0].Text)
+		assert.Equal(t, "api", lokiStore.items[1].Text)
+		require.Len(t, sqlStore.items, 2)
+		assert.Equal(t, "dashboard", sqlStore.items[0].Text)
+		assert.Equal(t, "alert", sqlStore.items[1].Text)
+	})
+
+	t.Run("reads annotations from both stores, most recent first", func(t *testing.T) {
+		store, sqlStore, lokiStore := setup()
+		sqlStore.dtos = []*annotations.ItemDTO{{ID: 1, Time: 30, TimeEnd: 30}, {ID: 2, Time: 10, TimeEnd: 10}}
+		lokiStore.dtos = []*annotations.ItemDTO{{ID: 3, Time: 20, TimeEnd: 20}}
+
+		items, err := store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, int64(1), items[0].ID)
+		assert.Equal(t, int64(3), items[1].ID)
+	})
+
+	t.Run("reads annotations from the database when Loki fails", func(t *testing.T) {
+		store, sqlStore, lokiStore := setup()
+		sqlStore.dtos = []*annotations.ItemDTO{{ID: 1}}
+		lokiStore.err = errors.New("loki is down")
+
+		items, err := store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+	})
+
+	t.Run("fails when the database fails", func(t *testing.T) {
+		store, sqlStore, _ := setup()
+		sqlStore.err = errors.New("database is down")
+
+		_, err := store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1})
+		require.Error(t, err)
+	})
+
+	t.Run("updates and deletes annotations in the store of their ID", func(t *testing.T) {
+		store, _, lokiStore := setup()
+		lokiStore.err = errLokiReadOnly
+		lokiID := int64(lokiMinID + 1)
+
+		require.NoError(t, store.Update(context.Background(), &annotations.Item{ID: 1, OrgID: 1}))
+		require.NoError(t, store.Delete(context.Background(), &annotations.DeleteParams{ID: 1, OrgID: 1}))
+		require.NoError(t, store.Delete(context.Background(), &annotations.DeleteParams{OrgID: 1, DashboardID: 2, PanelID: 3}))
+		require.ErrorIs(t, store.Update(context.Background(), &annotations.Item{ID: lokiID, OrgID: 1}), errLokiReadOnly)
+		require.ErrorIs(t, store.Delete(context.Background(), &annotations.DeleteParams{ID: lokiID, OrgID: 1}), errLokiReadOnly)
+	})
+
+	t.Run("adds up the tags of both stores", func(t *testing.T) {
+		store, sqlStore, lokiStore := setup()
+		sqlStore.tags = []*annotations.TagsDTO{{Tag: "deploy", Count: 2}, {Tag: "outage", Count: 1}}
+		lokiStore.tags = []*annotations.TagsDTO{{Tag: "deploy", Count: 3}, {Tag: "env:prod", Count: 3}}
+
+		result, err := store.GetTags(context.Background(), &annotations.TagsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.TagsDTO{
+			{Tag: "deploy", Count: 5},
+			{Tag: "env:prod", Count: 3},
+			{Tag: "outage", Count: 1},
+		}, result.Tags)
+	})
+}
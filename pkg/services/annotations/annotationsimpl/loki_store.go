@@ -0,0 +1,546 @@
+package annotationsimpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/loki/lokiclient"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/sqlstore/permissions"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	"github.com/grafana/grafana/pkg/services/tag"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	lokiAnnotationsLabelKey   = "from"
+	lokiAnnotationsLabelValue = "annotations"
+	lokiOrgIDLabel            = "orgID"
+	lokiSourceLabel           = "source"
+
+	// lokiQueryRange is the time range annotations and tags are looked up in
+	// when the query doesn't have one.
+	lokiQueryRange = 7 * 24 * time.Hour
+	// lokiQueryLimit is the maximum number of entries read from Loki when the
+	// entries have to be filtered after they are read.
+	lokiQueryLimit = 5000
+
+	// lokiIDsPerMillisecond is the number of IDs available for the annotations
+	// created in the same millisecond. IDs stay below 2^53, so that they are
+	// exact in JavaScript, until the year 2255.
+	lokiIDsPerMillisecond = 1000
+	// lokiMinID is the lowest ID of an annotation stored in Loki, which is the
+	// first ID of 2005. Annotations stored in the database have lower IDs.
+	lokiMinID = 1 << 50
+)
+
+var errLokiReadOnly = errors.New("annotations stored in Loki can't be updated or deleted")
+
+type lokiClient interface {
+	Push(ctx context.Context, streams []lokiclient.Stream) error
+	RangeQuery(ctx context.Context, logQL string, start, end, limit int64) (lokiclient.QueryRes, error)
+}
+
+// lokiRepositoryImpl stores annotations as log lines in Loki, in one stream
+// per organization and annotation source. The time of the log line is the
+// time of the annotation.
+//
+// Loki is append-only, so annotations can't be updated or deleted, and they
+// are removed by the retention of Loki rather than by the cleanup job.
+type lokiRepositoryImpl struct {
+	client   lokiClient
+	db       db.DB
+	features featuremgmt.FeatureToggles
+	log      log.Logger
+
+	mu     sync.Mutex
+	lastID int64
+}
+
+// lokiAnnotation is the log line of an annotation stored in Loki.
+type lokiAnnotation struct {
+	ID          int64            `json:"id"`
+	DashboardID int64            `json:"dashboardId,omitempty"`
+	PanelID     int64            `json:"panelId,omitempty"`
+	UserID      int64            `json:"userId,omitempty"`
+	AlertID     int64            `json:"alertId,omitempty"`
+	Text        string           `json:"text"`
+	PrevState   string           `json:"prevState,omitempty"`
+	NewState    string           `json:"newState,omitempty"`
+	EpochEnd    int64            `json:"epochEnd"`
+	Created     int64            `json:"created"`
+	Tags        []string         `json:"tags,omitempty"`
+	Data        *simplejson.Json `json:"data,omitempty"`
+}
+
+func newLokiRepositoryImpl(client lokiClient, db db.DB, features featuremgmt.FeatureToggles, logger log.Logger) *lokiRepositoryImpl {
+	return &lokiRepositoryImpl{
+		client:   client,
+		db:       db,
+		features: features,
+		log:      logger.New("store", "loki"),
+	}
+}
+
+func (r *lokiRepositoryImpl) Add(ctx context.Context, item *annotations.Item) error {
+	if err := r.prepareItem(item); err != nil {
+		return err
+	}
+	return r.client.Push(ctx, r.toStreams([]*annotations.Item{item}))
+}
+
+func (r *lokiRepositoryImpl) AddMany(ctx context.Context, items []annotations.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	prepared := make([]*annotations.Item, 0, len(items))
+	for i := range items {
+		if err := r.prepareItem(&items[i]); err != nil {
+			return err
+		}
+		prepared = append(prepared, &items[i])
+	}
+	return r.client.Push(ctx, r.toStreams(prepared))
+}
+
+func (r *lokiRepositoryImpl) Update(ctx context.Context, item *annotations.Item) error {
+	return errLokiReadOnly
+}
+
+func (r *lokiRepositoryImpl) Delete(ctx context.Context, params *annotations.DeleteParams) error {
+	return errLokiReadOnly
+}
+
+func (r *lokiRepositoryImpl) prepareItem(item *annotations.Item) error {
+	item.Tags = tag.JoinTagPairs(tag.ParseTagPairs(item.Tags))
+	item.Created = timeNow().UnixNano() / int64(time.Millisecond)
+	item.Updated = item.Created
+	if item.Epoch == 0 {
+		item.Epoch = item.Created
+	}
+	if err := validateTimeRange(item); err != nil {
+		return err
+	}
+	item.ID = r.nextID()
+	return nil
+}
+
+// nextID returns an ID for a new annotation. Loki has no sequences, so the ID
+// is the creation time in milliseconds followed by a counter of the
+// annotations created in the same millisecond.
+func (r *lokiRepositoryImpl) nextID() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := timeNow().UnixMilli() * lokiIDsPerMillisecond
+	if id <= r.lastID {
+		id = r.lastID + 1
+	}
+	r.lastID = id
+	return id
+}
+
+// isLokiID returns whether the annotation with the ID is stored in Loki.
+func isLokiID(id int64) bool {
+	return id >= lokiMinID
+}
+
+func (r *lokiRepositoryImpl) toStreams(items []*annotations.Item) []lokiclient.Stream {
+	streams := make(map[string]*lokiclient.Stream)
+	keys := make([]string, 0)
+	for _, item := range items {
+		source := annotationSource(item)
+		key := fmt.Sprintf("%d/%s", item.OrgID, source)
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiclient.Stream{Stream: map[string]string{
+				lokiAnnotationsLabelKey: lokiAnnotationsLabelValue,
+				lokiOrgIDLabel:          strconv.FormatInt(item.OrgID, 10),
+				lokiSourceLabel:         source,
+			}}
+			streams[key] = stream
+			keys = append(keys, key)
+		}
+
+		line, err := json.Marshal(lokiAnnotation{
+			ID:          item.ID,
+			DashboardID: item.DashboardID,
+			PanelID:     item.PanelID,
+			UserID:      item.UserID,
+			AlertID:     item.AlertID,
+			Text:        item.Text,
+			PrevState:   item.PrevState,
+			NewState:    item.NewState,
+			EpochEnd:    item.EpochEnd,
+			Created:     item.Created,
+			Tags:        item.Tags,
+			Data:        item.Data,
+		})
+		if err != nil {
+			r.log.Error("Failed to encode annotation, skipping", "error", err)
+			continue
+		}
+		stream.Values = append(stream.Values, lokiclient.Sample{
+			T: time.UnixMilli(item.Epoch),
+			V: string(line),
+		})
+	}
+
+	result := make([]lokiclient.Stream, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *streams[key])
+	}
+	return result
+}
+
+func (r *lokiRepositoryImpl) Get(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	canReadOrg, canReadDashboards, err := lokiAccessControl(query.SignedInUser)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	// Tags and dashboard permissions are checked after the entries are read, so
+	// more entries than requested are read from Loki when they are used.
+	readLimit := limit
+	if len(query.Tags) > 0 || canReadDashboards {
+		readLimit = lokiQueryLimit
+	}
+
+	items, err := r.query(ctx, query.OrgID, query.From, query.To, r.buildLogQuery(query, canReadOrg, canReadDashboards), readLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []*tag.Tag
+	if len(query.Tags) > 0 {
+		tags = tag.ParseTagPairs(query.Tags)
+	}
+	filtered := make([]*annotations.ItemDTO, 0, len(items))
+	for _, item := range items {
+		if query.From > 0 && query.To > 0 && (item.Time > query.To || item.TimeEnd < query.From) {
+			continue
+		}
+		if len(tags) > 0 && !matchTags(item.Tags, tags, query.MatchAny) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+
+	if canReadDashboards {
+		if filtered, err = r.filterDashboards(ctx, query.SignedInUser, filtered); err != nil {
+			return nil, err
+		}
+	}
+
+	sortItems(filtered)
+	if int64(len(filtered)) > limit {
+		filtered = filtered[:limit]
+	}
+
+	if err := r.setUsers(ctx, filtered); err != nil {
+		return nil, err
+	}
+	return filtered, nil
+}
+
+// lokiAccessControl returns whether the user can read organization annotations
+// and annotations of the dashboards the user can view.
+func lokiAccessControl(user identity.Requester) (bool, bool, error) {
+	if user == nil || user.IsNil() {
+		return false, false, errors.New("missing permissions")
+	}
+
+	scopes, has := user.GetPermissions()[ac.ActionAnnotationsRead]
+	if !has {
+		return false, false, errors.New("missing permissions")
+	}
+	types, hasWildcardScope := ac.ParseScopes(ac.ScopeAnnotationsProvider.GetResourceScopeType(""), scopes)
+	if hasWildcardScope {
+		return true, true, nil
+	}
+	_, canReadOrg := types[annotations.Organization.String()]
+	_, canReadDashboards := types[annotations.Dashboard.String()]
+	return canReadOrg, canReadDashboards, nil
+}
+
+func (r *lokiRepositoryImpl) buildLogQuery(query *annotations.ItemQuery, canReadOrg, canReadDashboards bool) string {
+	selectors := []string{
+		fmt.Sprintf("%s=%q", lokiAnnotationsLabelKey, lokiAnnotationsLabelValue),
+		fmt.Sprintf("%s=%q", lokiOrgIDLabel, strconv.FormatInt(query.OrgID, 10)),
+	}
+	if query.Type == "alert" {
+		selectors = append(selectors, fmt.Sprintf("%s=%q", lokiSourceLabel, sourceAlert))
+	} else if query.Type == "annotation" {
+		selectors = append(selectors, fmt.Sprintf("%s!=%q", lokiSourceLabel, sourceAlert))
+	}
+	logQL := "{" + strings.Join(selectors, ",") + "} | json"
+
+	filters := []struct {
+		field string
+		value int64
+	}{
+		{"id", query.AnnotationID},
+		{"alertId", query.AlertID},
+		{"dashboardId", query.DashboardID},
+		{"panelId", query.PanelID},
+		{"userId", query.UserID},
+	}
+	for _, f := range filters {
+		if f.value != 0 {
+			logQL += fmt.Sprintf(" | %s=%q", f.field, strconv.FormatInt(f.value, 10))
+		}
+	}
+
+	// annotations that are not associated with a dashboard don't have a dashboardId
+	if !canReadOrg {
+		logQL += ` | dashboardId!=""`
+	}
+	if !canReadDashboards {
+		logQL += ` | dashboardId=""`
+	}
+	return logQL
+}
+
+// query reads the annotations of the organization in the time range from Loki.
+// Annotations are looked up by their start time.
+func (r *lokiRepositoryImpl) query(ctx context.Context, orgID, from, to int64, logQL string, limit int64) ([]*annotations.ItemDTO, error) {
+	end := time.UnixMilli(to)
+	if to <= 0 {
+		end = timeNow()
+	}
+	start := time.UnixMilli(from)
+	if from <= 0 {
+		start = end.Add(-lokiQueryRange)
+	}
+
+	res, err := r.client.RangeQuery(ctx, logQL, start.UnixNano(), end.UnixNano(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*annotations.ItemDTO, 0)
+	for _, stream := range res.Data.Result {
+		if stream.Stream[lokiOrgIDLabel] != strconv.FormatInt(orgID, 10) {
+			continue
+		}
+		for _, sample := range stream.Values {
+			var entry lokiAnnotation
+			if err := json.Unmarshal([]byte(sample.V), &entry); err != nil {
+				r.log.Warn("Failed to decode annotation, skipping", "error", err)
+				continue
+			}
+			items = append(items, &annotations.ItemDTO{
+				ID:          entry.ID,
+				AlertID:     entry.AlertID,
+				DashboardID: entry.DashboardID,
+				PanelID:     entry.PanelID,
+				UserID:      entry.UserID,
+				NewState:    entry.NewState,
+				PrevState:   entry.PrevState,
+				Created:     entry.Created,
+				Updated:     entry.Created,
+				Time:        sample.T.UnixMilli(),
+				TimeEnd:     entry.EpochEnd,
+				Text:        entry.Text,
+				Tags:        entry.Tags,
+				Data:        entry.Data,
+			})
+		}
+	}
+	return items, nil
+}
+
+// filterDashboards removes the annotations of the dashboards the user can't view.
+func (r *lokiRepositoryImpl) filterDashboards(ctx context.Context, user identity.Requester, items []*annotations.ItemDTO) ([]*annotations.ItemDTO, error) {
+	dashboardIDs := make([]any, 0)
+	seen := make(map[int64]struct{})
+	for _, item := range items {
+		if _, ok := seen[item.DashboardID]; item.DashboardID == 0 || ok {
+			continue
+		}
+		seen[item.DashboardID] = struct{}{}
+		dashboardIDs = append(dashboardIDs, item.DashboardID)
+	}
+	if len(dashboardIDs) == 0 {
+		return items, nil
+	}
+
+	recursiveQueriesAreSupported, err := r.db.RecursiveQueriesAreSupported()
+	if err != nil {
+		return nil, err
+	}
+	filterRBAC := permissions.NewAccessControlDashboardPermissionFilter(user, dashboards.PERMISSION_VIEW, searchstore.TypeDashboard, r.features, recursiveQueriesAreSupported)
+	where, whereParams := filterRBAC.Where()
+	recQueries, recParams := filterRBAC.With()
+
+	sql := "SELECT dashboard.id FROM dashboard"
+	if leftJoin := filterRBAC.LeftJoin(); leftJoin != "" {
+		sql += " LEFT OUTER JOIN " + leftJoin
+	}
+	sql += fmt.Sprintf(" WHERE dashboard.id IN (?%s) AND %s", strings.Repeat(",?", len(dashboardIDs)-1), where)
+	params := append(dashboardIDs, whereParams...)
+	if recQueries != "" {
+		sql = recQueries + sql
+		params = append(recParams, params...)
+	}
+
+	var allowed []int64
+	if err := r.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(sql, params...).Find(&allowed)
+	}); err != nil {
+		return nil, err
+	}
+	allowedSet := make(map[int64]struct{}, len(allowed))
+	for _, id := range allowed {
+		allowedSet[id] = struct{}{}
+	}
+
+	filtered := make([]*annotations.ItemDTO, 0, len(items))
+	for _, item := range items {
+		if _, ok := allowedSet[item.DashboardID]; item.DashboardID == 0 || ok {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
+
+// setUsers sets the login and email of the users that created the annotations.
+func (r *lokiRepositoryImpl) setUsers(ctx context.Context, items []*annotations.ItemDTO) error {
+	userIDs := make([]int64, 0)
+	for _, item := range items {
+		if item.UserID != 0 {
+			userIDs = append(userIDs, item.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	type userRow struct {
+		ID    int64 `xorm:"id"`
+		Login string
+		Email string
+	}
+	var users []userRow
+	err := r.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table(r.db.GetDialect().Quote("user")).Cols("id", "login", "email").In("id", userIDs).Find(&users)
+	})
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int64]userRow, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	for _, item := range items {
+		if u, ok := byID[item.UserID]; ok {
+			item.Login = u.Login
+			item.Email = u.Email
+		}
+	}
+	return nil
+}
+
+func (r *lokiRepositoryImpl) GetTags(ctx context.Context, query *annotations.TagsQuery) (annotations.FindTagsResult, error) {
+	logQL := fmt.Sprintf("{%s=%q,%s=%q}", lokiAnnotationsLabelKey, lokiAnnotationsLabelValue, lokiOrgIDLabel, strconv.FormatInt(query.OrgID, 10))
+	if query.Tag != "" {
+		logQL += fmt.Sprintf(" |= %q", query.Tag)
+	}
+	items, err := r.query(ctx, query.OrgID, 0, 0, logQL, lokiQueryLimit)
+	if err != nil {
+		return annotations.FindTagsResult{Tags: []*annotations.TagsDTO{}}, err
+	}
+
+	counts := make(map[string]int64)
+	for _, item := range items {
+		for _, t := range tag.ParseTagPairs(item.Tags) {
+			if !strings.Contains(t.Key, query.Tag) && !strings.Contains(t.Value, query.Tag) {
+				continue
+			}
+			counts[tagString(t)]++
+		}
+	}
+	return tagsResult(counts, query.Limit), nil
+}
+
+func (r *lokiRepositoryImpl) CleanAnnotations(ctx context.Context, cfg setting.AnnotationCleanupSettings, annotationType string) (int64, error) {
+	return 0, nil
+}
+
+func (r *lokiRepositoryImpl) CleanOrphanedAnnotationTags(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// matchTags returns whether the tags of an annotation match all the tags of the
+// query, or any of them if matchAny is set.
+func matchTags(itemTags []string, tags []*tag.Tag, matchAny bool) bool {
+	set := make(map[string]struct{}, len(itemTags))
+	for _, t := range tag.ParseTagPairs(itemTags) {
+		set[t.Key] = struct{}{}
+		set[tagString(t)] = struct{}{}
+	}
+	matched := 0
+	for _, t := range tags {
+		if _, ok := set[tagString(t)]; ok {
+			matched++
+		}
+	}
+	if matchAny {
+		return matched > 0
+	}
+	return matched == len(tags)
+}
+
+func tagString(t *tag.Tag) string {
+	if t.Value == "" {
+		return t.Key
+	}
+	return t.Key + ":" + t.Value
+}
+
+// tagsResult returns the tags with their counts, sorted by tag.
+func tagsResult(counts map[string]int64, limit int64) annotations.FindTagsResult {
+	if limit == 0 {
+		limit = 100
+	}
+	tags := make([]*annotations.TagsDTO, 0, len(counts))
+	for t, count := range counts {
+		tags = append(tags, &annotations.TagsDTO{Tag: t, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Tag < tags[j].Tag
+	})
+	if int64(len(tags)) > limit {
+		tags = tags[:limit]
+	}
+	return annotations.FindTagsResult{Tags: tags}
+}
+
+// sortItems sorts annotations the same way the database store does, most recent first.
+func sortItems(items []*annotations.ItemDTO) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].TimeEnd != items[j].TimeEnd {
+			return items[i].TimeEnd > items[j].TimeEnd
+		}
+		return items[i].Time > items[j].Time
+	})
+}
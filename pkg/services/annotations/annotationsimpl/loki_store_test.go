@@ -0,0 +1,164 @@
+package annotationsimpl
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/loki/lokiclient"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeLokiClient struct {
+	streams []lokiclient.Stream
+	queries []string
+}
+
+func (c *fakeLokiClient) Push(ctx context.Context, streams []lokiclient.Stream) error {
+	c.streams = append(c.streams, streams...)
+	return nil
+}
+
+func (c *fakeLokiClient) RangeQuery(ctx context.Context, logQL string, start, end, limit int64) (lokiclient.QueryRes, error) {
+	c.queries = append(c.queries, logQL)
+	return lokiclient.QueryRes{Data: lokiclient.QueryData{Result: c.streams}}, nil
+}
+
+func TestLokiStore(t *testing.T) {
+	orgUser := &user.SignedInUser{
+		OrgID: 1,
+		Permissions: map[int64]map[string][]string{
+			1: {accesscontrol.ActionAnnotationsRead: []string{accesscontrol.ScopeAnnotationsTypeOrganization}},
+		},
+	}
+
+	setup := func() (*lokiRepositoryImpl, *fakeLokiClient) {
+		client := &fakeLokiClient{}
+		return newLokiRepositoryImpl(client, nil, featuremgmt.WithFeatures(), log.New("annotation.test")), client
+	}
+
+	t.Run("writes annotations in a stream per organization and source", func(t *testing.T) {
+		store, client := setup()
+
+		item := &annotations.Item{OrgID: 1, Text: "deploy", Epoch: 10, Tags: []string{"deploy", "env:prod"}}
+		require.NoError(t, store.Add(context.Background(), item))
+		require.NotZero(t, item.ID)
+		require.Equal(t, int64(10), item.EpochEnd)
+
+		require.NoError(t, store.AddMany(context.Background(), []annotations.Item{
+			{OrgID: 1, Text: "api", Epoch: 20},
+			{OrgID: 1, DashboardID: 2, Text: "dashboard", Epoch: 30},
+			{OrgID: 2, Text: "other org", Epoch: 40},
+		}))
+
+		require.Len(t, client.streams, 4)
+		require.Equal(t, map[string]string{"from": "annotations", "orgID": "1", "source": "api"}, client.streams[0].Stream)
+		require.Equal(t, time.UnixMilli(10), client.streams[0].Values[0].T)
+		require.JSONEq(t, `{"id": `+jsonInt(item.ID)+`, "text": "deploy", "epochEnd": 10, "created": `+jsonInt(item.Created)+`, "tags": ["deploy", "env:prod"]}`, client.streams[0].Values[0].V)
+		require.Equal(t, map[string]string{"from": "annotations", "orgID": "1", "source": "api"}, client.streams[1].Stream)
+		require.Equal(t, "dashboard", client.streams[2].Stream["source"])
+		require.Equal(t, "2", client.streams[3].Stream["orgID"])
+	})
+
+	t.Run("reads the annotations of the organization, most recent first", func(t *testing.T) {
+		store, client := setup()
+		require.NoError(t, store.AddMany(context.Background(), []annotations.Item{
+			{OrgID: 1, Text: "first", Epoch: 10, Tags: []string{"deploy", "env:dev"}},
+			{OrgID: 1, Text: "second", Epoch: 20, EpochEnd: 25, Tags: []string{"deploy", "env:prod"}},
+			{OrgID: 1, Text: "third", Epoch: 30},
+			{OrgID: 2, Text: "other org", Epoch: 40},
+		}))
+
+		items, err := store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1, SignedInUser: orgUser})
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		assert.Equal(t, "third", items[0].Text)
+		assert.Equal(t, "second", items[1].Text)
+		assert.Equal(t, int64(20), items[1].Time)
+		assert.Equal(t, int64(25), items[1].TimeEnd)
+		assert.Equal(t, `{from="annotations",orgID="1"} | json | dashboardId=""`, client.queries[0])
+
+		items, err = store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1, Tags: []string{"deploy", "env:prod"}, SignedInUser: orgUser})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "second", items[0].Text)
+
+		items, err = store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1, Tags: []string{"env:prod", "env:dev"}, MatchAny: true, SignedInUser: orgUser})
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+
+		items, err = store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1, From: 22, To: 28, SignedInUser: orgUser})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "second", items[0].Text)
+
+		items, err = store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1, Limit: 1, SignedInUser: orgUser})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "third", items[0].Text)
+	})
+
+	t.Run("filters by the query in LogQL", func(t *testing.T) {
+		store, client := setup()
+		_, err := store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1, DashboardID: 2, PanelID: 3, Type: "alert", SignedInUser: orgUser})
+		require.NoError(t, err)
+		assert.Equal(t, `{from="annotations",orgID="1",source="alert"} | json | dashboardId="2" | panelId="3" | dashboardId=""`, client.queries[0])
+	})
+
+	t.Run("requires permissions to read annotations", func(t *testing.T) {
+		store, _ := setup()
+		_, err := store.Get(context.Background(), &annotations.ItemQuery{OrgID: 1, SignedInUser: &user.SignedInUser{OrgID: 1}})
+		require.Error(t, err)
+	})
+
+	t.Run("counts the tags of the annotations", func(t *testing.T) {
+		store, _ := setup()
+		require.NoError(t, store.AddMany(context.Background(), []annotations.Item{
+			{OrgID: 1, Text: "first", Epoch: 10, Tags: []string{"deploy", "env:dev"}},
+			{OrgID: 1, Text: "second", Epoch: 20, Tags: []string{"deploy", "env:prod"}},
+		}))
+
+		result, err := store.GetTags(context.Background(), &annotations.TagsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.TagsDTO{
+			{Tag: "deploy", Count: 2},
+			{Tag: "env:dev", Count: 1},
+			{Tag: "env:prod", Count: 1},
+		}, result.Tags)
+
+		result, err = store.GetTags(context.Background(), &annotations.TagsQuery{OrgID: 1, Tag: "prod"})
+		require.NoError(t, err)
+		require.Equal(t, []*annotations.TagsDTO{{Tag: "env:prod", Count: 1}}, result.Tags)
+	})
+
+	t.Run("generates unique IDs that are exact in JavaScript", func(t *testing.T) {
+		store, _ := setup()
+		previous := int64(0)
+		for i := 0; i < 3000; i++ {
+			id := store.nextID()
+			require.Greater(t, id, previous)
+			require.Less(t, id, int64(1<<53))
+			require.True(t, isLokiID(id))
+			previous = id
+		}
+		require.False(t, isLokiID(1))
+	})
+
+	t.Run("annotations can't be updated or deleted", func(t *testing.T) {
+		store, _ := setup()
+		require.ErrorIs(t, store.Update(context.Background(), &annotations.Item{ID: 1, OrgID: 1}), errLokiReadOnly)
+		require.ErrorIs(t, store.Delete(context.Background(), &annotations.DeleteParams{ID: 1, OrgID: 1}), errLokiReadOnly)
+	})
+}
+
+func jsonInt(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
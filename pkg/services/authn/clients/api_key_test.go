@@ -67,7 +67,7 @@ func TestAPIKey_Authenticate(t *testing.T) {
 				},
 			}},
 			expectedKey: &apikey.APIKey{
-				ID:               1,
+				ID:               2,
 				OrgID:            1,
 				Key:              hash,
 				ServiceAccountId: intPtr(1),
@@ -89,6 +89,7 @@ func TestAPIKey_Authenticate(t *testing.T) {
 					SyncPermissions: true,
 				},
 				AuthenticatedBy: login.APIKeyAuthModule,
+				TokenID:         2,
 			},
 		},
 		{
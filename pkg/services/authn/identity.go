@@ -61,6 +61,9 @@ type Identity struct {
 	// AuthId is the unique identifier for the entity in the external system.
 	// Empty if the identity is provided by Grafana.
 	AuthID string
+	// TokenID is the ID of the service account token the entity authenticated with.
+	// Zero if the entity didn't authenticate with a service account token.
+	TokenID int64
 	// IsDisabled is true if the entity is disabled.
 	IsDisabled bool
 	// HelpFlags1 is the help flags for the entity.
@@ -222,6 +225,7 @@ func (i *Identity) SignedInUser() *user.SignedInUser {
 		Teams:           i.Teams,
 		Permissions:     i.Permissions,
 		IDToken:         i.IDToken,
+		TokenID:         i.TokenID,
 	}
 
 	if namespace == NamespaceAPIKey {
@@ -280,5 +284,6 @@ func IdentityFromSignedInUser(id string, usr *user.SignedInUser, params ClientPa
 		ClientParams:    params,
 		Permissions:     usr.Permissions,
 		IDToken:         usr.IDToken,
+		TokenID:         usr.TokenID,
 	}
 }
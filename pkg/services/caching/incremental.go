@@ -0,0 +1,315 @@
+package caching
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func ProvideIncrementalQueryCache(cfg *setting.Cfg, cache remotecache.CacheStorage) *IncrementalQueryCache {
+	return &IncrementalQueryCache{
+		caching: &OSSCachingService{
+			settings: cfg.QueryCaching,
+			cache:    cache,
+			log:      log.New("query-caching.incremental"),
+		},
+	}
+}
+
+// IncrementalQueryCache caches the time series returned by queries, so that a
+// query of a time range that overlaps the cached one, like a dashboard
+// refreshing a relative time range, only queries the data source for the end
+// of the time range that isn't cached yet.
+//
+// The time series of the cached and the new part of the time range are merged
+// by their name and labels, and trimmed to the time range of the query.
+// Responses that aren't time series, or whose fields change between queries,
+// are always queried over the whole time range. Data sources opt in with
+// incrementalQueryCaching in their JSON data.
+type IncrementalQueryCache struct {
+	caching *OSSCachingService
+}
+
+type incrementalEntry struct {
+	From   time.Time   `json:"from"`
+	To     time.Time   `json:"to"`
+	Frames data.Frames `json:"frames"`
+}
+
+// incrementalQuery is a query of the request and what's cached for it.
+type incrementalQuery struct {
+	query backend.DataQuery
+	key   string
+	// entry is the cached time series, or nil if they can't be reused.
+	entry *incrementalEntry
+	// from is the start of the time range queried from the data source.
+	from time.Time
+}
+
+func (c *IncrementalQueryCache) Enabled() bool {
+	return c != nil && c.caching.settings.IncrementalQueries && c.caching.cache != nil
+}
+
+// QueryData queries the data source through the handler, only for the parts of
+// the time ranges of the queries that aren't cached.
+func (c *IncrementalQueryCache) QueryData(ctx context.Context, req *backend.QueryDataRequest, handler backend.QueryDataHandler) (*backend.QueryDataResponse, error) {
+	ds := req.PluginContext.DataSourceInstanceSettings
+	if !c.Enabled() || ds == nil || !c.enabledFor(ds) {
+		return handler.QueryData(ctx, req)
+	}
+	ttl, ok := c.caching.ttl(ds, queryTTLKey, c.caching.settings.TTL)
+	if !ok {
+		return handler.QueryData(ctx, req)
+	}
+
+	queries := make([]*incrementalQuery, 0, len(req.Queries))
+	for _, q := range req.Queries {
+		queries = append(queries, c.lookup(ctx, req.PluginContext, q))
+	}
+
+	resp, err := handler.QueryData(ctx, withTimeRanges(req, queries))
+	if err != nil {
+		return nil, err
+	}
+
+	// Time series that can't be merged with the cached ones are queried again
+	// over the whole time range.
+	var retry []*incrementalQuery
+	for _, iq := range queries {
+		r, ok := resp.Responses[iq.query.RefID]
+		if !ok || !cacheableDataResponse(r) {
+			continue
+		}
+		frames := r.Frames
+		if iq.entry != nil {
+			if frames, ok = mergeFrames(iq.entry.Frames, r.Frames, iq.from, iq.query.TimeRange); !ok {
+				c.caching.log.Debug("Failed to merge cached time series", "refId", iq.query.RefID)
+				iq.entry, iq.from = nil, iq.query.TimeRange.From
+				retry = append(retry, iq)
+				continue
+			}
+			r.Frames = frames
+			resp.Responses[iq.query.RefID] = r
+		}
+		c.store(ctx, iq, frames, ttl)
+	}
+
+	if len(retry) > 0 {
+		retryResp, err := handler.QueryData(ctx, withTimeRanges(req, retry))
+		if err != nil {
+			return nil, err
+		}
+		for _, iq := range retry {
+			r := retryResp.Responses[iq.query.RefID]
+			resp.Responses[iq.query.RefID] = r
+			if cacheableDataResponse(r) {
+				c.store(ctx, iq, r.Frames, ttl)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// enabledFor returns true if the data source opted in to incremental queries.
+func (c *IncrementalQueryCache) enabledFor(ds *backend.DataSourceInstanceSettings) bool {
+	if len(ds.JSONData) == 0 {
+		return false
+	}
+	var jsonData map[string]any
+	if err := json.Unmarshal(ds.JSONData, &jsonData); err != nil {
+		c.caching.log.Warn("Failed to read the cache settings of the data source", "datasource", ds.UID, "error", err)
+		return false
+	}
+	enabled, _ := jsonData[incrementalQueriesKey].(bool)
+	return enabled
+}
+
+// lookup returns the query with the cached time series, if they overlap the
+// start of its time range. The end of the cached time series, within the
+// overlap set in the settings, is queried again as its data may have changed.
+func (c *IncrementalQueryCache) lookup(ctx context.Context, pCtx backend.PluginContext, q backend.DataQuery) *incrementalQuery {
+	iq := &incrementalQuery{query: q, from: q.TimeRange.From}
+	key, err := incrementalCacheKey(pCtx, q)
+	if err != nil {
+		c.caching.log.Error("Failed to create incremental query cache key", "error", err)
+		return iq
+	}
+	iq.key = key
+
+	value, status := c.caching.get(ctx, key)
+	if status != StatusHit {
+		return iq
+	}
+	entry := &incrementalEntry{}
+	if err := json.Unmarshal(value, entry); err != nil {
+		c.caching.log.Warn("Failed to decode cached time series", "error", err)
+		return iq
+	}
+	if entry.From.After(q.TimeRange.From) || !entry.To.After(q.TimeRange.From) || entry.To.After(q.TimeRange.To) {
+		return iq
+	}
+
+	from := entry.To.Add(-c.caching.settings.IncrementalOverlap).Truncate(queryInterval(q))
+	if !from.After(q.TimeRange.From) {
+		return iq
+	}
+	iq.entry, iq.from = entry, from
+	return iq
+}
+
+func (c *IncrementalQueryCache) store(ctx context.Context, iq *incrementalQuery, frames data.Frames, ttl time.Duration) {
+	if iq.key == "" || !timeSeriesFrames(frames) {
+		return
+	}
+	value, err := json.Marshal(incrementalEntry{From: iq.query.TimeRange.From, To: iq.query.TimeRange.To, Frames: frames})
+	if err != nil {
+		c.caching.log.Error("Failed to encode time series", "error", err)
+		return
+	}
+	c.caching.set(ctx, iq.key, value, ttl)
+}
+
+// withTimeRanges returns a copy of the request with the queries starting at
+// the start of the time ranges that aren't cached.
+func withTimeRanges(req *backend.QueryDataRequest, queries []*incrementalQuery) *backend.QueryDataRequest {
+	r := *req
+	r.Queries = make([]backend.DataQuery, 0, len(queries))
+	for _, iq := range queries {
+		q := iq.query
+		q.TimeRange.From = iq.from
+		r.Queries = append(r.Queries, q)
+	}
+	return &r
+}
+
+func queryInterval(q backend.DataQuery) time.Duration {
+	if q.Interval < time.Second {
+		return time.Second
+	}
+	return q.Interval
+}
+
+func cacheableDataResponse(r backend.DataResponse) bool {
+	return r.Error == nil && r.Status < http.StatusBadRequest
+}
+
+// timeSeriesFrames returns true if each of the frames has a time series type
+// and a single time field. Frames with a single time field but another type,
+// like logs or tables, can't be merged with the rows of another time range.
+func timeSeriesFrames(frames data.Frames) bool {
+	for _, f := range frames {
+		if f.Meta == nil || !f.Meta.Type.IsTimeSeries() {
+			return false
+		}
+		if _, ok := timeIndex(f); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func timeIndex(f *data.Frame) (int, bool) {
+	indices := f.TypeIndices(data.FieldTypeTime, data.FieldTypeNullableTime)
+	if len(indices) != 1 {
+		return 0, false
+	}
+	return indices[0], true
+}
+
+// mergeFrames merges the cached time series before the cut with the new time
+// series from the cut onwards, keeping the data points within the time range.
+// Time series are matched by their name and labels. It returns false if the
+// time series can't be merged, because their fields differ or the new frames
+// aren't time series.
+func mergeFrames(cached, fresh data.Frames, cut time.Time, tr backend.TimeRange) (data.Frames, bool) {
+	if !timeSeriesFrames(fresh) {
+		return nil, false
+	}
+	freshByKey := make(map[string]*data.Frame, len(fresh))
+	for _, f := range fresh {
+		freshByKey[seriesKey(f)] = f
+	}
+
+	merged := make(data.Frames, 0, len(cached)+len(fresh))
+	for _, c := range cached {
+		key := seriesKey(c)
+		f, ok := freshByKey[key]
+		if !ok {
+			merged = append(merged, appendRows(emptyCopy(c), c, tr.From, cut))
+			continue
+		}
+		if !sameFields(c, f) {
+			return nil, false
+		}
+		delete(freshByKey, key)
+		frame := appendRows(emptyCopy(f), c, tr.From, cut)
+		merged = append(merged, appendRows(frame, f, cut, tr.To.Add(time.Nanosecond)))
+	}
+	for _, f := range fresh {
+		if _, ok := freshByKey[seriesKey(f)]; ok {
+			merged = append(merged, appendRows(emptyCopy(f), f, tr.From, tr.To.Add(time.Nanosecond)))
+		}
+	}
+	return merged, true
+}
+
+// appendRows appends the rows of src with a time within [from, to) to dst.
+func appendRows(dst, src *data.Frame, from, to time.Time) *data.Frame {
+	idx, ok := timeIndex(src)
+	if !ok {
+		return dst
+	}
+	for i := 0; i < src.Rows(); i++ {
+		v, ok := src.Fields[idx].ConcreteAt(i)
+		if !ok {
+			continue
+		}
+		if t := v.(time.Time); !t.Before(from) && t.Before(to) {
+			dst.AppendRow(src.RowCopy(i)...)
+		}
+	}
+	return dst
+}
+
+// emptyCopy returns a copy of the frame without rows, that keeps its metadata
+// and field configs.
+func emptyCopy(f *data.Frame) *data.Frame {
+	c := f.EmptyCopy()
+	c.Meta = f.Meta
+	for i, field := range f.Fields {
+		c.Fields[i].Config = field.Config
+	}
+	return c
+}
+
+func seriesKey(f *data.Frame) string {
+	var sb strings.Builder
+	sb.WriteString(f.Name)
+	for _, field := range f.Fields {
+		sb.WriteString("\x00")
+		sb.WriteString(field.Name)
+		sb.WriteString(field.Labels.String())
+	}
+	return sb.String()
+}
+
+func sameFields(a, b *data.Frame) bool {
+	if len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Type() != b.Fields[i].Type() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,194 @@
+package caching
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIncrementalQueryCache(t *testing.T) {
+	now := time.Date(2023, 10, 10, 12, 0, 0, 0, time.UTC)
+	newRequest := func(from, to time.Time) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:                      1,
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "prometheus", JSONData: []byte(`{"incrementalQueryCaching": true}`)},
+			},
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				Interval:  time.Minute,
+				TimeRange: backend.TimeRange{From: from, To: to},
+				JSON:      []byte(`{"expr": "up"}`),
+			}},
+		}
+	}
+
+	// seriesHandler returns a data point per minute of the time range for each job,
+	// and records the time ranges it was queried for.
+	seriesHandler := func(jobs ...string) (*[]backend.TimeRange, backend.QueryDataHandlerFunc) {
+		var queried []backend.TimeRange
+		return &queried, func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+			resp := backend.NewQueryDataResponse()
+			for _, q := range req.Queries {
+				queried = append(queried, q.TimeRange)
+				var frames data.Frames
+				for _, job := range jobs {
+					var times []time.Time
+					var values []float64
+					for t := q.TimeRange.From; !t.After(q.TimeRange.To); t = t.Add(time.Minute) {
+						times = append(times, t)
+						values = append(values, float64(t.Unix()))
+					}
+					frames = append(frames, data.NewFrame("up",
+						data.NewField("time", nil, times),
+						data.NewField("value", data.Labels{"job": job}, values),
+					).SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti}))
+				}
+				resp.Responses[q.RefID] = backend.DataResponse{Frames: frames}
+			}
+			return resp, nil
+		}
+	}
+
+	setup := func(t *testing.T) (*IncrementalQueryCache, remotecache.FakeCacheStorage) {
+		cfg := setting.NewCfg()
+		cfg.QueryCaching = setting.QueryCachingSettings{IncrementalQueries: true, TTL: time.Minute, IncrementalOverlap: 2 * time.Minute}
+		cache := remotecache.NewFakeCacheStorage()
+		return ProvideIncrementalQueryCache(cfg, cache), cache
+	}
+
+	t.Run("only queries the part of the time range that isn't cached", func(t *testing.T) {
+		c, cache := setup(t)
+		queried, handler := seriesHandler("a")
+
+		resp, err := c.QueryData(context.Background(), newRequest(now.Add(-time.Hour), now), handler)
+		require.NoError(t, err)
+		require.Len(t, resp.Responses["A"].Frames, 1)
+		require.Equal(t, 61, resp.Responses["A"].Frames[0].Rows())
+		require.Len(t, cache.Storage, 1)
+
+		resp, err = c.QueryData(context.Background(), newRequest(now.Add(-55*time.Minute), now.Add(5*time.Minute)), handler)
+		require.NoError(t, err)
+		require.Equal(t, []backend.TimeRange{
+			{From: now.Add(-time.Hour), To: now},
+			{From: now.Add(-2 * time.Minute), To: now.Add(5 * time.Minute)},
+		}, *queried)
+
+		frames := resp.Responses["A"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 61, frames[0].Rows())
+		for i := 0; i < frames[0].Rows(); i++ {
+			expected := now.Add(-55 * time.Minute).Add(time.Duration(i) * time.Minute)
+			require.Equal(t, expected, frames[0].At(0, i))
+			require.Equal(t, float64(expected.Unix()), frames[0].At(1, i))
+		}
+	})
+
+	t.Run("merges time series by their labels", func(t *testing.T) {
+		c, _ := setup(t)
+		_, handler := seriesHandler("a", "b")
+		_, err := c.QueryData(context.Background(), newRequest(now.Add(-time.Hour), now), handler)
+		require.NoError(t, err)
+
+		_, handler = seriesHandler("b", "c")
+		resp, err := c.QueryData(context.Background(), newRequest(now.Add(-55*time.Minute), now.Add(5*time.Minute)), handler)
+		require.NoError(t, err)
+
+		frames := resp.Responses["A"].Frames
+		require.Len(t, frames, 3)
+		assert.Equal(t, "a", frames[0].Fields[1].Labels["job"])
+		assert.Equal(t, 53, frames[0].Rows())
+		assert.Equal(t, "b", frames[1].Fields[1].Labels["job"])
+		assert.Equal(t, 61, frames[1].Rows())
+		assert.Equal(t, "c", frames[2].Fields[1].Labels["job"])
+		assert.Equal(t, 8, frames[2].Rows())
+	})
+
+	t.Run("queries the whole time range when the fields of the time series change", func(t *testing.T) {
+		c, _ := setup(t)
+		_, handler := seriesHandler("a")
+		_, err := c.QueryData(context.Background(), newRequest(now.Add(-time.Hour), now), handler)
+		require.NoError(t, err)
+
+		var queried []backend.TimeRange
+		resp, err := c.QueryData(context.Background(), newRequest(now.Add(-55*time.Minute), now.Add(5*time.Minute)), backend.QueryDataHandlerFunc(
+			func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				queried = append(queried, req.Queries[0].TimeRange)
+				frame := data.NewFrame("up",
+					data.NewField("time", nil, []time.Time{req.Queries[0].TimeRange.From}),
+					data.NewField("value", data.Labels{"job": "a"}, []string{"up"}),
+				).SetMeta(&data.FrameMeta{Type: data.FrameTypeTimeSeriesMulti})
+				return &backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: data.Frames{frame}}}}, nil
+			}))
+		require.NoError(t, err)
+		require.Equal(t, []backend.TimeRange{
+			{From: now.Add(-2 * time.Minute), To: now.Add(5 * time.Minute)},
+			{From: now.Add(-55 * time.Minute), To: now.Add(5 * time.Minute)},
+		}, queried)
+		require.Equal(t, 1, resp.Responses["A"].Frames[0].Rows())
+	})
+
+	t.Run("queries the whole time range when it starts before the cached one", func(t *testing.T) {
+		c, _ := setup(t)
+		queried, handler := seriesHandler("a")
+		_, err := c.QueryData(context.Background(), newRequest(now.Add(-time.Hour), now), handler)
+		require.NoError(t, err)
+
+		_, err = c.QueryData(context.Background(), newRequest(now.Add(-2*time.Hour), now), handler)
+		require.NoError(t, err)
+		require.Equal(t, backend.TimeRange{From: now.Add(-2 * time.Hour), To: now}, (*queried)[1])
+	})
+
+	t.Run("doesn't cache responses that aren't time series", func(t *testing.T) {
+		c, cache := setup(t)
+		_, err := c.QueryData(context.Background(), newRequest(now.Add(-time.Hour), now), backend.QueryDataHandlerFunc(
+			func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				frame := data.NewFrame("table", data.NewField("value", nil, []float64{1}))
+				return &backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: data.Frames{frame}}}}, nil
+			}))
+		require.NoError(t, err)
+		require.Empty(t, cache.Storage)
+	})
+
+	t.Run("doesn't cache frames with a time field that aren't time series", func(t *testing.T) {
+		c, cache := setup(t)
+		_, err := c.QueryData(context.Background(), newRequest(now.Add(-time.Hour), now), backend.QueryDataHandlerFunc(
+			func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				frame := data.NewFrame("logs",
+					data.NewField("time", nil, []time.Time{now}),
+					data.NewField("line", nil, []string{"started"}),
+				).SetMeta(&data.FrameMeta{Type: data.FrameTypeLogLines})
+				return &backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: data.Frames{frame}}}}, nil
+			}))
+		require.NoError(t, err)
+		require.Empty(t, cache.Storage)
+	})
+
+	t.Run("data sources have to opt in", func(t *testing.T) {
+		c, cache := setup(t)
+		_, handler := seriesHandler("a")
+		req := newRequest(now.Add(-time.Hour), now)
+		req.PluginContext.DataSourceInstanceSettings.JSONData = []byte(`{}`)
+		_, err := c.QueryData(context.Background(), req, handler)
+		require.NoError(t, err)
+		require.Empty(t, cache.Storage)
+	})
+
+	t.Run("data sources can disable caching", func(t *testing.T) {
+		c, cache := setup(t)
+		_, handler := seriesHandler("a")
+		req := newRequest(now.Add(-time.Hour), now)
+		req.PluginContext.DataSourceInstanceSettings.JSONData = []byte(`{"incrementalQueryCaching": true, "queryCachingTTLMs": -1}`)
+		_, err := c.QueryData(context.Background(), req, handler)
+		require.NoError(t, err)
+		require.Empty(t, cache.Storage)
+	})
+}
@@ -0,0 +1,142 @@
+package caching
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const (
+	// queryTTLKey and resourceTTLKey are the keys of the JSON data of a data
+	// source that set its TTLs, in milliseconds. A negative TTL disables caching
+	// for the data source.
+	queryTTLKey    = "queryCachingTTLMs"
+	resourceTTLKey = "resourceCachingTTLMs"
+	// incrementalQueriesKey is the key of the JSON data of a data source that
+	// opts it in to reusing cached time series for overlapping time ranges.
+	incrementalQueriesKey = "incrementalQueryCaching"
+
+	queryKeyPrefix       = "query-cache:"
+	resourceKeyPrefix    = "resource-cache:"
+	incrementalKeyPrefix = "incremental-cache:"
+)
+
+// alignedTimeRange returns the time range of a query aligned to its interval,
+// so that queries of relative time ranges like "Last 6 hours" that are
+// repeated within an interval get the same cache key. The query itself keeps
+// its time range, so that the data source returns the data up to now.
+func alignedTimeRange(q backend.DataQuery) backend.TimeRange {
+	interval := q.Interval
+	if interval < time.Second {
+		interval = time.Second
+	}
+	return backend.TimeRange{
+		From: q.TimeRange.From.Truncate(interval),
+		To:   q.TimeRange.To.Truncate(interval),
+	}
+}
+
+// dataSourceKey identifies the data source, and the user if the data source
+// forwards the identity of users, as the results then depend on the user.
+type dataSourceKey struct {
+	OrgID   int64     `json:"orgId"`
+	UID     string    `json:"uid"`
+	Updated time.Time `json:"updated"`
+	User    string    `json:"user,omitempty"`
+}
+
+func newDataSourceKey(pCtx backend.PluginContext) dataSourceKey {
+	ds := pCtx.DataSourceInstanceSettings
+	key := dataSourceKey{OrgID: pCtx.OrgID, UID: ds.UID, Updated: ds.Updated}
+
+	var jsonData struct {
+		OAuthPassThru bool `json:"oauthPassThru"`
+	}
+	if err := json.Unmarshal(ds.JSONData, &jsonData); err == nil && jsonData.OAuthPassThru && pCtx.User != nil {
+		key.User = pCtx.User.Login
+	}
+	return key
+}
+
+type queryKey struct {
+	RefID         string          `json:"refId"`
+	QueryType     string          `json:"queryType"`
+	MaxDataPoints int64           `json:"maxDataPoints"`
+	Interval      time.Duration   `json:"interval"`
+	From          int64           `json:"from"`
+	To            int64           `json:"to"`
+	JSON          json.RawMessage `json:"json"`
+}
+
+// queryCacheKey returns the cache key of a query request, derived from the
+// data source and the queries with their aligned time ranges.
+func queryCacheKey(req *backend.QueryDataRequest) (string, error) {
+	queries := make([]queryKey, 0, len(req.Queries))
+	for _, q := range req.Queries {
+		timeRange := alignedTimeRange(q)
+		queries = append(queries, queryKey{
+			RefID:         q.RefID,
+			QueryType:     q.QueryType,
+			MaxDataPoints: q.MaxDataPoints,
+			Interval:      q.Interval,
+			From:          timeRange.From.UnixMilli(),
+			To:            timeRange.To.UnixMilli(),
+			JSON:          q.JSON,
+		})
+	}
+
+	return hashKey(queryKeyPrefix, struct {
+		DataSource dataSourceKey `json:"datasource"`
+		Queries    []queryKey    `json:"queries"`
+	}{
+		DataSource: newDataSourceKey(req.PluginContext),
+		Queries:    queries,
+	})
+}
+
+// incrementalCacheKey returns the cache key of the time series of a query,
+// which leaves out its time range so that the time series can be reused by
+// the same query over another time range.
+func incrementalCacheKey(pCtx backend.PluginContext, q backend.DataQuery) (string, error) {
+	return hashKey(incrementalKeyPrefix, struct {
+		DataSource dataSourceKey `json:"datasource"`
+		Query      queryKey      `json:"query"`
+	}{
+		DataSource: newDataSourceKey(pCtx),
+		Query: queryKey{
+			RefID:         q.RefID,
+			QueryType:     q.QueryType,
+			MaxDataPoints: q.MaxDataPoints,
+			Interval:      q.Interval,
+			JSON:          q.JSON,
+		},
+	})
+}
+
+// resourceCacheKey returns the cache key of a resource request, derived from
+// the data source and the URL and body of the request.
+func resourceCacheKey(req *backend.CallResourceRequest) (string, error) {
+	return hashKey(resourceKeyPrefix, struct {
+		DataSource dataSourceKey `json:"datasource"`
+		Method     string        `json:"method"`
+		URL        string        `json:"url"`
+		Body       []byte        `json:"body"`
+	}{
+		DataSource: newDataSourceKey(req.PluginContext),
+		Method:     req.Method,
+		URL:        req.URL,
+		Body:       req.Body,
+	})
+}
+
+func hashKey(prefix string, v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return prefix + hex.EncodeToString(sum[:]), nil
+}
@@ -2,12 +2,26 @@ package caching
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 const (
-	XCacheHeader   = "X-Cache"
+	XCacheHeader = "X-Cache"
+	// XCacheSkipHeader makes a request skip the cache when it's set to "true".
+	XCacheSkipHeader = "X-Cache-Skip"
+
 	StatusHit      = "HIT"
 	StatusMiss     = "MISS"
 	StatusBypass   = "BYPASS"
@@ -35,8 +49,12 @@ type CachedResourceDataResponse struct {
 	UpdateCacheFn CacheResourceResponseFn
 }
 
-func ProvideCachingService() *OSSCachingService {
-	return &OSSCachingService{}
+func ProvideCachingService(cfg *setting.Cfg, cache remotecache.CacheStorage) *OSSCachingService {
+	return &OSSCachingService{
+		settings: cfg.QueryCaching,
+		cache:    cache,
+		log:      log.New("query-caching"),
+	}
 }
 
 type CachingService interface {
@@ -49,16 +67,209 @@ type CachingService interface {
 	HandleResourceRequest(context.Context, *backend.CallResourceRequest) (bool, CachedResourceDataResponse)
 }
 
-// Implementation of interface - does nothing
+// OSSCachingService caches the results of data source queries and resource requests in the remote cache.
+// Query results are cached for all data sources when caching is enabled, unless a data source disables it
+// by setting a negative TTL in its JSON data.
 type OSSCachingService struct {
+	settings setting.QueryCachingSettings
+	cache    remotecache.CacheStorage
+	log      log.Logger
 }
 
 func (s *OSSCachingService) HandleQueryRequest(ctx context.Context, req *backend.QueryDataRequest) (bool, CachedQueryDataResponse) {
-	return false, CachedQueryDataResponse{}
+	if !s.enabled() || req.PluginContext.DataSourceInstanceSettings == nil {
+		return false, CachedQueryDataResponse{}
+	}
+
+	ttl, ok := s.ttl(req.PluginContext.DataSourceInstanceSettings, queryTTLKey, s.settings.TTL)
+	if !ok {
+		setCacheStatus(ctx, StatusDisabled)
+		return false, CachedQueryDataResponse{}
+	}
+
+	key, err := queryCacheKey(req)
+	if err != nil {
+		s.log.Error("Failed to create query cache key", "error", err)
+		setCacheStatus(ctx, StatusError)
+		return false, CachedQueryDataResponse{}
+	}
+
+	updateCache := func(ctx context.Context, resp *backend.QueryDataResponse) {
+		if resp == nil || !cacheableQueryResponse(resp) {
+			return
+		}
+		value, err := json.Marshal(resp)
+		if err != nil {
+			s.log.Error("Failed to encode query response", "error", err)
+			return
+		}
+		s.set(ctx, key, value, ttl)
+	}
+
+	value, status := s.get(ctx, key)
+	if status != StatusHit {
+		setCacheStatus(ctx, status)
+		if status == StatusError {
+			return false, CachedQueryDataResponse{}
+		}
+		return false, CachedQueryDataResponse{UpdateCacheFn: updateCache}
+	}
+
+	resp := &backend.QueryDataResponse{}
+	if err := json.Unmarshal(value, resp); err != nil {
+		s.log.Warn("Failed to decode cached query response", "error", err)
+		setCacheStatus(ctx, StatusMiss)
+		return false, CachedQueryDataResponse{UpdateCacheFn: updateCache}
+	}
+	setCacheStatus(ctx, StatusHit)
+	return true, CachedQueryDataResponse{Response: resp}
 }
 
+// HandleResourceRequest only caches GET requests, as other requests may change data.
 func (s *OSSCachingService) HandleResourceRequest(ctx context.Context, req *backend.CallResourceRequest) (bool, CachedResourceDataResponse) {
-	return false, CachedResourceDataResponse{}
+	if !s.enabled() || req.PluginContext.DataSourceInstanceSettings == nil {
+		return false, CachedResourceDataResponse{}
+	}
+
+	ttl, ok := s.ttl(req.PluginContext.DataSourceInstanceSettings, resourceTTLKey, s.settings.ResourceTTL)
+	if !ok {
+		setCacheStatus(ctx, StatusDisabled)
+		return false, CachedResourceDataResponse{}
+	}
+	if req.Method != http.MethodGet {
+		setCacheStatus(ctx, StatusBypass)
+		return false, CachedResourceDataResponse{}
+	}
+
+	key, err := resourceCacheKey(req)
+	if err != nil {
+		s.log.Error("Failed to create resource cache key", "error", err)
+		setCacheStatus(ctx, StatusError)
+		return false, CachedResourceDataResponse{}
+	}
+
+	// Plugins can send a response in several parts, which are not cached as
+	// only complete responses can be served from the cache.
+	var sent atomic.Int32
+	updateCache := func(ctx context.Context, resp *backend.CallResourceResponse) {
+		if sent.Add(1) > 1 {
+			if err := s.cache.Delete(ctx, key); err != nil {
+				s.log.Warn("Failed to delete partial resource response from the cache", "error", err)
+			}
+			return
+		}
+		if resp == nil || !cacheableResourceResponse(resp) {
+			return
+		}
+		value, err := json.Marshal(resp)
+		if err != nil {
+			s.log.Error("Failed to encode resource response", "error", err)
+			return
+		}
+		s.set(ctx, key, value, ttl)
+	}
+
+	value, status := s.get(ctx, key)
+	if status != StatusHit {
+		setCacheStatus(ctx, status)
+		if status == StatusError {
+			return false, CachedResourceDataResponse{}
+		}
+		return false, CachedResourceDataResponse{UpdateCacheFn: updateCache}
+	}
+
+	resp := &backend.CallResourceResponse{}
+	if err := json.Unmarshal(value, resp); err != nil {
+		s.log.Warn("Failed to decode cached resource response", "error", err)
+		setCacheStatus(ctx, StatusMiss)
+		return false, CachedResourceDataResponse{UpdateCacheFn: updateCache}
+	}
+	setCacheStatus(ctx, StatusHit)
+	return true, CachedResourceDataResponse{Response: resp}
+}
+
+func (s *OSSCachingService) enabled() bool {
+	return s.settings.Enabled && s.cache != nil
+}
+
+// get returns the cached value of the key and the cache status. Requests with
+// the X-Cache-Skip header are always a cache miss, so the cache is refreshed.
+func (s *OSSCachingService) get(ctx context.Context, key string) ([]byte, string) {
+	if reqCtx := contexthandler.FromContext(ctx); reqCtx != nil && reqCtx.Req.Header.Get(XCacheSkipHeader) == "true" {
+		return nil, StatusBypass
+	}
+
+	value, err := s.cache.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return nil, StatusMiss
+		}
+		s.log.Error("Failed to read from the cache", "error", err)
+		return nil, StatusError
+	}
+	return value, StatusHit
+}
+
+func (s *OSSCachingService) set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if s.settings.MaxValueSize > 0 && len(value) > s.settings.MaxValueSize {
+		s.log.Debug("Response is too large to be cached", "size", len(value), "maxSize", s.settings.MaxValueSize)
+		return
+	}
+	if err := s.cache.Set(ctx, key, value, ttl); err != nil {
+		s.log.Error("Failed to write to the cache", "error", err)
+	}
+}
+
+// ttl returns the TTL set in the JSON data of the data source, or the default
+// TTL, capped by the maximum TTL. It returns false if the data source disables caching.
+func (s *OSSCachingService) ttl(ds *backend.DataSourceInstanceSettings, key string, defaultTTL time.Duration) (time.Duration, bool) {
+	ttl := defaultTTL
+	if len(ds.JSONData) > 0 {
+		var jsonData map[string]any
+		if err := json.Unmarshal(ds.JSONData, &jsonData); err != nil {
+			s.log.Warn("Failed to read the cache settings of the data source", "datasource", ds.UID, "error", err)
+		} else if ms, ok := jsonData[key].(float64); ok && ms != 0 {
+			ttl = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	if ttl <= 0 {
+		return 0, false
+	}
+	if s.settings.MaxTTL > 0 && ttl > s.settings.MaxTTL {
+		ttl = s.settings.MaxTTL
+	}
+	return ttl, true
+}
+
+// setCacheStatus sets the X-Cache header of the response to the cache status.
+func setCacheStatus(ctx context.Context, status string) {
+	if reqCtx := contexthandler.FromContext(ctx); reqCtx != nil && reqCtx.Resp != nil {
+		reqCtx.Resp.Header().Set(XCacheHeader, status)
+	}
+}
+
+// cacheableQueryResponse returns false if any of the queries failed, so that errors are not cached.
+func cacheableQueryResponse(resp *backend.QueryDataResponse) bool {
+	for _, r := range resp.Responses {
+		if r.Error != nil || r.Status >= http.StatusBadRequest {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheableResourceResponse returns true for successful responses that are not specific to a user session.
+func cacheableResourceResponse(resp *backend.CallResourceResponse) bool {
+	if resp.Status < http.StatusOK || resp.Status >= http.StatusMultipleChoices {
+		return false
+	}
+	for name := range resp.Headers {
+		if strings.EqualFold(name, "Set-Cookie") {
+			return false
+		}
+	}
+	return true
 }
 
 var _ CachingService = &OSSCachingService{}
@@ -0,0 +1,255 @@
+package caching
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestOSSCachingService_HandleQueryRequest(t *testing.T) {
+	now := time.Date(2023, 10, 10, 12, 0, 7, 0, time.UTC)
+	newRequest := func(jsonData string) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				OrgID: 1,
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					UID:      "prometheus",
+					JSONData: []byte(jsonData),
+				},
+			},
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				Interval:  15 * time.Second,
+				TimeRange: backend.TimeRange{From: now.Add(-time.Hour), To: now},
+				JSON:      []byte(`{"expr": "up"}`),
+			}},
+		}
+	}
+	response := &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": {Frames: data.Frames{data.NewFrame("up", data.NewField("value", nil, []float64{1}))}},
+	}}
+
+	t.Run("caches query results until they expire", func(t *testing.T) {
+		svc, cache := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, TTL: time.Minute})
+
+		ctx, resp := newRequestContext(t, nil)
+		hit, cr := svc.HandleQueryRequest(ctx, newRequest(`{}`))
+		require.False(t, hit)
+		require.NotNil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusMiss, resp.Header().Get(XCacheHeader))
+
+		cr.UpdateCacheFn(ctx, response)
+		require.Len(t, cache.Storage, 1)
+
+		ctx, resp = newRequestContext(t, nil)
+		hit, cr = svc.HandleQueryRequest(ctx, newRequest(`{}`))
+		require.True(t, hit)
+		assert.Equal(t, StatusHit, resp.Header().Get(XCacheHeader))
+		require.Len(t, cr.Response.Responses["A"].Frames, 1)
+		assert.Equal(t, "up", cr.Response.Responses["A"].Frames[0].Name)
+	})
+
+	t.Run("aligns the time range of the queries to their interval in the cache key", func(t *testing.T) {
+		svc, _ := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, TTL: time.Minute})
+
+		req := newRequest(`{}`)
+		ctx, _ := newRequestContext(t, nil)
+		_, cr := svc.HandleQueryRequest(ctx, req)
+		// the data source still gets the time range of the query
+		assert.Equal(t, backend.TimeRange{From: now.Add(-time.Hour), To: now}, req.Queries[0].TimeRange)
+		cr.UpdateCacheFn(ctx, response)
+
+		// a query within the same interval is a hit
+		later := newRequest(`{}`)
+		later.Queries[0].TimeRange = backend.TimeRange{From: now.Add(-time.Hour + 5*time.Second), To: now.Add(5 * time.Second)}
+		hit, _ := svc.HandleQueryRequest(ctx, later)
+		assert.True(t, hit)
+
+		// a query in the next interval is a miss
+		next := newRequest(`{}`)
+		next.Queries[0].TimeRange = backend.TimeRange{From: now.Add(-time.Hour + 10*time.Second), To: now.Add(10 * time.Second)}
+		hit, _ = svc.HandleQueryRequest(ctx, next)
+		assert.False(t, hit)
+	})
+
+	t.Run("uses different keys for different data sources and queries", func(t *testing.T) {
+		svc, _ := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, TTL: time.Minute})
+		ctx, _ := newRequestContext(t, nil)
+		_, cr := svc.HandleQueryRequest(ctx, newRequest(`{}`))
+		cr.UpdateCacheFn(ctx, response)
+
+		otherQuery := newRequest(`{}`)
+		otherQuery.Queries[0].JSON = []byte(`{"expr": "down"}`)
+		hit, _ := svc.HandleQueryRequest(ctx, otherQuery)
+		assert.False(t, hit)
+
+		otherDataSource := newRequest(`{}`)
+		otherDataSource.PluginContext.DataSourceInstanceSettings.UID = "other"
+		hit, _ = svc.HandleQueryRequest(ctx, otherDataSource)
+		assert.False(t, hit)
+
+		updatedDataSource := newRequest(`{}`)
+		updatedDataSource.PluginContext.DataSourceInstanceSettings.Updated = now
+		hit, _ = svc.HandleQueryRequest(ctx, updatedDataSource)
+		assert.False(t, hit)
+
+		otherUser := newRequest(`{"oauthPassThru": true}`)
+		otherUser.PluginContext.User = &backend.User{Login: "other"}
+		hit, _ = svc.HandleQueryRequest(ctx, otherUser)
+		assert.False(t, hit)
+	})
+
+	t.Run("doesn't cache failed queries", func(t *testing.T) {
+		svc, cache := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, TTL: time.Minute})
+		ctx, _ := newRequestContext(t, nil)
+		_, cr := svc.HandleQueryRequest(ctx, newRequest(`{}`))
+		cr.UpdateCacheFn(ctx, &backend.QueryDataResponse{Responses: backend.Responses{
+			"A": backend.ErrDataResponse(backend.StatusBadRequest, "bad query"),
+		}})
+		require.Empty(t, cache.Storage)
+	})
+
+	t.Run("doesn't cache results larger than the maximum size", func(t *testing.T) {
+		svc, cache := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, TTL: time.Minute, MaxValueSize: 10})
+		ctx, _ := newRequestContext(t, nil)
+		_, cr := svc.HandleQueryRequest(ctx, newRequest(`{}`))
+		cr.UpdateCacheFn(ctx, response)
+		require.Empty(t, cache.Storage)
+	})
+
+	t.Run("bypasses the cache with the skip header", func(t *testing.T) {
+		svc, _ := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, TTL: time.Minute})
+		ctx, _ := newRequestContext(t, nil)
+		_, cr := svc.HandleQueryRequest(ctx, newRequest(`{}`))
+		cr.UpdateCacheFn(ctx, response)
+
+		ctx, resp := newRequestContext(t, http.Header{XCacheSkipHeader: []string{"true"}})
+		hit, cr := svc.HandleQueryRequest(ctx, newRequest(`{}`))
+		assert.False(t, hit)
+		assert.NotNil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusBypass, resp.Header().Get(XCacheHeader))
+	})
+
+	t.Run("data sources can disable caching", func(t *testing.T) {
+		svc, _ := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, TTL: time.Minute})
+		ctx, resp := newRequestContext(t, nil)
+		hit, cr := svc.HandleQueryRequest(ctx, newRequest(`{"queryCachingTTLMs": -1}`))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusDisabled, resp.Header().Get(XCacheHeader))
+	})
+
+	t.Run("does nothing when caching is disabled", func(t *testing.T) {
+		svc, _ := setupCachingService(t, setting.QueryCachingSettings{Enabled: false, TTL: time.Minute})
+		ctx, resp := newRequestContext(t, nil)
+		hit, cr := svc.HandleQueryRequest(ctx, newRequest(`{}`))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Empty(t, resp.Header().Get(XCacheHeader))
+	})
+}
+
+func TestOSSCachingService_TTL(t *testing.T) {
+	svc, _ := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, TTL: time.Minute, MaxTTL: time.Hour})
+
+	testCases := []struct {
+		name     string
+		jsonData string
+		expected time.Duration
+		enabled  bool
+	}{
+		{name: "default TTL", jsonData: `{}`, expected: time.Minute, enabled: true},
+		{name: "TTL of the data source", jsonData: `{"queryCachingTTLMs": 30000}`, expected: 30 * time.Second, enabled: true},
+		{name: "TTL capped by the maximum TTL", jsonData: `{"queryCachingTTLMs": 86400000}`, expected: time.Hour, enabled: true},
+		{name: "negative TTL disables caching", jsonData: `{"queryCachingTTLMs": -1}`, enabled: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ttl, enabled := svc.ttl(&backend.DataSourceInstanceSettings{JSONData: []byte(tc.jsonData)}, queryTTLKey, time.Minute)
+			assert.Equal(t, tc.enabled, enabled)
+			assert.Equal(t, tc.expected, ttl)
+		})
+	}
+}
+
+func TestOSSCachingService_HandleResourceRequest(t *testing.T) {
+	newRequest := func(method string) *backend.CallResourceRequest {
+		return &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				OrgID:                      1,
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: "prometheus", JSONData: []byte(`{}`)},
+			},
+			Method: method,
+			URL:    "api/v1/labels",
+		}
+	}
+
+	t.Run("caches GET requests", func(t *testing.T) {
+		svc, cache := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, ResourceTTL: time.Minute})
+		ctx, _ := newRequestContext(t, nil)
+		hit, cr := svc.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		require.False(t, hit)
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`["job"]`)})
+		require.Len(t, cache.Storage, 1)
+
+		ctx, resp := newRequestContext(t, nil)
+		hit, cr = svc.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		require.True(t, hit)
+		assert.Equal(t, StatusHit, resp.Header().Get(XCacheHeader))
+		assert.Equal(t, []byte(`["job"]`), cr.Response.Body)
+	})
+
+	t.Run("doesn't cache other requests", func(t *testing.T) {
+		svc, _ := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, ResourceTTL: time.Minute})
+		ctx, resp := newRequestContext(t, nil)
+		hit, cr := svc.HandleResourceRequest(ctx, newRequest(http.MethodPost))
+		assert.False(t, hit)
+		assert.Nil(t, cr.UpdateCacheFn)
+		assert.Equal(t, StatusBypass, resp.Header().Get(XCacheHeader))
+	})
+
+	t.Run("doesn't cache failed and partial responses", func(t *testing.T) {
+		svc, cache := setupCachingService(t, setting.QueryCachingSettings{Enabled: true, ResourceTTL: time.Minute})
+		ctx, _ := newRequestContext(t, nil)
+		_, cr := svc.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusInternalServerError})
+		require.Empty(t, cache.Storage)
+
+		_, cr = svc.HandleResourceRequest(ctx, newRequest(http.MethodGet))
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Status: http.StatusOK, Body: []byte(`["jo`)})
+		cr.UpdateCacheFn(ctx, &backend.CallResourceResponse{Body: []byte(`b"]`)})
+		require.Empty(t, cache.Storage)
+	})
+}
+
+func setupCachingService(t *testing.T, settings setting.QueryCachingSettings) (*OSSCachingService, remotecache.FakeCacheStorage) {
+	t.Helper()
+	cfg := setting.NewCfg()
+	cfg.QueryCaching = settings
+	cache := remotecache.NewFakeCacheStorage()
+	return ProvideCachingService(cfg, cache), cache
+}
+
+func newRequestContext(t *testing.T, header http.Header) (context.Context, web.ResponseWriter) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/ds/query", nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp := web.NewResponseWriter(req.Method, httptest.NewRecorder())
+	reqCtx := &contextmodel.ReqContext{Context: &web.Context{Req: req, Resp: resp}}
+	return ctxkey.Set(context.Background(), reqCtx), resp
+}
@@ -17,9 +17,11 @@ import (
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
@@ -29,7 +31,8 @@ import (
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner,
+	publicDashboardService publicdashboards.Service, dashboardService dashboards.DashboardService) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -43,6 +46,8 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tempUserService:           tempUserService,
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
+		publicDashboardService:    publicDashboardService,
+		dashboardService:          dashboardService,
 	}
 	return s
 }
@@ -60,6 +65,8 @@ type CleanUpService struct {
 	deleteExpiredImageService *image.DeleteExpiredService
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
+	publicDashboardService    publicdashboards.Service
+	dashboardService          dashboards.DashboardService
 }
 
 type cleanUpJob struct {
@@ -102,6 +109,9 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"expire old user invites", srv.expireOldUserInvites},
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
 		{"delete stale query history", srv.deleteStaleQueryHistory},
+		{"disable expired public dashboards", srv.disableExpiredPublicDashboards},
+		{"delete stale public dashboard access logs", srv.deleteStalePublicDashboardAccessLogs},
+		{"delete expired dashboards from the trash", srv.deleteExpiredDashboardTrash},
 	}
 
 	logger := srv.log.FromContext(ctx)
@@ -279,3 +289,33 @@ func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
 		logger.Debug("Enforced row limit for query_history_star", "rows affected", rowsCount)
 	}
 }
+
+func (srv *CleanUpService) disableExpiredPublicDashboards(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	rowsCount, err := srv.publicDashboardService.DisableExpired(ctx)
+	if err != nil {
+		logger.Error("Problem disabling expired public dashboards", "error", err.Error())
+	} else {
+		logger.Debug("Disabled expired public dashboards", "rows affected", rowsCount)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredDashboardTrash(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	rowsCount, err := srv.dashboardService.DeleteExpiredTrash(ctx)
+	if err != nil {
+		logger.Error("Problem deleting expired dashboards from the trash", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired dashboards from the trash", "rows affected", rowsCount)
+	}
+}
+
+func (srv *CleanUpService) deleteStalePublicDashboardAccessLogs(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	rowsCount, err := srv.publicDashboardService.DeleteStaleAccessLogs(ctx)
+	if err != nil {
+		logger.Error("Problem deleting stale public dashboard access logs", "error", err.Error())
+	} else {
+		logger.Debug("Deleted stale public dashboard access logs", "rows affected", rowsCount)
+	}
+}
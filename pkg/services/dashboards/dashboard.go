@@ -2,6 +2,7 @@ package dashboards
 
 import (
 	"context"
+	"time"
 
 	alertmodels "github.com/grafana/grafana/pkg/services/alerting/models"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
@@ -27,6 +28,13 @@ type DashboardService interface {
 	UpdateDashboardACL(ctx context.Context, uid int64, items []*DashboardACL) error
 	DeleteACLByUser(ctx context.Context, userID int64) error
 	CountInFolder(ctx context.Context, orgID int64, folderUID string, user identity.Requester) (int64, error)
+	// TrashDashboard deletes a dashboard, keeping it in the trash so it can be restored.
+	TrashDashboard(ctx context.Context, dashboardID int64, orgID int64, user identity.Requester) error
+	FindTrash(ctx context.Context, query *FindDashboardTrashQuery) ([]*DashboardTrash, error)
+	RestoreTrash(ctx context.Context, cmd *RestoreDashboardTrashCommand) (*DashboardTrash, error)
+	DeleteTrash(ctx context.Context, cmd *DeleteDashboardTrashCommand) error
+	// DeleteExpiredTrash permanently deletes the dashboards and folders kept in the trash for longer than the trash retention.
+	DeleteExpiredTrash(ctx context.Context) (int64, error)
 }
 
 // PluginService is a service for operating on plugin dashboards.
@@ -46,6 +54,7 @@ type DashboardProvisioningService interface {
 	SaveFolderForProvisionedDashboards(context.Context, *SaveDashboardDTO) (*Dashboard, error)
 	SaveProvisionedDashboard(ctx context.Context, dto *SaveDashboardDTO, provisioning *DashboardProvisioning) (*Dashboard, error)
 	UnprovisionDashboard(ctx context.Context, dashboardID int64) error
+	UpdateProvisionedDashboardData(ctx context.Context, provisioning *DashboardProvisioning) error
 }
 
 // Store is a dashboard store.
@@ -71,6 +80,8 @@ type Store interface {
 	SaveProvisionedDashboard(ctx context.Context, cmd SaveDashboardCommand, provisioning *DashboardProvisioning) (*Dashboard, error)
 	UnprovisionDashboard(ctx context.Context, id int64) error
 	UpdateDashboardACL(ctx context.Context, uid int64, items []*DashboardACL) error
+	// UpdateProvisionedDashboardData updates the external id, checksum and update time of a provisioned dashboard.
+	UpdateProvisionedDashboardData(ctx context.Context, provisioning *DashboardProvisioning) error
 	// ValidateDashboardBeforeSave validates a dashboard before save.
 	ValidateDashboardBeforeSave(ctx context.Context, dashboard *Dashboard, overwrite bool) (bool, error)
 	DeleteACLByUser(context.Context, int64) error
@@ -80,4 +91,13 @@ type Store interface {
 	// the given parent folder ID.
 	CountDashboardsInFolder(ctx context.Context, request *CountDashboardsInFolderRequest) (int64, error)
 	DeleteDashboardsInFolder(ctx context.Context, request *DeleteDashboardsInFolderRequest) error
+
+	// SaveTrash keeps a copy of a dashboard or folder, and of everything that is deleted with it, in the trash.
+	// It returns nil when the trash is disabled.
+	SaveTrash(ctx context.Context, cmd *SaveDashboardTrashCommand) (*DashboardTrash, error)
+	FindTrash(ctx context.Context, query *FindDashboardTrashQuery) ([]*DashboardTrash, error)
+	GetTrash(ctx context.Context, query *GetDashboardTrashQuery) (*DashboardTrash, error)
+	RestoreTrash(ctx context.Context, cmd *RestoreDashboardTrashCommand) (*DashboardTrash, error)
+	DeleteTrash(ctx context.Context, cmd *DeleteDashboardTrashCommand) error
+	DeleteExpiredTrash(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
@@ -185,6 +185,20 @@ func (_m *FakeDashboardProvisioning) UnprovisionDashboard(ctx context.Context, d
 	return r0
 }
 
+// UpdateProvisionedDashboardData provides a mock function with given fields: ctx, provisioning
+func (_m *FakeDashboardProvisioning) UpdateProvisionedDashboardData(ctx context.Context, provisioning *DashboardProvisioning) error {
+	ret := _m.Called(ctx, provisioning)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DashboardProvisioning) error); ok {
+		r0 = rf(ctx, provisioning)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewFakeDashboardProvisioning interface {
 	mock.TestingT
 	Cleanup(func())
@@ -92,6 +92,44 @@ func (_m *FakeDashboardService) DeleteDashboard(ctx context.Context, dashboardId
 	return r0
 }
 
+// DeleteExpiredTrash provides a mock function with given fields: ctx
+func (_m *FakeDashboardService) DeleteExpiredTrash(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTrash provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) DeleteTrash(ctx context.Context, cmd *DeleteDashboardTrashCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteDashboardTrashCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindDashboards provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) FindDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error) {
 	ret := _m.Called(ctx, query)
@@ -118,6 +156,32 @@ func (_m *FakeDashboardService) FindDashboards(ctx context.Context, query *FindP
 	return r0, r1
 }
 
+// FindTrash provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) FindTrash(ctx context.Context, query *FindDashboardTrashQuery) ([]*DashboardTrash, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*DashboardTrash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *FindDashboardTrashQuery) ([]*DashboardTrash, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *FindDashboardTrashQuery) []*DashboardTrash); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DashboardTrash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *FindDashboardTrashQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDashboard provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) GetDashboard(ctx context.Context, query *GetDashboardQuery) (*Dashboard, error) {
 	ret := _m.Called(ctx, query)
@@ -274,6 +338,32 @@ func (_m *FakeDashboardService) ImportDashboard(ctx context.Context, dto *SaveDa
 	return r0, r1
 }
 
+// RestoreTrash provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) RestoreTrash(ctx context.Context, cmd *RestoreDashboardTrashCommand) (*DashboardTrash, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *DashboardTrash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDashboardTrashCommand) (*DashboardTrash, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDashboardTrashCommand) *DashboardTrash); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardTrash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreDashboardTrashCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveDashboard provides a mock function with given fields: ctx, dto, allowUiUpdate
 func (_m *FakeDashboardService) SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*Dashboard, error) {
 	ret := _m.Called(ctx, dto, allowUiUpdate)
@@ -326,6 +416,20 @@ func (_m *FakeDashboardService) SearchDashboards(ctx context.Context, query *Fin
 	return r0, r1
 }
 
+// TrashDashboard provides a mock function with given fields: ctx, dashboardID, orgID, user
+func (_m *FakeDashboardService) TrashDashboard(ctx context.Context, dashboardID int64, orgID int64, user identity.Requester) error {
+	ret := _m.Called(ctx, dashboardID, orgID, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, identity.Requester) error); ok {
+		r0 = rf(ctx, dashboardID, orgID, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateDashboardACL provides a mock function with given fields: ctx, uid, items
 func (_m *FakeDashboardService) UpdateDashboardACL(ctx context.Context, uid int64, items []*DashboardACL) error {
 	ret := _m.Called(ctx, uid, items)
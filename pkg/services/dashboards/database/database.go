@@ -229,6 +229,16 @@ func (d *dashboardStore) UnprovisionDashboard(ctx context.Context, id int64) err
 	})
 }
 
+// UpdateProvisionedDashboardData updates the provisioning metadata of a dashboard, after its
+// definition is changed in the provisioning source.
+func (d *dashboardStore) UpdateProvisionedDashboardData(ctx context.Context, provisioning *dashboards.DashboardProvisioning) error {
+	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("dashboard_id = ? AND name = ?", provisioning.DashboardID, provisioning.Name).
+			Cols("external_id", "check_sum", "updated").Update(provisioning)
+		return err
+	})
+}
+
 func (d *dashboardStore) DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *dashboards.DeleteOrphanedProvisionedDashboardsCommand) error {
 	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
 		var result []*dashboards.DashboardProvisioning
@@ -685,9 +695,42 @@ func (d *dashboardStore) GetDashboardsByPluginID(ctx context.Context, query *das
 }
 
 func (d *dashboardStore) DeleteDashboard(ctx context.Context, cmd *dashboards.DeleteDashboardCommand) error {
-	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+	var trash *dashboards.DashboardTrash
+	if cmd.MoveToTrash {
+		trash = d.moveToTrash(ctx, cmd)
+	}
+
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		return d.deleteDashboard(cmd, sess, d.emitEntityEvent())
 	})
+	if err != nil && trash != nil {
+		if deleteErr := d.DeleteTrash(ctx, &dashboards.DeleteDashboardTrashCommand{OrgID: trash.OrgID, UID: trash.UID}); deleteErr != nil {
+			d.log.Warn("Failed to remove the trash copy of a dashboard that wasn't deleted", "uid", trash.DashboardUID, "error", deleteErr)
+		}
+	}
+	return err
+}
+
+// moveToTrash keeps a copy of the dashboard in the trash before it's deleted.
+// The copy is saved in its own transaction so that failing to make it doesn't prevent the delete.
+func (d *dashboardStore) moveToTrash(ctx context.Context, cmd *dashboards.DeleteDashboardCommand) *dashboards.DashboardTrash {
+	dashboard := dashboards.Dashboard{ID: cmd.ID, OrgID: cmd.OrgID}
+	var has bool
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		has, err = sess.Get(&dashboard)
+		return err
+	})
+	if err != nil || !has {
+		return nil
+	}
+
+	trash, err := d.SaveTrash(ctx, &dashboards.SaveDashboardTrashCommand{OrgID: dashboard.OrgID, UID: dashboard.UID, DeletedBy: cmd.DeletedBy})
+	if err != nil {
+		d.log.Warn("Failed to keep a copy of the dashboard in the trash, deleting it anyway", "uid", dashboard.UID, "error", err)
+		return nil
+	}
+	return trash
 }
 
 func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand, sess *db.Session, emitEntityEvent bool) error {
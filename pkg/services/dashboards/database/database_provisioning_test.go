@@ -114,6 +114,22 @@ func TestIntegrationDashboardProvisioningTest(t *testing.T) {
 			require.NotNil(t, data)
 		})
 
+		t.Run("Can update provisioning meta data", func(t *testing.T) {
+			err := dashboardStore.UpdateProvisionedDashboardData(context.Background(), &dashboards.DashboardProvisioning{
+				DashboardID: dashId,
+				Name:        "default",
+				ExternalID:  "/var/grafana.json",
+				CheckSum:    "checksum",
+				Updated:     now.Unix() + 1,
+			})
+			require.Nil(t, err)
+
+			data, err := dashboardStore.GetProvisionedDataByDashboardID(context.Background(), dashId)
+			require.Nil(t, err)
+			require.Equal(t, "checksum", data.CheckSum)
+			require.Equal(t, now.Unix()+1, data.Updated)
+		})
+
 		t.Run("Can query for none provisioned dashboard", func(t *testing.T) {
 			data, err := dashboardStore.GetProvisionedDataByDashboardID(context.Background(), 3000)
 			require.Nil(t, err)
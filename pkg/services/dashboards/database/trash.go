@@ -0,0 +1,506 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// trashSnapshot is the copy of the rows deleted along with a dashboard or folder,
+// which are inserted back with their original IDs when it's restored. Each row is
+// stored as a dashboard_trash_item.
+type trashSnapshot struct {
+	Dashboards                []*dashboards.Dashboard
+	Folders                   []*folderRow
+	Versions                  []*dashver.DashboardVersion
+	ACL                       []*dashboards.DashboardACL
+	Tags                      []*dashboardTag
+	Permissions               []*permissionRow
+	LibraryElements           []*model.LibraryElement
+	LibraryElementConnections []*model.LibraryElementConnection
+	AlertRules                []*ngmodels.AlertRule
+	AlertRuleVersions         []*ngmodels.AlertRuleVersion
+}
+
+// rows returns the rows of the snapshot by the kind of their trash items.
+func (s *trashSnapshot) rows() map[string]any {
+	return map[string]any{
+		"dashboard":                  &s.Dashboards,
+		"folder":                     &s.Folders,
+		"dashboard_version":          &s.Versions,
+		"dashboard_acl":              &s.ACL,
+		"dashboard_tag":              &s.Tags,
+		"permission":                 &s.Permissions,
+		"library_element":            &s.LibraryElements,
+		"library_element_connection": &s.LibraryElementConnections,
+		"alert_rule":                 &s.AlertRules,
+		"alert_rule_version":         &s.AlertRuleVersions,
+	}
+}
+
+// trashItem is a row deleted along with a dashboard or folder in the trash.
+type trashItem struct {
+	ID      int64  `xorm:"pk autoincr 'id'"`
+	TrashID int64  `xorm:"trash_id"`
+	Kind    string `xorm:"kind"`
+	Data    string `xorm:"data"`
+}
+
+func (trashItem) TableName() string { return "dashboard_trash_item" }
+
+// folderRow is a row of the folder table of nested folders
+type folderRow struct {
+	ID          int64   `xorm:"pk autoincr 'id'"`
+	OrgID       int64   `xorm:"org_id"`
+	UID         string  `xorm:"uid"`
+	ParentUID   *string `xorm:"parent_uid"`
+	Title       string
+	Description *string
+	Created     time.Time
+	Updated     time.Time
+}
+
+func (folderRow) TableName() string { return "folder" }
+
+// permissionRow is a row of the permission table. ac.Permission can't be used
+// as it doesn't serialize the role of the permission.
+type permissionRow struct {
+	ID         int64  `xorm:"pk autoincr 'id'"`
+	RoleID     int64  `xorm:"role_id"`
+	Action     string `xorm:"action"`
+	Scope      string `xorm:"scope"`
+	Kind       string `xorm:"kind"`
+	Attribute  string `xorm:"attribute"`
+	Identifier string `xorm:"identifier"`
+	Updated    time.Time
+	Created    time.Time
+}
+
+func (permissionRow) TableName() string { return "permission" }
+
+func (d *dashboardStore) SaveTrash(ctx context.Context, cmd *dashboards.SaveDashboardTrashCommand) (*dashboards.DashboardTrash, error) {
+	var trash *dashboards.DashboardTrash
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		trash, err = d.saveTrash(sess, cmd)
+		return err
+	})
+	return trash, err
+}
+
+// saveTrash keeps a copy of the dashboard or folder and of everything that is deleted with it in the trash.
+// It does nothing when the trash is disabled.
+func (d *dashboardStore) saveTrash(sess *db.Session, cmd *dashboards.SaveDashboardTrashCommand) (*dashboards.DashboardTrash, error) {
+	if d.cfg == nil || d.cfg.DashboardTrashRetention <= 0 {
+		return nil, nil
+	}
+
+	uids := append([]string{cmd.UID}, cmd.SubfolderUIDs...)
+	snapshot := trashSnapshot{}
+	if err := sess.Where("org_id = ?", cmd.OrgID).In("uid", uids).Find(&snapshot.Dashboards); err != nil {
+		return nil, err
+	}
+
+	var deleted *dashboards.Dashboard
+	folderIDs := make([]int64, 0, len(uids))
+	folderUIDs := make([]string, 0, len(uids))
+	for _, dash := range snapshot.Dashboards {
+		if dash.UID == cmd.UID {
+			deleted = dash
+		}
+		if dash.IsFolder {
+			folderIDs = append(folderIDs, dash.ID)
+			folderUIDs = append(folderUIDs, dash.UID)
+		}
+	}
+	if deleted == nil {
+		return nil, dashboards.ErrDashboardNotFound
+	}
+
+	if len(folderIDs) > 0 {
+		var children []*dashboards.Dashboard
+		if err := sess.Where("org_id = ? AND is_folder = ?", cmd.OrgID, false).In("folder_id", folderIDs).Find(&children); err != nil {
+			return nil, err
+		}
+		snapshot.Dashboards = append(snapshot.Dashboards, children...)
+
+		if err := sess.Where("org_id = ?", cmd.OrgID).In("uid", folderUIDs).Find(&snapshot.Folders); err != nil {
+			return nil, err
+		}
+		if err := sess.Where("org_id = ?", cmd.OrgID).In("folder_id", folderIDs).Find(&snapshot.LibraryElements); err != nil {
+			return nil, err
+		}
+		if err := sess.Where("org_id = ?", cmd.OrgID).In("namespace_uid", folderUIDs).Find(&snapshot.AlertRules); err != nil {
+			return nil, err
+		}
+		if err := sess.Where("rule_org_id = ?", cmd.OrgID).In("rule_namespace_uid", folderUIDs).Find(&snapshot.AlertRuleVersions); err != nil {
+			return nil, err
+		}
+	}
+
+	dashboardIDs := make([]int64, 0, len(snapshot.Dashboards))
+	scopes := make([]string, 0, len(snapshot.Dashboards))
+	for _, dash := range snapshot.Dashboards {
+		dashboardIDs = append(dashboardIDs, dash.ID)
+		if dash.IsFolder {
+			scopes = append(scopes, dashboards.ScopeFoldersProvider.GetResourceScopeUID(dash.UID))
+		} else {
+			scopes = append(scopes, ac.GetResourceScopeUID("dashboards", dash.UID))
+		}
+	}
+
+	if err := sess.In("dashboard_id", dashboardIDs).Find(&snapshot.Versions); err != nil {
+		return nil, err
+	}
+	if err := sess.In("dashboard_id", dashboardIDs).Find(&snapshot.ACL); err != nil {
+		return nil, err
+	}
+	if err := sess.In("dashboard_id", dashboardIDs).Find(&snapshot.Tags); err != nil {
+		return nil, err
+	}
+	if err := sess.Where("kind = ?", model.Dashboard).In("connection_id", dashboardIDs).Find(&snapshot.LibraryElementConnections); err != nil {
+		return nil, err
+	}
+	if err := sess.Table("permission").Select("permission.*").Join("INNER", "role", "permission.role_id = role.id").
+		Where("role.org_id = ?", cmd.OrgID).In("permission.scope", scopes).Find(&snapshot.Permissions); err != nil {
+		return nil, err
+	}
+
+	trash := &dashboards.DashboardTrash{
+		UID:          util.GenerateShortUID(),
+		OrgID:        cmd.OrgID,
+		DashboardUID: deleted.UID,
+		Title:        deleted.Title,
+		IsFolder:     deleted.IsFolder,
+		FolderUID:    deleted.FolderUID,
+		DeletedBy:    cmd.DeletedBy,
+		Deleted:      time.Now(),
+	}
+	if _, err := sess.Insert(trash); err != nil {
+		return nil, err
+	}
+	if err := insertTrashItems(sess, trash.ID, &snapshot); err != nil {
+		return nil, err
+	}
+	return trash, nil
+}
+
+// insertTrashItems inserts each row of the snapshot as an item of the trash.
+func insertTrashItems(sess *db.Session, trashID int64, snapshot *trashSnapshot) error {
+	for kind, rows := range snapshot.rows() {
+		data, err := json.Marshal(rows)
+		if err != nil {
+			return err
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		for _, item := range items {
+			if _, err := sess.Insert(&trashItem{TrashID: trashID, Kind: kind, Data: string(item)}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getTrashSnapshot returns the rows kept in the trash with a dashboard or folder.
+func getTrashSnapshot(sess *db.Session, trashID int64) (*trashSnapshot, error) {
+	var items []*trashItem
+	if err := sess.Where("trash_id = ?", trashID).Asc("id").Find(&items); err != nil {
+		return nil, err
+	}
+	byKind := make(map[string][]json.RawMessage)
+	for _, item := range items {
+		byKind[item.Kind] = append(byKind[item.Kind], json.RawMessage(item.Data))
+	}
+
+	snapshot := &trashSnapshot{}
+	for kind, rows := range snapshot.rows() {
+		data, err := json.Marshal(byKind[kind])
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, rows); err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// deleteTrash deletes the trash entries matching the condition along with their items.
+func deleteTrash(sess *db.Session, condition string, args ...any) (int64, error) {
+	var ids []int64
+	if err := sess.Table("dashboard_trash").Where(condition, args...).Cols("id").Find(&ids); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if _, err := sess.In("trash_id", ids).Delete(&trashItem{}); err != nil {
+		return 0, err
+	}
+	return sess.In("id", ids).Delete(&dashboards.DashboardTrash{})
+}
+
+func (d *dashboardStore) FindTrash(ctx context.Context, query *dashboards.FindDashboardTrashQuery) ([]*dashboards.DashboardTrash, error) {
+	result := make([]*dashboards.DashboardTrash, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", query.OrgID).Desc("deleted").Find(&result)
+	})
+	return result, err
+}
+
+func (d *dashboardStore) GetTrash(ctx context.Context, query *dashboards.GetDashboardTrashQuery) (*dashboards.DashboardTrash, error) {
+	var result *dashboards.DashboardTrash
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		result, err = getTrash(sess, query.OrgID, query.UID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func getTrash(sess *db.Session, orgID int64, uid string) (*dashboards.DashboardTrash, error) {
+	trash := dashboards.DashboardTrash{}
+	has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&trash)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, dashboards.ErrDashboardTrashNotFound
+	}
+	return &trash, nil
+}
+
+// RestoreTrash inserts the dashboard or folder back along with everything that was deleted with it,
+// and removes it from the trash. Dashboards and folders whose parent folder no longer exists are
+// restored to the root folder.
+func (d *dashboardStore) RestoreTrash(ctx context.Context, cmd *dashboards.RestoreDashboardTrashCommand) (*dashboards.DashboardTrash, error) {
+	var trash *dashboards.DashboardTrash
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		trash, err = getTrash(sess, cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+
+		snapshot, err := getTrashSnapshot(sess, trash.ID)
+		if err != nil {
+			return err
+		}
+		if err := d.restoreTrash(sess, cmd.OrgID, snapshot); err != nil {
+			return err
+		}
+
+		_, err = deleteTrash(sess, "id = ?", trash.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return trash, nil
+}
+
+func (d *dashboardStore) restoreTrash(sess *db.Session, orgID int64, snapshot *trashSnapshot) error {
+	restoredIDs := make(map[int64]bool, len(snapshot.Dashboards))
+	restoredUIDs := make(map[string]bool, len(snapshot.Dashboards))
+	for _, dash := range snapshot.Dashboards {
+		restoredIDs[dash.ID] = true
+		restoredUIDs[dash.UID] = true
+	}
+
+	for _, dash := range snapshot.Dashboards {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, dash.UID).Exist(&dashboards.Dashboard{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return dashboards.ErrDashboardTrashConflict
+		}
+
+		if (dash.FolderID != 0 && !restoredIDs[dash.FolderID]) || (dash.FolderUID != "" && !restoredUIDs[dash.FolderUID]) {
+			parentExists, err := folderExists(sess, orgID, dash.FolderID, dash.FolderUID)
+			if err != nil {
+				return err
+			}
+			if !parentExists {
+				dash.FolderID = 0
+				dash.FolderUID = ""
+			}
+		}
+
+		exists, err = sess.Where("org_id = ? AND folder_id = ? AND title = ?", orgID, dash.FolderID, dash.Title).Exist(&dashboards.Dashboard{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return dashboards.ErrDashboardTrashConflict
+		}
+	}
+
+	for _, dash := range snapshot.Dashboards {
+		if _, err := sess.Insert(dash); err != nil {
+			return err
+		}
+		if d.emitEntityEvent() {
+			if _, err := sess.Insert(createEntityEvent(dash, store.EntityEventTypeCreate)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, f := range snapshot.Folders {
+		if f.ParentUID != nil && !restoredUIDs[*f.ParentUID] {
+			exists, err := sess.Where("org_id = ? AND uid = ?", orgID, *f.ParentUID).Exist(&folderRow{})
+			if err != nil {
+				return err
+			}
+			if !exists {
+				f.ParentUID = nil
+			}
+		}
+		if _, err := sess.Insert(f); err != nil {
+			return err
+		}
+	}
+
+	if err := insertRows(sess, snapshot.Versions); err != nil {
+		return err
+	}
+	if err := insertRows(sess, snapshot.ACL); err != nil {
+		return err
+	}
+	if err := insertRows(sess, snapshot.Tags); err != nil {
+		return err
+	}
+
+	// the permissions of roles that have been deleted in the meantime are not restored
+	for _, p := range snapshot.Permissions {
+		exists, err := sess.Table("role").Where("id = ?", p.RoleID).Exist()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if _, err := sess.Insert(p); err != nil {
+			return err
+		}
+	}
+
+	// library panels whose uid has been taken in the meantime are not restored
+	for _, element := range snapshot.LibraryElements {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, element.UID).Exist(&model.LibraryElement{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := sess.Insert(element); err != nil {
+			return err
+		}
+	}
+
+	for _, connection := range snapshot.LibraryElementConnections {
+		elementExists, err := sess.Where("id = ?", connection.ElementID).Exist(&model.LibraryElement{})
+		if err != nil {
+			return err
+		}
+		connectionExists, err := sess.Where("element_id = ? AND kind = ? AND connection_id = ?", connection.ElementID, connection.Kind, connection.ConnectionID).
+			Exist(&model.LibraryElementConnection{})
+		if err != nil {
+			return err
+		}
+		if !elementExists || connectionExists {
+			continue
+		}
+		if _, err := sess.Insert(connection); err != nil {
+			return err
+		}
+	}
+
+	// alert rules whose uid has been taken in the meantime are not restored
+	restoredRules := make(map[string]bool, len(snapshot.AlertRules))
+	for _, rule := range snapshot.AlertRules {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, rule.UID).Exist(&ngmodels.AlertRule{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := sess.Insert(rule); err != nil {
+			return err
+		}
+		// the version column is used for optimistic locking, so xorm always inserts 1
+		if _, err := sess.Exec("UPDATE alert_rule SET version = ? WHERE id = ?", rule.Version, rule.ID); err != nil {
+			return err
+		}
+		restoredRules[rule.UID] = true
+	}
+	for _, version := range snapshot.AlertRuleVersions {
+		if !restoredRules[version.RuleUID] {
+			continue
+		}
+		if _, err := sess.Insert(version); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func folderExists(sess *db.Session, orgID int64, folderID int64, folderUID string) (bool, error) {
+	if folderID != 0 {
+		return sess.Where("org_id = ? AND id = ? AND is_folder = ?", orgID, folderID, true).Exist(&dashboards.Dashboard{})
+	}
+	return sess.Where("org_id = ? AND uid = ? AND is_folder = ?", orgID, folderUID, true).Exist(&dashboards.Dashboard{})
+}
+
+func insertRows[T any](sess *db.Session, rows []T) error {
+	for _, row := range rows {
+		if _, err := sess.Insert(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *dashboardStore) DeleteTrash(ctx context.Context, cmd *dashboards.DeleteDashboardTrashCommand) error {
+	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := deleteTrash(sess, "org_id = ? AND uid = ?", cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return dashboards.ErrDashboardTrashNotFound
+		}
+		return nil
+	})
+}
+
+// DeleteExpiredTrash deletes the dashboards and folders that were moved to the trash before the given time.
+func (d *dashboardStore) DeleteExpiredTrash(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var affected int64
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affected, err = deleteTrash(sess, "deleted < ?", deletedBefore.UTC().Format("2006-01-02 15:04:05"))
+		return err
+	})
+	return affected, err
+}
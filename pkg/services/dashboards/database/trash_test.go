@@ -0,0 +1,245 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationDashboardTrash(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var sqlStore *sqlstore.SQLStore
+	var cfg *setting.Cfg
+	var savedFolder, savedDash, savedDash2 *dashboards.Dashboard
+	var dashboardStore dashboards.Store
+	var role *ac.Role
+
+	setup := func() {
+		sqlStore, cfg = db.InitTestDBwithCfg(t)
+		cfg.DashboardTrashRetention = 24 * time.Hour
+		var err error
+		dashboardStore, err = ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+		require.NoError(t, err)
+		savedFolder = insertTestDashboard(t, dashboardStore, "trash folder", 1, 0, "", true)
+		savedDash = insertTestDashboard(t, dashboardStore, "trash dash", 1, savedFolder.ID, savedFolder.UID, false, "prod")
+		savedDash2 = insertTestDashboard(t, dashboardStore, "trash dash 2", 1, 0, "", false, "prod")
+		insertTestRule(t, sqlStore, savedFolder.OrgID, savedFolder.UID)
+		require.NoError(t, updateDashboardACL(t, dashboardStore, savedDash.ID, dashboards.DashboardACL{
+			OrgID: 1, DashboardID: savedDash.ID, UserID: 1, Permission: dashboards.PERMISSION_EDIT,
+		}))
+
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			role = &ac.Role{OrgID: 1, UID: "managed_1_users_1", Name: "managed:users:1:permissions", Created: time.Now(), Updated: time.Now()}
+			if _, err := sess.Insert(role); err != nil {
+				return err
+			}
+			if _, err := sess.Insert(&permissionRow{
+				RoleID: role.ID, Action: dashboards.ActionDashboardsRead, Scope: ac.GetResourceScopeUID("dashboards", savedDash.UID),
+				Created: time.Now(), Updated: time.Now(),
+			}); err != nil {
+				return err
+			}
+			element := &model.LibraryElement{
+				OrgID: 1, FolderID: savedFolder.ID, UID: "trash-panel", Name: "panel", Kind: int64(model.PanelElement),
+				Type: "text", Model: json.RawMessage(`{}`), Version: 1, Created: time.Now(), Updated: time.Now(),
+			}
+			if _, err := sess.Insert(element); err != nil {
+				return err
+			}
+			_, err := sess.Insert(&model.LibraryElementConnection{ElementID: element.ID, Kind: int64(model.Dashboard), ConnectionID: savedDash.ID, Created: time.Now()})
+			return err
+		})
+		require.NoError(t, err)
+	}
+
+	count := func(t *testing.T, table string, where string, args ...any) int64 {
+		t.Helper()
+		var n int64
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			var err error
+			n, err = sess.Table(table).Where(where, args...).Count()
+			return err
+		})
+		require.NoError(t, err)
+		return n
+	}
+
+	trashFolder := func(t *testing.T) *dashboards.DashboardTrash {
+		t.Helper()
+		_, err := dashboardStore.SaveTrash(context.Background(), &dashboards.SaveDashboardTrashCommand{OrgID: 1, UID: savedFolder.UID, DeletedBy: 1})
+		require.NoError(t, err)
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			// library panels are deleted by the library panel service when a folder is deleted
+			if _, err := sess.Exec("DELETE FROM library_element_connection WHERE connection_id = ?", savedDash.ID); err != nil {
+				return err
+			}
+			_, err := sess.Exec("DELETE FROM library_element WHERE folder_id = ?", savedFolder.ID)
+			return err
+		})
+		require.NoError(t, err)
+		err = dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{OrgID: 1, ID: savedFolder.ID, ForceDeleteFolderRules: true})
+		require.NoError(t, err)
+
+		trash, err := dashboardStore.FindTrash(context.Background(), &dashboards.FindDashboardTrashQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		return trash[0]
+	}
+
+	t.Run("Should move a dashboard to the trash when deleting it", func(t *testing.T) {
+		setup()
+		err := dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{OrgID: 1, ID: savedDash2.ID, MoveToTrash: true, DeletedBy: 2})
+		require.NoError(t, err)
+
+		_, err = dashboardStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{OrgID: 1, UID: savedDash2.UID})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+
+		trash, err := dashboardStore.FindTrash(context.Background(), &dashboards.FindDashboardTrashQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.Equal(t, savedDash2.UID, trash[0].DashboardUID)
+		assert.Equal(t, savedDash2.Title, trash[0].Title)
+		assert.False(t, trash[0].IsFolder)
+		assert.EqualValues(t, 2, trash[0].DeletedBy)
+		assert.EqualValues(t, 1, count(t, "dashboard_trash_item", "trash_id = ? AND kind = ?", trash[0].ID, "dashboard"))
+		assert.EqualValues(t, 1, count(t, "dashboard_trash_item", "trash_id = ? AND kind = ?", trash[0].ID, "dashboard_tag"))
+
+		trash, err = dashboardStore.FindTrash(context.Background(), &dashboards.FindDashboardTrashQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Empty(t, trash)
+	})
+
+	t.Run("Should not move a dashboard to the trash when the trash is disabled", func(t *testing.T) {
+		setup()
+		cfg.DashboardTrashRetention = 0
+		err := dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{OrgID: 1, ID: savedDash2.ID, MoveToTrash: true})
+		require.NoError(t, err)
+
+		trash, err := dashboardStore.FindTrash(context.Background(), &dashboards.FindDashboardTrashQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, trash)
+	})
+
+	t.Run("Should restore a folder with its dashboards, versions, permissions, library panels and alert rules", func(t *testing.T) {
+		setup()
+		trash := trashFolder(t)
+		assert.True(t, trash.IsFolder)
+		assert.EqualValues(t, 2, count(t, "dashboard_trash_item", "trash_id = ? AND kind = ?", trash.ID, "dashboard"))
+		assert.EqualValues(t, 1, count(t, "dashboard_trash_item", "trash_id = ? AND kind = ?", trash.ID, "alert_rule"))
+		require.EqualValues(t, 1, count(t, "dashboard", "org_id = ?", 1))
+		require.EqualValues(t, 0, count(t, "alert_rule", "namespace_uid = ?", savedFolder.UID))
+
+		restored, err := dashboardStore.RestoreTrash(context.Background(), &dashboards.RestoreDashboardTrashCommand{OrgID: 1, UID: trash.UID})
+		require.NoError(t, err)
+		assert.Equal(t, savedFolder.UID, restored.DashboardUID)
+
+		dash, err := dashboardStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{OrgID: 1, UID: savedDash.UID})
+		require.NoError(t, err)
+		assert.Equal(t, savedDash.ID, dash.ID)
+		assert.Equal(t, savedFolder.ID, dash.FolderID)
+
+		assert.EqualValues(t, 1, count(t, "dashboard_version", "dashboard_id = ?", savedDash.ID))
+		assert.EqualValues(t, 1, count(t, "dashboard_tag", "dashboard_id = ? AND term = ?", savedDash.ID, "prod"))
+		assert.EqualValues(t, 1, count(t, "dashboard_acl", "dashboard_id = ?", savedDash.ID))
+		assert.EqualValues(t, 1, count(t, "permission", "role_id = ? AND scope = ?", role.ID, ac.GetResourceScopeUID("dashboards", savedDash.UID)))
+		assert.EqualValues(t, 1, count(t, "library_element", "uid = ?", "trash-panel"))
+		assert.EqualValues(t, 1, count(t, "library_element_connection", "connection_id = ?", savedDash.ID))
+		assert.EqualValues(t, 1, count(t, "alert_rule", "namespace_uid = ?", savedFolder.UID))
+		assert.EqualValues(t, 1, count(t, "alert_rule_version", "rule_namespace_uid = ?", savedFolder.UID))
+
+		versions := []*dashver.DashboardVersion{}
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			return sess.Where("dashboard_id = ?", savedDash.ID).Find(&versions)
+		})
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+		assert.Equal(t, savedDash.Title, versions[0].Data.Get("title").MustString())
+
+		_, err = dashboardStore.GetTrash(context.Background(), &dashboards.GetDashboardTrashQuery{OrgID: 1, UID: trash.UID})
+		require.ErrorIs(t, err, dashboards.ErrDashboardTrashNotFound)
+		assert.EqualValues(t, 0, count(t, "dashboard_trash_item", "trash_id = ?", trash.ID))
+	})
+
+	t.Run("Should restore a dashboard to the root folder when its folder no longer exists", func(t *testing.T) {
+		setup()
+		err := dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{OrgID: 1, ID: savedDash.ID, MoveToTrash: true})
+		require.NoError(t, err)
+		err = dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{OrgID: 1, ID: savedFolder.ID, ForceDeleteFolderRules: true})
+		require.NoError(t, err)
+
+		trash, err := dashboardStore.FindTrash(context.Background(), &dashboards.FindDashboardTrashQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		_, err = dashboardStore.RestoreTrash(context.Background(), &dashboards.RestoreDashboardTrashCommand{OrgID: 1, UID: trash[0].UID})
+		require.NoError(t, err)
+
+		dash, err := dashboardStore.GetDashboard(context.Background(), &dashboards.GetDashboardQuery{OrgID: 1, UID: savedDash.UID})
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, dash.FolderID)
+		assert.Empty(t, dash.FolderUID)
+	})
+
+	t.Run("Should not restore a dashboard when one with the same uid exists", func(t *testing.T) {
+		setup()
+		err := dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{OrgID: 1, ID: savedDash2.ID, MoveToTrash: true})
+		require.NoError(t, err)
+		_, err = dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+			OrgID:     1,
+			Dashboard: simplejson.NewFromAny(map[string]any{"title": "new dash", "uid": savedDash2.UID}),
+		})
+		require.NoError(t, err)
+
+		trash, err := dashboardStore.FindTrash(context.Background(), &dashboards.FindDashboardTrashQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		_, err = dashboardStore.RestoreTrash(context.Background(), &dashboards.RestoreDashboardTrashCommand{OrgID: 1, UID: trash[0].UID})
+		require.ErrorIs(t, err, dashboards.ErrDashboardTrashConflict)
+
+		_, err = dashboardStore.GetTrash(context.Background(), &dashboards.GetDashboardTrashQuery{OrgID: 1, UID: trash[0].UID})
+		require.NoError(t, err)
+	})
+
+	t.Run("Should delete dashboards from the trash", func(t *testing.T) {
+		setup()
+		trash := trashFolder(t)
+
+		err := dashboardStore.DeleteTrash(context.Background(), &dashboards.DeleteDashboardTrashCommand{OrgID: 2, UID: trash.UID})
+		require.ErrorIs(t, err, dashboards.ErrDashboardTrashNotFound)
+		err = dashboardStore.DeleteTrash(context.Background(), &dashboards.DeleteDashboardTrashCommand{OrgID: 1, UID: trash.UID})
+		require.NoError(t, err)
+		_, err = dashboardStore.RestoreTrash(context.Background(), &dashboards.RestoreDashboardTrashCommand{OrgID: 1, UID: trash.UID})
+		require.ErrorIs(t, err, dashboards.ErrDashboardTrashNotFound)
+		assert.EqualValues(t, 0, count(t, "dashboard_trash_item", "trash_id = ?", trash.ID))
+	})
+
+	t.Run("Should delete expired dashboards from the trash", func(t *testing.T) {
+		setup()
+		trash := trashFolder(t)
+
+		affected, err := dashboardStore.DeleteExpiredTrash(context.Background(), time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, affected)
+
+		affected, err = dashboardStore.DeleteExpiredTrash(context.Background(), time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, affected)
+		assert.EqualValues(t, 0, count(t, "dashboard_trash_item", "trash_id = ?", trash.ID))
+	})
+}
@@ -116,6 +116,16 @@ var (
 		StatusCode: 404,
 		Status:     "not-found",
 	}
+	ErrDashboardTrashNotFound = DashboardErr{
+		Reason:     "Deleted dashboard or folder not found in the trash",
+		StatusCode: 404,
+		Status:     "not-found",
+	}
+	ErrDashboardTrashConflict = DashboardErr{
+		Reason:     "A dashboard or folder with the same uid or name as the deleted one already exists",
+		StatusCode: 409,
+		Status:     "conflict",
+	}
 
 	ErrFolderNotFound           = errors.New("folder not found")
 	ErrFolderVersionMismatch    = errors.New("the folder has been changed by someone else")
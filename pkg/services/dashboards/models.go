@@ -278,6 +278,45 @@ type DeleteDashboardCommand struct {
 	ID                     int64
 	OrgID                  int64
 	ForceDeleteFolderRules bool
+	// MoveToTrash keeps a copy of the dashboard in the trash of the organization,
+	// from where it can be restored until the trash retention expires.
+	MoveToTrash bool
+	DeletedBy   int64
+}
+
+// DashboardTrash is a dashboard or folder that has been deleted. Deleted folders
+// are kept in the trash along with their subfolders and everything they contain.
+type DashboardTrash struct {
+	ID           int64     `json:"id" xorm:"pk autoincr 'id'"`
+	UID          string    `json:"uid" xorm:"uid"`
+	OrgID        int64     `json:"orgId" xorm:"org_id"`
+	DashboardUID string    `json:"dashboardUid" xorm:"dashboard_uid"`
+	Title        string    `json:"title"`
+	IsFolder     bool      `json:"isFolder"`
+	FolderUID    string    `json:"folderUid" xorm:"folder_uid"`
+	DeletedBy    int64     `json:"deletedBy"`
+	Deleted      time.Time `json:"deleted"`
+}
+
+func (DashboardTrash) TableName() string { return "dashboard_trash" }
+
+// SaveDashboardTrashCommand keeps a copy of a dashboard or folder in the trash.
+// The UIDs of the subfolders of a folder are in SubfolderUIDs.
+type SaveDashboardTrashCommand struct {
+	OrgID         int64
+	UID           string
+	SubfolderUIDs []string
+	DeletedBy     int64
+}
+
+type RestoreDashboardTrashCommand struct {
+	OrgID int64
+	UID   string
+}
+
+type DeleteDashboardTrashCommand struct {
+	OrgID int64
+	UID   string
 }
 
 type DeleteOrphanedProvisionedDashboardsCommand struct {
@@ -318,6 +357,15 @@ type GetDashboardTagsQuery struct {
 	OrgID int64
 }
 
+type GetDashboardTrashQuery struct {
+	OrgID int64
+	UID   string
+}
+
+type FindDashboardTrashQuery struct {
+	OrgID int64
+}
+
 type GetDashboardsQuery struct {
 	DashboardIDs  []int64
 	DashboardUIDs []string
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
@@ -411,7 +412,33 @@ func (dr *DashboardServiceImpl) SaveDashboard(ctx context.Context, dto *dashboar
 // DeleteDashboard removes dashboard from the DB. Errors out if the dashboard was provisioned. Should be used for
 // operations by the user where we want to make sure user does not delete provisioned dashboard.
 func (dr *DashboardServiceImpl) DeleteDashboard(ctx context.Context, dashboardId int64, orgId int64) error {
-	return dr.deleteDashboard(ctx, dashboardId, orgId, true)
+	return dr.deleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId}, true)
+}
+
+// TrashDashboard deletes a dashboard and keeps it in the trash of the organization,
+// from where it can be restored until the trash retention expires.
+func (dr *DashboardServiceImpl) TrashDashboard(ctx context.Context, dashboardID int64, orgID int64, user identity.Requester) error {
+	deletedBy, err := resolveUserID(user, dr.log)
+	if err != nil {
+		return err
+	}
+	return dr.deleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: orgID, ID: dashboardID, MoveToTrash: true, DeletedBy: deletedBy}, true)
+}
+
+func (dr *DashboardServiceImpl) FindTrash(ctx context.Context, query *dashboards.FindDashboardTrashQuery) ([]*dashboards.DashboardTrash, error) {
+	return dr.dashboardStore.FindTrash(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) RestoreTrash(ctx context.Context, cmd *dashboards.RestoreDashboardTrashCommand) (*dashboards.DashboardTrash, error) {
+	return dr.dashboardStore.RestoreTrash(ctx, cmd)
+}
+
+func (dr *DashboardServiceImpl) DeleteTrash(ctx context.Context, cmd *dashboards.DeleteDashboardTrashCommand) error {
+	return dr.dashboardStore.DeleteTrash(ctx, cmd)
+}
+
+func (dr *DashboardServiceImpl) DeleteExpiredTrash(ctx context.Context) (int64, error) {
+	return dr.dashboardStore.DeleteExpiredTrash(ctx, time.Now().Add(-dr.cfg.DashboardTrashRetention))
 }
 
 func (dr *DashboardServiceImpl) GetDashboardByPublicUid(ctx context.Context, dashboardPublicUid string) (*dashboards.Dashboard, error) {
@@ -420,12 +447,12 @@ func (dr *DashboardServiceImpl) GetDashboardByPublicUid(ctx context.Context, das
 
 // DeleteProvisionedDashboard removes dashboard from the DB even if it is provisioned.
 func (dr *DashboardServiceImpl) DeleteProvisionedDashboard(ctx context.Context, dashboardId int64, orgId int64) error {
-	return dr.deleteDashboard(ctx, dashboardId, orgId, false)
+	return dr.deleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId}, false)
 }
 
-func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, dashboardId int64, orgId int64, validateProvisionedDashboard bool) error {
+func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, cmd *dashboards.DeleteDashboardCommand, validateProvisionedDashboard bool) error {
 	if validateProvisionedDashboard {
-		provisionedData, err := dr.GetProvisionedDashboardDataByDashboardID(ctx, dashboardId)
+		provisionedData, err := dr.GetProvisionedDashboardDataByDashboardID(ctx, cmd.ID)
 		if err != nil {
 			return fmt.Errorf("%v: %w", "failed to check if dashboard is provisioned", err)
 		}
@@ -434,7 +461,6 @@ func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, dashboardId
 			return dashboards.ErrDashboardCannotDeleteProvisionedDashboard
 		}
 	}
-	cmd := &dashboards.DeleteDashboardCommand{OrgID: orgId, ID: dashboardId}
 	return dr.dashboardStore.DeleteDashboard(ctx, cmd)
 }
 
@@ -468,6 +494,12 @@ func (dr *DashboardServiceImpl) UnprovisionDashboard(ctx context.Context, dashbo
 	return dr.dashboardStore.UnprovisionDashboard(ctx, dashboardId)
 }
 
+// UpdateProvisionedDashboardData updates the provisioning metadata of a dashboard. Used after a
+// dashboard saved from the UI is written back to its provisioning source.
+func (dr *DashboardServiceImpl) UpdateProvisionedDashboardData(ctx context.Context, provisioning *dashboards.DashboardProvisioning) error {
+	return dr.dashboardStore.UpdateProvisionedDashboardData(ctx, provisioning)
+}
+
 func (dr *DashboardServiceImpl) GetDashboardsByPluginID(ctx context.Context, query *dashboards.GetDashboardsByPluginIDQuery) ([]*dashboards.Dashboard, error) {
 	return dr.dashboardStore.GetDashboardsByPluginID(ctx, query)
 }
@@ -202,6 +202,12 @@ func TestDashboardService(t *testing.T) {
 				err := service.DeleteDashboard(context.Background(), 1, 1)
 				require.Equal(t, err, dashboards.ErrDashboardCannotDeleteProvisionedDashboard)
 			})
+
+			t.Run("TrashDashboard should fail to delete it when provisioning information is missing", func(t *testing.T) {
+				fakeStore.On("GetProvisionedDataByDashboardID", mock.Anything, mock.AnythingOfType("int64")).Return(&dashboards.DashboardProvisioning{}, nil).Once()
+				err := service.TrashDashboard(context.Background(), 1, 1, &user.SignedInUser{UserID: 2, OrgID: 1})
+				require.Equal(t, err, dashboards.ErrDashboardCannotDeleteProvisionedDashboard)
+			})
 		})
 
 		t.Run("Given non provisioned dashboard", func(t *testing.T) {
@@ -220,6 +226,14 @@ func TestDashboardService(t *testing.T) {
 				require.NoError(t, err)
 			})
 
+			t.Run("TrashDashboard should move it to the trash", func(t *testing.T) {
+				args := &dashboards.DeleteDashboardCommand{OrgID: 1, ID: 1, MoveToTrash: true, DeletedBy: 2}
+				fakeStore.On("DeleteDashboard", mock.Anything, args).Return(nil).Once()
+				fakeStore.On("GetProvisionedDataByDashboardID", mock.Anything, mock.AnythingOfType("int64")).Return(nil, nil).Once()
+				err := service.TrashDashboard(context.Background(), 1, 1, &user.SignedInUser{UserID: 2, OrgID: 1})
+				require.NoError(t, err)
+			})
+
 			// t.Run("Delete ACL by user", func(t *testing.T) {
 			// 	fakeStore := dashboards.FakeDashboardStore{}
 			// 	args := 1
@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	quota "github.com/grafana/grafana/pkg/services/quota"
+
+	time "time"
 )
 
 // FakeDashboardStore is an autogenerated mock type for the Store type
@@ -108,6 +110,30 @@ func (_m *FakeDashboardStore) DeleteDashboardsInFolder(ctx context.Context, requ
 	return r0
 }
 
+// DeleteExpiredTrash provides a mock function with given fields: ctx, deletedBefore
+func (_m *FakeDashboardStore) DeleteExpiredTrash(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, deletedBefore)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteOrphanedProvisionedDashboards provides a mock function with given fields: ctx, cmd
 func (_m *FakeDashboardStore) DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *DeleteOrphanedProvisionedDashboardsCommand) error {
 	ret := _m.Called(ctx, cmd)
@@ -122,6 +148,20 @@ func (_m *FakeDashboardStore) DeleteOrphanedProvisionedDashboards(ctx context.Co
 	return r0
 }
 
+// DeleteTrash provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) DeleteTrash(ctx context.Context, cmd *DeleteDashboardTrashCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteDashboardTrashCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindDashboards provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardStore) FindDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error) {
 	ret := _m.Called(ctx, query)
@@ -148,6 +188,32 @@ func (_m *FakeDashboardStore) FindDashboards(ctx context.Context, query *FindPer
 	return r0, r1
 }
 
+// FindTrash provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) FindTrash(ctx context.Context, query *FindDashboardTrashQuery) ([]*DashboardTrash, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*DashboardTrash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *FindDashboardTrashQuery) ([]*DashboardTrash, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *FindDashboardTrashQuery) []*DashboardTrash); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DashboardTrash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *FindDashboardTrashQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDashboard provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardStore) GetDashboard(ctx context.Context, query *GetDashboardQuery) (*Dashboard, error) {
 	ret := _m.Called(ctx, query)
@@ -382,6 +448,58 @@ func (_m *FakeDashboardStore) GetProvisionedDataByDashboardUID(ctx context.Conte
 	return r0, r1
 }
 
+// GetTrash provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetTrash(ctx context.Context, query *GetDashboardTrashQuery) (*DashboardTrash, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *DashboardTrash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardTrashQuery) (*DashboardTrash, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDashboardTrashQuery) *DashboardTrash); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardTrash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDashboardTrashQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreTrash provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) RestoreTrash(ctx context.Context, cmd *RestoreDashboardTrashCommand) (*DashboardTrash, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *DashboardTrash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDashboardTrashCommand) (*DashboardTrash, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDashboardTrashCommand) *DashboardTrash); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardTrash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreDashboardTrashCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveAlerts provides a mock function with given fields: ctx, dashID, alerts
 func (_m *FakeDashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []*models.Alert) error {
 	ret := _m.Called(ctx, dashID, alerts)
@@ -448,6 +566,32 @@ func (_m *FakeDashboardStore) SaveProvisionedDashboard(ctx context.Context, cmd
 	return r0, r1
 }
 
+// SaveTrash provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) SaveTrash(ctx context.Context, cmd *SaveDashboardTrashCommand) (*DashboardTrash, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *DashboardTrash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *SaveDashboardTrashCommand) (*DashboardTrash, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *SaveDashboardTrashCommand) *DashboardTrash); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardTrash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *SaveDashboardTrashCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UnprovisionDashboard provides a mock function with given fields: ctx, id
 func (_m *FakeDashboardStore) UnprovisionDashboard(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -476,6 +620,20 @@ func (_m *FakeDashboardStore) UpdateDashboardACL(ctx context.Context, uid int64,
 	return r0
 }
 
+// UpdateProvisionedDashboardData provides a mock function with given fields: ctx, provisioning
+func (_m *FakeDashboardStore) UpdateProvisionedDashboardData(ctx context.Context, provisioning *DashboardProvisioning) error {
+	ret := _m.Called(ctx, provisioning)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DashboardProvisioning) error); ok {
+		r0 = rf(ctx, provisioning)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ValidateDashboardBeforeSave provides a mock function with given fields: ctx, dashboard, overwrite
 func (_m *FakeDashboardStore) ValidateDashboardBeforeSave(ctx context.Context, dashboard *Dashboard, overwrite bool) (bool, error) {
 	ret := _m.Called(ctx, dashboard, overwrite)
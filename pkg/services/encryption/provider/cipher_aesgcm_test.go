@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/encryption"
+)
+
+func Test_aesGcmCipher(t *testing.T) {
+	cipher := aesGcmCipher{}
+	ctx := context.Background()
+
+	encrypted, err := cipher.Encrypt(ctx, []byte("grafana"), "1234")
+	require.NoError(t, err)
+	assert.NotEmpty(t, encrypted)
+
+	decipher := aesDecipher{algorithm: encryption.AesGcm}
+	decrypted, err := decipher.Decrypt(ctx, encrypted, "1234")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("grafana"), decrypted)
+
+	t.Run("tampered ciphertext can't be decrypted", func(t *testing.T) {
+		encrypted[len(encrypted)-1] ^= 0xff
+		_, err := decipher.Decrypt(ctx, encrypted, "1234")
+		require.Error(t, err)
+	})
+
+	t.Run("wrong secret can't decrypt", func(t *testing.T) {
+		encrypted, err := cipher.Encrypt(ctx, []byte("grafana"), "1234")
+		require.NoError(t, err)
+		_, err = decipher.Decrypt(ctx, encrypted, "4321")
+		require.Error(t, err)
+	})
+}
@@ -966,5 +966,11 @@ var (
 			RequiresDevMode: false,
 			Owner:           grafanaAuthnzSquad,
 		},
+		{
+			Name:        "sqlExpressions",
+			Description: "Enables SQL expressions over the results of queries in server-side expressions",
+			Stage:       FeatureStageExperimental,
+			Owner:       grafanaObservabilityMetricsSquad,
+		},
 	}
 )
@@ -558,4 +558,8 @@ const (
 	// FlagAnnotationPermissionUpdate
 	// Separate annotation permissions from dashboard permissions to allow for more granular control.
 	FlagAnnotationPermissionUpdate = "annotationPermissionUpdate"
+
+	// FlagSqlExpressions
+	// Enables SQL expressions over the results of queries in server-side expressions
+	FlagSqlExpressions = "sqlExpressions"
 )
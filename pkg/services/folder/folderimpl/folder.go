@@ -528,6 +528,12 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 		return dashboards.ErrFolderAccessDenied
 	}
 
+	// The copy in the trash is saved on its own, so that failing to make it doesn't prevent the delete.
+	trash, err := s.saveTrash(ctx, cmd)
+	if err != nil {
+		logger.Warn("failed to move the folder to the trash, deleting it anyway", "error", err)
+	}
+
 	result := []string{cmd.UID}
 	err = s.db.InTransaction(ctx, func(ctx context.Context) error {
 		if s.features.IsEnabled(featuremgmt.FlagNestedFolders) {
@@ -564,10 +570,45 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 		}
 		return nil
 	})
+	if err != nil && trash != nil {
+		if err := s.dashboardStore.DeleteTrash(ctx, &dashboards.DeleteDashboardTrashCommand{OrgID: trash.OrgID, UID: trash.UID}); err != nil {
+			logger.Warn("failed to remove the trash copy of a folder that wasn't deleted", "error", err)
+		}
+	}
 
 	return err
 }
 
+// saveTrash keeps a copy of the folder, its subfolders and everything they contain in the trash,
+// so they can be restored after they are deleted.
+func (s *Service) saveTrash(ctx context.Context, cmd *folder.DeleteFolderCommand) (*dashboards.DashboardTrash, error) {
+	var subfolders []string
+	if s.features.IsEnabled(featuremgmt.FlagNestedFolders) {
+		var err error
+		subfolders, err = s.getNestedFolders(ctx, cmd.OrgID, cmd.UID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var deletedBy int64
+	namespace, id := cmd.SignedInUser.GetNamespacedID()
+	if namespace == identity.NamespaceUser || namespace == identity.NamespaceServiceAccount {
+		var err error
+		deletedBy, err = identity.IntIdentifier(namespace, id)
+		if err != nil {
+			s.log.Debug("failed to parse user ID", "namespace", namespace, "userID", id, "error", err)
+		}
+	}
+
+	return s.dashboardStore.SaveTrash(ctx, &dashboards.SaveDashboardTrashCommand{
+		OrgID:         cmd.OrgID,
+		UID:           cmd.UID,
+		SubfolderUIDs: subfolders,
+		DeletedBy:     deletedBy,
+	})
+}
+
 func (s *Service) deleteChildrenInFolder(ctx context.Context, orgID int64, folderUID string, user identity.Requester) error {
 	for _, v := range s.registry {
 		if err := v.DeleteInFolder(ctx, orgID, folderUID, user); err != nil {
@@ -242,6 +242,11 @@ func TestIntegrationFolderService(t *testing.T) {
 				f.UID = util.GenerateShortUID()
 				folderStore.On("GetFolders", mock.Anything, orgID, []string{f.UID}).Return(map[string]*folder.Folder{f.UID: f}, nil)
 
+				var actualTrashCmd *dashboards.SaveDashboardTrashCommand
+				dashStore.On("SaveTrash", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					actualTrashCmd = args.Get(1).(*dashboards.SaveDashboardTrashCommand)
+				}).Return(&dashboards.DashboardTrash{}, nil).Once()
+
 				var actualCmd *dashboards.DeleteDashboardCommand
 				dashStore.On("DeleteDashboard", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
 					actualCmd = args.Get(1).(*dashboards.DeleteDashboardCommand)
@@ -259,6 +264,31 @@ func TestIntegrationFolderService(t *testing.T) {
 				require.Equal(t, f.ID, actualCmd.ID)
 				require.Equal(t, orgID, actualCmd.OrgID)
 				require.Equal(t, expectedForceDeleteRules, actualCmd.ForceDeleteFolderRules)
+				require.NotNil(t, actualTrashCmd)
+				require.Equal(t, f.UID, actualTrashCmd.UID)
+				require.Equal(t, orgID, actualTrashCmd.OrgID)
+			})
+
+			t.Run("When deleting folder should delete it even if moving it to the trash fails", func(t *testing.T) {
+				f := folder.NewFolder(util.GenerateShortUID(), "")
+				f.ID = rand.Int63()
+				f.UID = util.GenerateShortUID()
+				folderStore.On("GetFolders", mock.Anything, orgID, []string{f.UID}).Return(map[string]*folder.Folder{f.UID: f}, nil)
+				dashStore.On("SaveTrash", mock.Anything, mock.Anything).Return(nil, errors.New("data too long")).Once()
+
+				var actualCmd *dashboards.DeleteDashboardCommand
+				dashStore.On("DeleteDashboard", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					actualCmd = args.Get(1).(*dashboards.DeleteDashboardCommand)
+				}).Return(nil).Once()
+
+				err := service.Delete(context.Background(), &folder.DeleteFolderCommand{
+					UID:          f.UID,
+					OrgID:        orgID,
+					SignedInUser: usr,
+				})
+				require.NoError(t, err)
+				require.NotNil(t, actualCmd)
+				require.Equal(t, f.ID, actualCmd.ID)
 			})
 
 			t.Cleanup(func() {
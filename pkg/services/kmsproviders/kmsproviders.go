@@ -1,6 +1,12 @@
 package kmsproviders
 
-import "github.com/grafana/grafana/pkg/services/secrets"
+import (
+	"fmt"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
 
 const (
 	// Legacy is used for historical reasons (keeping backwards).
@@ -12,12 +18,51 @@ const (
 	// which fallbacks to Grafana's secret key. See the
 	// defaultprovider package for further information.
 	Default = "secretKey.v1"
+
+	// SectionPrefix is the prefix of the configuration sections of provider keys,
+	// e.g. [security.encryption.vault.my-key] for the provider identified by vault.my-key.
+	SectionPrefix = "security.encryption."
 )
 
 type Service interface {
 	Provide() (map[secrets.ProviderID]secrets.Provider, error)
 }
 
+// ProviderFactory creates the provider of a key from the configuration section of the key.
+// The kind of the provider is the first part of the identifier, e.g. vault for vault.my-key.
+type ProviderFactory func(id secrets.ProviderID, section *setting.DynamicSection) (secrets.Provider, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]ProviderFactory)
+)
+
+// RegisterProviderFactory makes a kind of provider, such as an external KMS, available
+// to be configured through available_encryption_providers. It is meant to be called
+// before the secrets service is initialized, typically from an init function.
+// It panics if the factory is nil or if the kind is already registered.
+func RegisterProviderFactory(kind string, factory ProviderFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("kmsproviders: provider factory is nil")
+	}
+	if _, ok := factories[kind]; ok || kind == Legacy {
+		panic(fmt.Sprintf("kmsproviders: provider factory for kind %s registered twice", kind))
+	}
+	factories[kind] = factory
+}
+
+// GetProviderFactory returns the factory registered for a kind of provider.
+func GetProviderFactory(kind string) (ProviderFactory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	factory, ok := factories[kind]
+	return factory, ok
+}
+
 func NormalizeProviderID(id secrets.ProviderID) secrets.ProviderID {
 	if id == Legacy {
 		return Default
@@ -1,6 +1,10 @@
 package osskmsproviders
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/kmsproviders"
@@ -13,6 +17,7 @@ type Service struct {
 	enc      encryption.Internal
 	cfg      *setting.Cfg
 	features featuremgmt.FeatureToggles
+	log      log.Logger
 }
 
 func ProvideService(enc encryption.Internal, cfg *setting.Cfg, features featuremgmt.FeatureToggles) Service {
@@ -20,11 +25,40 @@ func ProvideService(enc encryption.Internal, cfg *setting.Cfg, features featurem
 		enc:      enc,
 		cfg:      cfg,
 		features: features,
+		log:      log.New("kmsproviders"),
 	}
 }
 
+// Provide returns the default provider, and the providers of the keys listed in available_encryption_providers
+// whose kind has been registered with kmsproviders.RegisterProviderFactory.
 func (s Service) Provide() (map[secrets.ProviderID]secrets.Provider, error) {
-	return map[secrets.ProviderID]secrets.Provider{
+	providers := map[secrets.ProviderID]secrets.Provider{
 		kmsproviders.Default: grafana.New(s.cfg, s.enc),
-	}, nil
+	}
+
+	available := s.cfg.SectionWithEnvOverrides("security").Key("available_encryption_providers").MustString("")
+	for _, rawID := range strings.Fields(available) {
+		id := kmsproviders.NormalizeProviderID(secrets.ProviderID(rawID))
+		if _, ok := providers[id]; ok {
+			continue
+		}
+
+		kind, err := id.Kind()
+		if err != nil {
+			return nil, err
+		}
+		factory, ok := kmsproviders.GetProviderFactory(kind)
+		if !ok {
+			s.log.Warn("Skipping encryption provider with unknown kind", "provider", id, "kind", kind)
+			continue
+		}
+
+		provider, err := factory(id, s.cfg.SectionWithEnvOverrides(kmsproviders.SectionPrefix+string(id)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize encryption provider %s: %w", id, err)
+		}
+		providers[id] = provider
+	}
+
+	return providers, nil
 }
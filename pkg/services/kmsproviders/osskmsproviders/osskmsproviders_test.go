@@ -0,0 +1,100 @@
+package osskmsproviders
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/infra/usagestats"
+	encryptionprovider "github.com/grafana/grafana/pkg/services/encryption/provider"
+	encryptionservice "github.com/grafana/grafana/pkg/services/encryption/service"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/kmsproviders"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeExternalProvider struct {
+	keyName string
+}
+
+func (p fakeExternalProvider) Encrypt(_ context.Context, blob []byte) ([]byte, error) {
+	return append([]byte(p.keyName+":"), blob...), nil
+}
+
+func (p fakeExternalProvider) Decrypt(_ context.Context, blob []byte) ([]byte, error) {
+	return blob[len(p.keyName)+1:], nil
+}
+
+func init() {
+	kmsproviders.RegisterProviderFactory("fakekms", func(id secrets.ProviderID, section *setting.DynamicSection) (secrets.Provider, error) {
+		keyName := section.Key("key_name").MustString("")
+		if keyName == "" {
+			return nil, errors.New("key_name is required")
+		}
+		return fakeExternalProvider{keyName: keyName}, nil
+	})
+}
+
+func setupService(t *testing.T, rawCfg string) Service {
+	t.Helper()
+	raw, err := ini.Load([]byte(rawCfg))
+	require.NoError(t, err)
+	cfg := &setting.Cfg{Raw: raw}
+
+	enc, err := encryptionservice.ProvideEncryptionService(encryptionprovider.Provider{}, &usagestats.UsageStatsMock{}, cfg)
+	require.NoError(t, err)
+	return ProvideService(enc, cfg, featuremgmt.WithFeatures())
+}
+
+func TestService_Provide(t *testing.T) {
+	t.Run("only the default provider is available by default", func(t *testing.T) {
+		providers, err := setupService(t, `
+		[security]
+		secret_key = sdDkslslld
+		`).Provide()
+		require.NoError(t, err)
+		require.Len(t, providers, 1)
+		require.Contains(t, providers, secrets.ProviderID(kmsproviders.Default))
+	})
+
+	t.Run("providers of registered kinds are created from their section", func(t *testing.T) {
+		providers, err := setupService(t, `
+		[security]
+		secret_key = sdDkslslld
+		available_encryption_providers = secretKey.v1 fakekms.one fakekms.two unknown.v1
+
+		[security.encryption.fakekms.one]
+		key_name = first
+
+		[security.encryption.fakekms.two]
+		key_name = second
+		`).Provide()
+		require.NoError(t, err)
+		require.Len(t, providers, 3)
+
+		encrypted, err := providers["fakekms.two"].Encrypt(context.Background(), []byte("grafana"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("second:grafana"), encrypted)
+	})
+
+	t.Run("invalid configuration of a provider fails", func(t *testing.T) {
+		_, err := setupService(t, `
+		[security]
+		secret_key = sdDkslslld
+		available_encryption_providers = fakekms.one
+		`).Provide()
+		require.ErrorContains(t, err, "key_name is required")
+	})
+
+	t.Run("registering a kind twice panics", func(t *testing.T) {
+		require.Panics(t, func() {
+			kmsproviders.RegisterProviderFactory("fakekms", func(secrets.ProviderID, *setting.DynamicSection) (secrets.Provider, error) {
+				return nil, nil
+			})
+		})
+	})
+}
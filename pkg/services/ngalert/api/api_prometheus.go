@@ -312,11 +312,12 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 	ngmodels.RulesGroup(rules).SortByGroupIndex()
 	for _, rule := range rules {
 		alertingRule := apimodels.AlertingRule{
-			State:       "inactive",
-			Name:        rule.Title,
-			Query:       ruleToQuery(srv.log, rule),
-			Duration:    rule.For.Seconds(),
-			Annotations: rule.Annotations,
+			State:         "inactive",
+			Name:          rule.Title,
+			Query:         ruleToQuery(srv.log, rule),
+			Duration:      rule.For.Seconds(),
+			KeepFiringFor: rule.KeepFiringFor.Seconds(),
+			Annotations:   rule.Annotations,
 		}
 
 		newRule := apimodels.Rule{
@@ -326,6 +327,9 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 			Type:           apiv1.RuleTypeAlerting,
 			LastEvaluation: time.Time{},
 		}
+		if rule.Type() == ngmodels.RuleTypeRecording {
+			newRule.Type = apiv1.RuleTypeRecording
+		}
 
 		states := srv.manager.GetStatesForRuleUID(rule.OrgID, rule.UID)
 		totals := make(map[string]int64)
@@ -67,6 +67,16 @@ func validateRuleNode(
 		}
 	}
 
+	record := ModelRecordFromApiRecord(ruleNode.GrafanaManagedAlert.Record)
+	if record != nil {
+		if !cfg.RecordingRules.Enabled {
+			return nil, fmt.Errorf("%w: recording rules are not enabled", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if err := record.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
+		}
+	}
+
 	if len(ruleNode.GrafanaManagedAlert.Data) == 0 {
 		if canPatch {
 			if ruleNode.GrafanaManagedAlert.Condition != "" {
@@ -76,7 +86,12 @@ func validateRuleNode(
 			return nil, fmt.Errorf("%w: no queries or expressions are found", ngmodels.ErrAlertRuleFailedValidation)
 		}
 	} else {
-		err = validateCondition(ruleNode.GrafanaManagedAlert.Condition, ruleNode.GrafanaManagedAlert.Data)
+		condition := ruleNode.GrafanaManagedAlert.Condition
+		if record != nil {
+			// recording rules write the result of the referenced query or expression instead of evaluating a condition
+			condition = record.From
+		}
+		err = validateCondition(condition, ruleNode.GrafanaManagedAlert.Data)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
 		}
@@ -95,6 +110,7 @@ func validateRuleNode(
 		RuleGroup:       groupName,
 		NoDataState:     noDataState,
 		ExecErrState:    errorState,
+		Record:          record,
 	}
 
 	newAlertRule.For, err = validateForInterval(ruleNode)
@@ -102,6 +118,11 @@ func validateRuleNode(
 		return nil, err
 	}
 
+	newAlertRule.KeepFiringFor, err = validateKeepFiringFor(ruleNode)
+	if err != nil {
+		return nil, err
+	}
+
 	if ruleNode.ApiRuleNode != nil {
 		newAlertRule.Annotations = ruleNode.ApiRuleNode.Annotations
 		newAlertRule.Labels = ruleNode.ApiRuleNode.Labels
@@ -173,6 +194,21 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringFor validates ApiRuleNode.KeepFiringFor and converts it to time.Duration. If the field is not specified returns 0 if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateKeepFiringFor(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
 // validateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 // It also returns a map containing current existing alerts that don't contain the is_paused field in the body of the call.
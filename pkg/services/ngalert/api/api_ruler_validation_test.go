@@ -346,10 +346,23 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 			},
 			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
 				require.Equal(t, time.Duration(0), alert.For)
+				require.Equal(t, time.Duration(0), alert.KeepFiringFor)
 				require.Nil(t, alert.Annotations)
 				require.Nil(t, alert.Labels)
 			},
 		},
+		{
+			name: "coverts keep_firing_for",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(5 * time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, 5*time.Minute, alert.KeepFiringFor)
+			},
+		},
 		{
 			name: "defaults to NoData if NoDataState is empty",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -511,6 +524,15 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if keep_firing_for is negative",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(-time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+		},
 		{
 			name: "fail if Condition is empty",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -566,6 +588,66 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 	}
 }
 
+func TestValidateRuleNode_Record(t *testing.T) {
+	orgId := rand.Int63()
+	folder := randFolder()
+	cfg := config(t)
+	cfg.RecordingRules.Enabled = true
+
+	t.Run("converts recording configuration", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.Condition = ""
+		r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "test_metric", From: "A"}
+
+		alert, err := validateRuleNode(&r, "", cfg.BaseInterval, orgId, folder, cfg)
+		require.NoError(t, err)
+		require.Equal(t, models.RuleTypeRecording, alert.Type())
+		require.Equal(t, &models.Record{Metric: "test_metric", From: "A"}, alert.Record)
+		require.Equal(t, "A", alert.GetEvalCondition().Condition)
+	})
+
+	testCases := []struct {
+		name   string
+		record *apimodels.Record
+	}{
+		{
+			name:   "fails if metric is empty",
+			record: &apimodels.Record{From: "A"},
+		},
+		{
+			name:   "fails if metric is not a valid metric name",
+			record: &apimodels.Record{Metric: "test metric", From: "A"},
+		},
+		{
+			name:   "fails if from is empty",
+			record: &apimodels.Record{Metric: "test_metric"},
+		},
+		{
+			name:   "fails if from does not reference a query",
+			record: &apimodels.Record{Metric: "test_metric", From: "B"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := validRule()
+			r.GrafanaManagedAlert.Record = testCase.record
+
+			_, err := validateRuleNode(&r, "", cfg.BaseInterval, orgId, folder, cfg)
+			require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		})
+	}
+
+	t.Run("fails if recording rules are disabled", func(t *testing.T) {
+		cfg := config(t)
+		r := validRule()
+		r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "test_metric", From: "A"}
+
+		_, err := validateRuleNode(&r, "", cfg.BaseInterval, orgId, folder, cfg)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
+
 func TestValidateRuleNode_UID(t *testing.T) {
 	orgId := rand.Int63()
 	folder := randFolder()
@@ -627,6 +709,19 @@ func TestValidateRuleNode_UID(t *testing.T) {
 				require.Len(t, alert.Data, 0)
 			},
 		},
+		{
+			name: "use -1 for For and KeepFiringFor if they are not specified",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.ApiRuleNode.For = nil
+				r.ApiRuleNode.KeepFiringFor = nil
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, time.Duration(-1), alert.For)
+				require.Equal(t, time.Duration(-1), alert.KeepFiringFor)
+			},
+		},
 		{
 			name: "extracts Dashboard UID and Panel Id from annotations",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -14,43 +14,45 @@ import (
 // AlertRuleFromProvisionedAlertRule converts definitions.ProvisionedAlertRule to models.AlertRule
 func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (models.AlertRule, error) {
 	return models.AlertRule{
-		ID:           a.ID,
-		UID:          a.UID,
-		OrgID:        a.OrgID,
-		NamespaceUID: a.FolderUID,
-		RuleGroup:    a.RuleGroup,
-		Title:        a.Title,
-		Condition:    a.Condition,
-		Data:         AlertQueriesFromApiAlertQueries(a.Data),
-		Updated:      a.Updated,
-		NoDataState:  models.NoDataState(a.NoDataState),          // TODO there must be a validation
-		ExecErrState: models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
-		For:          time.Duration(a.For),
-		Annotations:  a.Annotations,
-		Labels:       a.Labels,
-		IsPaused:     a.IsPaused,
+		ID:            a.ID,
+		UID:           a.UID,
+		OrgID:         a.OrgID,
+		NamespaceUID:  a.FolderUID,
+		RuleGroup:     a.RuleGroup,
+		Title:         a.Title,
+		Condition:     a.Condition,
+		Data:          AlertQueriesFromApiAlertQueries(a.Data),
+		Updated:       a.Updated,
+		NoDataState:   models.NoDataState(a.NoDataState),          // TODO there must be a validation
+		ExecErrState:  models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
+		For:           time.Duration(a.For),
+		KeepFiringFor: time.Duration(a.KeepFiringFor),
+		Annotations:   a.Annotations,
+		Labels:        a.Labels,
+		IsPaused:      a.IsPaused,
 	}, nil
 }
 
 // ProvisionedAlertRuleFromAlertRule converts models.AlertRule to definitions.ProvisionedAlertRule and sets provided provenance status
 func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.Provenance) definitions.ProvisionedAlertRule {
 	return definitions.ProvisionedAlertRule{
-		ID:           rule.ID,
-		UID:          rule.UID,
-		OrgID:        rule.OrgID,
-		FolderUID:    rule.NamespaceUID,
-		RuleGroup:    rule.RuleGroup,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         ApiAlertQueriesFromAlertQueries(rule.Data),
-		Updated:      rule.Updated,
-		NoDataState:  definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
-		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		Provenance:   definitions.Provenance(provenance), // TODO validate enum conversion?
-		IsPaused:     rule.IsPaused,
+		ID:            rule.ID,
+		UID:           rule.UID,
+		OrgID:         rule.OrgID,
+		FolderUID:     rule.NamespaceUID,
+		RuleGroup:     rule.RuleGroup,
+		Title:         rule.Title,
+		For:           model.Duration(rule.For),
+		KeepFiringFor: model.Duration(rule.KeepFiringFor),
+		Condition:     rule.Condition,
+		Data:          ApiAlertQueriesFromAlertQueries(rule.Data),
+		Updated:       rule.Updated,
+		NoDataState:   definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
+		ExecErrState:  definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
+		Annotations:   rule.Annotations,
+		Labels:        rule.Labels,
+		Provenance:    definitions.Provenance(provenance), // TODO validate enum conversion?
+		IsPaused:      rule.IsPaused,
 	}
 }
 
@@ -99,6 +101,28 @@ func ApiAlertQueriesFromAlertQueries(queries []models.AlertQuery) []definitions.
 	return result
 }
 
+// ModelRecordFromApiRecord converts definitions.Record to models.Record
+func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
+	if r == nil {
+		return nil
+	}
+	return &models.Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+}
+
+// ApiRecordFromModelRecord converts models.Record to definitions.Record
+func ApiRecordFromModelRecord(r *models.Record) *definitions.Record {
+	if r == nil {
+		return nil
+	}
+	return &definitions.Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+}
+
 func AlertRuleGroupFromApiAlertRuleGroup(a definitions.AlertRuleGroup) (models.AlertRuleGroup, error) {
 	ruleGroup := models.AlertRuleGroup{
 		Title:     a.Title,
@@ -174,20 +198,24 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 	}
 
 	result := definitions.AlertRuleExport{
-		UID:          rule.UID,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         data,
-		DashboardUID: rule.DashboardUID,
-		PanelID:      rule.PanelID,
-		NoDataState:  definitions.NoDataState(rule.NoDataState),
-		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState),
-		IsPaused:     rule.IsPaused,
+		UID:           rule.UID,
+		Title:         rule.Title,
+		For:           model.Duration(rule.For),
+		KeepFiringFor: model.Duration(rule.KeepFiringFor),
+		Condition:     rule.Condition,
+		Data:          data,
+		DashboardUID:  rule.DashboardUID,
+		PanelID:       rule.PanelID,
+		NoDataState:   definitions.NoDataState(rule.NoDataState),
+		ExecErrState:  definitions.ExecutionErrorState(rule.ExecErrState),
+		IsPaused:      rule.IsPaused,
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
 	}
+	if rule.KeepFiringFor.Seconds() > 0 {
+		result.KeepFiringForString = util.Pointer(model.Duration(rule.KeepFiringFor).String())
+	}
 	if rule.Annotations != nil {
 		result.Annotations = &rule.Annotations
 	}
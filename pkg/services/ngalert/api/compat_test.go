@@ -2,10 +2,13 @@ package api
 
 import (
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 func TestToModel(t *testing.T) {
@@ -35,3 +38,29 @@ func TestToModel(t *testing.T) {
 		require.Len(t, tm.Rules, 1)
 	})
 }
+
+func TestKeepFiringForConversion(t *testing.T) {
+	rule := models.AlertRuleGen(models.WithKeepFiringFor(5 * time.Minute))()
+
+	t.Run("provisioned alert rule should keep the keep firing for duration", func(t *testing.T) {
+		provisioned := ProvisionedAlertRuleFromAlertRule(*rule, models.ProvenanceNone)
+		require.Equal(t, model.Duration(5*time.Minute), provisioned.KeepFiringFor)
+
+		converted, err := AlertRuleFromProvisionedAlertRule(provisioned)
+		require.NoError(t, err)
+		require.Equal(t, rule.KeepFiringFor, converted.KeepFiringFor)
+	})
+
+	t.Run("export should format the keep firing for duration for HCL", func(t *testing.T) {
+		export, err := AlertRuleExportFromAlertRule(*rule)
+		require.NoError(t, err)
+		require.Equal(t, model.Duration(5*time.Minute), export.KeepFiringFor)
+		require.Equal(t, "5m", *export.KeepFiringForString)
+
+		rule := models.CopyRule(rule)
+		rule.KeepFiringFor = 0
+		export, err = AlertRuleExportFromAlertRule(*rule)
+		require.NoError(t, err)
+		require.Nil(t, export.KeepFiringForString)
+	})
+}
@@ -398,6 +398,7 @@ type PostableGrafanaRule struct {
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	IsPaused     *bool               `json:"is_paused" yaml:"is_paused"`
+	Record       *Record             `json:"record,omitempty" yaml:"record,omitempty"`
 }
 
 // swagger:model
@@ -418,6 +419,20 @@ type GettableGrafanaRule struct {
 	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	Provenance      Provenance          `json:"provenance,omitempty" yaml:"provenance,omitempty"`
 	IsPaused        bool                `json:"is_paused" yaml:"is_paused"`
+	Record          *Record             `json:"record,omitempty" yaml:"record,omitempty"`
+}
+
+// Record defines how the results of a recording rule are written.
+// swagger:model
+type Record struct {
+	// Name of the metric the results are written to. Must be a valid Prometheus metric name.
+	// required: true
+	// example: grafana_alerts_ratio
+	Metric string `json:"metric" yaml:"metric"`
+	// RefID of the query or expression whose results are written.
+	// required: true
+	// example: A
+	From string `json:"from" yaml:"from"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
@@ -0,0 +1,29 @@
+package eval
+
+import (
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/quota"
+)
+
+// rateLimitedEvaluatorFactory counts each evaluator it creates towards the
+// rate quota of alert rule evaluations of the org of the evaluation context.
+type rateLimitedEvaluatorFactory struct {
+	EvaluatorFactory
+	quotaService quota.Service
+}
+
+// NewRateLimitedEvaluatorFactory returns an EvaluatorFactory that fails to create
+// evaluators with a *quota.RateLimitedError once the org reached its rate quota of
+// alert rule evaluations. The scheduler skips these evaluations.
+func NewRateLimitedEvaluatorFactory(factory EvaluatorFactory, quotaService quota.Service) EvaluatorFactory {
+	return &rateLimitedEvaluatorFactory{EvaluatorFactory: factory, quotaService: quotaService}
+}
+
+func (f *rateLimitedEvaluatorFactory) Create(ctx EvaluationContext, condition models.Condition) (ConditionEvaluator, error) {
+	if ctx.User != nil {
+		if err := f.quotaService.CheckRateReached(ctx.Ctx, quota.RateTargetAlertRuleEvaluations, quota.OrgScope, ctx.User.GetOrgID()); err != nil {
+			return nil, err
+		}
+	}
+	return f.EvaluatorFactory.Create(ctx, condition)
+}
@@ -0,0 +1,45 @@
+package eval
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeEvaluatorFactory struct {
+	EvaluatorFactory
+	created int
+}
+
+func (f *fakeEvaluatorFactory) Create(ctx EvaluationContext, condition models.Condition) (ConditionEvaluator, error) {
+	f.created++
+	return nil, nil
+}
+
+func TestRateLimitedEvaluatorFactory(t *testing.T) {
+	evalCtx := NewContext(context.Background(), &user.SignedInUser{OrgID: 1})
+
+	t.Run("creates evaluators within the rate quota", func(t *testing.T) {
+		factory := &fakeEvaluatorFactory{}
+		_, err := NewRateLimitedEvaluatorFactory(factory, &quotatest.FakeQuotaService{}).Create(evalCtx, models.Condition{})
+		require.NoError(t, err)
+		require.Equal(t, 1, factory.created)
+	})
+
+	t.Run("fails when the rate quota is reached", func(t *testing.T) {
+		factory := &fakeEvaluatorFactory{}
+		quotaService := &quotatest.FakeQuotaService{
+			ExpectedRateError: quota.NewRateLimitedError(quota.RateTargetAlertRuleEvaluations, quota.OrgScope, time.Second),
+		}
+		_, err := NewRateLimitedEvaluatorFactory(factory, quotaService).Create(evalCtx, models.Condition{})
+		require.ErrorIs(t, err, quota.ErrRateLimited)
+		require.Zero(t, factory.created)
+	})
+}
@@ -17,6 +17,7 @@ type Scheduler struct {
 	BehindSeconds                       prometheus.Gauge
 	EvalTotal                           *prometheus.CounterVec
 	EvalFailures                        *prometheus.CounterVec
+	EvalThrottled                       *prometheus.CounterVec
 	EvalDuration                        *prometheus.HistogramVec
 	ProcessDuration                     *prometheus.HistogramVec
 	SendDuration                        *prometheus.HistogramVec
@@ -27,6 +28,8 @@ type Scheduler struct {
 	UpdateSchedulableAlertRulesDuration prometheus.Histogram
 	Ticker                              *ticker.Metrics
 	EvaluationMissed                    *prometheus.CounterVec
+	TickEvaluations                     prometheus.Gauge
+	EvaluationsPerTick                  prometheus.Histogram
 }
 
 func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
@@ -60,6 +63,15 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org"},
 		),
+		EvalThrottled: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_evaluations_throttled_total",
+				Help:      "The total number of rule evaluations skipped because the org reached its rate quota of rule evaluations.",
+			},
+			[]string{"org"},
+		),
 		EvalDuration: promauto.With(r).NewHistogramVec(
 			prometheus.HistogramOpts{
 				Namespace: Namespace,
@@ -143,5 +155,22 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org", "name"},
 		),
+		TickEvaluations: promauto.With(r).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_tick_rule_evaluations",
+				Help:      "The number of rule evaluations scheduled in the last tick.",
+			},
+		),
+		EvaluationsPerTick: promauto.With(r).NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_rule_evaluations_per_tick",
+				Help:      "The distribution of the number of rule evaluations scheduled per tick.",
+				Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+			},
+		),
 	}
 }
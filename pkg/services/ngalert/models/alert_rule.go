@@ -12,6 +12,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	alertingModels "github.com/grafana/alerting/models"
+	prommodel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/util/cmputil"
@@ -201,10 +202,49 @@ type AlertRule struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For time.Duration
+	// KeepFiringFor is how long an alert keeps firing after the condition stops being met.
+	KeepFiringFor time.Duration `xorm:"keep_firing_for"`
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	// Record is set if the rule is a recording rule. Recording rules do not produce alert states.
+	// Instead, the result of the query or expression referenced by Record.From is written as a new time series.
+	Record *Record `xorm:"json 'record'"`
+}
+
+// RuleType is the type of alert rule.
+type RuleType string
+
+const (
+	RuleTypeAlerting  RuleType = "alerting"
+	RuleTypeRecording RuleType = "recording"
+)
+
+func (t RuleType) String() string {
+	return string(t)
+}
+
+// Record contains the configuration of a recording rule.
+type Record struct {
+	// Metric is the name of the metric the evaluation results are written to.
+	Metric string `json:"metric"`
+	// From is the RefID of the query or expression whose results are written.
+	From string `json:"from"`
+}
+
+// Validate checks that the recording rule configuration is valid.
+func (r *Record) Validate() error {
+	if r.Metric == "" {
+		return errors.New("metric name of a recording rule cannot be empty")
+	}
+	if !prommodel.IsValidMetricName(prommodel.LabelValue(r.Metric)) {
+		return fmt.Errorf("metric name '%s' of a recording rule is not a valid Prometheus metric name", r.Metric)
+	}
+	if r.From == "" {
+		return errors.New("the query or expression to record from cannot be empty")
+	}
+	return nil
 }
 
 // AlertRuleWithOptionals This is to avoid having to pass in additional arguments deep in the call stack. Alert rule
@@ -277,6 +317,14 @@ func WithoutInternalLabels() LabelOption {
 	}
 }
 
+// Type returns the type of the rule: RuleTypeRecording if the rule has a recording configuration, RuleTypeAlerting otherwise.
+func (alertRule *AlertRule) Type() RuleType {
+	if alertRule.Record != nil {
+		return RuleTypeRecording
+	}
+	return RuleTypeAlerting
+}
+
 // GetLabels returns the labels specified as part of the alert rule.
 func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 	labels := alertRule.Labels
@@ -288,7 +336,14 @@ func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 	return labels
 }
 
+// GetEvalCondition returns the condition to evaluate. For recording rules, it is the query or expression referenced by Record.From.
 func (alertRule *AlertRule) GetEvalCondition() Condition {
+	if alertRule.Type() == RuleTypeRecording {
+		return Condition{
+			Condition: alertRule.Record.From,
+			Data:      alertRule.Data,
+		}
+	}
 	return Condition{
 		Condition: alertRule.Condition,
 		Data:      alertRule.Data,
@@ -456,10 +511,12 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For           time.Duration
+	KeepFiringFor time.Duration `xorm:"keep_firing_for"`
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	Record        *Record `xorm:"json 'record'"`
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -546,16 +603,19 @@ func (c Condition) IsValid() bool {
 // There are several exceptions:
 // 1. Following fields are not patched and therefore will be ignored: AlertRule.ID, AlertRule.OrgID, AlertRule.Updated, AlertRule.Version, AlertRule.UID, AlertRule.DashboardUID, AlertRule.PanelID, AlertRule.Annotations and AlertRule.Labels
 // 2. There are fields that are patched together:
-//   - AlertRule.Condition and AlertRule.Data
+//   - AlertRule.Condition, AlertRule.Record and AlertRule.Data
 //
-// If either of the pair is specified, neither is patched.
+// If the data and either the condition or the recording configuration are specified, none is patched.
 func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOptionals) {
 	if ruleToPatch.Title == "" {
 		ruleToPatch.Title = existingRule.Title
 	}
-	if ruleToPatch.Condition == "" || len(ruleToPatch.Data) == 0 {
+	if (ruleToPatch.Condition == "" && ruleToPatch.Record == nil) || len(ruleToPatch.Data) == 0 {
 		ruleToPatch.Condition = existingRule.Condition
 		ruleToPatch.Data = existingRule.Data
+		if ruleToPatch.Record == nil {
+			ruleToPatch.Record = existingRule.Record
+		}
 	}
 	if ruleToPatch.IntervalSeconds == 0 {
 		ruleToPatch.IntervalSeconds = existingRule.IntervalSeconds
@@ -575,6 +635,9 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
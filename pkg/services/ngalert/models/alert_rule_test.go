@@ -207,6 +207,40 @@ func TestSetDashboardAndPanelFromAnnotations(t *testing.T) {
 	}
 }
 
+func TestRecordValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		record Record
+		valid  bool
+	}{
+		{name: "valid", record: Record{Metric: "test_metric:rate5m", From: "A"}, valid: true},
+		{name: "empty metric", record: Record{From: "A"}},
+		{name: "invalid metric", record: Record{Metric: "1_metric", From: "A"}},
+		{name: "empty from", record: Record{Metric: "test_metric"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.record.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestAlertRuleType(t *testing.T) {
+	rule := AlertRuleGen()()
+	require.Equal(t, RuleTypeAlerting, rule.Type())
+	require.Equal(t, rule.Condition, rule.GetEvalCondition().Condition)
+
+	rule.Record = &Record{Metric: "test_metric", From: "B"}
+	require.Equal(t, RuleTypeRecording, rule.Type())
+	require.Equal(t, "B", rule.GetEvalCondition().Condition)
+}
+
 func TestPatchPartialAlertRule(t *testing.T) {
 	t.Run("patches", func(t *testing.T) {
 		testCases := []struct {
@@ -244,6 +278,12 @@ func TestPatchPartialAlertRule(t *testing.T) {
 					r.For = -1
 				},
 			},
+			{
+				name: "KeepFiringFor is -1",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.KeepFiringFor = -1
+				},
+			},
 			{
 				name: "IsPaused did not come in request",
 				mutator: func(r *AlertRuleWithOptionals) {
@@ -258,6 +298,7 @@ func TestPatchPartialAlertRule(t *testing.T) {
 				for {
 					rule := AlertRuleGen(func(rule *AlertRule) {
 						rule.For = time.Duration(rand.Int63n(1000) + 1)
+						rule.KeepFiringFor = time.Duration(rand.Int63n(1000) + 1)
 					})()
 					existing = &AlertRuleWithOptionals{AlertRule: *rule}
 					cloned := *existing
@@ -14,6 +14,9 @@ type AlertInstance struct {
 	CurrentStateSince time.Time
 	CurrentStateEnd   time.Time
 	LastEvalTime      time.Time
+	// KeepFiringSince is the time the condition of a firing alert stopped being met, while it keeps
+	// firing for the keep_firing_for duration of its rule. It is zero if it isn't set.
+	KeepFiringSince time.Time
 }
 
 type AlertInstanceKey struct {
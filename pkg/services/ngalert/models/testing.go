@@ -188,6 +188,12 @@ func WithForNTimes(timesOfInterval int64) AlertRuleMutator {
 	}
 }
 
+func WithKeepFiringFor(duration time.Duration) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.KeepFiringFor = duration
+	}
+}
+
 func WithNoDataExecAs(nodata NoDataState) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.NoDataState = nodata
@@ -230,6 +236,15 @@ func WithLabel(key, value string) AlertRuleMutator {
 	}
 }
 
+func WithRecord(metric string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Record = &Record{
+			Metric: metric,
+			From:   rule.Condition,
+		}
+	}
+}
+
 func WithUniqueUID(knownUids *sync.Map) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		uid := rule.UID
@@ -345,6 +360,7 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		KeepFiringFor:   r.KeepFiringFor,
 	}
 
 	if r.DashboardUID != nil {
@@ -382,6 +398,11 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	if r.Record != nil {
+		record := *r.Record
+		result.Record = &record
+	}
+
 	return &result
 }
 
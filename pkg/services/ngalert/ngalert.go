@@ -39,6 +39,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -176,8 +177,13 @@ func (ng *AlertNG) init() error {
 	var overrides []notifier.Option
 	if ng.Cfg.UnifiedAlerting.RemoteAlertmanager.Enable {
 		override := notifier.WithAlertmanagerOverride(func(ctx context.Context, orgID int64) (notifier.Alertmanager, error) {
-			externalAMCfg := remote.AlertmanagerConfig{}
-			return remote.NewAlertmanager(externalAMCfg, orgID)
+			externalAMCfg := remote.AlertmanagerConfig{
+				URL:               ng.Cfg.UnifiedAlerting.RemoteAlertmanager.URL,
+				TenantID:          ng.Cfg.UnifiedAlerting.RemoteAlertmanager.TenantID,
+				BasicAuthPassword: ng.Cfg.UnifiedAlerting.RemoteAlertmanager.Password,
+				DefaultConfig:     ng.Cfg.UnifiedAlerting.DefaultConfiguration,
+			}
+			return remote.NewAlertmanager(externalAMCfg, orgID, ng.store, ng.SecretsService.Decrypt)
 		})
 
 		overrides = append(overrides, override)
@@ -217,6 +223,14 @@ func (ng *AlertNG) init() error {
 	ng.AlertsRouter = alertsRouter
 
 	evalFactory := eval.NewEvaluatorFactory(ng.Cfg.UnifiedAlerting, ng.DataSourceCache, ng.ExpressionService, ng.pluginsStore)
+	recordingWriter, err := configureRecordingWriter(ng.Cfg.UnifiedAlerting.RecordingRules, ng.Log)
+	if err != nil {
+		return err
+	}
+	jitterStrategy, err := schedule.ParseJitterStrategy(ng.Cfg.UnifiedAlerting.JitterStrategy)
+	if err != nil {
+		return err
+	}
 	schedCfg := schedule.SchedulerCfg{
 		MaxAttempts:          ng.Cfg.UnifiedAlerting.MaxAttempts,
 		C:                    clk,
@@ -224,10 +238,12 @@ func (ng *AlertNG) init() error {
 		MinRuleInterval:      ng.Cfg.UnifiedAlerting.MinInterval,
 		DisableGrafanaFolder: ng.Cfg.UnifiedAlerting.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel),
 		AppURL:               appUrl,
-		EvaluatorFactory:     evalFactory,
+		EvaluatorFactory:     eval.NewRateLimitedEvaluatorFactory(evalFactory, ng.QuotaService),
 		RuleStore:            ng.store,
 		Metrics:              ng.Metrics.GetSchedulerMetrics(),
 		AlertSender:          alertsRouter,
+		RecordingWriter:      recordingWriter,
+		JitterStrategy:       jitterStrategy,
 		Tracer:               ng.tracer,
 		Log:                  log.New("ngalert.scheduler"),
 	}
@@ -414,6 +430,18 @@ func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
 	return limits, nil
 }
 
+func configureRecordingWriter(cfg setting.RecordingRuleSettings, l log.Logger) (schedule.RecordingWriter, error) {
+	if !cfg.Enabled {
+		return writer.NoopWriter{}, nil
+	}
+	writerCfg, err := writer.NewPrometheusWriterConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure recording rules writer: %w", err)
+	}
+	l.Info("Recording rules are enabled", "url", writerCfg.URL.Redacted())
+	return writer.NewPrometheusWriter(writerCfg, writer.NewRequester(cfg.Timeout), log.New("ngalert.writer")), nil
+}
+
 type Historian interface {
 	api.Historian
 	state.Historian
@@ -438,6 +466,9 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		if err != nil {
 			return nil, fmt.Errorf("multi-backend target \"%s\" was misconfigured: %w", cfg.MultiPrimary, err)
 		}
+		if _, ok := primary.(*historian.PrometheusBackend); ok {
+			return nil, fmt.Errorf("multi-backend target \"%s\" cannot be used as primary, it does not support queries", cfg.MultiPrimary)
+		}
 
 		var secondaries []historian.Backend
 		for _, b := range cfg.MultiSecondaries {
@@ -473,6 +504,15 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 		return backend, nil
 	}
 
+	if backend == historian.BackendTypePrometheus {
+		pcfg, err := historian.NewPrometheusConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote prometheus configuration: %w", err)
+		}
+		req := historian.NewRequester()
+		return historian.NewPrometheusBackend(pcfg, req, met), nil
+	}
+
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
 
@@ -104,6 +104,38 @@ func TestConfigureHistorianBackend(t *testing.T) {
 		require.ErrorContains(t, err, "unrecognized")
 	})
 
+	t.Run("fail initialization if prometheus is the multi-backend primary", func(t *testing.T) {
+		met := metrics.NewHistorianMetrics(prometheus.NewRegistry())
+		logger := log.NewNopLogger()
+		cfg := setting.UnifiedAlertingStateHistorySettings{
+			Enabled:                  true,
+			Backend:                  "multiple",
+			MultiPrimary:             "prometheus",
+			PrometheusRemoteWriteURL: "http://gone.invalid",
+		}
+
+		_, err := configureHistorianBackend(context.Background(), cfg, nil, nil, nil, met, logger)
+
+		require.ErrorContains(t, err, "cannot be used as primary")
+	})
+
+	t.Run("allow prometheus as a multi-backend secondary", func(t *testing.T) {
+		met := metrics.NewHistorianMetrics(prometheus.NewRegistry())
+		logger := log.NewNopLogger()
+		cfg := setting.UnifiedAlertingStateHistorySettings{
+			Enabled:                  true,
+			Backend:                  "multiple",
+			MultiPrimary:             "annotations",
+			MultiSecondaries:         []string{"prometheus"},
+			PrometheusRemoteWriteURL: "http://gone.invalid",
+		}
+
+		h, err := configureHistorianBackend(context.Background(), cfg, nil, nil, nil, met, logger)
+
+		require.NotNil(t, h)
+		require.NoError(t, err)
+	})
+
 	t.Run("do not fail initialization if pinging Loki fails", func(t *testing.T) {
 		met := metrics.NewHistorianMetrics(prometheus.NewRegistry())
 		logger := log.NewNopLogger()
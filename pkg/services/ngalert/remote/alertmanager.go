@@ -2,48 +2,77 @@ package remote
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/grafana/grafana/pkg/infra/log"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	amclient "github.com/prometheus/alertmanager/api/v2/client"
 	amalert "github.com/prometheus/alertmanager/api/v2/client/alert"
 	amalertgroup "github.com/prometheus/alertmanager/api/v2/client/alertgroup"
 	amreceiver "github.com/prometheus/alertmanager/api/v2/client/receiver"
 	amsilence "github.com/prometheus/alertmanager/api/v2/client/silence"
+	"github.com/prometheus/alertmanager/types"
+	prommodel "github.com/prometheus/common/model"
 )
 
 const readyPath = "/-/ready"
 
+// DecryptFn decrypts a payload previously encrypted by Grafana's secrets service.
+type DecryptFn func(ctx context.Context, payload []byte) ([]byte, error)
+
+type configStore interface {
+	GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) (*models.AlertConfiguration, error)
+	SaveAlertmanagerConfigurationWithCallback(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd, callback store.SaveCallback) error
+}
+
 type Alertmanager struct {
-	log      log.Logger
-	orgID    int64
-	tenantID string
-	url      string
+	log           log.Logger
+	orgID         int64
+	tenantID      string
+	url           string
+	defaultConfig string
 
 	amClient   *amclient.AlertmanagerAPI
 	httpClient *http.Client
 	ready      bool
 	sender     *sender.ExternalAlertmanager
+
+	store   configStore
+	decrypt DecryptFn
+
+	// configMtx guards the upload of the configuration and configHash,
+	// the hash of the last configuration known to be in the remote Alertmanager.
+	configMtx  sync.Mutex
+	configHash string
 }
 
 type AlertmanagerConfig struct {
 	URL               string
 	TenantID          string
 	BasicAuthPassword string
+
+	// DefaultConfig is the configuration used by SaveAndApplyDefaultConfig.
+	DefaultConfig string
 }
 
-func NewAlertmanager(cfg AlertmanagerConfig, orgID int64) (*Alertmanager, error) {
+func NewAlertmanager(cfg AlertmanagerConfig, orgID int64, store configStore, decryptFn DecryptFn) (*Alertmanager, error) {
 	client := http.Client{
 		Transport: &roundTripper{
 			tenantID:          cfg.TenantID,
@@ -79,22 +108,104 @@ func NewAlertmanager(cfg AlertmanagerConfig, orgID int64) (*Alertmanager, error)
 	}
 
 	return &Alertmanager{
-		amClient:   amclient.New(transport, nil),
-		httpClient: &client,
-		log:        log.New("ngalert.remote.alertmanager"),
-		sender:     s,
-		orgID:      orgID,
-		tenantID:   cfg.TenantID,
-		url:        cfg.URL,
+		amClient:      amclient.New(transport, nil),
+		httpClient:    &client,
+		log:           log.New("ngalert.remote.alertmanager"),
+		sender:        s,
+		orgID:         orgID,
+		tenantID:      cfg.TenantID,
+		url:           cfg.URL,
+		defaultConfig: cfg.DefaultConfig,
+		store:         store,
+		decrypt:       decryptFn,
 	}, nil
 }
 
+// ApplyConfig waits for the remote Alertmanager to be ready and makes sure it's running the given configuration.
+// The configuration is only uploaded if the one in the remote Alertmanager differs from it,
+// which reconciles any drift between Grafana and the remote Alertmanager on startup.
 func (am *Alertmanager) ApplyConfig(ctx context.Context, config *models.AlertConfiguration) error {
-	if am.ready {
+	if !am.ready {
+		if err := am.checkReadiness(ctx); err != nil {
+			return err
+		}
+	}
+
+	return am.compareAndSendConfiguration(ctx, config)
+}
+
+// compareAndSendConfiguration uploads the given configuration to the remote Alertmanager
+// only if its hash differs from the one of the configuration stored remotely.
+func (am *Alertmanager) compareAndSendConfiguration(ctx context.Context, config *models.AlertConfiguration) error {
+	am.configMtx.Lock()
+	defer am.configMtx.Unlock()
+
+	if config.ConfigurationHash != "" && config.ConfigurationHash == am.configHash {
+		return nil
+	}
+
+	remoteCfg, err := am.getConfig(ctx)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return fmt.Errorf("unable to get the remote Alertmanager configuration: %w", err)
+	}
+	if remoteCfg != nil && remoteCfg.Hash == config.ConfigurationHash {
+		am.log.Debug("Remote Alertmanager configuration is up to date", "hash", config.ConfigurationHash)
+		am.configHash = config.ConfigurationHash
 		return nil
 	}
 
-	return am.checkReadiness(ctx)
+	cfg, err := notifier.Load([]byte(config.AlertmanagerConfiguration))
+	if err != nil {
+		return fmt.Errorf("failed to parse Alertmanager config: %w", err)
+	}
+
+	am.log.Debug("Remote Alertmanager configuration differs from the local one, uploading", "hash", config.ConfigurationHash)
+	return am.sendConfiguration(ctx, cfg, config.ConfigurationHash, config.CreatedAt, config.Default)
+}
+
+// sendConfiguration decrypts the secure settings of the given configuration and uploads it to the remote Alertmanager.
+// The caller must hold configMtx.
+func (am *Alertmanager) sendConfiguration(ctx context.Context, cfg *apimodels.PostableUserConfig, hash string, createdAt int64, isDefault bool) error {
+	if err := am.decryptReceivers(ctx, cfg.AlertmanagerConfig.Receivers); err != nil {
+		return err
+	}
+
+	rawConfig, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to serialize the Alertmanager configuration: %w", err)
+	}
+
+	if err := am.createConfig(ctx, &UserGrafanaConfig{
+		GrafanaAlertmanagerConfig: string(rawConfig),
+		Hash:                      hash,
+		CreatedAt:                 createdAt,
+		Default:                   isDefault,
+	}); err != nil {
+		return fmt.Errorf("unable to upload the configuration to the remote Alertmanager: %w", err)
+	}
+
+	am.configHash = hash
+	return nil
+}
+
+// decryptReceivers replaces the encrypted secure settings of the given receivers with their decrypted values.
+func (am *Alertmanager) decryptReceivers(ctx context.Context, receivers []*apimodels.PostableApiReceiver) error {
+	for _, r := range receivers {
+		for _, gr := range r.PostableGrafanaReceivers.GrafanaManagedReceivers {
+			for k, v := range gr.SecureSettings {
+				decoded, err := base64.StdEncoding.DecodeString(v)
+				if err != nil {
+					return fmt.Errorf("failed to decode secure setting %q of receiver %q: %w", k, gr.Name, err)
+				}
+				decrypted, err := am.decrypt(ctx, decoded)
+				if err != nil {
+					return fmt.Errorf("failed to decrypt secure setting %q of receiver %q: %w", k, gr.Name, err)
+				}
+				gr.SecureSettings[k] = string(decrypted)
+			}
+		}
+	}
+	return nil
 }
 
 func (am *Alertmanager) checkReadiness(ctx context.Context) error {
@@ -139,12 +250,44 @@ func (am *Alertmanager) checkReadiness(ctx context.Context) error {
 	}
 }
 
+// SaveAndApplyConfig saves the configuration to the database and uploads it to the remote Alertmanager.
+// It rolls back the save if we fail to upload the configuration.
 func (am *Alertmanager) SaveAndApplyConfig(ctx context.Context, cfg *apimodels.PostableUserConfig) error {
-	return nil
+	rawConfig, err := json.Marshal(&cfg)
+	if err != nil {
+		return fmt.Errorf("failed to serialize to the Alertmanager configuration: %w", err)
+	}
+
+	return am.saveAndSendConfiguration(ctx, string(rawConfig), false)
 }
 
+// SaveAndApplyDefaultConfig saves the default configuration to the database and uploads it to the remote Alertmanager.
+// It rolls back the save if we fail to upload the configuration.
 func (am *Alertmanager) SaveAndApplyDefaultConfig(ctx context.Context) error {
-	return nil
+	return am.saveAndSendConfiguration(ctx, am.defaultConfig, true)
+}
+
+func (am *Alertmanager) saveAndSendConfiguration(ctx context.Context, rawConfig string, isDefault bool) error {
+	am.configMtx.Lock()
+	defer am.configMtx.Unlock()
+
+	now := time.Now().UTC().Unix()
+	cmd := &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: rawConfig,
+		ConfigurationVersion:      fmt.Sprintf("v%d", models.AlertConfigurationVersion),
+		Default:                   isDefault,
+		OrgID:                     am.orgID,
+		LastApplied:               now,
+	}
+
+	return am.store.SaveAlertmanagerConfigurationWithCallback(ctx, cmd, func() error {
+		// The configuration is loaded again so the secure settings we decrypt aren't the ones held by the caller.
+		cfg, err := notifier.Load([]byte(rawConfig))
+		if err != nil {
+			return err
+		}
+		return am.sendConfiguration(ctx, cfg, hashConfig(rawConfig), now, isDefault)
+	})
 }
 
 func (am *Alertmanager) CreateSilence(ctx context.Context, silence *apimodels.PostableSilence) (string, error) {
@@ -260,8 +403,38 @@ func (am *Alertmanager) PutAlerts(ctx context.Context, alerts apimodels.Postable
 	return nil
 }
 
+// GetStatus returns the status of the remote Alertmanager.
+// An empty status is returned if the remote Alertmanager can't be reached.
 func (am *Alertmanager) GetStatus() apimodels.GettableStatus {
-	return apimodels.GettableStatus{}
+	statusURL := strings.TrimSuffix(am.url, "/") + statusPath
+	req, err := http.NewRequest(http.MethodGet, statusURL, nil)
+	if err != nil {
+		am.log.Error("Error creating status request", "err", err)
+		return apimodels.GettableStatus{}
+	}
+
+	res, err := am.httpClient.Do(req)
+	if err != nil {
+		am.log.Error("Error getting the remote Alertmanager status", "err", err)
+		return apimodels.GettableStatus{}
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			am.log.Warn("Error closing response body", "err", err)
+		}
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		am.log.Error("Error getting the remote Alertmanager status", "status", res.StatusCode)
+		return apimodels.GettableStatus{}
+	}
+
+	var status apimodels.GettableStatus
+	if err := json.NewDecoder(res.Body).Decode(&status); err != nil {
+		am.log.Error("Error decoding the remote Alertmanager status", "err", err)
+		return apimodels.GettableStatus{}
+	}
+	return status
 }
 
 func (am *Alertmanager) GetReceivers(ctx context.Context) ([]apimodels.Receiver, error) {
@@ -278,12 +451,72 @@ func (am *Alertmanager) GetReceivers(ctx context.Context) ([]apimodels.Receiver,
 	return rcvs, nil
 }
 
+// TestReceivers sends test notifications through the remote Alertmanager using the given receivers.
 func (am *Alertmanager) TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error) {
-	return &notifier.TestReceiversResult{}, nil
+	if err := am.decryptReceivers(ctx, c.Receivers); err != nil {
+		return nil, err
+	}
+
+	var res apimodels.TestReceiversResult
+	if err := am.doGrafana(ctx, http.MethodPost, grafanaTestReceiversPath, c, &res); err != nil {
+		return nil, fmt.Errorf("unable to test receivers in the remote Alertmanager: %w", err)
+	}
+
+	receivers := make([]notifier.TestReceiverResult, 0, len(res.Receivers))
+	for _, r := range res.Receivers {
+		configs := make([]notifier.TestReceiverConfigResult, 0, len(r.Configs))
+		for _, cfg := range r.Configs {
+			var err error
+			if cfg.Error != "" {
+				err = errors.New(cfg.Error)
+			}
+			configs = append(configs, notifier.TestReceiverConfigResult{
+				Name:   cfg.Name,
+				UID:    cfg.UID,
+				Status: cfg.Status,
+				Error:  err,
+			})
+		}
+		receivers = append(receivers, notifier.TestReceiverResult{
+			Name:    r.Name,
+			Configs: configs,
+		})
+	}
+
+	return &notifier.TestReceiversResult{
+		Alert: types.Alert{
+			Alert: prommodel.Alert{
+				Labels:      res.Alert.Labels,
+				Annotations: res.Alert.Annotations,
+			},
+		},
+		Receivers: receivers,
+		NotifedAt: res.NotifiedAt,
+	}, nil
 }
 
+// TestTemplate renders the given template in the remote Alertmanager.
 func (am *Alertmanager) TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*notifier.TestTemplatesResults, error) {
-	return &notifier.TestTemplatesResults{}, nil
+	var res apimodels.TestTemplatesResults
+	if err := am.doGrafana(ctx, http.MethodPost, grafanaTestTemplatesPath, c, &res); err != nil {
+		return nil, fmt.Errorf("unable to test template in the remote Alertmanager: %w", err)
+	}
+
+	results := &notifier.TestTemplatesResults{}
+	for _, r := range res.Results {
+		results.Results = append(results.Results, alertingNotify.TestTemplatesResult{
+			Name: r.Name,
+			Text: r.Text,
+		})
+	}
+	for _, e := range res.Errors {
+		results.Errors = append(results.Errors, alertingNotify.TestTemplatesErrorResult{
+			Name:  e.Name,
+			Kind:  alertingNotify.TemplateErrorKind(e.Kind),
+			Error: errors.New(e.Message),
+		})
+	}
+	return results, nil
 }
 
 func (am *Alertmanager) StopAndWait() {
@@ -297,6 +530,10 @@ func (am *Alertmanager) Ready() bool {
 // We don't have files on disk, no-op.
 func (am *Alertmanager) CleanUp() {}
 
+func hashConfig(rawConfig string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(rawConfig)))
+}
+
 type roundTripper struct {
 	tenantID          string
 	basicAuthPassword string
@@ -2,18 +2,30 @@ package remote
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/go-openapi/strfmt"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	prommodel "github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 )
 
+// Valid Grafana config with a secure setting encrypted with fakeDecrypt ("c2VjcmV0" is "secret" in base64).
+const grafanaConfig = `{"template_files":null,"alertmanager_config":{"route":{"receiver":"slack"},"templates":null,"receivers":[{"name":"slack","grafana_managed_receiver_configs":[{"uid":"abc","name":"slack","type":"slack","disableResolveMessage":false,"settings":{"recipient":"#alerts"},"secureSettings":{"token":"c2VjcmV0"}}]}]}}`
+
 // Valid config for Cloud AM, no `grafana_managed_receievers` field.
 const upstreamConfig = `{"template_files": {}, "alertmanager_config": "{\"global\": {\"smtp_from\": \"test@test.com\"}, \"route\": {\"receiver\": \"discord\"}, \"receivers\": [{\"name\": \"discord\", \"discord_configs\": [{\"webhook_url\": \"http://localhost:1234\"}]}]}"}`
 
@@ -50,7 +62,7 @@ func TestNewAlertmanager(t *testing.T) {
 				TenantID:          test.tenantID,
 				BasicAuthPassword: test.password,
 			}
-			am, err := NewAlertmanager(cfg, test.orgID)
+			am, err := NewAlertmanager(cfg, test.orgID, nil, nil)
 			if test.expErr != "" {
 				require.EqualError(tt, err, test.expErr)
 				return
@@ -66,6 +78,237 @@ func TestNewAlertmanager(t *testing.T) {
 	}
 }
 
+func TestApplyConfig(t *testing.T) {
+	hash := fmt.Sprintf("%x", md5.Sum([]byte(grafanaConfig)))
+	dbCfg := &models.AlertConfiguration{
+		AlertmanagerConfiguration: grafanaConfig,
+		ConfigurationHash:         hash,
+		OrgID:                     1,
+	}
+
+	t.Run("uploads the configuration if it's not in the remote Alertmanager", func(t *testing.T) {
+		srv := newFakeRemoteAlertmanager(t, nil)
+		am := newTestAlertmanager(t, srv.URL)
+
+		require.NoError(t, am.ApplyConfig(context.Background(), dbCfg))
+		require.True(t, am.Ready())
+		require.Len(t, srv.uploaded, 1)
+		require.Equal(t, hash, srv.uploaded[0].Hash)
+
+		// Secure settings should be decrypted.
+		cfg, err := notifier.Load([]byte(srv.uploaded[0].GrafanaAlertmanagerConfig))
+		require.NoError(t, err)
+		require.Equal(t, "secret", cfg.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].SecureSettings["token"])
+
+		// Applying the same configuration again should not upload it.
+		require.NoError(t, am.ApplyConfig(context.Background(), dbCfg))
+		require.Len(t, srv.uploaded, 1)
+	})
+
+	t.Run("does not upload the configuration if the remote one has the same hash", func(t *testing.T) {
+		srv := newFakeRemoteAlertmanager(t, &UserGrafanaConfig{Hash: hash})
+		am := newTestAlertmanager(t, srv.URL)
+		am.ready = true
+
+		require.NoError(t, am.ApplyConfig(context.Background(), dbCfg))
+		require.Empty(t, srv.uploaded)
+	})
+
+	t.Run("uploads the configuration if the remote one drifted", func(t *testing.T) {
+		srv := newFakeRemoteAlertmanager(t, &UserGrafanaConfig{Hash: "drifted"})
+		am := newTestAlertmanager(t, srv.URL)
+		am.ready = true
+
+		require.NoError(t, am.ApplyConfig(context.Background(), dbCfg))
+		require.Len(t, srv.uploaded, 1)
+		require.Equal(t, hash, srv.uploaded[0].Hash)
+	})
+
+	t.Run("returns an error if the remote Alertmanager rejects the configuration", func(t *testing.T) {
+		srv := newFakeRemoteAlertmanager(t, nil)
+		srv.rejectConfig = true
+		am := newTestAlertmanager(t, srv.URL)
+		am.ready = true
+
+		require.ErrorContains(t, am.ApplyConfig(context.Background(), dbCfg), "invalid configuration")
+	})
+}
+
+func TestSaveAndApplyConfig(t *testing.T) {
+	t.Run("saves and uploads the configuration", func(t *testing.T) {
+		srv := newFakeRemoteAlertmanager(t, nil)
+		am := newTestAlertmanager(t, srv.URL)
+
+		cfg, err := notifier.Load([]byte(grafanaConfig))
+		require.NoError(t, err)
+		require.NoError(t, am.SaveAndApplyConfig(context.Background(), cfg))
+
+		saved, err := am.store.GetLatestAlertmanagerConfiguration(context.Background(), &models.GetLatestAlertmanagerConfigurationQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, srv.uploaded, 1)
+		require.Equal(t, saved.ConfigurationHash, srv.uploaded[0].Hash)
+		require.False(t, srv.uploaded[0].Default)
+
+		// The configuration held by the caller and the one saved should still have encrypted secure settings.
+		require.Equal(t, "c2VjcmV0", cfg.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].SecureSettings["token"])
+		require.Contains(t, saved.AlertmanagerConfiguration, "c2VjcmV0")
+	})
+
+	t.Run("saves and uploads the default configuration", func(t *testing.T) {
+		srv := newFakeRemoteAlertmanager(t, nil)
+		am := newTestAlertmanager(t, srv.URL)
+
+		require.NoError(t, am.SaveAndApplyDefaultConfig(context.Background()))
+		require.Len(t, srv.uploaded, 1)
+		require.True(t, srv.uploaded[0].Default)
+	})
+
+	t.Run("does not save the configuration if the upload fails", func(t *testing.T) {
+		srv := newFakeRemoteAlertmanager(t, nil)
+		srv.rejectConfig = true
+		am := newTestAlertmanager(t, srv.URL)
+
+		cfg, err := notifier.Load([]byte(grafanaConfig))
+		require.NoError(t, err)
+		require.Error(t, am.SaveAndApplyConfig(context.Background(), cfg))
+	})
+}
+
+func TestGetStatus(t *testing.T) {
+	srv := newFakeRemoteAlertmanager(t, nil)
+	am := newTestAlertmanager(t, srv.URL)
+
+	status := am.GetStatus()
+	require.NotNil(t, status.Config)
+	require.Equal(t, "discord", status.Config.Route.Receiver)
+	require.NotNil(t, status.VersionInfo)
+	require.Equal(t, "0.26.0", *status.VersionInfo.Version)
+}
+
+func TestTestReceiversAndTemplates(t *testing.T) {
+	srv := newFakeRemoteAlertmanager(t, nil)
+	am := newTestAlertmanager(t, srv.URL)
+
+	cfg, err := notifier.Load([]byte(grafanaConfig))
+	require.NoError(t, err)
+
+	res, err := am.TestReceivers(context.Background(), apimodels.TestReceiversConfigBodyParams{
+		Receivers: cfg.AlertmanagerConfig.Receivers,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "secret", srv.testedReceivers.Receivers[0].GrafanaManagedReceivers[0].SecureSettings["token"])
+	require.Len(t, res.Receivers, 1)
+	require.Equal(t, "slack", res.Receivers[0].Name)
+	require.EqualError(t, res.Receivers[0].Configs[0].Error, "failed to send notification")
+	require.Equal(t, "test", string(res.Alert.Labels["alertname"]))
+
+	tmplRes, err := am.TestTemplate(context.Background(), apimodels.TestTemplatesConfigBodyParams{
+		Name:     "test",
+		Template: `{{ define "test" }}hello{{ end }}`,
+	})
+	require.NoError(t, err)
+	require.Len(t, tmplRes.Results, 1)
+	require.Equal(t, "hello", tmplRes.Results[0].Text)
+}
+
+// fakeRemoteAlertmanager is an httptest server implementing the endpoints of the remote Alertmanager.
+type fakeRemoteAlertmanager struct {
+	*httptest.Server
+
+	config          *UserGrafanaConfig
+	uploaded        []UserGrafanaConfig
+	rejectConfig    bool
+	testedReceivers apimodels.TestReceiversConfigBodyParams
+}
+
+func newFakeRemoteAlertmanager(t *testing.T, cfg *UserGrafanaConfig) *fakeRemoteAlertmanager {
+	t.Helper()
+
+	f := &fakeRemoteAlertmanager{config: cfg}
+	writeData := func(w http.ResponseWriter, data any) {
+		b, err := json.Marshal(data)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(w).Encode(mimirResponse{Status: "success", Data: b}))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/alertmanager/-/ready", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/alertmanager/api/v2/status", func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"cluster":{"status":"ready"},"config":{"original":"route:\n  receiver: discord\nreceivers:\n- name: discord\n"},"uptime":"2023-01-01T00:00:00.000Z","versionInfo":{"branch":"main","buildDate":"","buildUser":"","goVersion":"go1.21","revision":"","version":"0.26.0"}}`))
+		require.NoError(t, err)
+	})
+	mux.HandleFunc("/api/v1/grafana/config", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if f.config == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			writeData(w, f.config)
+		case http.MethodPost:
+			if f.rejectConfig {
+				w.WriteHeader(http.StatusBadRequest)
+				require.NoError(t, json.NewEncoder(w).Encode(mimirResponse{Status: "error", Error: "invalid configuration"}))
+				return
+			}
+			var cfg UserGrafanaConfig
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&cfg))
+			f.uploaded = append(f.uploaded, cfg)
+			f.config = &cfg
+			writeData(w, nil)
+		}
+	})
+	mux.HandleFunc("/api/v1/grafana/receivers/test", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&f.testedReceivers))
+		writeData(w, apimodels.TestReceiversResult{
+			Alert: apimodels.TestReceiversConfigAlertParams{
+				Labels: prommodel.LabelSet{"alertname": "test"},
+			},
+			Receivers: []apimodels.TestReceiverResult{{
+				Name: "slack",
+				Configs: []apimodels.TestReceiverConfigResult{{
+					Name:   "slack",
+					UID:    "abc",
+					Status: "failed",
+					Error:  "failed to send notification",
+				}},
+			}},
+			NotifiedAt: time.Now(),
+		})
+	})
+	mux.HandleFunc("/api/v1/grafana/templates/test", func(w http.ResponseWriter, r *http.Request) {
+		writeData(w, apimodels.TestTemplatesResults{
+			Results: []apimodels.TestTemplatesResult{{Name: "test", Text: "hello"}},
+		})
+	})
+
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func newTestAlertmanager(t *testing.T, url string) *Alertmanager {
+	t.Helper()
+
+	cfg := AlertmanagerConfig{
+		URL:           url + "/alertmanager",
+		TenantID:      "1",
+		DefaultConfig: setting.GetAlertmanagerDefaultConfiguration(),
+	}
+	store := notifier.NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{})
+	am, err := NewAlertmanager(cfg, 1, store, fakeDecrypt)
+	require.NoError(t, err)
+	t.Cleanup(am.StopAndWait)
+	return am
+}
+
+// fakeDecrypt returns the payload as is.
+func fakeDecrypt(_ context.Context, payload []byte) ([]byte, error) {
+	return payload, nil
+}
+
 func TestIntegrationRemoteAlertmanagerSilences(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -83,7 +326,7 @@ func TestIntegrationRemoteAlertmanagerSilences(t *testing.T) {
 		TenantID:          tenantID,
 		BasicAuthPassword: password,
 	}
-	am, err := NewAlertmanager(cfg, 1)
+	am, err := NewAlertmanager(cfg, 1, nil, nil)
 	require.NoError(t, err)
 
 	// We should have no silences at first.
@@ -162,7 +405,7 @@ func TestIntegrationRemoteAlertmanagerAlerts(t *testing.T) {
 		TenantID:          tenantID,
 		BasicAuthPassword: password,
 	}
-	am, err := NewAlertmanager(cfg, 1)
+	am, err := NewAlertmanager(cfg, 1, nil, nil)
 	require.NoError(t, err)
 
 	// Wait until the Alertmanager is ready to send alerts.
@@ -224,7 +467,7 @@ func TestIntegrationRemoteAlertmanagerReceivers(t *testing.T) {
 		BasicAuthPassword: password,
 	}
 
-	am, err := NewAlertmanager(cfg, 1)
+	am, err := NewAlertmanager(cfg, 1, nil, nil)
 	require.NoError(t, err)
 
 	// We should start with the default config.
@@ -0,0 +1,120 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	grafanaAlertmanagerConfigPath = "/api/v1/grafana/config"
+	grafanaTestReceiversPath      = "/api/v1/grafana/receivers/test"
+	grafanaTestTemplatesPath      = "/api/v1/grafana/templates/test"
+	statusPath                    = "/api/v2/status"
+)
+
+var errNotFound = errors.New("not found in the remote Alertmanager")
+
+// UserGrafanaConfig is the Grafana Alertmanager configuration as stored in the remote Alertmanager.
+type UserGrafanaConfig struct {
+	GrafanaAlertmanagerConfig string `json:"configuration"`
+	Hash                      string `json:"configuration_hash"`
+	CreatedAt                 int64  `json:"created"`
+	Default                   bool   `json:"default"`
+}
+
+// mimirResponse is the envelope used by the remote Alertmanager for its Grafana-specific endpoints.
+type mimirResponse struct {
+	Status string          `json:"status"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// getConfig fetches the Grafana Alertmanager configuration currently stored in the remote Alertmanager.
+// It returns errNotFound if the remote Alertmanager doesn't have a configuration for the tenant.
+func (am *Alertmanager) getConfig(ctx context.Context) (*UserGrafanaConfig, error) {
+	var cfg UserGrafanaConfig
+	if err := am.doGrafana(ctx, http.MethodGet, grafanaAlertmanagerConfigPath, nil, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// createConfig uploads a Grafana Alertmanager configuration to the remote Alertmanager.
+func (am *Alertmanager) createConfig(ctx context.Context, cfg *UserGrafanaConfig) error {
+	return am.doGrafana(ctx, http.MethodPost, grafanaAlertmanagerConfigPath, cfg, nil)
+}
+
+// doGrafana performs a request against one of the Grafana-specific endpoints of the remote Alertmanager.
+// These endpoints live outside the Alertmanager API prefix, so the "/alertmanager" suffix is removed from the URL.
+func (am *Alertmanager) doGrafana(ctx context.Context, method, path string, payload, out any) error {
+	u := strings.TrimSuffix(strings.TrimSuffix(am.url, "/"), "/alertmanager") + path
+	return am.do(ctx, method, u, payload, out)
+}
+
+func (am *Alertmanager) do(ctx context.Context, method, u string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := am.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			am.log.Warn("Error while closing body", "err", err)
+		}
+	}()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+
+	var envelope mimirResponse
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		if res.StatusCode/100 != 2 {
+			return fmt.Errorf("request failed with status code %d: %s", res.StatusCode, string(b))
+		}
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if res.StatusCode/100 != 2 || envelope.Status == "error" {
+		if envelope.Error != "" {
+			return fmt.Errorf("request failed with status code %d: %s", res.StatusCode, envelope.Error)
+		}
+		return fmt.Errorf("request failed with status code %d", res.StatusCode)
+	}
+
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// JitterStrategy determines how the evaluations of rules with the same interval are spread across that interval.
+type JitterStrategy string
+
+const (
+	// JitterNever evaluates all rules with the same interval on the same tick.
+	JitterNever JitterStrategy = "none"
+	// JitterByGroup evaluates all rules of a group on the same tick, but spreads groups across the interval.
+	JitterByGroup JitterStrategy = "group"
+	// JitterByRule spreads every rule across the interval, regardless of its group.
+	JitterByRule JitterStrategy = "rule"
+)
+
+// ParseJitterStrategy parses the jitter strategy from its configuration value. An empty value means JitterNever.
+func ParseJitterStrategy(s string) (JitterStrategy, error) {
+	norm := JitterStrategy(strings.ToLower(strings.TrimSpace(s)))
+	switch norm {
+	case "":
+		return JitterNever, nil
+	case JitterNever, JitterByGroup, JitterByRule:
+		return norm, nil
+	default:
+		return "", fmt.Errorf("unrecognized jitter strategy: %s", s)
+	}
+}
+
+// jitterOffsetInTicks returns the number of ticks, between 0 and the number of ticks in the rule's interval,
+// by which the evaluation of the rule is delayed. The offset is deterministic, so a rule keeps being evaluated
+// on the same ticks as long as its interval and group don't change.
+func jitterOffsetInTicks(r *ngmodels.AlertRule, baseInterval time.Duration, strategy JitterStrategy) int64 {
+	if strategy == JitterNever || strategy == "" {
+		return 0
+	}
+
+	itemFrequency := r.IntervalSeconds / int64(baseInterval.Seconds())
+	if itemFrequency <= 1 {
+		return 0
+	}
+
+	return int64(jitterHash(r, strategy) % uint64(itemFrequency))
+}
+
+func jitterHash(r *ngmodels.AlertRule, strategy JitterStrategy) uint64 {
+	h := fnv.New64a()
+	// fnv never returns errors on write.
+	_, _ = fmt.Fprintf(h, "%d\x00%s\x00%s", r.OrgID, r.NamespaceUID, r.RuleGroup)
+	if strategy == JitterByRule {
+		_, _ = fmt.Fprintf(h, "\x00%s", r.UID)
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,115 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestParseJitterStrategy(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected JitterStrategy
+		expErr   bool
+	}{
+		{in: "", expected: JitterNever},
+		{in: "none", expected: JitterNever},
+		{in: " Group ", expected: JitterByGroup},
+		{in: "rule", expected: JitterByRule},
+		{in: "random", expErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			s, err := ParseJitterStrategy(tc.in)
+			if tc.expErr {
+				require.ErrorContains(t, err, "unrecognized jitter strategy")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, s)
+		})
+	}
+}
+
+func TestJitterOffsetInTicks(t *testing.T) {
+	baseInterval := 10 * time.Second
+	interval := time.Minute
+	gen := models.AlertRuleGen(models.WithInterval(interval))
+
+	t.Run("no jitter is applied with the none strategy", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			require.Zero(t, jitterOffsetInTicks(gen(), baseInterval, JitterNever))
+		}
+	})
+
+	t.Run("no jitter is applied to rules evaluated every tick", func(t *testing.T) {
+		r := models.AlertRuleGen(models.WithInterval(baseInterval))()
+		require.Zero(t, jitterOffsetInTicks(r, baseInterval, JitterByRule))
+	})
+
+	t.Run("offset is deterministic and within the rule interval", func(t *testing.T) {
+		for _, strategy := range []JitterStrategy{JitterByGroup, JitterByRule} {
+			seen := map[int64]struct{}{}
+			for i := 0; i < 100; i++ {
+				r := gen()
+				offset := jitterOffsetInTicks(r, baseInterval, strategy)
+				require.GreaterOrEqual(t, offset, int64(0))
+				require.Less(t, offset, int64(interval/baseInterval))
+				require.Equal(t, offset, jitterOffsetInTicks(models.CopyRule(r), baseInterval, strategy))
+				seen[offset] = struct{}{}
+			}
+			require.Greater(t, len(seen), 1, "rules should be spread across the interval")
+		}
+	})
+
+	t.Run("rules of the same group share the offset with the group strategy", func(t *testing.T) {
+		r1 := gen()
+		r2 := models.CopyRule(r1)
+		r2.UID = "another-uid"
+		require.Equal(t, jitterOffsetInTicks(r1, baseInterval, JitterByGroup), jitterOffsetInTicks(r2, baseInterval, JitterByGroup))
+	})
+}
+
+func TestProcessTicksWithJitter(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	sched := setupScheduler(t, ruleStore, nil, reg, nil, eval_mocks.NewFailingEvaluatorFactory(nil))
+	sched.jitterStrategy = JitterByRule
+	baseInterval := sched.baseInterval
+
+	const ticksPerInterval = 10
+	rules := models.GenerateAlertRules(50, models.AlertRuleGen(models.WithInterval(ticksPerInterval*baseInterval)))
+	ruleStore.PutRule(ctx, rules...)
+
+	evaluated := map[models.AlertRuleKey]int64{}
+	maxPerTick := 0
+	tick := time.Unix(0, 0)
+	for i := int64(0); i < ticksPerInterval; i++ {
+		scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		for _, s := range scheduled {
+			_, ok := evaluated[s.rule.GetKey()]
+			require.False(t, ok, "rule should be evaluated only once per interval")
+			evaluated[s.rule.GetKey()] = i
+			require.Equal(t, jitterOffsetInTicks(s.rule, baseInterval, JitterByRule), i)
+		}
+		if len(scheduled) > maxPerTick {
+			maxPerTick = len(scheduled)
+		}
+		require.Equal(t, float64(len(scheduled)), testutil.ToFloat64(sched.metrics.TickEvaluations))
+		tick = tick.Add(baseInterval)
+	}
+
+	require.Len(t, evaluated, len(rules))
+	require.Less(t, maxPerTick, len(rules), "evaluations should be spread across ticks")
+}
@@ -329,6 +329,10 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeLabels(rule.Labels)
 	writeString(rule.Condition)
 	writeQuery()
+	if rule.Record != nil {
+		writeString(rule.Record.Metric)
+		writeString(rule.Record.From)
+	}
 
 	if rule.IsPaused {
 		writeInt(1)
@@ -342,6 +346,7 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(rule.OrgID)
 	writeInt(rule.IntervalSeconds)
 	writeInt(int64(rule.For))
+	writeInt(int64(rule.KeepFiringFor))
 	writeLabels(rule.Annotations)
 	if rule.DashboardUID != nil {
 		writeString(*rule.DashboardUID)
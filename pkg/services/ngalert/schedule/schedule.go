@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -22,6 +24,7 @@ import (
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util/ticker"
 )
@@ -41,6 +44,11 @@ type AlertsSender interface {
 	Send(ctx context.Context, key ngmodels.AlertRuleKey, alerts definitions.PostableAlerts)
 }
 
+// RecordingWriter is an interface for a service that writes the results of recording rules.
+type RecordingWriter interface {
+	Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error
+}
+
 // RulesStore is a store that provides alert rules for scheduling
 type RulesStore interface {
 	GetAlertRulesKeysForScheduling(ctx context.Context) ([]ngmodels.AlertRuleKeyWithVersion, error)
@@ -82,7 +90,9 @@ type schedule struct {
 	metrics *metrics.Scheduler
 
 	alertsSender    AlertsSender
+	recordingWriter RecordingWriter
 	minRuleInterval time.Duration
+	jitterStrategy  JitterStrategy
 
 	// schedulableAlertRules contains the alert rules that are considered for
 	// evaluation in the current tick. The evaluation of an alert rule in the
@@ -105,6 +115,8 @@ type SchedulerCfg struct {
 	RuleStore            RulesStore
 	Metrics              *metrics.Scheduler
 	AlertSender          AlertsSender
+	RecordingWriter      RecordingWriter
+	JitterStrategy       JitterStrategy
 	Tracer               tracing.Tracer
 	Log                  log.Logger
 }
@@ -124,8 +136,10 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		disableGrafanaFolder:  cfg.DisableGrafanaFolder,
 		stateManager:          stateManager,
 		minRuleInterval:       cfg.MinRuleInterval,
+		jitterStrategy:        cfg.JitterStrategy,
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
+		recordingWriter:       cfg.RecordingWriter,
 		tracer:                cfg.Tracer,
 	}
 
@@ -271,7 +285,8 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
-		isReadyToRun := item.IntervalSeconds != 0 && tickNum%itemFrequency == 0
+		offset := jitterOffsetInTicks(item, sch.baseInterval, sch.jitterStrategy)
+		isReadyToRun := item.IntervalSeconds != 0 && tickNum%itemFrequency == offset
 
 		var folderTitle string
 		if !sch.disableGrafanaFolder {
@@ -313,6 +328,9 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.log.Warn("Unable to obtain folder titles for some rules", "missingFolderUIDToRuleUID", missingFolder)
 	}
 
+	sch.metrics.EvaluationsPerTick.Observe(float64(len(readyToRun)))
+	sch.metrics.TickEvaluations.Set(float64(len(readyToRun)))
+
 	var step int64 = 0
 	if len(readyToRun) > 0 {
 		step = sch.baseInterval.Nanoseconds() / int64(len(readyToRun))
@@ -354,6 +372,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	evalTotal := sch.metrics.EvalTotal.WithLabelValues(orgID)
 	evalDuration := sch.metrics.EvalDuration.WithLabelValues(orgID)
 	evalTotalFailures := sch.metrics.EvalFailures.WithLabelValues(orgID)
+	evalThrottled := sch.metrics.EvalThrottled.WithLabelValues(orgID)
 	processDuration := sch.metrics.ProcessDuration.WithLabelValues(orgID)
 	sendDuration := sch.metrics.SendDuration.WithLabelValues(orgID)
 
@@ -364,6 +383,19 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		}
 	}
 
+	// throttled reports whether the evaluator could not be created because the org reached its rate quota
+	// of rule evaluations. The evaluation is then skipped, and the rule keeps its current state.
+	throttled := func(logger log.Logger, err error, span trace.Span) bool {
+		var rateErr *quota.RateLimitedError
+		if !errors.As(err, &rateErr) {
+			return false
+		}
+		evalThrottled.Inc()
+		logger.Warn("Skip the rule evaluation because the org reached its rate quota of rule evaluations", "retryAfter", rateErr.RetryAfter)
+		span.AddEvent("rule evaluation throttled")
+		return true
+	}
+
 	resetState := func(ctx context.Context, isPaused bool) {
 		rule := sch.schedulableAlertRules.get(key)
 		reason := ngmodels.StateReasonUpdated
@@ -374,12 +406,68 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		notify(states)
 	}
 
+	record := func(ctx context.Context, f fingerprint, attempt int64, e *evaluation, span trace.Span) {
+		logger := logger.New("version", e.rule.Version, "fingerprint", f, "attempt", attempt, "now", e.scheduledAt, "metric", e.rule.Record.Metric).FromContext(ctx)
+		start := sch.clock.Now()
+
+		evalCtx := eval.NewContext(ctx, SchedulerUserFor(e.rule.OrgID))
+		ruleEval, err := sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
+		if throttled(logger, err, span) {
+			return
+		}
+		var frames data.Frames
+		if err == nil {
+			var resp *backend.QueryDataResponse
+			resp, err = ruleEval.EvaluateRaw(ctx, e.scheduledAt)
+			if err == nil {
+				frames, err = recordingResultFrames(resp, e.rule.Record.From)
+			}
+		}
+		dur := sch.clock.Now().Sub(start)
+
+		evalTotal.Inc()
+		evalDuration.Observe(dur.Seconds())
+
+		if err != nil {
+			evalTotalFailures.Inc()
+			logger.Error("Failed to evaluate recording rule", "error", err, "duration", dur)
+			span.SetStatus(codes.Error, "rule evaluation failed")
+			span.RecordError(err)
+			return
+		}
+		logger.Debug("Recording rule evaluated", "frames", len(frames), "duration", dur)
+		span.AddEvent("rule evaluated", trace.WithAttributes(
+			attribute.Int64("frames", int64(len(frames))),
+		))
+		if ctx.Err() != nil { // check if the context is not cancelled. The evaluation can be a long-running task.
+			logger.Debug("Skip writing the results because the context has been cancelled")
+			return
+		}
+
+		start = sch.clock.Now()
+		if err := sch.recordingWriter.Write(ctx, e.rule.Record.Metric, e.scheduledAt, frames, e.rule.Labels); err != nil {
+			logger.Error("Failed to write the results of recording rule", "error", err)
+			span.SetStatus(codes.Error, "failed to write the results")
+			span.RecordError(err)
+		} else {
+			span.AddEvent("results written")
+		}
+		sendDuration.Observe(sch.clock.Now().Sub(start).Seconds())
+	}
+
 	evaluate := func(ctx context.Context, f fingerprint, attempt int64, e *evaluation, span trace.Span) {
+		if e.rule.Type() == ngmodels.RuleTypeRecording {
+			record(ctx, f, attempt, e, span)
+			return
+		}
 		logger := logger.New("version", e.rule.Version, "fingerprint", f, "attempt", attempt, "now", e.scheduledAt).FromContext(ctx)
 		start := sch.clock.Now()
 
 		evalCtx := eval.NewContext(ctx, SchedulerUserFor(e.rule.OrgID))
 		ruleEval, err := sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
+		if throttled(logger, err, span) {
+			return
+		}
 		var results eval.Results
 		var dur time.Duration
 		if err != nil {
@@ -544,6 +632,21 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	}
 }
 
+// recordingResultFrames returns the frames of the query or expression with the given RefID.
+func recordingResultFrames(resp *backend.QueryDataResponse, refID string) (data.Frames, error) {
+	if resp == nil {
+		return nil, fmt.Errorf("no response for query or expression %s", refID)
+	}
+	res, ok := resp.Responses[refID]
+	if !ok {
+		return nil, fmt.Errorf("no response for query or expression %s", refID)
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("query or expression %s failed: %w", refID, res.Error)
+	}
+	return res.Frames, nil
+}
+
 // evalApplied is only used on tests.
 func (sch *schedule) evalApplied(alertDefKey ngmodels.AlertRuleKey, now time.Time) {
 	if sch.evalAppliedFunc == nil {
@@ -30,7 +30,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -819,6 +822,145 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 
 		require.NotEmpty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
 	})
+
+	t.Run("when the org reached its rate quota of evaluations", func(t *testing.T) {
+		rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting))()
+
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+
+		sender := AlertsSenderMock{}
+		sender.EXPECT().Send(mock.Anything, rule.GetKey(), mock.Anything).Return()
+
+		sch, ruleStore, _, reg := createSchedule(evalAppliedChan, &sender)
+		rateLimited := quota.NewRateLimitedError(quota.RateTargetAlertRuleEvaluations, quota.OrgScope, time.Second)
+		sch.evaluatorFactory = eval.NewRateLimitedEvaluatorFactory(sch.evaluatorFactory, &quotatest.FakeQuotaService{ExpectedRateError: rateLimited})
+		ruleStore.PutRule(context.Background(), rule)
+
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersionAndPauseStatus))
+		}()
+
+		evalChan <- &evaluation{
+			scheduledAt: sch.clock.Now(),
+			rule:        rule,
+		}
+
+		waitForTimeChannel(t, evalAppliedChan)
+
+		t.Run("it should skip the evaluation and keep the state", func(t *testing.T) {
+			sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		})
+
+		t.Run("it should increase the throttled counter", func(t *testing.T) {
+			expectedMetric := fmt.Sprintf(
+				`# HELP grafana_alerting_rule_evaluations_throttled_total The total number of rule evaluations skipped because the org reached its rate quota of rule evaluations.
+				# TYPE grafana_alerting_rule_evaluations_throttled_total counter
+				grafana_alerting_rule_evaluations_throttled_total{org="%[1]d"} 1
+				# HELP grafana_alerting_rule_evaluation_failures_total The total number of rule evaluation failures.
+				# TYPE grafana_alerting_rule_evaluation_failures_total counter
+				grafana_alerting_rule_evaluation_failures_total{org="%[1]d"} 0
+				`, rule.OrgID)
+
+			err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric), "grafana_alerting_rule_evaluations_throttled_total", "grafana_alerting_rule_evaluation_failures_total")
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("when rule is a recording rule", func(t *testing.T) {
+		rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting), models.WithRecord("test_metric"), models.WithLabel("team", "alerting"))()
+
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+
+		sender := AlertsSenderMock{}
+		sender.EXPECT().Send(mock.Anything, rule.GetKey(), mock.Anything).Return()
+
+		sch, ruleStore, _, reg := createSchedule(evalAppliedChan, &sender)
+		fakeWriter := &writer.FakeWriter{}
+		sch.recordingWriter = fakeWriter
+		ruleStore.PutRule(context.Background(), rule)
+
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersionAndPauseStatus))
+		}()
+
+		scheduledAt := sch.clock.Now()
+		evalChan <- &evaluation{
+			scheduledAt: scheduledAt,
+			rule:        rule,
+		}
+
+		waitForTimeChannel(t, evalAppliedChan)
+
+		t.Run("it should write the result", func(t *testing.T) {
+			points := fakeWriter.WrittenPoints()
+			require.Len(t, points, 1)
+			assert.Equal(t, "test_metric", points[0].Name)
+			assert.Equal(t, "alerting", points[0].Labels["team"])
+			assert.Equal(t, scheduledAt, points[0].Metric.T)
+			assert.Equal(t, float64(1), points[0].Metric.V)
+		})
+
+		t.Run("it should not send alerts or create state", func(t *testing.T) {
+			sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		})
+
+		t.Run("it reports metrics", func(t *testing.T) {
+			expectedMetric := fmt.Sprintf(
+				`# HELP grafana_alerting_rule_evaluations_total The total number of rule evaluations.
+        	            	# TYPE grafana_alerting_rule_evaluations_total counter
+        	            	grafana_alerting_rule_evaluations_total{org="%[1]d"} 1
+				# HELP grafana_alerting_rule_evaluation_failures_total The total number of rule evaluation failures.
+        	            	# TYPE grafana_alerting_rule_evaluation_failures_total counter
+        	            	grafana_alerting_rule_evaluation_failures_total{org="%[1]d"} 0
+				`, rule.OrgID)
+
+			err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric), "grafana_alerting_rule_evaluations_total", "grafana_alerting_rule_evaluation_failures_total")
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("when recording rule evaluation fails", func(t *testing.T) {
+		rule := models.AlertRuleGen(withQueryForState(t, eval.Error), models.WithRecord("test_metric"))()
+
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+
+		sch, ruleStore, _, reg := createSchedule(evalAppliedChan, nil)
+		fakeWriter := &writer.FakeWriter{}
+		sch.recordingWriter = fakeWriter
+		ruleStore.PutRule(context.Background(), rule)
+
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersionAndPauseStatus))
+		}()
+
+		evalChan <- &evaluation{
+			scheduledAt: sch.clock.Now(),
+			rule:        rule,
+		}
+
+		waitForTimeChannel(t, evalAppliedChan)
+
+		require.Empty(t, fakeWriter.WrittenPoints())
+
+		expectedMetric := fmt.Sprintf(
+			`# HELP grafana_alerting_rule_evaluation_failures_total The total number of rule evaluation failures.
+        	            	# TYPE grafana_alerting_rule_evaluation_failures_total counter
+        	            	grafana_alerting_rule_evaluation_failures_total{org="%[1]d"} 1
+				`, rule.OrgID)
+		err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric), "grafana_alerting_rule_evaluation_failures_total")
+		require.NoError(t, err)
+	})
 }
 
 func TestSchedule_deleteAlertRule(t *testing.T) {
@@ -890,6 +1032,7 @@ func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStor
 		RuleStore:        rs,
 		Metrics:          m.GetSchedulerMetrics(),
 		AlertSender:      senderMock,
+		RecordingWriter:  writer.NoopWriter{},
 		Tracer:           testTracer,
 		Log:              log.New("ngalert.scheduler"),
 	}
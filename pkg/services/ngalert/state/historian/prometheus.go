@@ -0,0 +1,185 @@
+package historian
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/weaveworks/common/http/client"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// DefaultPrometheusMetricName is the name of the series written by the Prometheus backend, unless configured otherwise.
+	DefaultPrometheusMetricName = "GRAFANA_ALERTS"
+	// AlertStateLabel contains the state of the alert instance the series belongs to.
+	AlertStateLabel = "grafana_alertstate"
+	// AlertRuleUIDLabel contains the UID of the rule the series belongs to.
+	AlertRuleUIDLabel = "grafana_rule_uid"
+)
+
+// ErrQueryNotSupported is returned when querying a backend that can only be written to.
+var ErrQueryNotSupported = errors.New("the state history backend does not support queries")
+
+type PrometheusConfig struct {
+	Writer         writer.PrometheusWriterConfig
+	MetricName     string
+	ExternalLabels map[string]string
+}
+
+func NewPrometheusConfig(cfg setting.UnifiedAlertingStateHistorySettings) (PrometheusConfig, error) {
+	if cfg.PrometheusRemoteWriteURL == "" {
+		return PrometheusConfig{}, fmt.Errorf("prometheus remote write URL must be provided")
+	}
+	u, err := url.Parse(cfg.PrometheusRemoteWriteURL)
+	if err != nil {
+		return PrometheusConfig{}, fmt.Errorf("failed to parse prometheus remote write URL: %w", err)
+	}
+
+	name := cfg.PrometheusMetricName
+	if name == "" {
+		name = DefaultPrometheusMetricName
+	}
+
+	return PrometheusConfig{
+		Writer: writer.PrometheusWriterConfig{
+			URL:               u,
+			BasicAuthUsername: cfg.PrometheusBasicAuthUsername,
+			BasicAuthPassword: cfg.PrometheusBasicAuthPassword,
+		},
+		MetricName:     name,
+		ExternalLabels: cfg.ExternalLabels,
+	}, nil
+}
+
+type remoteWriter interface {
+	WriteTimeSeries(ctx context.Context, series []prompb.TimeSeries) error
+}
+
+// PrometheusBackend is a state.Historian that writes the state of alert instances as time series
+// to a Prometheus remote write endpoint, similarly to the ALERTS series of Prometheus.
+//
+// Every evaluation, each alert instance that is not Normal results in a sample with value 1 labeled with its state.
+// When an instance leaves a state, a stale marker ends the series of the previous state.
+// The backend is write-only, so it can't be queried nor used as the primary of the multiple backend.
+type PrometheusBackend struct {
+	writer         remoteWriter
+	metricName     string
+	externalLabels map[string]string
+	metrics        *metrics.Historian
+	log            log.Logger
+}
+
+func NewPrometheusBackend(cfg PrometheusConfig, req client.Requester, metrics *metrics.Historian) *PrometheusBackend {
+	logger := log.New("ngalert.state.historian", "backend", "prometheus")
+	tc := client.NewTimedClient(req, metrics.WriteDuration)
+	return &PrometheusBackend{
+		writer:         writer.NewPrometheusWriter(cfg.Writer, tc, logger),
+		metricName:     cfg.MetricName,
+		externalLabels: cfg.ExternalLabels,
+		metrics:        metrics,
+		log:            logger,
+	}
+}
+
+// Record writes the state of the alert instances of a given rule to a Prometheus remote write endpoint.
+func (h *PrometheusBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	series := h.statesToSeries(rule, states)
+	// The series include the states that didn't change and the stale markers, which aren't transitions.
+	transitions := 0
+	for _, s := range states {
+		if shouldRecord(s) {
+			transitions++
+		}
+	}
+
+	errCh := make(chan error, 1)
+	if len(series) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We don't want grafana shutdowns or timeouts of the evaluation to interrupt the write.
+	writeCtx := context.Background()
+	writeCtx, cancel := context.WithTimeout(writeCtx, StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+	writeCtx = trace.ContextWithSpan(writeCtx, trace.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, BackendTypePrometheus.String()).Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(transitions))
+
+		if err := h.writer.WriteTimeSeries(ctx, series); err != nil {
+			logger.Error("Failed to write alert state history series", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, BackendTypePrometheus.String()).Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(transitions))
+			errCh <- fmt.Errorf("failed to write alert state history series: %w", err)
+		}
+	}(writeCtx)
+	return errCh
+}
+
+// Query is not supported, state history has to be queried from the Prometheus-compatible data source.
+func (h *PrometheusBackend) Query(_ context.Context, _ models.HistoryQuery) (*data.Frame, error) {
+	return nil, ErrQueryNotSupported
+}
+
+func (h *PrometheusBackend) statesToSeries(rule history_model.RuleMeta, states []state.StateTransition) []prompb.TimeSeries {
+	series := make([]prompb.TimeSeries, 0, len(states))
+	for _, s := range states {
+		ts := s.State.LastEvaluationTime.UnixMilli()
+		if s.State.State != eval.Normal {
+			series = append(series, h.newSeries(rule, s, s.State.State, 1, ts))
+		}
+		if s.PreviousState != s.State.State && s.PreviousState != eval.Normal {
+			series = append(series, h.newSeries(rule, s, s.PreviousState, math.Float64frombits(value.StaleNaN), ts))
+		}
+	}
+	return series
+}
+
+func (h *PrometheusBackend) newSeries(rule history_model.RuleMeta, s state.StateTransition, st eval.State, v float64, ts int64) prompb.TimeSeries {
+	labels := mergeLabels(make(map[string]string), h.externalLabels)
+	labels = mergeLabels(labels, removePrivateLabels(s.Labels))
+	// System-defined labels take precedence over user-defined and instance labels.
+	labels["__name__"] = h.metricName
+	labels[AlertStateLabel] = strings.ToLower(st.String())
+	labels[AlertRuleUIDLabel] = rule.UID
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lbls := make([]prompb.Label, 0, len(keys))
+	for _, k := range keys {
+		lbls = append(lbls, prompb.Label{Name: k, Value: labels[k]})
+	}
+	return prompb.TimeSeries{
+		Labels:  lbls,
+		Samples: []prompb.Sample{{Value: v, Timestamp: ts}},
+	}
+}
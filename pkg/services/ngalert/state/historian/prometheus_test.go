@@ -0,0 +1,197 @@
+package historian
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestPrometheusConfig(t *testing.T) {
+	t.Run("requires the remote write URL", func(t *testing.T) {
+		_, err := NewPrometheusConfig(setting.UnifiedAlertingStateHistorySettings{})
+		require.ErrorContains(t, err, "remote write URL must be provided")
+	})
+
+	t.Run("defaults the metric name", func(t *testing.T) {
+		cfg, err := NewPrometheusConfig(setting.UnifiedAlertingStateHistorySettings{
+			PrometheusRemoteWriteURL:    "http://mimir:8080/api/v1/push",
+			PrometheusBasicAuthUsername: "user",
+			PrometheusBasicAuthPassword: "pass",
+		})
+		require.NoError(t, err)
+		require.Equal(t, DefaultPrometheusMetricName, cfg.MetricName)
+		require.Equal(t, "http://mimir:8080/api/v1/push", cfg.Writer.URL.String())
+		require.Equal(t, "user", cfg.Writer.BasicAuthUsername)
+		require.Equal(t, "pass", cfg.Writer.BasicAuthPassword)
+	})
+}
+
+func TestPrometheusBackend(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("writes non-normal states and stale markers for the previous state", func(t *testing.T) {
+		srv := newRemoteWriteReceiver(t, http.StatusOK)
+		b := createTestPrometheusBackend(t, srv.URL)
+		rule := createTestRule()
+		states := []state.StateTransition{
+			{
+				PreviousState: eval.Pending,
+				State: &state.State{
+					State:              eval.Alerting,
+					Labels:             data.Labels{"alertname": "my-rule", "a": "b", "__private__": "c"},
+					LastEvaluationTime: now,
+				},
+			},
+			{
+				PreviousState: eval.Normal,
+				State: &state.State{
+					State:              eval.Normal,
+					Labels:             data.Labels{"alertname": "my-rule", "a": "d"},
+					LastEvaluationTime: now,
+				},
+			},
+		}
+
+		err := <-b.Record(context.Background(), rule, states)
+		require.NoError(t, err)
+
+		series := srv.series()
+		require.Len(t, series, 2)
+		require.Equal(t, map[string]string{
+			"__name__":        DefaultPrometheusMetricName,
+			"alertname":       "my-rule",
+			"a":               "b",
+			"externalLabel":   "value",
+			AlertStateLabel:   "alerting",
+			AlertRuleUIDLabel: rule.UID,
+		}, labelsMap(series[0].Labels))
+		require.Equal(t, []prompb.Sample{{Value: 1, Timestamp: now.UnixMilli()}}, series[0].Samples)
+
+		require.Equal(t, "pending", labelsMap(series[1].Labels)[AlertStateLabel])
+		require.True(t, value.IsStaleNaN(series[1].Samples[0].Value))
+		require.Equal(t, 1.0, testutil.ToFloat64(b.metrics.TransitionsTotal.WithLabelValues("1")))
+	})
+
+	t.Run("does not count instances that stay in the same state as transitions", func(t *testing.T) {
+		srv := newRemoteWriteReceiver(t, http.StatusOK)
+		b := createTestPrometheusBackend(t, srv.URL)
+		states := []state.StateTransition{
+			{
+				PreviousState: eval.Alerting,
+				State:         &state.State{State: eval.Alerting, Labels: data.Labels{"a": "b"}, LastEvaluationTime: now},
+			},
+		}
+
+		err := <-b.Record(context.Background(), createTestRule(), states)
+		require.NoError(t, err)
+		require.Len(t, srv.series(), 1)
+		require.Equal(t, 0.0, testutil.ToFloat64(b.metrics.TransitionsTotal.WithLabelValues("1")))
+	})
+
+	t.Run("does not write if all instances are normal", func(t *testing.T) {
+		srv := newRemoteWriteReceiver(t, http.StatusOK)
+		b := createTestPrometheusBackend(t, srv.URL)
+
+		err := <-b.Record(context.Background(), createTestRule(), singleFromNormal(&state.State{State: eval.Normal}))
+		require.NoError(t, err)
+		require.Empty(t, srv.series())
+	})
+
+	t.Run("returns an error if the write fails", func(t *testing.T) {
+		srv := newRemoteWriteReceiver(t, http.StatusInternalServerError)
+		b := createTestPrometheusBackend(t, srv.URL)
+
+		err := <-b.Record(context.Background(), createTestRule(), singleFromNormal(&state.State{State: eval.NoData}))
+		require.ErrorContains(t, err, "failed to write alert state history series")
+	})
+
+	t.Run("does not support queries", func(t *testing.T) {
+		b := createTestPrometheusBackend(t, "http://some.url")
+
+		_, err := b.Query(context.Background(), ngmodels.HistoryQuery{})
+		require.ErrorIs(t, err, ErrQueryNotSupported)
+	})
+
+	t.Run("works as a secondary of the multiple backend", func(t *testing.T) {
+		srv := newRemoteWriteReceiver(t, http.StatusOK)
+		primary := &fakeBackend{}
+		fan := NewMultipleBackend(primary, createTestPrometheusBackend(t, srv.URL))
+
+		err := <-fan.Record(context.Background(), createTestRule(), singleFromNormal(&state.State{State: eval.Alerting, LastEvaluationTime: now}))
+		require.NoError(t, err)
+		require.NotEmpty(t, primary.last)
+		require.Len(t, srv.series(), 1)
+	})
+}
+
+func createTestPrometheusBackend(t *testing.T, u string) *PrometheusBackend {
+	t.Helper()
+	cfg, err := NewPrometheusConfig(setting.UnifiedAlertingStateHistorySettings{
+		PrometheusRemoteWriteURL: u,
+		ExternalLabels:           map[string]string{"externalLabel": "value"},
+	})
+	require.NoError(t, err)
+	met := metrics.NewHistorianMetrics(prometheus.NewRegistry())
+	return NewPrometheusBackend(cfg, NewRequester(), met)
+}
+
+// remoteWriteReceiver is a Prometheus remote write endpoint that keeps the received series.
+type remoteWriteReceiver struct {
+	*httptest.Server
+
+	mtx      sync.Mutex
+	received []prompb.TimeSeries
+}
+
+func newRemoteWriteReceiver(t *testing.T, status int) *remoteWriteReceiver {
+	t.Helper()
+	r := &remoteWriteReceiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		compressed, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		raw, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+		var wr prompb.WriteRequest
+		require.NoError(t, proto.Unmarshal(raw, &wr))
+
+		r.mtx.Lock()
+		r.received = append(r.received, wr.Timeseries...)
+		r.mtx.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *remoteWriteReceiver) series() []prompb.TimeSeries {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.received
+}
+
+func labelsMap(lbls []prompb.Label) map[string]string {
+	m := make(map[string]string, len(lbls))
+	for _, l := range lbls {
+		m[l.Name] = l.Value
+	}
+	return m
+}
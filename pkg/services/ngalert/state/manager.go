@@ -169,6 +169,7 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				StartsAt:             entry.CurrentStateSince,
 				EndsAt:               entry.CurrentStateEnd,
 				LastEvaluationTime:   entry.LastEvalTime,
+				KeepFiringSince:      entry.KeepFiringSince,
 				Annotations:          ruleForEntry.Annotations,
 			}
 			statesCount++
@@ -458,6 +459,7 @@ func (st *Manager) saveAlertStates(ctx context.Context, logger log.Logger, state
 			LastEvalTime:      s.LastEvaluationTime,
 			CurrentStateSince: s.StartsAt,
 			CurrentStateEnd:   s.EndsAt,
+			KeepFiringSince:   s.KeepFiringSince,
 		}
 
 		err = st.instanceStore.SaveAlertInstance(ctx, instance)
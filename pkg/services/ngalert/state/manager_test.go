@@ -69,6 +69,7 @@ func TestWarmStateCache(t *testing.T) {
 			StartsAt:           evaluationTime.Add(-1 * time.Minute),
 			EndsAt:             evaluationTime.Add(1 * time.Minute),
 			LastEvaluationTime: evaluationTime,
+			KeepFiringSince:    evaluationTime.Add(-30 * time.Second),
 			Annotations:        map[string]string{"testAnnoKey": "testAnnoValue"},
 		},
 		{
@@ -141,6 +142,7 @@ func TestWarmStateCache(t *testing.T) {
 		LastEvalTime:      evaluationTime,
 		CurrentStateSince: evaluationTime.Add(-1 * time.Minute),
 		CurrentStateEnd:   evaluationTime.Add(1 * time.Minute),
+		KeepFiringSince:   evaluationTime.Add(-30 * time.Second),
 		Labels:            labels,
 	})
 
@@ -1028,6 +1030,102 @@ func TestProcessEvalResults(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:      "alerting -> alerting when result is Normal and KeepFiringFor is not exceeded",
+			alertRule: baseRuleWith(models.WithKeepFiringFor(2 * evaluationInterval)),
+			evalResults: map[time.Time]eval.Results{
+				t1: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+				t2: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				t3: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+			},
+			expectedAnnotations: 1,
+			expectedStates: []*state.State{
+				{
+					Labels: labels["system + rule + labels1"],
+					State:  eval.Alerting,
+					Results: []state.Evaluation{
+						newEvaluation(t1, eval.Alerting),
+						newEvaluation(t2, eval.Normal),
+						newEvaluation(t3, eval.Normal),
+					},
+					KeepFiringSince:    t2,
+					StartsAt:           t1,
+					EndsAt:             t3.Add(state.ResendDelay * 4),
+					LastEvaluationTime: t3,
+				},
+			},
+		},
+		{
+			desc:      "alerting -> normal when result is Normal and KeepFiringFor is exceeded",
+			alertRule: baseRuleWith(models.WithKeepFiringFor(2 * evaluationInterval)),
+			evalResults: map[time.Time]eval.Results{
+				t1: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+				t2: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				t3: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				tn(4): {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+			},
+			expectedAnnotations: 2,
+			expectedStates: []*state.State{
+				{
+					Labels: labels["system + rule + labels1"],
+					State:  eval.Normal,
+					Results: []state.Evaluation{
+						newEvaluation(t1, eval.Alerting),
+						newEvaluation(t2, eval.Normal),
+						newEvaluation(t3, eval.Normal),
+						newEvaluation(tn(4), eval.Normal),
+					},
+					Resolved:           true,
+					StartsAt:           tn(4),
+					EndsAt:             tn(4),
+					LastEvaluationTime: tn(4),
+				},
+			},
+		},
+		{
+			desc:      "alerting -> alerting when result is Normal with KeepFiringFor and then Alerting again",
+			alertRule: baseRuleWith(models.WithKeepFiringFor(2 * evaluationInterval)),
+			evalResults: map[time.Time]eval.Results{
+				t1: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+				t2: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				t3: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+			},
+			expectedAnnotations: 1,
+			expectedStates: []*state.State{
+				{
+					Labels: labels["system + rule + labels1"],
+					State:  eval.Alerting,
+					Results: []state.Evaluation{
+						newEvaluation(t1, eval.Alerting),
+						newEvaluation(t2, eval.Normal),
+						newEvaluation(t3, eval.Alerting),
+					},
+					StartsAt:           t1,
+					EndsAt:             t3.Add(state.ResendDelay * 4),
+					LastEvaluationTime: t3,
+				},
+			},
+		},
 		{
 			desc:      "normal -> alerting -> error when result is Error and ExecErrorState is Error",
 			alertRule: baseRuleWith(models.WithForNTimes(2)),
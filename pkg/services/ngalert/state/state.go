@@ -61,6 +61,11 @@ type State struct {
 	// conditions.
 	Values map[string]float64
 
+	// KeepFiringSince is the time the condition of an Alerting state stopped being met. It is set only
+	// while the state is kept Alerting because of the KeepFiringFor duration of the alert rule, and is
+	// saved with the alert instance so that the window doesn't restart when Grafana restarts.
+	KeepFiringSince time.Time
+
 	StartsAt             time.Time
 	EndsAt               time.Time
 	LastSentAt           time.Time
@@ -92,6 +97,7 @@ func (a *State) SetAlerting(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetPending the state to Pending. It changes both the start and end time.
@@ -101,6 +107,7 @@ func (a *State) SetPending(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetNoData sets the state to NoData. It changes both the start and end time.
@@ -110,6 +117,7 @@ func (a *State) SetNoData(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetError sets the state to Error. It changes both the start and end time.
@@ -119,6 +127,7 @@ func (a *State) SetError(err error, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = err
+	a.KeepFiringSince = time.Time{}
 }
 
 // SetNormal sets the state to Normal. It changes both the start and end time.
@@ -128,6 +137,7 @@ func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeepFiringSince = time.Time{}
 }
 
 // Resolve sets the State to Normal. It updates the StateReason, the end time, and sets Resolved to true.
@@ -136,6 +146,7 @@ func (a *State) Resolve(reason string, endsAt time.Time) {
 	a.StateReason = reason
 	a.Resolved = true
 	a.EndsAt = endsAt
+	a.KeepFiringSince = time.Time{}
 }
 
 // Maintain updates the end time using the most recent evaluation.
@@ -187,9 +198,22 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]*float
 	return result
 }
 
-func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger log.Logger) {
+func resultNormal(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
 	if state.State == eval.Normal {
 		logger.Debug("Keeping state", "state", state.State)
+	} else if state.State == eval.Alerting && rule.KeepFiringFor > 0 && !keepFiringElapsed(state, rule, result.EvaluatedAt) {
+		// If the alert rule has a KeepFiringFor duration then the state should be kept Alerting until it elapses
+		prevEndsAt := state.EndsAt
+		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+		logger.Debug("Keeping state",
+			"state",
+			state.State,
+			"keep_firing_since",
+			state.KeepFiringSince,
+			"previous_ends_at",
+			prevEndsAt,
+			"next_ends_at",
+			state.EndsAt)
 	} else {
 		nextEndsAt := result.EvaluatedAt
 		logger.Debug("Changing state",
@@ -206,9 +230,20 @@ func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger
 	}
 }
 
+// keepFiringElapsed returns true if the KeepFiringFor duration of the rule has been observed since the condition
+// of the state stopped being met. The first time it is called for a state, it records when that happened.
+func keepFiringElapsed(state *State, rule *models.AlertRule, evaluatedAt time.Time) bool {
+	if state.KeepFiringSince.IsZero() {
+		state.KeepFiringSince = evaluatedAt
+	}
+	return evaluatedAt.Sub(state.KeepFiringSince) >= rule.KeepFiringFor
+}
+
 func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
 	switch state.State {
 	case eval.Alerting:
+		// The condition is met again, so the state is no longer kept firing
+		state.KeepFiringSince = time.Time{}
 		prevEndsAt := state.EndsAt
 		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
 		logger.Debug("Keeping state",
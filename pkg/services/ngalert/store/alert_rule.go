@@ -154,8 +154,10 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				NoDataState:      r.NoDataState,
 				ExecErrState:     r.ExecErrState,
 				For:              r.For,
+				KeepFiringFor:    r.KeepFiringFor,
 				Annotations:      r.Annotations,
 				Labels:           r.Labels,
+				Record:           r.Record,
 			})
 		}
 		if len(newRules) > 0 {
@@ -230,8 +232,10 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				NoDataState:      r.New.NoDataState,
 				ExecErrState:     r.New.ExecErrState,
 				For:              r.New.For,
+				KeepFiringFor:    r.New.KeepFiringFor,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				Record:           r.New.Record,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -677,5 +681,15 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
 	}
+
+	if alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: field `keep_firing_for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	if alertRule.Record != nil {
+		if err := alertRule.Record.Validate(); err != nil {
+			return fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
+		}
+	}
 	return nil
 }
@@ -556,6 +556,22 @@ func TestIntegrationInsertAlertRules(t *testing.T) {
 		}
 		require.Truef(t, found, "Rule with key %#v was not found in database", keyWithID)
 	}
+
+	t.Run("should persist recording rules", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval), models.WithRecord("test_metric"))()
+
+		ids, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+		require.NoError(t, err)
+		require.Len(t, ids, 1)
+
+		dbRule, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{
+			OrgID: 1,
+			UID:   ids[0].UID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, models.RuleTypeRecording, dbRule.Type())
+		require.Equal(t, rule.Record, dbRule.Record)
+	})
 }
 
 func createRule(t *testing.T, store *DBstore, generate func() *models.AlertRule) *models.AlertRule {
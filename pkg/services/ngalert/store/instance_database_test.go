@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -94,9 +95,10 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 				RuleUID:    alertRule1.UID,
 				LabelsHash: hash,
 			},
-			CurrentState:  models.InstanceStateFiring,
-			CurrentReason: string(models.InstanceStateError),
-			Labels:        labels,
+			CurrentState:    models.InstanceStateFiring,
+			CurrentReason:   string(models.InstanceStateError),
+			Labels:          labels,
+			KeepFiringSince: time.Unix(1700000000, 0),
 		}
 		err := dbstore.SaveAlertInstance(ctx, instance)
 		require.NoError(t, err)
@@ -113,6 +115,7 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, alertRule1.OrgID, alerts[0].RuleOrgID)
 		require.Equal(t, alertRule1.UID, alerts[0].RuleUID)
 		require.Equal(t, instance.CurrentReason, alerts[0].CurrentReason)
+		require.True(t, instance.KeepFiringSince.Equal(alerts[0].KeepFiringSince))
 	})
 
 	t.Run("can save and read new alert instance with no labels", func(t *testing.T) {
@@ -142,6 +145,7 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, alertRule2.OrgID, alerts[0].RuleOrgID)
 		require.Equal(t, alertRule2.UID, alerts[0].RuleUID)
 		require.Equal(t, instance.Labels, alerts[0].Labels)
+		require.True(t, alerts[0].KeepFiringSince.IsZero())
 	})
 
 	t.Run("can save two instances with same org_id, uid and different labels", func(t *testing.T) {
@@ -0,0 +1,151 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/weaveworks/common/http/client"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// PrometheusWriterConfig is the configuration of a Prometheus remote write endpoint.
+type PrometheusWriterConfig struct {
+	URL               *url.URL
+	BasicAuthUsername string
+	BasicAuthPassword string
+	// ExtraHeaders are added to every request sent to the remote write endpoint.
+	ExtraHeaders map[string]string
+}
+
+// NewPrometheusWriterConfig creates a PrometheusWriterConfig from the recording rule settings.
+func NewPrometheusWriterConfig(cfg setting.RecordingRuleSettings) (PrometheusWriterConfig, error) {
+	if cfg.URL == "" {
+		return PrometheusWriterConfig{}, fmt.Errorf("remote write URL must be provided")
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return PrometheusWriterConfig{}, fmt.Errorf("failed to parse remote write URL: %w", err)
+	}
+	return PrometheusWriterConfig{
+		URL:               u,
+		BasicAuthUsername: cfg.BasicAuthUsername,
+		BasicAuthPassword: cfg.BasicAuthPassword,
+	}, nil
+}
+
+// NewRequester returns the HTTP client used to send requests to the remote write endpoint.
+func NewRequester(timeout time.Duration) client.Requester {
+	return &http.Client{Timeout: timeout}
+}
+
+// PrometheusWriter writes points to a Prometheus remote write compatible endpoint.
+type PrometheusWriter struct {
+	client client.Requester
+	cfg    PrometheusWriterConfig
+	logger log.Logger
+}
+
+func NewPrometheusWriter(cfg PrometheusWriterConfig, req client.Requester, l log.Logger) *PrometheusWriter {
+	return &PrometheusWriter{
+		client: req,
+		cfg:    cfg,
+		logger: l,
+	}
+}
+
+// Write converts the frames to points of the metric with the given name and sends them to the remote write endpoint.
+func (w *PrometheusWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		w.logger.Debug("No points to write", "metric", name)
+		return nil
+	}
+	return w.WriteTimeSeries(ctx, TimeSeriesFromPoints(points))
+}
+
+// WriteTimeSeries sends the time series to the remote write endpoint.
+func (w *PrometheusWriter) WriteTimeSeries(ctx context.Context, series []prompb.TimeSeries) error {
+	body, err := encode(series)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create remote write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	for k, v := range w.cfg.ExtraHeaders {
+		req.Header.Set(k, v)
+	}
+	if w.cfg.BasicAuthUsername != "" || w.cfg.BasicAuthPassword != "" {
+		req.SetBasicAuth(w.cfg.BasicAuthUsername, w.cfg.BasicAuthPassword)
+	}
+
+	resp, err := w.client.Do(req)
+	if resp != nil {
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				w.logger.Warn("Failed to close response body", "error", err)
+			}
+		}()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send remote write request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		byt, _ := io.ReadAll(resp.Body)
+		w.logger.Error("Error response from remote write endpoint", "response", string(byt), "status", resp.StatusCode)
+		return fmt.Errorf("received a non-200 response from the remote write endpoint, status: %d", resp.StatusCode)
+	}
+	w.logger.Debug("Wrote time series to the remote write endpoint", "series", len(series))
+	return nil
+}
+
+// TimeSeriesFromPoints converts points to Prometheus time series. Every point becomes a separate series with a single sample.
+func TimeSeriesFromPoints(points []Point) []prompb.TimeSeries {
+	series := make([]prompb.TimeSeries, 0, len(points))
+	for _, p := range points {
+		labels := make([]prompb.Label, 0, len(p.Labels)+1)
+		labels = append(labels, prompb.Label{Name: "__name__", Value: p.Name})
+		for _, k := range sortedKeys(p.Labels) {
+			if k == "__name__" {
+				continue
+			}
+			labels = append(labels, prompb.Label{Name: k, Value: p.Labels[k]})
+		}
+		series = append(series, prompb.TimeSeries{
+			Labels: labels,
+			Samples: []prompb.Sample{{
+				Value:     p.Metric.V,
+				Timestamp: p.Metric.T.UnixMilli(),
+			}},
+		})
+	}
+	return series
+}
+
+func encode(series []prompb.TimeSeries) ([]byte, error) {
+	raw, err := proto.Marshal(&prompb.WriteRequest{Timeseries: series})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal remote write request: %w", err)
+	}
+	return snappy.Encode(nil, raw), nil
+}
@@ -0,0 +1,131 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestNewPrometheusWriterConfig(t *testing.T) {
+	t.Run("should fail if URL is empty", func(t *testing.T) {
+		_, err := NewPrometheusWriterConfig(setting.RecordingRuleSettings{Enabled: true})
+		require.Error(t, err)
+	})
+
+	t.Run("should fail if URL is invalid", func(t *testing.T) {
+		_, err := NewPrometheusWriterConfig(setting.RecordingRuleSettings{Enabled: true, URL: "://invalid"})
+		require.Error(t, err)
+	})
+
+	t.Run("should parse settings", func(t *testing.T) {
+		cfg, err := NewPrometheusWriterConfig(setting.RecordingRuleSettings{
+			Enabled:           true,
+			URL:               "http://localhost:9090/api/v1/write",
+			BasicAuthUsername: "user",
+			BasicAuthPassword: "password",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost:9090/api/v1/write", cfg.URL.String())
+		require.Equal(t, "user", cfg.BasicAuthUsername)
+		require.Equal(t, "password", cfg.BasicAuthPassword)
+	})
+}
+
+func TestPrometheusWriter_Write(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	frames := data.Frames{
+		data.NewFrame("A", data.NewField("value", data.Labels{"host": "a"}, []float64{42})),
+	}
+
+	t.Run("should send time series to the remote write endpoint", func(t *testing.T) {
+		var received prompb.WriteRequest
+		var user, password string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+			require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+			user, password, _ = r.BasicAuth()
+			received = decodeWriteRequest(t, r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		w := NewPrometheusWriter(PrometheusWriterConfig{
+			URL:               mustParseURL(t, srv.URL),
+			BasicAuthUsername: "user",
+			BasicAuthPassword: "password",
+		}, NewRequester(time.Second), log.NewNopLogger())
+
+		err := w.Write(context.Background(), "test_metric", now, frames, map[string]string{"team": "alerting"})
+		require.NoError(t, err)
+
+		require.Equal(t, "user", user)
+		require.Equal(t, "password", password)
+		require.Equal(t, []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "test_metric"},
+					{Name: "host", Value: "a"},
+					{Name: "team", Value: "alerting"},
+				},
+				Samples: []prompb.Sample{{Value: 42, Timestamp: now.UnixMilli()}},
+			},
+		}, received.Timeseries)
+	})
+
+	t.Run("should return error if endpoint responds with non-2xx status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		t.Cleanup(srv.Close)
+
+		w := NewPrometheusWriter(PrometheusWriterConfig{URL: mustParseURL(t, srv.URL)}, NewRequester(time.Second), log.NewNopLogger())
+
+		err := w.Write(context.Background(), "test_metric", now, frames, nil)
+		require.ErrorContains(t, err, "status: 400")
+	})
+
+	t.Run("should not send a request if there is nothing to write", func(t *testing.T) {
+		called := false
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		t.Cleanup(srv.Close)
+
+		w := NewPrometheusWriter(PrometheusWriterConfig{URL: mustParseURL(t, srv.URL)}, NewRequester(time.Second), log.NewNopLogger())
+
+		err := w.Write(context.Background(), "test_metric", now, data.Frames{}, nil)
+		require.NoError(t, err)
+		require.False(t, called)
+	})
+}
+
+func decodeWriteRequest(t *testing.T, body io.Reader) prompb.WriteRequest {
+	t.Helper()
+	compressed, err := io.ReadAll(body)
+	require.NoError(t, err)
+	raw, err := snappy.Decode(nil, compressed)
+	require.NoError(t, err)
+	var req prompb.WriteRequest
+	require.NoError(t, proto.Unmarshal(raw, &req))
+	return req
+}
+
+func mustParseURL(t *testing.T, s string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(s)
+	require.NoError(t, err)
+	return u
+}
@@ -0,0 +1,42 @@
+package writer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// FakeWriter is an in-memory writer that keeps all points written to it. It is used in tests.
+type FakeWriter struct {
+	mtx sync.Mutex
+	// WriteFunc, if set, is called on every write and its error is returned to the caller.
+	WriteFunc func(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error
+	Points    []Point
+}
+
+func (w *FakeWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.WriteFunc != nil {
+		if err := w.WriteFunc(ctx, name, t, frames, extraLabels); err != nil {
+			return err
+		}
+	}
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return err
+	}
+	w.Points = append(w.Points, points...)
+	return nil
+}
+
+// WrittenPoints returns a copy of all points written so far.
+func (w *FakeWriter) WrittenPoints() []Point {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	result := make([]Point, len(w.Points))
+	copy(result, w.Points)
+	return result
+}
@@ -0,0 +1,103 @@
+// Package writer contains the targets that the results of recording rules are written to.
+package writer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// Point is a single sample of a time series produced by a recording rule.
+type Point struct {
+	Name   string
+	Labels map[string]string
+	Metric Metric
+}
+
+// Metric is a value of a time series at a given time.
+type Metric struct {
+	T time.Time
+	V float64
+}
+
+// PointsFromFrames converts the frames returned by the evaluation of a recording rule to points of the metric with the given name.
+// Every numeric field produces a single point. If a frame has a time field, the most recent value of the field is used.
+// Labels of the field are merged with the extra labels, the latter take precedence.
+func PointsFromFrames(name string, t time.Time, frames data.Frames, extraLabels map[string]string) ([]Point, error) {
+	points := make([]Point, 0, len(frames))
+	for _, frame := range frames {
+		if frame == nil {
+			continue
+		}
+		timeIdx := -1
+		for i, field := range frame.Fields {
+			if field.Type().Time() {
+				timeIdx = i
+				break
+			}
+		}
+		for i, field := range frame.Fields {
+			if i == timeIdx || !field.Type().Numeric() {
+				continue
+			}
+			v, ok, err := lastValue(field)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read value of field '%s': %w", field.Name, err)
+			}
+			if !ok {
+				continue
+			}
+			points = append(points, Point{
+				Name:   name,
+				Labels: mergeLabels(field.Labels, extraLabels),
+				Metric: Metric{T: t, V: v},
+			})
+		}
+	}
+	return points, nil
+}
+
+func lastValue(field *data.Field) (float64, bool, error) {
+	for i := field.Len() - 1; i >= 0; i-- {
+		f, err := field.NullableFloatAt(i)
+		if err != nil {
+			return 0, false, err
+		}
+		if f == nil {
+			continue
+		}
+		return *f, true, nil
+	}
+	return math.NaN(), false, nil
+}
+
+func mergeLabels(labels data.Labels, extra map[string]string) map[string]string {
+	result := make(map[string]string, len(labels)+len(extra))
+	for k, v := range labels {
+		result[k] = v
+	}
+	for k, v := range extra {
+		result[k] = v
+	}
+	return result
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NoopWriter is a writer that discards all points. It is used when recording rules are disabled.
+type NoopWriter struct{}
+
+func (w NoopWriter) Write(_ context.Context, _ string, _ time.Time, _ data.Frames, _ map[string]string) error {
+	return nil
+}
@@ -0,0 +1,57 @@
+package writer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestPointsFromFrames(t *testing.T) {
+	now := time.Now()
+	extraLabels := map[string]string{"team": "alerting", "instance": "overridden"}
+
+	t.Run("should convert numeric frames", func(t *testing.T) {
+		frames := data.Frames{
+			data.NewFrame("A", data.NewField("value", data.Labels{"instance": "a"}, []float64{1})),
+			data.NewFrame("A", data.NewField("value", data.Labels{"host": "b"}, []*float64{util.Pointer(2.0)})),
+		}
+
+		points, err := PointsFromFrames("test_metric", now, frames, extraLabels)
+		require.NoError(t, err)
+		require.Equal(t, []Point{
+			{Name: "test_metric", Labels: map[string]string{"team": "alerting", "instance": "overridden"}, Metric: Metric{T: now, V: 1}},
+			{Name: "test_metric", Labels: map[string]string{"team": "alerting", "instance": "overridden", "host": "b"}, Metric: Metric{T: now, V: 2}},
+		}, points)
+	})
+
+	t.Run("should use the last non-null value of time series", func(t *testing.T) {
+		frames := data.Frames{
+			data.NewFrame("A",
+				data.NewField("time", nil, []time.Time{now.Add(-2 * time.Minute), now.Add(-time.Minute), now}),
+				data.NewField("value", data.Labels{"host": "a"}, []*float64{util.Pointer(1.0), util.Pointer(5.0), nil}),
+			),
+		}
+
+		points, err := PointsFromFrames("test_metric", now, frames, nil)
+		require.NoError(t, err)
+		require.Len(t, points, 1)
+		require.Equal(t, float64(5), points[0].Metric.V)
+		require.Equal(t, map[string]string{"host": "a"}, points[0].Labels)
+	})
+
+	t.Run("should skip fields without values", func(t *testing.T) {
+		frames := data.Frames{
+			data.NewFrame("A", data.NewField("value", nil, []*float64{nil})),
+			data.NewFrame("B", data.NewField("value", nil, []float64{})),
+			data.NewFrame("C", data.NewField("value", nil, []string{"test"})),
+		}
+
+		points, err := PointsFromFrames("test_metric", now, frames, nil)
+		require.NoError(t, err)
+		require.Empty(t, points)
+	})
+}
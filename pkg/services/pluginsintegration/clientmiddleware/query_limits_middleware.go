@@ -0,0 +1,359 @@
+package clientmiddleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	rejectedReasonQueueTimeout = "queue_timeout"
+	rejectedReasonCircuitOpen  = "circuit_open"
+)
+
+var (
+	// ErrQueryQueueTimeout is returned when a query waits longer than the queue timeout for the
+	// concurrency limits of its data source or org.
+	ErrQueryQueueTimeout = errutil.TooManyRequests("plugin.queryQueueTimeout",
+		errutil.WithPublicMessage("Too many concurrent queries to the data source, try again later"))
+	// ErrCircuitOpen is returned when the queries to a data source fail fast after repeated timeouts.
+	ErrCircuitOpen = errutil.BadGateway("plugin.circuitOpen",
+		errutil.WithPublicMessage("The data source is not responding, try again later"),
+		errutil.WithDownstream())
+)
+
+// queryLimitsMetrics contains the prometheus metrics used by the QueryLimitsMiddleware.
+type queryLimitsMetrics struct {
+	queriesQueued       *prometheus.GaugeVec
+	queriesRejected     *prometheus.CounterVec
+	circuitBreakersOpen *prometheus.GaugeVec
+}
+
+// QueryLimitsMiddleware is a middleware that protects data sources from too many queries.
+// It limits the number of queries running at once per data source and per org, queueing the
+// queries over the limits up to a timeout, and fails queries to a data source fast after
+// repeated timeouts, until a query to the data source succeeds again.
+type QueryLimitsMiddleware struct {
+	queryLimitsMetrics
+	settings setting.QueryLimitsSettings
+	next     plugins.Client
+	now      func() time.Time
+
+	mu          sync.Mutex
+	dataSources map[dataSourceLimitsKey]*dataSourceLimits
+	orgs        map[int64]chan struct{}
+}
+
+type dataSourceLimitsKey struct {
+	orgID int64
+	uid   string
+}
+
+type dataSourceLimits struct {
+	// slots holds a value for each running query, or is nil if there is no limit.
+	slots   chan struct{}
+	breaker circuitBreaker
+}
+
+func newQueryLimitsMiddleware(settings setting.QueryLimitsSettings, promRegisterer prometheus.Registerer) *QueryLimitsMiddleware {
+	queriesQueued := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grafana",
+		Name:      "plugin_queries_queued",
+		Help:      "The number of plugin queries waiting for the concurrency limits of their data source or org",
+	}, []string{"plugin_id"})
+	queriesRejected := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "plugin_queries_rejected_total",
+		Help:      "The total amount of plugin queries rejected by the concurrency limits or circuit breakers",
+	}, []string{"plugin_id", "reason"})
+	circuitBreakersOpen := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grafana",
+		Name:      "plugin_circuit_breakers_open",
+		Help:      "The number of data sources whose queries fail fast after repeated timeouts",
+	}, []string{"plugin_id"})
+	promRegisterer.MustRegister(
+		queriesQueued,
+		queriesRejected,
+		circuitBreakersOpen,
+	)
+	return &QueryLimitsMiddleware{
+		queryLimitsMetrics: queryLimitsMetrics{
+			queriesQueued:       queriesQueued,
+			queriesRejected:     queriesRejected,
+			circuitBreakersOpen: circuitBreakersOpen,
+		},
+		settings:    settings,
+		now:         time.Now,
+		dataSources: map[dataSourceLimitsKey]*dataSourceLimits{},
+		orgs:        map[int64]chan struct{}{},
+	}
+}
+
+// NewQueryLimitsMiddleware returns a new QueryLimitsMiddleware.
+func NewQueryLimitsMiddleware(settings setting.QueryLimitsSettings, promRegisterer prometheus.Registerer) plugins.ClientMiddleware {
+	mw := newQueryLimitsMiddleware(settings, promRegisterer)
+	return plugins.ClientMiddlewareFunc(func(next plugins.Client) plugins.Client {
+		mw.next = next
+		return mw
+	})
+}
+
+// limitsFor returns the limits of the data source and of its org, creating them on first use.
+func (m *QueryLimitsMiddleware) limitsFor(pCtx backend.PluginContext) (*dataSourceLimits, chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := dataSourceLimitsKey{orgID: pCtx.OrgID, uid: pCtx.DataSourceInstanceSettings.UID}
+	ds, ok := m.dataSources[key]
+	if !ok {
+		ds = &dataSourceLimits{breaker: circuitBreaker{
+			maxTimeouts:  m.settings.CircuitBreakerTimeouts,
+			openDuration: m.settings.CircuitBreakerOpenDuration,
+		}}
+		if m.settings.MaxConcurrentPerDataSource > 0 {
+			ds.slots = make(chan struct{}, m.settings.MaxConcurrentPerDataSource)
+		}
+		m.dataSources[key] = ds
+	}
+
+	if m.settings.MaxConcurrentPerOrg <= 0 {
+		return ds, nil
+	}
+	org, ok := m.orgs[pCtx.OrgID]
+	if !ok {
+		org = make(chan struct{}, m.settings.MaxConcurrentPerOrg)
+		m.orgs[pCtx.OrgID] = org
+	}
+	return ds, org
+}
+
+// acquire takes a slot, waiting up to the queue timeout for one to be released.
+// It returns a function that releases the slot.
+func (m *QueryLimitsMiddleware) acquire(ctx context.Context, slots chan struct{}, pluginID string) (func(), error) {
+	release := func() { <-slots }
+	if slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	queued := m.queriesQueued.WithLabelValues(pluginID)
+	queued.Inc()
+	defer queued.Dec()
+
+	timer := time.NewTimer(m.settings.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		m.queriesRejected.WithLabelValues(pluginID, rejectedReasonQueueTimeout).Inc()
+		return nil, ErrQueryQueueTimeout.Errorf("query waited more than %s for the concurrency limits", m.settings.QueueTimeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (m *QueryLimitsMiddleware) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	if req == nil || req.PluginContext.DataSourceInstanceSettings == nil {
+		return m.next.QueryData(ctx, req)
+	}
+
+	pluginID := req.PluginContext.PluginID
+	ds, org := m.limitsFor(req.PluginContext)
+	allowed, probe := ds.breaker.allow(m.now())
+	if !allowed {
+		m.queriesRejected.WithLabelValues(pluginID, rejectedReasonCircuitOpen).Inc()
+		return nil, ErrCircuitOpen.Errorf("queries to data source %s fail fast after repeated timeouts", req.PluginContext.DataSourceInstanceSettings.UID)
+	}
+
+	// The slot of the data source is taken first, so that the queries queued
+	// for a busy data source don't hold the slots of the org.
+	releaseDS, err := m.acquire(ctx, ds.slots, pluginID)
+	if err != nil {
+		ds.breaker.record(outcomeIgnored, probe, m.now())
+		return nil, err
+	}
+	defer releaseDS()
+	releaseOrg, err := m.acquire(ctx, org, pluginID)
+	if err != nil {
+		ds.breaker.record(outcomeIgnored, probe, m.now())
+		return nil, err
+	}
+	defer releaseOrg()
+
+	resp, err := m.next.QueryData(ctx, req)
+	switch ds.breaker.record(queryOutcome(resp, err), probe, m.now()) {
+	case circuitOpened:
+		m.circuitBreakersOpen.WithLabelValues(pluginID).Inc()
+	case circuitClosed:
+		m.circuitBreakersOpen.WithLabelValues(pluginID).Dec()
+	}
+	return resp, err
+}
+
+func (m *QueryLimitsMiddleware) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return m.next.CallResource(ctx, req, sender)
+}
+
+func (m *QueryLimitsMiddleware) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	return m.next.CheckHealth(ctx, req)
+}
+
+func (m *QueryLimitsMiddleware) CollectMetrics(ctx context.Context, req *backend.CollectMetricsRequest) (*backend.CollectMetricsResult, error) {
+	return m.next.CollectMetrics(ctx, req)
+}
+
+func (m *QueryLimitsMiddleware) SubscribeStream(ctx context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+	return m.next.SubscribeStream(ctx, req)
+}
+
+func (m *QueryLimitsMiddleware) PublishStream(ctx context.Context, req *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+	return m.next.PublishStream(ctx, req)
+}
+
+func (m *QueryLimitsMiddleware) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+	return m.next.RunStream(ctx, req, sender)
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeTimeout
+	// outcomeIgnored is the outcome of queries that didn't reach the data source or were canceled.
+	outcomeIgnored
+)
+
+// queryOutcome returns whether the query timed out. Queries that failed for
+// other reasons count as a success, as the data source responded.
+func queryOutcome(resp *backend.QueryDataResponse, err error) outcome {
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return outcomeIgnored
+		}
+		if isTimeout(err) {
+			return outcomeTimeout
+		}
+		return outcomeSuccess
+	}
+	if resp != nil {
+		for _, r := range resp.Responses {
+			if r.Status == backend.StatusTimeout || (r.Error != nil && isTimeout(r.Error)) {
+				return outcomeTimeout
+			}
+		}
+	}
+	return outcomeSuccess
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var t interface{ Timeout() bool }
+	return errors.As(err, &t) && t.Timeout()
+}
+
+type circuitChange int
+
+const (
+	circuitUnchanged circuitChange = iota
+	circuitOpened
+	circuitClosed
+)
+
+// circuitBreaker opens after maxTimeouts consecutive timeouts, and makes
+// queries fail fast for openDuration. A single query, the probe, is then let
+// through, and the circuit breaker closes if it doesn't time out, or stays
+// open for another openDuration if it does.
+type circuitBreaker struct {
+	maxTimeouts  int
+	openDuration time.Duration
+
+	mu        sync.Mutex
+	timeouts  int
+	open      bool
+	openUntil time.Time
+	// probe identifies the running probe, or is 0 if there is none.
+	probe     uint64
+	lastProbe uint64
+}
+
+// allow returns whether a query can run, and the ID of the probe if the query
+// is the probe, or 0 if it isn't.
+func (b *circuitBreaker) allow(now time.Time) (bool, uint64) {
+	if b.maxTimeouts <= 0 {
+		return true, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true, 0
+	}
+	if now.Before(b.openUntil) || b.probe != 0 {
+		return false, 0
+	}
+	b.lastProbe++
+	b.probe = b.lastProbe
+	return true, b.probe
+}
+
+// record records the outcome of a query, with the probe ID allow returned for it.
+func (b *circuitBreaker) record(o outcome, probe uint64, now time.Time) circuitChange {
+	if b.maxTimeouts <= 0 {
+		return circuitUnchanged
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	isProbe := probe != 0 && probe == b.probe
+	switch o {
+	case outcomeTimeout:
+		b.timeouts++
+		if b.open {
+			// Queries that started before the circuit breaker opened don't
+			// delay the next probe.
+			if isProbe {
+				b.probe = 0
+				b.openUntil = now.Add(b.openDuration)
+			}
+			return circuitUnchanged
+		}
+		if b.timeouts >= b.maxTimeouts {
+			b.open = true
+			b.openUntil = now.Add(b.openDuration)
+			return circuitOpened
+		}
+	case outcomeSuccess:
+		if b.open {
+			// Only the probe closes the circuit breaker, since queries that
+			// started before it opened say nothing about the data source now.
+			if !isProbe {
+				return circuitUnchanged
+			}
+			b.open, b.probe = false, 0
+			b.timeouts = 0
+			return circuitClosed
+		}
+		b.timeouts = 0
+	case outcomeIgnored:
+		if isProbe {
+			b.probe = 0
+		}
+	}
+	return circuitUnchanged
+}
@@ -0,0 +1,232 @@
+package clientmiddleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins/manager/client/clienttest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestQueryLimitsMiddleware(t *testing.T) {
+	newRequest := func(orgID int64, uid string) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{PluginContext: backend.PluginContext{
+			OrgID:                      orgID,
+			PluginID:                   pluginID,
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{UID: uid},
+		}}
+	}
+
+	// setup returns a middleware whose queries to the data source "slow" signal
+	// started when they run, and block until unblock is closed.
+	setup := func(t *testing.T, settings setting.QueryLimitsSettings) (*QueryLimitsMiddleware, chan struct{}, chan struct{}) {
+		started, unblock := make(chan struct{}, 10), make(chan struct{})
+		mw := newQueryLimitsMiddleware(settings, prometheus.NewRegistry())
+		mw.next = &clienttest.TestClient{
+			QueryDataFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				if req.PluginContext.DataSourceInstanceSettings.UID == "slow" {
+					started <- struct{}{}
+					<-unblock
+				}
+				return backend.NewQueryDataResponse(), nil
+			},
+		}
+		return mw, started, unblock
+	}
+
+	// runQuery runs a query in the background.
+	runQuery := func(mw *QueryLimitsMiddleware, req *backend.QueryDataRequest) chan error {
+		done := make(chan error, 1)
+		go func() {
+			_, err := mw.QueryData(context.Background(), req)
+			done <- err
+		}()
+		return done
+	}
+
+	t.Run("limits the concurrent queries per data source", func(t *testing.T) {
+		mw, started, unblock := setup(t, setting.QueryLimitsSettings{MaxConcurrentPerDataSource: 1, QueueTimeout: 50 * time.Millisecond})
+
+		running := runQuery(mw, newRequest(1, "slow"))
+		<-started
+
+		_, err := mw.QueryData(context.Background(), newRequest(1, "slow"))
+		require.ErrorIs(t, err, ErrQueryQueueTimeout)
+		require.Equal(t, 1.0, testutil.ToFloat64(mw.queriesRejected.WithLabelValues(pluginID, rejectedReasonQueueTimeout)))
+
+		_, err = mw.QueryData(context.Background(), newRequest(1, "other"))
+		require.NoError(t, err)
+
+		close(unblock)
+		require.NoError(t, <-running)
+		_, err = mw.QueryData(context.Background(), newRequest(1, "slow"))
+		require.NoError(t, err)
+	})
+
+	t.Run("queues queries until a query completes", func(t *testing.T) {
+		mw, started, unblock := setup(t, setting.QueryLimitsSettings{MaxConcurrentPerDataSource: 1, QueueTimeout: time.Minute})
+
+		running := runQuery(mw, newRequest(1, "slow"))
+		<-started
+		queued := runQuery(mw, newRequest(1, "slow"))
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(mw.queriesQueued.WithLabelValues(pluginID)) == 1
+		}, time.Second, time.Millisecond)
+
+		close(unblock)
+		require.NoError(t, <-running)
+		require.NoError(t, <-queued)
+		require.Equal(t, 0.0, testutil.ToFloat64(mw.queriesQueued.WithLabelValues(pluginID)))
+	})
+
+	t.Run("limits the concurrent queries per org", func(t *testing.T) {
+		mw, started, unblock := setup(t, setting.QueryLimitsSettings{MaxConcurrentPerOrg: 1, QueueTimeout: 50 * time.Millisecond})
+
+		running := runQuery(mw, newRequest(1, "slow"))
+		<-started
+
+		_, err := mw.QueryData(context.Background(), newRequest(1, "other"))
+		require.ErrorIs(t, err, ErrQueryQueueTimeout)
+
+		_, err = mw.QueryData(context.Background(), newRequest(2, "other"))
+		require.NoError(t, err)
+
+		close(unblock)
+		require.NoError(t, <-running)
+	})
+
+	t.Run("queries queued for a busy data source don't hold the slots of the org", func(t *testing.T) {
+		mw, started, unblock := setup(t, setting.QueryLimitsSettings{MaxConcurrentPerDataSource: 1, MaxConcurrentPerOrg: 2, QueueTimeout: time.Minute})
+
+		running := runQuery(mw, newRequest(1, "slow"))
+		<-started
+		queued := runQuery(mw, newRequest(1, "slow"))
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(mw.queriesQueued.WithLabelValues(pluginID)) == 1
+		}, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err := mw.QueryData(ctx, newRequest(1, "other"))
+		require.NoError(t, err)
+
+		close(unblock)
+		require.NoError(t, <-running)
+		require.NoError(t, <-queued)
+	})
+
+	t.Run("fails fast after repeated timeouts", func(t *testing.T) {
+		now := time.Now()
+		mw := newQueryLimitsMiddleware(setting.QueryLimitsSettings{CircuitBreakerTimeouts: 2, CircuitBreakerOpenDuration: time.Minute}, prometheus.NewRegistry())
+		mw.now = func() time.Time { return now }
+		var queryErr error
+		var calls int
+		mw.next = &clienttest.TestClient{
+			QueryDataFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				calls++
+				return backend.NewQueryDataResponse(), queryErr
+			},
+		}
+		open := func() float64 { return testutil.ToFloat64(mw.circuitBreakersOpen.WithLabelValues(pluginID)) }
+
+		queryErr = context.DeadlineExceeded
+		for i := 0; i < 2; i++ {
+			_, err := mw.QueryData(context.Background(), newRequest(1, "ds"))
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+		}
+		require.Equal(t, 1.0, open())
+
+		_, err := mw.QueryData(context.Background(), newRequest(1, "ds"))
+		require.ErrorIs(t, err, ErrCircuitOpen)
+		require.Equal(t, 2, calls)
+		require.Equal(t, 1.0, testutil.ToFloat64(mw.queriesRejected.WithLabelValues(pluginID, rejectedReasonCircuitOpen)))
+
+		// other data sources are not affected
+		_, err = mw.QueryData(context.Background(), newRequest(1, "other"))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// a query that times out after the circuit breaker opened keeps it open
+		now = now.Add(time.Minute)
+		_, err = mw.QueryData(context.Background(), newRequest(1, "ds"))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		_, err = mw.QueryData(context.Background(), newRequest(1, "ds"))
+		require.ErrorIs(t, err, ErrCircuitOpen)
+
+		// a query that succeeds closes it
+		now = now.Add(time.Minute)
+		queryErr = nil
+		_, err = mw.QueryData(context.Background(), newRequest(1, "ds"))
+		require.NoError(t, err)
+		require.Equal(t, 0.0, open())
+		_, err = mw.QueryData(context.Background(), newRequest(1, "ds"))
+		require.NoError(t, err)
+	})
+
+	t.Run("only the outcome of the probe lets another probe through", func(t *testing.T) {
+		now := time.Now()
+		b := &circuitBreaker{maxTimeouts: 1, openDuration: time.Minute}
+
+		// a query starts before the circuit breaker opens
+		allowed, inFlight := b.allow(now)
+		require.True(t, allowed)
+		require.Zero(t, inFlight)
+		require.Equal(t, circuitOpened, b.record(outcomeTimeout, 0, now))
+
+		now = now.Add(time.Minute)
+		allowed, probe := b.allow(now)
+		require.True(t, allowed)
+		require.NotZero(t, probe)
+
+		// the query that started before is canceled or times out while the probe runs
+		b.record(outcomeIgnored, inFlight, now)
+		b.record(outcomeTimeout, inFlight, now)
+		allowed, _ = b.allow(now)
+		require.False(t, allowed)
+
+		// the probe is canceled, so the next query is the probe
+		b.record(outcomeIgnored, probe, now)
+		allowed, next := b.allow(now)
+		require.True(t, allowed)
+		require.NotEqual(t, probe, next)
+
+		// the outcome of the previous probe is not the one of the running probe
+		b.record(outcomeIgnored, probe, now)
+		allowed, _ = b.allow(now)
+		require.False(t, allowed)
+
+		// a query that started before the circuit breaker opened doesn't close it
+		require.Equal(t, circuitUnchanged, b.record(outcomeSuccess, inFlight, now))
+		require.Equal(t, circuitUnchanged, b.record(outcomeSuccess, probe, now))
+		allowed, _ = b.allow(now)
+		require.False(t, allowed)
+
+		require.Equal(t, circuitClosed, b.record(outcomeSuccess, next, now))
+	})
+
+	t.Run("counts timeouts in the responses of queries", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			resp     *backend.QueryDataResponse
+			err      error
+			expected outcome
+		}{
+			{name: "success", resp: backend.NewQueryDataResponse(), expected: outcomeSuccess},
+			{name: "error", err: errors.New("bad query"), expected: outcomeSuccess},
+			{name: "canceled", err: context.Canceled, expected: outcomeIgnored},
+			{name: "deadline", err: context.DeadlineExceeded, expected: outcomeTimeout},
+			{name: "timeout status", resp: &backend.QueryDataResponse{Responses: backend.Responses{"A": {Status: backend.StatusTimeout}}}, expected: outcomeTimeout},
+			{name: "timeout error", resp: &backend.QueryDataResponse{Responses: backend.Responses{"A": {Error: context.DeadlineExceeded}}}, expected: outcomeTimeout},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				require.Equal(t, tc.expected, queryOutcome(tc.resp, tc.err))
+			})
+		}
+	})
+}
@@ -171,11 +171,17 @@ func CreateMiddlewares(cfg *setting.Cfg, oAuthTokenService oauthtoken.OAuthToken
 		clientmiddleware.NewResourceResponseMiddleware(),
 	)
 
-	// Placing the new service implementation behind a feature flag until it is known to be stable
-	if features.IsEnabled(featuremgmt.FlagUseCachingService) {
+	// Placing the new service implementation behind a feature flag until it is known to be stable.
+	// The query caching of the remote cache is enabled with its own setting.
+	if features.IsEnabled(featuremgmt.FlagUseCachingService) || cfg.QueryCaching.Enabled {
 		middlewares = append(middlewares, clientmiddleware.NewCachingMiddlewareWithFeatureManager(cachingService, features))
 	}
 
+	// Queries served from the cache don't count towards the concurrency limits of data sources.
+	if limits := cfg.QueryLimits; limits.MaxConcurrentPerDataSource > 0 || limits.MaxConcurrentPerOrg > 0 || limits.CircuitBreakerTimeouts > 0 {
+		middlewares = append(middlewares, clientmiddleware.NewQueryLimitsMiddleware(limits, promRegisterer))
+	}
+
 	if features.IsEnabled(featuremgmt.FlagIdForwarding) {
 		middlewares = append(middlewares, clientmiddleware.NewForwardIDMiddleware())
 	}
@@ -61,18 +61,19 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 }
 
 type AlertRuleV1 struct {
-	UID          values.StringValue    `json:"uid" yaml:"uid"`
-	Title        values.StringValue    `json:"title" yaml:"title"`
-	Condition    values.StringValue    `json:"condition" yaml:"condition"`
-	Data         []QueryV1             `json:"data" yaml:"data"`
-	DashboardUID values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
-	PanelID      values.Int64Value     `json:"panelId" yaml:"panelId"`
-	NoDataState  values.StringValue    `json:"noDataState" yaml:"noDataState"`
-	ExecErrState values.StringValue    `json:"execErrState" yaml:"execErrState"`
-	For          values.StringValue    `json:"for" yaml:"for"`
-	Annotations  values.StringMapValue `json:"annotations" yaml:"annotations"`
-	Labels       values.StringMapValue `json:"labels" yaml:"labels"`
-	IsPaused     values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	UID           values.StringValue    `json:"uid" yaml:"uid"`
+	Title         values.StringValue    `json:"title" yaml:"title"`
+	Condition     values.StringValue    `json:"condition" yaml:"condition"`
+	Data          []QueryV1             `json:"data" yaml:"data"`
+	DashboardUID  values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
+	PanelID       values.Int64Value     `json:"panelId" yaml:"panelId"`
+	NoDataState   values.StringValue    `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  values.StringValue    `json:"execErrState" yaml:"execErrState"`
+	For           values.StringValue    `json:"for" yaml:"for"`
+	KeepFiringFor values.StringValue    `json:"keepFiringFor" yaml:"keepFiringFor"`
+	Annotations   values.StringMapValue `json:"annotations" yaml:"annotations"`
+	Labels        values.StringMapValue `json:"labels" yaml:"labels"`
+	IsPaused      values.BoolValue      `json:"isPaused" yaml:"isPaused"`
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
@@ -91,6 +92,13 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
 	}
 	alertRule.For = time.Duration(duration)
+	if keepFiringFor := strings.TrimSpace(rule.KeepFiringFor.Value()); keepFiringFor != "" {
+		duration, err := model.ParseDuration(keepFiringFor)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.KeepFiringFor = time.Duration(duration)
+	}
 	dashboardUID := rule.DashboardUID.Value()
 	alertRule.DashboardUID = &dashboardUID
 	panelID := rule.PanelID.Value()
@@ -125,6 +125,31 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 48*time.Hour, ruleMapped.For)
 	})
+	t.Run("a rule with out a keep firing for duration should default to 0", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with an invalid keep firing for duration should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("10x"), &keepFiringFor)
+		rule.KeepFiringFor = keepFiringFor
+		require.NoError(t, err)
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a rule with a keep firing for duration should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("5m"), &keepFiringFor)
+		rule.KeepFiringFor = keepFiringFor
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, ruleMapped.KeepFiringFor)
+	})
 	t.Run("a rule with out a condition should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Condition = values.StringValue{}
@@ -106,6 +106,10 @@ func (cr *configReader) readConfig(ctx context.Context) ([]*config, error) {
 
 		if dashboard.UpdateIntervalSeconds == 0 {
 			dashboard.UpdateIntervalSeconds = 10
+			if dashboard.Type == "git" {
+				// Fetching a remote repository is more expensive than reading files.
+				dashboard.UpdateIntervalSeconds = 60
+			}
 		}
 		if len(dashboard.FolderUID) > 0 {
 			uidUsage[dashboard.FolderUID]++
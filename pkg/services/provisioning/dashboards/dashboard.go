@@ -3,12 +3,14 @@ package dashboards
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 // DashboardProvisioner is responsible for syncing dashboard from disk to
@@ -19,11 +21,15 @@ type DashboardProvisioner interface {
 	PollChanges(ctx context.Context)
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	GetWriteBackFromConfig(name string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
+	HandleGitWebhook(name string, header http.Header, body []byte) error
+	WriteBackDashboard(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, author *user.SignedInUser, message string) error
+	GetFailedWriteBacks() []FailedWriteBack
 }
 
 // DashboardProvisionerFactory creates DashboardProvisioners based on input
-type DashboardProvisionerFactory func(context.Context, string, dashboards.DashboardProvisioningService, org.Service, utils.DashboardStore) (DashboardProvisioner, error)
+type DashboardProvisionerFactory func(context.Context, string, string, dashboards.DashboardProvisioningService, org.Service, utils.DashboardStore) (DashboardProvisioner, error)
 
 // Provisioner is responsible for syncing dashboard from disk to Grafana's database.
 type Provisioner struct {
@@ -38,8 +44,8 @@ func (provider *Provisioner) HasDashboardSources() bool {
 	return len(provider.fileReaders) > 0
 }
 
-// New returns a new DashboardProvisioner
-func New(ctx context.Context, configDirectory string, provisioner dashboards.DashboardProvisioningService, orgService org.Service, dashboardStore utils.DashboardStore) (DashboardProvisioner, error) {
+// New returns a new DashboardProvisioner. The repositories of git providers are cloned in the data path.
+func New(ctx context.Context, configDirectory string, dataPath string, provisioner dashboards.DashboardProvisioningService, orgService org.Service, dashboardStore utils.DashboardStore) (DashboardProvisioner, error) {
 	logger := log.New("provisioning.dashboard")
 	cfgReader := &configReader{path: configDirectory, log: logger, orgService: orgService}
 	configs, err := cfgReader.readConfig(ctx)
@@ -47,7 +53,7 @@ func New(ctx context.Context, configDirectory string, provisioner dashboards.Das
 		return nil, fmt.Errorf("%v: %w", "Failed to read dashboards config", err)
 	}
 
-	fileReaders, err := getFileReaders(configs, logger, provisioner, dashboardStore)
+	fileReaders, err := getFileReaders(configs, dataPath, logger, provisioner, dashboardStore)
 	if err != nil {
 		return nil, fmt.Errorf("%v: %w", "Failed to initialize file readers", err)
 	}
@@ -120,14 +126,45 @@ func (provider *Provisioner) GetProvisionerResolvedPath(name string) string {
 func (provider *Provisioner) GetAllowUIUpdatesFromConfig(name string) bool {
 	for _, config := range provider.configs {
 		if config.Name == name {
-			return config.AllowUIUpdates
+			return config.AllowUIUpdates || config.WriteBack
 		}
 	}
 	return false
 }
 
+// GetWriteBackFromConfig return if a dashboard provisioner writes the updates from the UI back to its source
+func (provider *Provisioner) GetWriteBackFromConfig(name string) bool {
+	for _, config := range provider.configs {
+		if config.Name == name {
+			return config.WriteBack
+		}
+	}
+	return false
+}
+
+// WriteBackDashboard writes a dashboard saved from the UI back to the file it was provisioned from. For git
+// providers, the file is committed by the author with the message, and pushed to the branch of the provider
+// in the background.
+func (provider *Provisioner) WriteBackDashboard(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, author *user.SignedInUser, message string) error {
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name == provisioning.Name && reader.Cfg.WriteBack {
+			return reader.writeBack(ctx, provisioning, dash, author, message)
+		}
+	}
+	return fmt.Errorf("dashboard provider %q doesn't write back dashboards", provisioning.Name)
+}
+
+// GetFailedWriteBacks returns the dashboards saved from the UI that the git providers couldn't push.
+func (provider *Provisioner) GetFailedWriteBacks() []FailedWriteBack {
+	result := []FailedWriteBack{}
+	for _, reader := range provider.fileReaders {
+		result = append(result, reader.FailedWriteBacks()...)
+	}
+	return result
+}
+
 func getFileReaders(
-	configs []*config, logger log.Logger, service dashboards.DashboardProvisioningService, store utils.DashboardStore,
+	configs []*config, dataPath string, logger log.Logger, service dashboards.DashboardProvisioningService, store utils.DashboardStore,
 ) ([]*FileReader, error) {
 	var readers []*FileReader
 
@@ -139,6 +176,12 @@ func getFileReaders(
 				return nil, fmt.Errorf("failed to create file reader for config %v: %w", config.Name, err)
 			}
 			readers = append(readers, fileReader)
+		case "git":
+			gitReader, err := newGitFileReader(config, dataPath, logger.New("type", config.Type, "name", config.Name), service, store)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create git reader for config %v: %w", config.Name, err)
+			}
+			readers = append(readers, gitReader)
 		default:
 			return nil, fmt.Errorf("type %s is not supported", config.Type)
 		}
@@ -1,6 +1,12 @@
 package dashboards
 
-import "context"
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/user"
+)
 
 // Calls is a mock implementation of the provisioner interface
 type calls struct {
@@ -65,5 +71,25 @@ func (dpm *ProvisionerMock) GetAllowUIUpdatesFromConfig(name string) bool {
 	return false
 }
 
+// GetWriteBackFromConfig not implemented for mocks
+func (dpm *ProvisionerMock) GetWriteBackFromConfig(name string) bool {
+	return false
+}
+
 // CleanUpOrphanedDashboards not implemented for mocks
 func (dpm *ProvisionerMock) CleanUpOrphanedDashboards(ctx context.Context) {}
+
+// HandleGitWebhook not implemented for mocks
+func (dpm *ProvisionerMock) HandleGitWebhook(name string, header http.Header, body []byte) error {
+	return nil
+}
+
+// WriteBackDashboard not implemented for mocks
+func (dpm *ProvisionerMock) WriteBackDashboard(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, author *user.SignedInUser, message string) error {
+	return nil
+}
+
+// GetFailedWriteBacks not implemented for mocks
+func (dpm *ProvisionerMock) GetFailedWriteBacks() []FailedWriteBack {
+	return nil
+}
@@ -11,6 +11,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-git/go-git/v5/plumbing/object"
+
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -35,9 +37,15 @@ type FileReader struct {
 	dashboardStore               utils.DashboardStore
 	FoldersFromFilesStructure    bool
 
+	// git is the repository of the dashboards of git providers, nil for file providers.
+	git *gitRepository
+	// walkMux serializes the walks of the disk and the write-backs of dashboards saved from the UI.
+	walkMux sync.Mutex
+
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
 	dbWriteAccessRestricted bool
+	commit                  *object.Commit
 }
 
 // NewDashboardFileReader returns a new filereader based on `config`
@@ -53,6 +61,10 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		log.Warn("[Deprecated] The folder property is deprecated. Please use path instead.")
 	}
 
+	return newFileReader(cfg, path, log, service, dashboardStore)
+}
+
+func newFileReader(cfg *config, path string, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
 	foldersFromFilesStructure, _ := cfg.Options["foldersFromFilesStructure"].(bool)
 	if foldersFromFilesStructure && cfg.Folder != "" && cfg.FolderUID != "" {
 		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
@@ -69,25 +81,58 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 	}, nil
 }
 
-// pollChanges periodically runs walkDisk based on interval specified in the config.
+// pollChanges periodically runs walkDisk based on interval specified in the config,
+// and when the webhook of a git provider requests it. It also commits and pushes the
+// dashboards saved from the UI to the repository of a git provider.
 func (fr *FileReader) pollChanges(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(int64(time.Second) * fr.Cfg.UpdateIntervalSeconds))
+	var syncRequested chan struct{}
+	var writeBacks chan *writeBackRequest
+	if fr.git != nil {
+		syncRequested = fr.git.syncRequested
+		writeBacks = fr.git.writeBacks
+	}
 	for {
 		select {
 		case <-ticker.C:
-			if err := fr.walkDisk(ctx); err != nil {
-				fr.log.Error("failed to search for dashboards", "error", err)
-			}
+			fr.sync(ctx)
+		case <-syncRequested:
+			fr.sync(ctx)
+		case req := <-writeBacks:
+			fr.pushWriteBack(ctx, req)
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// sync retries the dashboards of a git provider that couldn't be pushed, before walkDisk
+// provisions the files again.
+func (fr *FileReader) sync(ctx context.Context) {
+	if fr.git != nil {
+		fr.retryWriteBacks(ctx)
+	}
+	if err := fr.walkDisk(ctx); err != nil {
+		fr.log.Error("failed to search for dashboards", "error", err)
+	}
+}
+
 // walkDisk traverses the file system for the defined path, reading dashboard definition files,
 // and applies any change to the database.
 func (fr *FileReader) walkDisk(ctx context.Context) error {
+	fr.walkMux.Lock()
+	defer fr.walkMux.Unlock()
+
 	fr.log.Debug("Start walking disk", "path", fr.Path)
+	if fr.git != nil {
+		// The files of the last commit checked out are provisioned if the repository can't be fetched.
+		commit, err := fr.git.sync(ctx)
+		if err != nil {
+			fr.log.Error("Failed to sync git repository", "url", fr.git.url, "error", err)
+		}
+		fr.setCommit(commit)
+	}
+
 	resolvedPath := fr.resolvedPath()
 	if _, err := os.Stat(resolvedPath); err != nil {
 		return err
@@ -130,6 +175,21 @@ func (fr *FileReader) changeWritePermissions(restrict bool) {
 	fr.dbWriteAccessRestricted = restrict
 }
 
+func (fr *FileReader) setCommit(commit *object.Commit) {
+	fr.mux.Lock()
+	defer fr.mux.Unlock()
+
+	fr.commit = commit
+}
+
+// checkedOutCommit returns the git commit the dashboards are read from, or nil for file providers.
+func (fr *FileReader) checkedOutCommit() *object.Commit {
+	fr.mux.RLock()
+	defer fr.mux.RUnlock()
+
+	return fr.commit
+}
+
 func (fr *FileReader) isDatabaseAccessRestricted() bool {
 	fr.mux.RLock()
 	defer fr.mux.RUnlock()
@@ -258,6 +318,10 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 		dash.Dashboard.SetID(provisionedData.DashboardID)
 	}
 
+	if commit := fr.checkedOutCommit(); commit != nil {
+		dash.Message = commitMessage(commit)
+	}
+
 	if !fr.isDatabaseAccessRestricted() {
 		fr.log.Debug("saving new dashboard", "provisioner", fr.Cfg.Name, "file", path, "folderId", dash.Dashboard.FolderID)
 		dp := &dashboards.DashboardProvisioning{
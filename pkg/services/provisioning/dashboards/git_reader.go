@@ -0,0 +1,396 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const gitRemoteName = "origin"
+
+// maxPendingWriteBacks is the number of dashboards saved from the UI that can wait to be
+// committed and pushed to the repository of a git provider.
+const maxPendingWriteBacks = 100
+
+// maxPushAttempts is the number of times a dashboard is committed and pushed to the branch,
+// committing it again on top of the new commits of the branch when the push fails.
+const maxPushAttempts = 3
+
+// failedWriteBacksFile is the file in the .git directory of the checkout where the dashboards
+// that couldn't be pushed are kept, so they are retried after Grafana restarts.
+const failedWriteBacksFile = "grafana-failed-write-backs.json"
+
+// gitRepository keeps a local clone of the repository of a git provider, checked
+// out at the latest commit of its branch or tag.
+type gitRepository struct {
+	url    string
+	branch string
+	tag    string
+	dir    string
+	auth   transport.AuthMethod
+	log    log.Logger
+
+	// syncRequested is signaled by webhooks, to fetch the repository before the next poll.
+	syncRequested chan struct{}
+	// writeBacks are the dashboards saved from the UI waiting to be committed and pushed.
+	writeBacks chan *writeBackRequest
+
+	failedMu sync.Mutex
+	// failed are the dashboards that couldn't be pushed by their file, retried on every sync.
+	failed map[string]*failedWriteBack
+
+	mu     sync.Mutex
+	repo   *git.Repository
+	commit *object.Commit
+}
+
+// newGitFileReader returns a FileReader of the dashboards of a git provider. The
+// repository is cloned in the data path, and the path option is the directory
+// of the dashboards within the repository.
+func newGitFileReader(cfg *config, dataPath string, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
+	subPath, _ := cfg.Options["path"].(string)
+	if subPath != "" && !filepath.IsLocal(subPath) {
+		return nil, fmt.Errorf("failed to load dashboards, path param must be a relative path within the repository")
+	}
+
+	key, err := util.Md5SumString(cfg.Name)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := newGitRepository(cfg, filepath.Join(dataPath, "provisioning", "dashboards", "git", key), log)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := newFileReader(cfg, filepath.Join(repo.dir, subPath), log, service, dashboardStore)
+	if err != nil {
+		return nil, err
+	}
+	reader.git = repo
+	return reader, nil
+}
+
+func newGitRepository(cfg *config, dir string, log log.Logger) (*gitRepository, error) {
+	url, ok := cfg.Options["url"].(string)
+	if !ok || url == "" {
+		return nil, fmt.Errorf("failed to load dashboards, url param is not a string")
+	}
+	branch, _ := cfg.Options["branch"].(string)
+	tag, _ := cfg.Options["tag"].(string)
+	if branch != "" && tag != "" {
+		return nil, fmt.Errorf("'branch' and 'tag' options can't be set together")
+	}
+	if cfg.WriteBack && tag != "" {
+		return nil, fmt.Errorf("'writeBack' can't be set for a provider checking out a tag")
+	}
+
+	auth, err := gitAuth(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &gitRepository{
+		url:           url,
+		branch:        branch,
+		tag:           tag,
+		dir:           dir,
+		auth:          auth,
+		log:           log,
+		syncRequested: make(chan struct{}, 1),
+		writeBacks:    make(chan *writeBackRequest, maxPendingWriteBacks),
+		failed:        map[string]*failedWriteBack{},
+	}
+	if err := repo.loadFailedWriteBacks(); err != nil {
+		log.Error("Failed to load the dashboards that couldn't be pushed", "url", url, "error", err)
+	}
+	return repo, nil
+}
+
+// gitAuth returns the authentication method set in the secure options: an SSH
+// key, or a token used as the password of HTTP basic authentication.
+func gitAuth(cfg *config) (transport.AuthMethod, error) {
+	username, _ := cfg.Options["username"].(string)
+	if username == "" {
+		username = "git"
+	}
+
+	if key := cfg.SecureOptions["sshKey"]; key != "" {
+		keys, err := gitssh.NewPublicKeys(username, []byte(key), cfg.SecureOptions["sshKeyPassphrase"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the ssh key: %w", err)
+		}
+		if knownHosts, _ := cfg.Options["knownHosts"].(string); knownHosts != "" {
+			callback, err := gitssh.NewKnownHostsCallback(knownHosts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load the known hosts: %w", err)
+			}
+			keys.HostKeyCallback = callback
+		}
+		return keys, nil
+	}
+
+	if token := cfg.SecureOptions["token"]; token != "" {
+		return &githttp.BasicAuth{Username: username, Password: token}, nil
+	}
+	return nil, nil
+}
+
+// requestSync makes the provider fetch the repository without waiting for the next poll.
+func (r *gitRepository) requestSync() {
+	select {
+	case r.syncRequested <- struct{}{}:
+	default:
+	}
+}
+
+// sync fetches the repository and checks out the latest commit of its branch or
+// tag. It returns the commit the files are checked out at, which is the last
+// one checked out if the sync fails.
+func (r *gitRepository) sync(ctx context.Context) (*object.Commit, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.repo == nil {
+		repo, err := r.open()
+		if err != nil {
+			return nil, err
+		}
+		r.repo = repo
+		if head, err := repo.Head(); err == nil {
+			r.commit, _ = repo.CommitObject(head.Hash())
+		}
+	}
+	return r.checkout(ctx)
+}
+
+// checkout fetches the repository and checks out the latest commit of its branch or tag.
+// The caller must hold the lock of the repository.
+func (r *gitRepository) checkout(ctx context.Context) (*object.Commit, error) {
+	err := r.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: gitRemoteName,
+		RefSpecs: []gitconfig.RefSpec{
+			"+refs/heads/*:refs/remotes/" + gitRemoteName + "/*",
+			"+refs/tags/*:refs/tags/*",
+		},
+		Auth:  r.auth,
+		Tags:  git.NoTags,
+		Force: true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return r.commit, fmt.Errorf("failed to fetch %s: %w", r.url, err)
+	}
+
+	ref, err := r.reference(ctx)
+	if err != nil {
+		return r.commit, err
+	}
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return r.commit, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	if r.commit != nil && r.commit.Hash == *hash {
+		return r.commit, nil
+	}
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return r.commit, err
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true}); err != nil {
+		return r.commit, fmt.Errorf("failed to check out %s: %w", hash, err)
+	}
+	commit, err := r.repo.CommitObject(*hash)
+	if err != nil {
+		return r.commit, err
+	}
+
+	r.log.Info("Checked out git commit", "url", r.url, "ref", ref, "commit", hash.String())
+	r.commit = commit
+	return commit, nil
+}
+
+// commitFile writes the content to the file at path in the checkout, commits it on top of the
+// checked out commit and pushes it to the branch. When the branch has new commits, they are
+// checked out and the file is committed again on top of them. The checkout is reset to the
+// last commit checked out if the commit can't be pushed.
+func (r *gitRepository) commitFile(ctx context.Context, path string, content []byte, author *object.Signature, message string) (*object.Commit, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.repo == nil || r.commit == nil {
+		return nil, fmt.Errorf("git repository %s is not checked out", r.url)
+	}
+	// The path of the file is resolved by the file reader, which evaluates the symlinks.
+	dir, err := filepath.Abs(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("file %s is not in the git repository", path)
+	}
+	ref, err := r.reference(ctx)
+	if err != nil {
+		return nil, err
+	}
+	branch := strings.TrimPrefix(ref, plumbing.NewRemoteReferenceName(gitRemoteName, "").String())
+
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 1; ; attempt++ {
+		commit, err := r.commitAndPush(ctx, worktree, branch, path, rel, content, author, message)
+		if err == nil || attempt == maxPushAttempts {
+			return commit, err
+		}
+
+		// The push is retried only if the branch has moved on since the last checkout.
+		base := r.commit.Hash
+		if _, syncErr := r.checkout(ctx); syncErr != nil || r.commit.Hash == base {
+			return nil, err
+		}
+		r.log.Info("Branch has new commits, committing the dashboard again on top of them", "url", r.url, "branch", branch, "commit", r.commit.Hash.String())
+	}
+}
+
+// commitAndPush commits the file on top of the checked out commit and pushes it to the branch.
+func (r *gitRepository) commitAndPush(ctx context.Context, worktree *git.Worktree, branch, path, rel string, content []byte, author *object.Signature, message string) (*object.Commit, error) {
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return nil, err
+	}
+	if _, err := worktree.Add(rel); err != nil {
+		return nil, r.reset(worktree, err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, r.reset(worktree, err)
+	}
+	if status.IsClean() {
+		return r.commit, nil
+	}
+
+	hash, err := worktree.Commit(message, &git.CommitOptions{Author: author})
+	if err != nil {
+		return nil, r.reset(worktree, err)
+	}
+	branchRef := plumbing.NewBranchReferenceName(branch)
+	if err := r.repo.Storer.SetReference(plumbing.NewHashReference(branchRef, hash)); err != nil {
+		return nil, r.reset(worktree, err)
+	}
+	err = r.repo.PushContext(ctx, &git.PushOptions{
+		RemoteName: gitRemoteName,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(branchRef + ":" + branchRef)},
+		Auth:       r.auth,
+	})
+	if err != nil {
+		return nil, r.reset(worktree, fmt.Errorf("failed to push to the %s branch of %s: %w", branch, r.url, err))
+	}
+	commit, err := r.repo.CommitObject(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	r.log.Info("Pushed git commit", "url", r.url, "branch", branch, "commit", hash.String())
+	r.commit = commit
+	return commit, nil
+}
+
+// reset checks out the last commit checked out again, discarding the changes of a failed commit.
+func (r *gitRepository) reset(worktree *git.Worktree, err error) error {
+	if resetErr := worktree.Checkout(&git.CheckoutOptions{Hash: r.commit.Hash, Force: true}); resetErr != nil {
+		r.log.Error("Failed to reset git checkout", "url", r.url, "commit", r.commit.Hash.String(), "error", resetErr)
+	}
+	return err
+}
+
+// open opens the local clone of the repository, creating it on first use.
+func (r *gitRepository) open() (*git.Repository, error) {
+	repo, err := git.PlainOpen(r.dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		if err := os.MkdirAll(r.dir, 0750); err != nil {
+			return nil, err
+		}
+		repo, err = git.PlainInit(r.dir, false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository in %s: %w", r.dir, err)
+	}
+
+	// The url of the provider can change between restarts.
+	remote, err := repo.Remote(gitRemoteName)
+	if err == nil && len(remote.Config().URLs) > 0 && remote.Config().URLs[0] == r.url {
+		return repo, nil
+	}
+	if err == nil {
+		if err := repo.DeleteRemote(gitRemoteName); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: gitRemoteName, URLs: []string{r.url}}); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// reference returns the name of the reference to check out: the tag, the
+// branch, or the default branch of the remote repository.
+func (r *gitRepository) reference(ctx context.Context) (string, error) {
+	if r.tag != "" {
+		return plumbing.NewTagReferenceName(r.tag).String(), nil
+	}
+	if r.branch != "" {
+		return plumbing.NewRemoteReferenceName(gitRemoteName, r.branch).String(), nil
+	}
+
+	remote, err := r.repo.Remote(gitRemoteName)
+	if err != nil {
+		return "", err
+	}
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: r.auth})
+	if err != nil {
+		return "", fmt.Errorf("failed to list the references of %s: %w", r.url, err)
+	}
+
+	var head *plumbing.Reference
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD {
+			head = ref
+		}
+	}
+	if head == nil {
+		return "", fmt.Errorf("failed to find the default branch of %s", r.url)
+	}
+	for _, ref := range refs {
+		if ref.Name().IsBranch() && (ref.Name() == head.Target() || ref.Hash() == head.Hash()) {
+			return plumbing.NewRemoteReferenceName(gitRemoteName, ref.Name().Short()).String(), nil
+		}
+	}
+	return "", fmt.Errorf("failed to find the default branch of %s", r.url)
+}
+
+// commitMessage returns the message of the dashboard versions saved from the commit.
+func commitMessage(commit *object.Commit) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	return fmt.Sprintf("Provisioned from git commit %s by %s: %s", commit.Hash.String()[:7], commit.Author.Name, subject)
+}
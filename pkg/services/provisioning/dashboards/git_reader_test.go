@@ -0,0 +1,218 @@
+package dashboards
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+// testGitRemote is a local bare repository to provision dashboards from.
+type testGitRemote struct {
+	t    *testing.T
+	url  string
+	work *git.Repository
+	dir  string
+}
+
+func newTestGitRemote(t *testing.T) *testGitRemote {
+	url := t.TempDir()
+	remote, err := git.PlainInit(url, true)
+	require.NoError(t, err)
+	require.NoError(t, remote.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))))
+
+	dir := t.TempDir()
+	work, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	require.NoError(t, work.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))))
+	_, err = work.CreateRemote(&gitconfig.RemoteConfig{Name: gitRemoteName, URLs: []string{url}})
+	require.NoError(t, err)
+
+	return &testGitRemote{t: t, url: url, work: work, dir: dir}
+}
+
+// commit pushes a commit to the main branch that writes the files, or deletes
+// them if their content is empty.
+func (r *testGitRemote) commit(message string, files map[string]string) plumbing.Hash {
+	for name, content := range files {
+		path := filepath.Join(r.dir, name)
+		if content == "" {
+			require.NoError(r.t, os.Remove(path))
+			continue
+		}
+		require.NoError(r.t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(r.t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	worktree, err := r.work.Worktree()
+	require.NoError(r.t, err)
+	require.NoError(r.t, worktree.AddWithOptions(&git.AddOptions{All: true}))
+	hash, err := worktree.Commit(message, &git.CommitOptions{
+		All:    true,
+		Author: &object.Signature{Name: "Jane Doe", Email: "jane@example.com", When: time.Now()},
+	})
+	require.NoError(r.t, err)
+	require.NoError(r.t, r.work.Push(&git.PushOptions{RemoteName: gitRemoteName, RefSpecs: []gitconfig.RefSpec{"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"}}))
+	return hash
+}
+
+func (r *testGitRemote) tag(name string, hash plumbing.Hash) {
+	_, err := r.work.CreateTag(name, hash, &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "Jane Doe", Email: "jane@example.com", When: time.Now()},
+		Message: "Release " + name,
+	})
+	require.NoError(r.t, err)
+	require.NoError(r.t, r.work.Push(&git.PushOptions{RemoteName: gitRemoteName, RefSpecs: []gitconfig.RefSpec{"refs/tags/*:refs/tags/*"}}))
+}
+
+func TestGitRepository(t *testing.T) {
+	newRepository := func(t *testing.T, options map[string]any) *gitRepository {
+		repo, err := newGitRepository(&config{Name: "git", Type: "git", Options: options}, t.TempDir(), log.New("test-logger"))
+		require.NoError(t, err)
+		return repo
+	}
+	readFile := func(t *testing.T, repo *gitRepository, name string) string {
+		content, err := os.ReadFile(filepath.Join(repo.dir, name))
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("checks out the changes pushed to the default branch", func(t *testing.T) {
+		remote := newTestGitRemote(t)
+		first := remote.commit("Add dashboards", map[string]string{"a.json": "a1", "b.json": "b1"})
+		repo := newRepository(t, map[string]any{"url": remote.url})
+
+		commit, err := repo.sync(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, first, commit.Hash)
+		require.Equal(t, "a1", readFile(t, repo, "a.json"))
+
+		second := remote.commit("Update dashboards", map[string]string{"a.json": "a2", "b.json": ""})
+		commit, err = repo.sync(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, second, commit.Hash)
+		require.Equal(t, "a2", readFile(t, repo, "a.json"))
+		require.NoFileExists(t, filepath.Join(repo.dir, "b.json"))
+	})
+
+	t.Run("checks out a tag", func(t *testing.T) {
+		remote := newTestGitRemote(t)
+		first := remote.commit("Add dashboards", map[string]string{"a.json": "a1"})
+		remote.tag("v1", first)
+		remote.commit("Update dashboards", map[string]string{"a.json": "a2"})
+		repo := newRepository(t, map[string]any{"url": remote.url, "tag": "v1"})
+
+		commit, err := repo.sync(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, first, commit.Hash)
+		require.Equal(t, "a1", readFile(t, repo, "a.json"))
+	})
+
+	t.Run("keeps the last commit checked out when the repository can't be fetched", func(t *testing.T) {
+		remote := newTestGitRemote(t)
+		first := remote.commit("Add dashboards", map[string]string{"a.json": "a1"})
+		repo := newRepository(t, map[string]any{"url": remote.url})
+		_, err := repo.sync(context.Background())
+		require.NoError(t, err)
+
+		require.NoError(t, os.RemoveAll(remote.url))
+		commit, err := repo.sync(context.Background())
+		require.Error(t, err)
+		require.Equal(t, first, commit.Hash)
+		require.Equal(t, "a1", readFile(t, repo, "a.json"))
+	})
+
+	t.Run("fails without a url", func(t *testing.T) {
+		_, err := newGitRepository(&config{Name: "git", Type: "git", Options: map[string]any{}}, t.TempDir(), log.New("test-logger"))
+		require.Error(t, err)
+	})
+}
+
+func TestGitFileReader(t *testing.T) {
+	remote := newTestGitRemote(t)
+	hash := remote.commit("Add dashboards", map[string]string{
+		"README.md":            "not a dashboard",
+		"dashboards/team.json": `{"title": "Team", "uid": "team"}`,
+	})
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+	fakeService.On("GetProvisionedDashboardData", mock.Anything, "git").Return(nil, nil).Once()
+	fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&dashboards.Dashboard{ID: 1}, nil).Once().
+		Run(func(args mock.Arguments) {
+			dash := args.Get(1).(*dashboards.SaveDashboardDTO)
+			assert.Equal(t, "Team", dash.Dashboard.Title)
+			assert.Equal(t, "Provisioned from git commit "+hash.String()[:7]+" by Jane Doe: Add dashboards", dash.Message)
+		})
+
+	cfg := &config{Name: "git", Type: "git", OrgID: 1, Options: map[string]any{"url": remote.url, "path": "dashboards"}}
+	reader, err := newGitFileReader(cfg, t.TempDir(), log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+	require.NoError(t, reader.walkDisk(context.Background()))
+
+	t.Run("fails with a path outside of the repository", func(t *testing.T) {
+		cfg := &config{Name: "git", Type: "git", OrgID: 1, Options: map[string]any{"url": remote.url, "path": "../dashboards"}}
+		_, err := newGitFileReader(cfg, t.TempDir(), log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.Error(t, err)
+	})
+}
+
+func TestHandleGitWebhook(t *testing.T) {
+	body := []byte(`{"ref": "refs/heads/main"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	setup := func(t *testing.T, secureOptions map[string]string) (*Provisioner, *gitRepository) {
+		cfg := &config{Name: "git", Type: "git", Options: map[string]any{"url": "https://example.com/dashboards.git"}, SecureOptions: secureOptions}
+		reader, err := newGitFileReader(cfg, t.TempDir(), log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		return &Provisioner{fileReaders: []*FileReader{reader}}, reader.git
+	}
+
+	for _, tc := range []struct {
+		name     string
+		header   http.Header
+		expected error
+	}{
+		{name: "github signature", header: http.Header{"X-Hub-Signature-256": {"sha256=" + signature}}},
+		{name: "gitea signature", header: http.Header{"X-Gitea-Signature": {signature}}},
+		{name: "gitlab token", header: http.Header{"X-Gitlab-Token": {"secret"}}},
+		{name: "invalid signature", header: http.Header{"X-Hub-Signature-256": {"sha256=" + hex.EncodeToString([]byte("invalid"))}}, expected: ErrInvalidWebhookSignature},
+		{name: "invalid token", header: http.Header{"X-Gitlab-Token": {"invalid"}}, expected: ErrInvalidWebhookSignature},
+		{name: "missing signature", header: http.Header{}, expected: ErrInvalidWebhookSignature},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			provisioner, repo := setup(t, map[string]string{"webhookSecret": "secret"})
+			err := provisioner.HandleGitWebhook("git", tc.header, body)
+			require.ErrorIs(t, err, tc.expected)
+			require.Equal(t, tc.expected == nil, len(repo.syncRequested) == 1)
+		})
+	}
+
+	t.Run("fails for unknown providers", func(t *testing.T) {
+		provisioner, _ := setup(t, map[string]string{"webhookSecret": "secret"})
+		require.ErrorIs(t, provisioner.HandleGitWebhook("other", http.Header{"X-Gitlab-Token": {"secret"}}, body), ErrGitProviderNotFound)
+	})
+
+	t.Run("fails for providers without a webhook secret", func(t *testing.T) {
+		provisioner, _ := setup(t, nil)
+		require.ErrorIs(t, provisioner.HandleGitWebhook("git", http.Header{"X-Gitlab-Token": {""}}, body), ErrGitProviderNotFound)
+	})
+}
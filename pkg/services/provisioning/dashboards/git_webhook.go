@@ -0,0 +1,60 @@
+package dashboards
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrGitProviderNotFound is returned when there is no git provider with the name, or it has no webhook secret.
+	ErrGitProviderNotFound = errors.New("git dashboard provider not found")
+	// ErrInvalidWebhookSignature is returned when a webhook request isn't signed with the webhook secret.
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+)
+
+// HandleGitWebhook makes the git provider with the name fetch its repository, if the
+// webhook request is signed with its webhook secret. It supports the signatures of
+// GitHub and Gitea webhooks, and the tokens of GitLab webhooks.
+func (provider *Provisioner) HandleGitWebhook(name string, header http.Header, body []byte) error {
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name != name || reader.git == nil {
+			continue
+		}
+
+		secret := reader.Cfg.SecureOptions["webhookSecret"]
+		if secret == "" {
+			return ErrGitProviderNotFound
+		}
+		if !validWebhookSignature(secret, header, body) {
+			return ErrInvalidWebhookSignature
+		}
+
+		reader.log.Debug("Git repository sync requested by webhook")
+		reader.git.requestSync()
+		return nil
+	}
+	return ErrGitProviderNotFound
+}
+
+func validWebhookSignature(secret string, header http.Header, body []byte) bool {
+	if token := header.Get("X-Gitlab-Token"); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+
+	signature := strings.TrimPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+	if signature == "" {
+		signature = header.Get("X-Gitea-Signature")
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -17,9 +17,11 @@ type config struct {
 	FolderUID             string
 	Editable              bool
 	Options               map[string]any
+	SecureOptions         map[string]string
 	DisableDeletion       bool
 	UpdateIntervalSeconds int64
 	AllowUIUpdates        bool
+	WriteBack             bool
 }
 
 type configV0 struct {
@@ -33,6 +35,7 @@ type configV0 struct {
 	DisableDeletion       bool           `json:"disableDeletion" yaml:"disableDeletion"`
 	UpdateIntervalSeconds int64          `json:"updateIntervalSeconds" yaml:"updateIntervalSeconds"`
 	AllowUIUpdates        bool           `json:"allowUiUpdates" yaml:"allowUiUpdates"`
+	WriteBack             bool           `json:"writeBack" yaml:"writeBack"`
 }
 
 type configVersion struct {
@@ -44,16 +47,18 @@ type configV1 struct {
 }
 
 type configs struct {
-	Name                  values.StringValue `json:"name" yaml:"name"`
-	Type                  values.StringValue `json:"type" yaml:"type"`
-	OrgID                 values.Int64Value  `json:"orgId" yaml:"orgId"`
-	Folder                values.StringValue `json:"folder" yaml:"folder"`
-	FolderUID             values.StringValue `json:"folderUid" yaml:"folderUid"`
-	Editable              values.BoolValue   `json:"editable" yaml:"editable"`
-	Options               values.JSONValue   `json:"options" yaml:"options"`
-	DisableDeletion       values.BoolValue   `json:"disableDeletion" yaml:"disableDeletion"`
-	UpdateIntervalSeconds values.Int64Value  `json:"updateIntervalSeconds" yaml:"updateIntervalSeconds"`
-	AllowUIUpdates        values.BoolValue   `json:"allowUiUpdates" yaml:"allowUiUpdates"`
+	Name                  values.StringValue    `json:"name" yaml:"name"`
+	Type                  values.StringValue    `json:"type" yaml:"type"`
+	OrgID                 values.Int64Value     `json:"orgId" yaml:"orgId"`
+	Folder                values.StringValue    `json:"folder" yaml:"folder"`
+	FolderUID             values.StringValue    `json:"folderUid" yaml:"folderUid"`
+	Editable              values.BoolValue      `json:"editable" yaml:"editable"`
+	Options               values.JSONValue      `json:"options" yaml:"options"`
+	SecureOptions         values.StringMapValue `json:"secureOptions" yaml:"secureOptions"`
+	DisableDeletion       values.BoolValue      `json:"disableDeletion" yaml:"disableDeletion"`
+	UpdateIntervalSeconds values.Int64Value     `json:"updateIntervalSeconds" yaml:"updateIntervalSeconds"`
+	AllowUIUpdates        values.BoolValue      `json:"allowUiUpdates" yaml:"allowUiUpdates"`
+	WriteBack             values.BoolValue      `json:"writeBack" yaml:"writeBack"`
 }
 
 func createDashboardJSON(data *simplejson.Json, lastModified time.Time, cfg *config, folderID int64) (*dashboards.SaveDashboardDTO, error) {
@@ -93,6 +98,7 @@ func mapV0ToDashboardsAsConfig(v0 []*configV0) ([]*config, error) {
 			DisableDeletion:       v.DisableDeletion,
 			UpdateIntervalSeconds: v.UpdateIntervalSeconds,
 			AllowUIUpdates:        v.AllowUIUpdates,
+			WriteBack:             v.WriteBack,
 		})
 	}
 
@@ -117,9 +123,11 @@ func (dc *configV1) mapToDashboardsAsConfig() ([]*config, error) {
 			FolderUID:             v.FolderUID.Value(),
 			Editable:              v.Editable.Value(),
 			Options:               v.Options.Value(),
+			SecureOptions:         v.SecureOptions.Value(),
 			DisableDeletion:       v.DisableDeletion.Value(),
 			UpdateIntervalSeconds: v.UpdateIntervalSeconds.Value(),
 			AllowUIUpdates:        v.AllowUIUpdates.Value(),
+			WriteBack:             v.WriteBack.Value(),
 		})
 	}
 
@@ -0,0 +1,309 @@
+package dashboards
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// writeBackRequest is a dashboard saved from the UI to write back to the file it was provisioned from.
+type writeBackRequest struct {
+	dashboardID int64
+	path        string
+	content     []byte
+	login       string
+	// author and message are the author and the message of the commit of git providers.
+	author  *object.Signature
+	message string
+}
+
+// failedWriteBack is a dashboard of a git provider that couldn't be pushed, kept to be retried.
+type failedWriteBack struct {
+	DashboardID int64     `json:"dashboardId"`
+	Path        string    `json:"path"`
+	Content     []byte    `json:"content"`
+	Login       string    `json:"login"`
+	AuthorName  string    `json:"authorName"`
+	AuthorEmail string    `json:"authorEmail"`
+	Message     string    `json:"message"`
+	Error       string    `json:"error"`
+	Failed      time.Time `json:"failed"`
+}
+
+func (f *failedWriteBack) request() *writeBackRequest {
+	return &writeBackRequest{
+		dashboardID: f.DashboardID,
+		path:        f.Path,
+		content:     f.Content,
+		login:       f.Login,
+		author:      &object.Signature{Name: f.AuthorName, Email: f.AuthorEmail, When: time.Now()},
+		message:     f.Message,
+	}
+}
+
+// FailedWriteBack is a dashboard saved from the UI that couldn't be pushed to the repository of a git provider.
+// It's pushed again on every sync of the provider, until it succeeds or the dashboard is saved again.
+type FailedWriteBack struct {
+	Provider string    `json:"provider"`
+	File     string    `json:"file"`
+	Login    string    `json:"login"`
+	Message  string    `json:"message"`
+	Error    string    `json:"error"`
+	Failed   time.Time `json:"failed"`
+}
+
+// writeBack writes the dashboard back to the file it was provisioned from. File providers write
+// the file right away, while git providers queue the dashboard to be committed and pushed by
+// pollChanges, so that saving the dashboard doesn't wait for the remote repository.
+func (fr *FileReader) writeBack(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, author *user.SignedInUser, message string) error {
+	path := provisioning.ExternalID
+	rel, err := filepath.Rel(fr.resolvedPath(), path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return fmt.Errorf("dashboard file %s is not in the path of the provider", path)
+	}
+
+	content, err := dashboardFileContent(dash)
+	if err != nil {
+		return err
+	}
+	req := &writeBackRequest{dashboardID: provisioning.DashboardID, path: path, content: content, login: author.Login}
+	if fr.git == nil {
+		return fr.applyWriteBack(ctx, req)
+	}
+
+	req.message = message
+	if req.message == "" {
+		req.message = fmt.Sprintf("Update dashboard %s", dash.Title)
+	}
+	req.author = &object.Signature{Name: author.Name, Email: author.Email, When: time.Now()}
+	if req.author.Name == "" {
+		req.author.Name = author.Login
+	}
+	select {
+	case fr.git.writeBacks <- req:
+		return nil
+	default:
+		return fmt.Errorf("too many dashboards are waiting to be pushed to git repository %s", fr.git.url)
+	}
+}
+
+// applyWriteBack writes the dashboard to its file, committing and pushing it for git providers,
+// and updates the checksum of the provisioned dashboard so that the file isn't provisioned again.
+func (fr *FileReader) applyWriteBack(ctx context.Context, req *writeBackRequest) error {
+	fr.walkMux.Lock()
+	defer fr.walkMux.Unlock()
+
+	if fr.git != nil {
+		commit, err := fr.git.commitFile(ctx, req.path, req.content, req.author, req.message)
+		if err != nil {
+			return err
+		}
+		fr.setCommit(commit)
+	} else if err := writeDashboardFile(req.path, req.content); err != nil {
+		return err
+	}
+
+	fileInfo, err := os.Stat(req.path)
+	if err != nil {
+		return err
+	}
+	checkSum, err := util.Md5SumString(string(req.content))
+	if err != nil {
+		return err
+	}
+
+	fr.log.Info("Wrote back dashboard saved from the UI", "file", req.path, "user", req.login)
+	return fr.dashboardProvisioningService.UpdateProvisionedDashboardData(ctx, &dashboards.DashboardProvisioning{
+		DashboardID: req.dashboardID,
+		Name:        fr.Cfg.Name,
+		ExternalID:  req.path,
+		CheckSum:    checkSum,
+		Updated:     fileInfo.ModTime().Unix(),
+	})
+}
+
+// pushWriteBack commits and pushes a dashboard to the repository of a git provider. The dashboard
+// is kept to be retried if it can't be pushed.
+func (fr *FileReader) pushWriteBack(ctx context.Context, req *writeBackRequest) {
+	err := fr.applyWriteBack(ctx, req)
+	if err != nil {
+		fr.log.Error("Failed to write back dashboard", "file", req.path, "user", req.login, "error", err)
+	}
+	fr.git.setFailedWriteBack(req, err)
+}
+
+// retryWriteBacks pushes the dashboards that couldn't be pushed again.
+func (fr *FileReader) retryWriteBacks(ctx context.Context) {
+	for _, failed := range fr.git.failedWriteBacks() {
+		fr.log.Info("Retrying to write back dashboard", "file", failed.Path, "user", failed.Login)
+		fr.pushWriteBack(ctx, failed.request())
+	}
+}
+
+// FailedWriteBacks returns the dashboards saved from the UI that couldn't be pushed to the
+// repository of the git provider.
+func (fr *FileReader) FailedWriteBacks() []FailedWriteBack {
+	if fr.git == nil {
+		return nil
+	}
+
+	var result []FailedWriteBack
+	for _, failed := range fr.git.failedWriteBacks() {
+		file := failed.Path
+		if rel, err := filepath.Rel(fr.resolvedPath(), failed.Path); err == nil {
+			file = rel
+		}
+		result = append(result, FailedWriteBack{
+			Provider: fr.Cfg.Name,
+			File:     file,
+			Login:    failed.Login,
+			Message:  failed.Message,
+			Error:    failed.Error,
+			Failed:   failed.Failed,
+		})
+	}
+	return result
+}
+
+// failedWriteBacks returns the dashboards that couldn't be pushed, the oldest first.
+func (r *gitRepository) failedWriteBacks() []*failedWriteBack {
+	r.failedMu.Lock()
+	defer r.failedMu.Unlock()
+
+	result := make([]*failedWriteBack, 0, len(r.failed))
+	for _, failed := range r.failed {
+		result = append(result, failed)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Failed.Before(result[j].Failed) })
+	return result
+}
+
+// setFailedWriteBack keeps the dashboard if it failed to be pushed, or forgets the previous failure
+// of its file otherwise, since the last dashboard saved replaces the content of the file.
+func (r *gitRepository) setFailedWriteBack(req *writeBackRequest, err error) {
+	r.failedMu.Lock()
+	defer r.failedMu.Unlock()
+
+	if err == nil {
+		if _, ok := r.failed[req.path]; !ok {
+			return
+		}
+		delete(r.failed, req.path)
+	} else {
+		failed := &failedWriteBack{
+			DashboardID: req.dashboardID,
+			Path:        req.path,
+			Content:     req.content,
+			Login:       req.login,
+			Message:     req.message,
+			Error:       err.Error(),
+			Failed:      time.Now(),
+		}
+		if req.author != nil {
+			failed.AuthorName, failed.AuthorEmail = req.author.Name, req.author.Email
+		}
+		r.failed[req.path] = failed
+	}
+
+	if err := r.saveFailedWriteBacks(); err != nil {
+		r.log.Error("Failed to save the dashboards that couldn't be pushed", "url", r.url, "error", err)
+	}
+}
+
+// loadFailedWriteBacks loads the dashboards that couldn't be pushed before Grafana restarted.
+func (r *gitRepository) loadFailedWriteBacks() error {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because the file is in the data path of Grafana.
+	data, err := os.ReadFile(filepath.Join(r.dir, git.GitDirName, failedWriteBacksFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var failed []*failedWriteBack
+	if err := json.Unmarshal(data, &failed); err != nil {
+		return err
+	}
+	for _, f := range failed {
+		r.failed[f.Path] = f
+	}
+	return nil
+}
+
+// saveFailedWriteBacks saves the dashboards that couldn't be pushed. The caller must hold failedMu.
+func (r *gitRepository) saveFailedWriteBacks() error {
+	path := filepath.Join(r.dir, git.GitDirName, failedWriteBacksFile)
+	if len(r.failed) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	failed := make([]*failedWriteBack, 0, len(r.failed))
+	for _, f := range r.failed {
+		failed = append(failed, f)
+	}
+	data, err := json.Marshal(failed)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// dashboardFileContent returns the JSON of the dashboard as written to provisioning files,
+// without the id which is specific to the Grafana instance.
+func dashboardFileContent(dash *dashboards.Dashboard) ([]byte, error) {
+	data, err := dash.Data.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var model map[string]any
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, err
+	}
+	delete(model, "id")
+
+	content, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// writeDashboardFile replaces the content of the dashboard file, keeping its permissions.
+func writeDashboardFile(path string, content []byte) error {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` is in the path of the provisioning configuration file.
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(current, content) {
+		return nil
+	}
+	return os.WriteFile(path, content, fileInfo.Mode().Perm())
+}
@@ -0,0 +1,193 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestWriteBack(t *testing.T) {
+	author := &user.SignedInUser{Login: "jdoe", Name: "John Doe", Email: "john@example.com"}
+	savedDashboard := &dashboards.Dashboard{
+		ID:    1,
+		Title: "Team",
+		Data:  simplejson.NewFromAny(map[string]any{"id": 1, "uid": "team", "title": "Team", "version": 2}),
+	}
+	const savedContent = "{\n  \"title\": \"Team\",\n  \"uid\": \"team\",\n  \"version\": 2\n}\n"
+	checkSum, err := util.Md5SumString(savedContent)
+	require.NoError(t, err)
+
+	expectUpdate := func(t *testing.T, fakeService *dashboards.FakeDashboardProvisioning, path string) {
+		fakeService.On("UpdateProvisionedDashboardData", mock.Anything, mock.MatchedBy(func(provisioning *dashboards.DashboardProvisioning) bool {
+			return provisioning.DashboardID == 1 && provisioning.Name == "default" && provisioning.ExternalID == path && provisioning.CheckSum == checkSum
+		})).Return(nil).Once()
+	}
+
+	t.Run("writes the dashboard to the file of file providers", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "team.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"title": "Team", "uid": "team"}`), 0600))
+
+		fakeService := dashboards.NewFakeDashboardProvisioning(t)
+		expectUpdate(t, fakeService, path)
+
+		cfg := &config{Name: "default", Type: "file", OrgID: 1, WriteBack: true, Options: map[string]any{"path": dir}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+
+		provisioning := &dashboards.DashboardProvisioning{DashboardID: 1, Name: "default", ExternalID: path}
+		require.NoError(t, reader.writeBack(context.Background(), provisioning, savedDashboard, author, "Update team dashboard"))
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, savedContent, string(content))
+	})
+
+	t.Run("fails for files outside of the path of the provider", func(t *testing.T) {
+		fakeService := dashboards.NewFakeDashboardProvisioning(t)
+		cfg := &config{Name: "default", Type: "file", OrgID: 1, WriteBack: true, Options: map[string]any{"path": t.TempDir()}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+
+		provisioning := &dashboards.DashboardProvisioning{DashboardID: 1, Name: "default", ExternalID: filepath.Join(t.TempDir(), "team.json")}
+		require.Error(t, reader.writeBack(context.Background(), provisioning, savedDashboard, author, ""))
+	})
+
+	setupGit := func(t *testing.T) (*testGitRemote, *FileReader, *dashboards.FakeDashboardProvisioning) {
+		remote := newTestGitRemote(t)
+		remote.commit("Add dashboards", map[string]string{"dashboards/team.json": `{"title": "Team", "uid": "team"}`})
+
+		fakeService := dashboards.NewFakeDashboardProvisioning(t)
+		cfg := &config{Name: "default", Type: "git", OrgID: 1, WriteBack: true, Options: map[string]any{"url": remote.url, "path": "dashboards"}}
+		reader, err := newGitFileReader(cfg, t.TempDir(), log.New("test-logger"), fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+		_, err = reader.git.sync(context.Background())
+		require.NoError(t, err)
+		return remote, reader, fakeService
+	}
+
+	t.Run("commits the dashboard and pushes it to the branch of git providers", func(t *testing.T) {
+		remote, reader, fakeService := setupGit(t)
+		path := filepath.Join(reader.resolvedPath(), "team.json")
+		expectUpdate(t, fakeService, path)
+
+		provisioning := &dashboards.DashboardProvisioning{DashboardID: 1, Name: "default", ExternalID: path}
+		require.NoError(t, reader.writeBack(context.Background(), provisioning, savedDashboard, author, "Update team dashboard"))
+
+		// the dashboard is pushed in the background
+		repo, err := git.PlainOpen(remote.url)
+		require.NoError(t, err)
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+		require.NoError(t, err)
+		commit, err := repo.CommitObject(ref.Hash())
+		require.NoError(t, err)
+		require.Equal(t, "Add dashboards", commit.Message)
+		require.Len(t, reader.git.writeBacks, 1)
+
+		require.NoError(t, reader.applyWriteBack(context.Background(), <-reader.git.writeBacks))
+		ref, err = repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+		require.NoError(t, err)
+		commit, err = repo.CommitObject(ref.Hash())
+		require.NoError(t, err)
+		require.Equal(t, "Update team dashboard", commit.Message)
+		require.Equal(t, "John Doe", commit.Author.Name)
+		require.Equal(t, "john@example.com", commit.Author.Email)
+		require.Equal(t, commit.Hash, reader.checkedOutCommit().Hash)
+
+		file, err := commit.File("dashboards/team.json")
+		require.NoError(t, err)
+		content, err := file.Contents()
+		require.NoError(t, err)
+		require.Equal(t, savedContent, content)
+	})
+
+	t.Run("commits the dashboard again on top of the new commits of the branch", func(t *testing.T) {
+		remote, reader, fakeService := setupGit(t)
+		remote.commit("Add ops dashboard", map[string]string{"dashboards/ops.json": `{"title": "Ops", "uid": "ops"}`})
+		path := filepath.Join(reader.resolvedPath(), "team.json")
+		expectUpdate(t, fakeService, path)
+
+		provisioning := &dashboards.DashboardProvisioning{DashboardID: 1, Name: "default", ExternalID: path}
+		require.NoError(t, reader.writeBack(context.Background(), provisioning, savedDashboard, author, "Update team dashboard"))
+		require.NoError(t, reader.applyWriteBack(context.Background(), <-reader.git.writeBacks))
+
+		repo, err := git.PlainOpen(remote.url)
+		require.NoError(t, err)
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+		require.NoError(t, err)
+		commit, err := repo.CommitObject(ref.Hash())
+		require.NoError(t, err)
+		require.Equal(t, "Update team dashboard", commit.Message)
+		parent, err := commit.Parent(0)
+		require.NoError(t, err)
+		require.Equal(t, "Add ops dashboard", parent.Message)
+		_, err = commit.File("dashboards/ops.json")
+		require.NoError(t, err)
+		require.Equal(t, commit.Hash, reader.checkedOutCommit().Hash)
+	})
+
+	t.Run("keeps the dashboards that can't be pushed and retries them", func(t *testing.T) {
+		remote, reader, fakeService := setupGit(t)
+		path := filepath.Join(reader.resolvedPath(), "team.json")
+
+		unreachable := remote.url + ".moved"
+		require.NoError(t, os.Rename(remote.url, unreachable))
+		provisioning := &dashboards.DashboardProvisioning{DashboardID: 1, Name: "default", ExternalID: path}
+		require.NoError(t, reader.writeBack(context.Background(), provisioning, savedDashboard, author, "Update team dashboard"))
+		reader.pushWriteBack(context.Background(), <-reader.git.writeBacks)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, `{"title": "Team", "uid": "team"}`, string(content))
+		failed := reader.FailedWriteBacks()
+		require.Len(t, failed, 1)
+		require.Equal(t, "default", failed[0].Provider)
+		require.Equal(t, "team.json", failed[0].File)
+		require.Equal(t, "jdoe", failed[0].Login)
+		require.NotEmpty(t, failed[0].Error)
+
+		// the dashboards that can't be pushed are kept when the provider is reloaded
+		repo, err := newGitRepository(reader.Cfg, reader.git.dir, log.New("test-logger"))
+		require.NoError(t, err)
+		require.Len(t, repo.failedWriteBacks(), 1)
+
+		require.NoError(t, os.Rename(unreachable, remote.url))
+		expectUpdate(t, fakeService, path)
+		reader.retryWriteBacks(context.Background())
+		require.Empty(t, reader.FailedWriteBacks())
+		require.Equal(t, "Update team dashboard", reader.checkedOutCommit().Message)
+
+		repo, err = newGitRepository(reader.Cfg, reader.git.dir, log.New("test-logger"))
+		require.NoError(t, err)
+		require.Empty(t, repo.failedWriteBacks())
+	})
+
+	t.Run("fails when too many dashboards are waiting to be pushed", func(t *testing.T) {
+		_, reader, _ := setupGit(t)
+		path := filepath.Join(reader.resolvedPath(), "team.json")
+
+		provisioning := &dashboards.DashboardProvisioning{DashboardID: 1, Name: "default", ExternalID: path}
+		for i := 0; i < maxPendingWriteBacks; i++ {
+			require.NoError(t, reader.writeBack(context.Background(), provisioning, savedDashboard, author, ""))
+		}
+		require.Error(t, reader.writeBack(context.Background(), provisioning, savedDashboard, author, ""))
+	})
+
+	t.Run("fails for git providers checking out a tag", func(t *testing.T) {
+		cfg := &config{Name: "default", Type: "git", WriteBack: true, Options: map[string]any{"url": "https://example.com/dashboards.git", "tag": "v1"}}
+		_, err := newGitFileReader(cfg, t.TempDir(), log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
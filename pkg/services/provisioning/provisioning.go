@@ -3,6 +3,7 @@ package provisioning
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"path/filepath"
 	"sync"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -89,6 +91,10 @@ type ProvisioningService interface {
 	ProvisionAlerting(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	HandleDashboardsGitWebhook(name string, header http.Header, body []byte) error
+	GetWriteBackFromConfig(name string) bool
+	WriteBackDashboard(ctx context.Context, provisioning *dashboardservice.DashboardProvisioning, dash *dashboardservice.Dashboard, author *user.SignedInUser, message string) error
+	GetDashboardsFailedWriteBacks() []dashboards.FailedWriteBack
 }
 
 // Add a public constructor for overriding service to be able to instantiate OSS as fallback
@@ -239,7 +245,7 @@ func (ps *ProvisioningServiceImpl) ProvisionNotifications(ctx context.Context) e
 
 func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) error {
 	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
-	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService)
+	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.Cfg.DataPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService)
 	if err != nil {
 		return fmt.Errorf("%v: %w", "Failed to create provisioner", err)
 	}
@@ -306,6 +312,47 @@ func (ps *ProvisioningServiceImpl) GetAllowUIUpdatesFromConfig(name string) bool
 	return ps.dashboardProvisioner.GetAllowUIUpdatesFromConfig(name)
 }
 
+// HandleDashboardsGitWebhook makes the git dashboard provider with the name fetch its repository.
+func (ps *ProvisioningServiceImpl) HandleDashboardsGitWebhook(name string, header http.Header, body []byte) error {
+	ps.mutex.Lock()
+	provisioner := ps.dashboardProvisioner
+	ps.mutex.Unlock()
+
+	if provisioner == nil {
+		return dashboards.ErrGitProviderNotFound
+	}
+	return provisioner.HandleGitWebhook(name, header, body)
+}
+
+func (ps *ProvisioningServiceImpl) GetWriteBackFromConfig(name string) bool {
+	return ps.dashboardProvisioner.GetWriteBackFromConfig(name)
+}
+
+// WriteBackDashboard writes a dashboard saved from the UI back to the source of its dashboard provider.
+// Git providers push the dashboard in the background, and keep the dashboards they fail to push to retry them.
+func (ps *ProvisioningServiceImpl) WriteBackDashboard(ctx context.Context, provisioning *dashboardservice.DashboardProvisioning, dash *dashboardservice.Dashboard, author *user.SignedInUser, message string) error {
+	ps.mutex.Lock()
+	provisioner := ps.dashboardProvisioner
+	ps.mutex.Unlock()
+
+	if provisioner == nil {
+		return fmt.Errorf("dashboard provider %q not found", provisioning.Name)
+	}
+	return provisioner.WriteBackDashboard(ctx, provisioning, dash, author, message)
+}
+
+// GetDashboardsFailedWriteBacks returns the dashboards saved from the UI that git providers couldn't push.
+func (ps *ProvisioningServiceImpl) GetDashboardsFailedWriteBacks() []dashboards.FailedWriteBack {
+	ps.mutex.Lock()
+	provisioner := ps.dashboardProvisioner
+	ps.mutex.Unlock()
+
+	if provisioner == nil {
+		return []dashboards.FailedWriteBack{}
+	}
+	return provisioner.GetFailedWriteBacks()
+}
+
 func (ps *ProvisioningServiceImpl) cancelPolling() {
 	if ps.pollingCtxCancel != nil {
 		ps.log.Debug("Stop polling for dashboard changes")
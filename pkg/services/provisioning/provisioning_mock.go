@@ -1,6 +1,13 @@
 package provisioning
 
-import "context"
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashboardprovisioning "github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+	"github.com/grafana/grafana/pkg/services/user"
+)
 
 type Calls struct {
 	RunInitProvisioners                 []any
@@ -11,6 +18,10 @@ type Calls struct {
 	ProvisionAlerting                   []any
 	GetDashboardProvisionerResolvedPath []any
 	GetAllowUIUpdatesFromConfig         []any
+	HandleDashboardsGitWebhook          []any
+	GetWriteBackFromConfig              []any
+	WriteBackDashboard                  []any
+	GetDashboardsFailedWriteBacks       []any
 	Run                                 []any
 }
 
@@ -23,6 +34,10 @@ type ProvisioningServiceMock struct {
 	ProvisionDashboardsFunc                 func() error
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
+	HandleDashboardsGitWebhookFunc          func(name string, header http.Header, body []byte) error
+	GetWriteBackFromConfigFunc              func(name string) bool
+	WriteBackDashboardFunc                  func(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, author *user.SignedInUser, message string) error
+	GetDashboardsFailedWriteBacksFunc       func() []dashboardprovisioning.FailedWriteBack
 	RunFunc                                 func(ctx context.Context) error
 }
 
@@ -93,6 +108,38 @@ func (mock *ProvisioningServiceMock) GetAllowUIUpdatesFromConfig(name string) bo
 	return false
 }
 
+func (mock *ProvisioningServiceMock) HandleDashboardsGitWebhook(name string, header http.Header, body []byte) error {
+	mock.Calls.HandleDashboardsGitWebhook = append(mock.Calls.HandleDashboardsGitWebhook, name)
+	if mock.HandleDashboardsGitWebhookFunc != nil {
+		return mock.HandleDashboardsGitWebhookFunc(name, header, body)
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) GetWriteBackFromConfig(name string) bool {
+	mock.Calls.GetWriteBackFromConfig = append(mock.Calls.GetWriteBackFromConfig, name)
+	if mock.GetWriteBackFromConfigFunc != nil {
+		return mock.GetWriteBackFromConfigFunc(name)
+	}
+	return false
+}
+
+func (mock *ProvisioningServiceMock) WriteBackDashboard(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, author *user.SignedInUser, message string) error {
+	mock.Calls.WriteBackDashboard = append(mock.Calls.WriteBackDashboard, provisioning)
+	if mock.WriteBackDashboardFunc != nil {
+		return mock.WriteBackDashboardFunc(ctx, provisioning, dash, author, message)
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) GetDashboardsFailedWriteBacks() []dashboardprovisioning.FailedWriteBack {
+	mock.Calls.GetDashboardsFailedWriteBacks = append(mock.Calls.GetDashboardsFailedWriteBacks, nil)
+	if mock.GetDashboardsFailedWriteBacksFunc != nil {
+		return mock.GetDashboardsFailedWriteBacksFunc()
+	}
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) Run(ctx context.Context) error {
 	mock.Calls.Run = append(mock.Calls.Run, nil)
 	if mock.RunFunc != nil {
@@ -72,6 +72,11 @@ func (api *Api) RegisterAPIEndpoints() {
 		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, uidScope)),
 		routing.Wrap(api.GetPublicDashboard))
 
+	// Get public dashboard access log
+	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/access-log",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.GetPublicDashboardAccessLog))
+
 	// Create Public Dashboard
 	api.RouteRegister.Post("/api/dashboards/uid/:dashboardUid/public-dashboards",
 		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
@@ -152,6 +157,36 @@ func (api *Api) GetPublicDashboard(c *contextmodel.ReqContext) response.Response
 	return response.JSON(http.StatusOK, pd)
 }
 
+// swagger:route GET /dashboards/uid/{dashboardUid}/public-dashboards/{uid}/access-log dashboard_public getPublicDashboardAccessLog
+//
+//	Get the most recent entries of the access log of a public dashboard
+//
+// Responses:
+// 200: getPublicDashboardAccessLogResponse
+// 400: badRequestPublicError
+// 401: unauthorisedPublicError
+// 403: forbiddenPublicError
+// 404: notFoundPublicError
+// 500: internalServerPublicError
+func (api *Api) GetPublicDashboardAccessLog(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("GetPublicDashboardAccessLog: invalid Uid %s", uid))
+	}
+
+	dashboardUid := web.Params(c.Req)[":dashboardUid"]
+	if !validation.IsValidShortUID(dashboardUid) {
+		return response.Err(ErrInvalidUid.Errorf("GetPublicDashboardAccessLog: invalid dashboard Uid %s", dashboardUid))
+	}
+
+	entries, err := api.PublicDashboardService.FindAccessLogs(c.Req.Context(), c.SignedInUser.GetOrgID(), uid, dashboardUid)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, entries)
+}
+
 // swagger:route POST /dashboards/uid/{dashboardUid}/public-dashboards dashboard_public createPublicDashboard
 //
 //	Create public dashboard for a dashboard
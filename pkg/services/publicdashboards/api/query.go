@@ -35,6 +35,11 @@ func (api *Api) ViewPublicDashboard(c *contextmodel.ReqContext) response.Respons
 		return response.Err(err)
 	}
 
+	// Only successful views are logged, with panel 0. Failing to log does not fail the view.
+	if err := api.PublicDashboardService.LogAccess(c.Req.Context(), accessToken, 0, c.RemoteAddr()); err != nil {
+		api.Log.Warn("Failed to log public dashboard access", "error", err)
+	}
+
 	return response.JSON(http.StatusOK, dto)
 }
 
@@ -71,6 +76,11 @@ func (api *Api) QueryPublicDashboard(c *contextmodel.ReqContext) response.Respon
 		return response.Err(err)
 	}
 
+	// Only successful queries are logged. Failing to log does not fail the query.
+	if err := api.PublicDashboardService.LogAccess(c.Req.Context(), accessToken, panelId, c.RemoteAddr()); err != nil {
+		api.Log.Warn("Failed to log public dashboard access", "error", err)
+	}
+
 	return toJsonStreamingResponse(api.Features, resp)
 }
 
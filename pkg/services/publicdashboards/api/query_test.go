@@ -96,6 +96,10 @@ func TestAPIViewPublicDashboard(t *testing.T) {
 			service := publicdashboards.NewFakePublicDashboardService(t)
 			service.On("GetPublicDashboardForView", mock.Anything, mock.AnythingOfType("string")).
 				Return(test.DashboardResult, test.Err).Maybe()
+			if test.ExpectedHttpResponse == http.StatusOK {
+				service.On("LogAccess", mock.Anything, test.AccessToken, int64(0), mock.AnythingOfType("string")).
+					Return(nil).Once()
+			}
 
 			cfg := setting.NewCfg()
 
@@ -243,6 +247,7 @@ func TestAPIQueryPublicDashboard(t *testing.T) {
 	t.Run("Returns query data when feature toggle is enabled", func(t *testing.T) {
 		server, fakeDashboardService := setup(true)
 		fakeDashboardService.On("GetQueryDataResponse", mock.Anything, true, mock.Anything, int64(2), validAccessToken).Return(mockedResponse, nil)
+		fakeDashboardService.On("LogAccess", mock.Anything, validAccessToken, int64(2), mock.AnythingOfType("string")).Return(nil).Once()
 
 		resp := callAPI(server, http.MethodPost, getValidQueryPath(validAccessToken), strings.NewReader("{}"), t)
 
@@ -3,6 +3,8 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"strconv"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -212,9 +214,10 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByDashboardUid(ctx context.Conte
 func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	hasPublicDashboard := false
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true"
+		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true" +
+			" AND (expires_at IS NULL OR expires_at > ?)"
 
-		result, err := dbSession.SQL(sql, accessToken).Count()
+		result, err := dbSession.SQL(sql, accessToken, formatTime(time.Now())).Count()
 		if err != nil {
 			return err
 		}
@@ -268,14 +271,21 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		var expiresAt any
+		if cmd.PublicDashboard.ExpiresAt != nil {
+			expiresAt = formatTime(*cmd.PublicDashboard.ExpiresAt)
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, expires_at = ?, max_views = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			cmd.PublicDashboard.Share,
 			string(timeSettingsJSON),
+			expiresAt,
+			cmd.PublicDashboard.MaxViews,
 			cmd.PublicDashboard.UpdatedBy,
-			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
+			formatTime(cmd.PublicDashboard.UpdatedAt),
 			cmd.PublicDashboard.Uid)
 
 		if err != nil {
@@ -294,16 +304,122 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 func (d *PublicDashboardStoreImpl) Delete(ctx context.Context, uid string) (int64, error) {
 	dashboard := &PublicDashboard{Uid: uid}
 	var affectedRows int64
-	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		var err error
 		affectedRows, err = sess.Delete(dashboard)
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Exec("DELETE FROM dashboard_public_access_log WHERE public_dashboard_uid = ?", uid)
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// IncrementViewCount Increments the view count of a public dashboard by access token, unless it reached its max
+// views. Responds false when the max views is reached. The check and the increment are a single statement, so
+// concurrent views can't go past the limit.
+func (d *PublicDashboardStoreImpl) IncrementViewCount(ctx context.Context, accessToken string) (bool, error) {
+	incremented := false
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET view_count = view_count + 1 WHERE access_token = ? AND (max_views = 0 OR view_count < max_views)", accessToken)
+		if err != nil {
+			return err
+		}
+
+		affectedRows, err := sqlResult.RowsAffected()
+		incremented = affectedRows > 0
+		return err
+	})
+
+	return incremented, err
+}
+
+// DisableExpired Disables the enabled public dashboards that expired at the given time
+func (d *PublicDashboardStoreImpl) DisableExpired(ctx context.Context, now time.Time) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ? WHERE is_enabled = ? AND expires_at IS NOT NULL AND expires_at <= ?",
+			false, true, formatTime(now))
+		if err != nil {
+			return err
+		}
 
+		affectedRows, err = sqlResult.RowsAffected()
 		return err
 	})
 
 	return affectedRows, err
 }
 
+// CreateAccessLog Adds an entry to the access log of a public dashboard
+func (d *PublicDashboardStoreImpl) CreateAccessLog(ctx context.Context, entry *PublicDashboardAccessLog) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(entry)
+		return err
+	})
+}
+
+// DeleteStaleAccessLogs Deletes the access log entries created before the given time, then the oldest entries
+// over the row limit, at most 10000 per call
+func (d *PublicDashboardStoreImpl) DeleteStaleAccessLogs(ctx context.Context, olderThan time.Time, rowLimit int64) (int64, error) {
+	var deletedRows int64
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		sqlResult, err := sess.Exec("DELETE FROM dashboard_public_access_log WHERE created < ?", formatTime(olderThan))
+		if err != nil {
+			return err
+		}
+		deletedRows, err = sqlResult.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		rowsCount, err := sess.Table("dashboard_public_access_log").Count(&PublicDashboardAccessLog{})
+		if err != nil {
+			return err
+		}
+		countRowsToDelete := rowsCount - rowLimit
+		if countRowsToDelete <= 0 {
+			return nil
+		}
+		if countRowsToDelete > 10000 {
+			countRowsToDelete = 10000
+		}
+
+		sqlResult, err = sess.Exec(`DELETE FROM dashboard_public_access_log
+			WHERE id IN (
+				SELECT id FROM (
+					SELECT id FROM dashboard_public_access_log
+					ORDER BY id ASC
+					LIMIT ?
+				) AS l
+			)`, strconv.FormatInt(countRowsToDelete, 10))
+		if err != nil {
+			return err
+		}
+		overLimitRows, err := sqlResult.RowsAffected()
+		deletedRows += overLimitRows
+		return err
+	})
+
+	return deletedRows, err
+}
+
+// FindAccessLogs Returns the most recent entries of the access log of a public dashboard
+func (d *PublicDashboardStoreImpl) FindAccessLogs(ctx context.Context, uid string, limit int) ([]*PublicDashboardAccessLog, error) {
+	entries := make([]*PublicDashboardAccessLog, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("public_dashboard_uid = ?", uid).Desc("created", "id").Limit(limit).Find(&entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
 func (d *PublicDashboardStoreImpl) FindByDashboardFolder(ctx context.Context, dashboard *dashboards.Dashboard) ([]*PublicDashboard, error) {
 	if dashboard == nil || !dashboard.IsFolder {
 		return nil, nil
@@ -334,3 +450,8 @@ func (d *PublicDashboardStoreImpl) GetMetrics(ctx context.Context) (*Metrics, er
 
 	return metrics, nil
 }
+
+// formatTime formats a time the way it is stored in the database
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
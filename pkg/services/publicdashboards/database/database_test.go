@@ -764,6 +764,213 @@ func TestIntegrationDelete(t *testing.T) {
 	})
 }
 
+func TestIntegrationExpiration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var sqlStore db.DB
+	var cfg *setting.Cfg
+	var dashboardStore dashboards.Store
+	var publicdashboardStore *PublicDashboardStoreImpl
+	var savedDashboard *dashboards.Dashboard
+	var savedPublicDashboard *PublicDashboard
+	var err error
+
+	setup := func() {
+		sqlStore, cfg = db.InitTestDBwithCfg(t)
+		dashboardStore, err = dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+		require.NoError(t, err)
+		publicdashboardStore = ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+		savedDashboard = insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, "", true)
+		savedPublicDashboard = insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true, PublicShareType)
+	}
+
+	update := func(expiresAt *time.Time, maxViews int64) {
+		savedPublicDashboard.ExpiresAt = expiresAt
+		savedPublicDashboard.MaxViews = maxViews
+		savedPublicDashboard.UpdatedAt = time.Now()
+		_, err := publicdashboardStore.Update(context.Background(), SavePublicDashboardCommand{PublicDashboard: *savedPublicDashboard})
+		require.NoError(t, err)
+	}
+
+	t.Run("Update saves the expiration and max views", func(t *testing.T) {
+		setup()
+		expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		update(&expiresAt, 10)
+
+		pubdash, err := publicdashboardStore.Find(context.Background(), savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		require.NotNil(t, pubdash.ExpiresAt)
+		assert.True(t, expiresAt.Equal(*pubdash.ExpiresAt))
+		assert.EqualValues(t, 10, pubdash.MaxViews)
+
+		update(nil, 0)
+		pubdash, err = publicdashboardStore.Find(context.Background(), savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		assert.Nil(t, pubdash.ExpiresAt)
+		assert.EqualValues(t, 0, pubdash.MaxViews)
+	})
+
+	incrementViewCount := func(accessToken string) bool {
+		incremented, err := publicdashboardStore.IncrementViewCount(context.Background(), accessToken)
+		require.NoError(t, err)
+		return incremented
+	}
+
+	t.Run("IncrementViewCount increments the view count", func(t *testing.T) {
+		setup()
+		require.True(t, incrementViewCount(savedPublicDashboard.AccessToken))
+		require.True(t, incrementViewCount(savedPublicDashboard.AccessToken))
+
+		pubdash, err := publicdashboardStore.Find(context.Background(), savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, pubdash.ViewCount)
+	})
+
+	t.Run("IncrementViewCount does not increment the view count past max views", func(t *testing.T) {
+		setup()
+		update(nil, 2)
+		require.True(t, incrementViewCount(savedPublicDashboard.AccessToken))
+		require.True(t, incrementViewCount(savedPublicDashboard.AccessToken))
+		require.False(t, incrementViewCount(savedPublicDashboard.AccessToken))
+
+		pubdash, err := publicdashboardStore.Find(context.Background(), savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, pubdash.ViewCount)
+	})
+
+	t.Run("ExistsEnabledByAccessToken will return false when expired", func(t *testing.T) {
+		setup()
+		expiresAt := time.Now().Add(-time.Minute)
+		update(&expiresAt, 0)
+
+		res, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), savedPublicDashboard.AccessToken)
+		require.NoError(t, err)
+		require.False(t, res)
+	})
+
+	t.Run("ExistsEnabledByAccessToken will return true when max views is reached", func(t *testing.T) {
+		setup()
+		update(nil, 1)
+		require.True(t, incrementViewCount(savedPublicDashboard.AccessToken))
+
+		res, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), savedPublicDashboard.AccessToken)
+		require.NoError(t, err)
+		require.True(t, res)
+	})
+
+	t.Run("DisableExpired disables expired public dashboards", func(t *testing.T) {
+		setup()
+		expiresAt := time.Now().Add(time.Hour)
+		update(&expiresAt, 0)
+
+		viewLimited := insertPublicDashboard(t, publicdashboardStore, insertTestDashboard(t, dashboardStore, "viewLimited", 1, 0, "", true).UID, 1, true, PublicShareType)
+		viewLimited.MaxViews = 1
+		viewLimited.UpdatedAt = time.Now()
+		_, err := publicdashboardStore.Update(context.Background(), SavePublicDashboardCommand{PublicDashboard: *viewLimited})
+		require.NoError(t, err)
+		require.True(t, incrementViewCount(viewLimited.AccessToken))
+
+		unlimited := insertPublicDashboard(t, publicdashboardStore, insertTestDashboard(t, dashboardStore, "unlimited", 1, 0, "", true).UID, 1, true, PublicShareType)
+
+		affectedRows, err := publicdashboardStore.DisableExpired(context.Background(), time.Now())
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, affectedRows)
+
+		affectedRows, err = publicdashboardStore.DisableExpired(context.Background(), time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, affectedRows)
+
+		for uid, enabled := range map[string]bool{savedPublicDashboard.Uid: false, viewLimited.Uid: true, unlimited.Uid: true} {
+			pubdash, err := publicdashboardStore.Find(context.Background(), uid)
+			require.NoError(t, err)
+			assert.Equal(t, enabled, pubdash.IsEnabled)
+		}
+	})
+}
+
+func TestIntegrationAccessLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBwithCfg(t)
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+	require.NoError(t, err)
+	publicdashboardStore := ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+	savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, "", true)
+	savedPublicDashboard := insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true, PublicShareType)
+
+	now := time.Now().Truncate(time.Second)
+	for i, ipHash := range []string{"first", "second"} {
+		err := publicdashboardStore.CreateAccessLog(context.Background(), &PublicDashboardAccessLog{
+			PublicDashboardUid: savedPublicDashboard.Uid,
+			OrgId:              savedPublicDashboard.OrgId,
+			PanelId:            int64(i),
+			IpHash:             ipHash,
+			Created:            now.Add(time.Duration(i) * time.Second),
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("FindAccessLogs returns the most recent entries first", func(t *testing.T) {
+		entries, err := publicdashboardStore.FindAccessLogs(context.Background(), savedPublicDashboard.Uid, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "second", entries[0].IpHash)
+		assert.Equal(t, "first", entries[1].IpHash)
+		assert.Equal(t, int64(1), entries[0].PanelId)
+		assert.Equal(t, int64(0), entries[1].PanelId)
+
+		entries, err = publicdashboardStore.FindAccessLogs(context.Background(), savedPublicDashboard.Uid, 1)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("DeleteStaleAccessLogs deletes old entries and entries over the row limit", func(t *testing.T) {
+		for i, ipHash := range []string{"old", "newer", "newest"} {
+			err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+				_, err := sess.NoAutoTime().Insert(&PublicDashboardAccessLog{
+					PublicDashboardUid: "other",
+					OrgId:              savedPublicDashboard.OrgId,
+					IpHash:             ipHash,
+					Created:            now.Add(-time.Hour).Add(time.Duration(i) * time.Minute),
+				})
+				return err
+			})
+			require.NoError(t, err)
+		}
+
+		deleted, err := publicdashboardStore.DeleteStaleAccessLogs(context.Background(), now.Add(-time.Hour).Add(time.Minute), 10)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+
+		deleted, err = publicdashboardStore.DeleteStaleAccessLogs(context.Background(), now.Add(-24*time.Hour), 3)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+
+		entries, err := publicdashboardStore.FindAccessLogs(context.Background(), "other", 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "newest", entries[0].IpHash)
+		assert.Equal(t, "newer", entries[1].IpHash)
+
+		// the row limit deletes the entries that were added first
+		entries, err = publicdashboardStore.FindAccessLogs(context.Background(), savedPublicDashboard.Uid, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "second", entries[0].IpHash)
+	})
+
+	t.Run("Delete removes the access log", func(t *testing.T) {
+		_, err := publicdashboardStore.Delete(context.Background(), savedPublicDashboard.Uid)
+		require.NoError(t, err)
+
+		entries, err := publicdashboardStore.FindAccessLogs(context.Background(), savedPublicDashboard.Uid, 10)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
+
 func TestGetDashboardByFolder(t *testing.T) {
 	t.Run("returns nil when dashboard is not a folder", func(t *testing.T) {
 		sqlStore, _ := db.InitTestDBwithCfg(t)
@@ -23,6 +23,10 @@ var (
 	ErrDashboardIsPublic                   = errutil.BadRequest("publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
 	ErrPublicDashboardUidExists            = errutil.BadRequest("publicdashboards.uidExists", errutil.WithPublicMessage("Public Dashboard Uid already exists"))
 	ErrPublicDashboardAccessTokenExists    = errutil.BadRequest("publicdashboards.accessTokenExists", errutil.WithPublicMessage("Public Dashboard Access Token already exists"))
+	ErrInvalidMaxViews                     = errutil.BadRequest("publicdashboards.invalidMaxViews", errutil.WithPublicMessage("maxViews should not be negative"))
+	ErrInvalidTemplateVariable             = errutil.BadRequest("publicdashboards.invalidTemplateVariable", errutil.WithPublicMessage("Invalid template variable value"))
 
-	ErrPublicDashboardNotEnabled = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
+	ErrPublicDashboardNotEnabled       = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
+	ErrPublicDashboardExpired          = errutil.Forbidden("publicdashboards.expired", errutil.WithPublicMessage("Public dashboard expired"))
+	ErrPublicDashboardViewLimitReached = errutil.Forbidden("publicdashboards.viewLimitReached", errutil.WithPublicMessage("Public dashboard reached its max number of views"))
 )
@@ -53,6 +53,16 @@ type PublicDashboard struct {
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	Share                ShareType     `json:"share" xorm:"share"`
 	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	// ExpiresAt is the time after which the public dashboard can't be accessed. Nil means it never expires.
+	ExpiresAt *time.Time `json:"expiresAt" xorm:"expires_at"`
+	// MaxViews is the number of times the public dashboard can be viewed. Zero means no limit.
+	MaxViews  int64 `json:"maxViews" xorm:"max_views"`
+	ViewCount int64 `json:"viewCount" xorm:"view_count"`
+}
+
+// IsExpired returns true if the public dashboard has an expiration time and it has passed
+func (pd PublicDashboard) IsExpired(now time.Time) bool {
+	return pd.ExpiresAt != nil && !pd.ExpiresAt.After(now)
 }
 
 type PublicDashboardDTO struct {
@@ -62,6 +72,10 @@ type PublicDashboardDTO struct {
 	IsEnabled            *bool     `json:"isEnabled"`
 	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
 	Share                ShareType `json:"share"`
+	// ExpiresAt is the expiration time of the public dashboard. The zero time removes the expiration.
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// MaxViews is the max number of views of the public dashboard. Zero removes the limit.
+	MaxViews *int64 `json:"maxViews"`
 }
 
 type EmailDTO struct {
@@ -119,6 +133,21 @@ type PublicDashboardListResponse struct {
 	IsEnabled    bool   `json:"isEnabled" xorm:"is_enabled"`
 }
 
+// PublicDashboardAccessLog is an entry of the access log of a public dashboard, for a view of the dashboard or
+// a query of one of its panels. Viewers are anonymous, so only a hash of their IP address is recorded.
+type PublicDashboardAccessLog struct {
+	Id                 int64     `json:"id" xorm:"pk autoincr 'id'"`
+	PublicDashboardUid string    `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	OrgId              int64     `json:"-" xorm:"org_id"`
+	PanelId            int64     `json:"panelId" xorm:"panel_id"`
+	IpHash             string    `json:"ipHash" xorm:"ip_hash"`
+	Created            time.Time `json:"created" xorm:"created"`
+}
+
+func (l PublicDashboardAccessLog) TableName() string {
+	return "dashboard_public_access_log"
+}
+
 type TimeSettings struct {
 	From string `json:"from,omitempty"`
 	To   string `json:"to,omitempty"`
@@ -152,6 +181,8 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// Variables are the values of the template variables selected by the viewer, by variable name
+	Variables map[string][]string
 }
 
 type AnnotationsQueryDTO struct {
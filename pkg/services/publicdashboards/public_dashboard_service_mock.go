@@ -77,6 +77,54 @@ func (_m *FakePublicDashboardService) DeleteByDashboard(ctx context.Context, das
 	return r0
 }
 
+// DeleteStaleAccessLogs provides a mock function with given fields: ctx
+func (_m *FakePublicDashboardService) DeleteStaleAccessLogs(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DisableExpired provides a mock function with given fields: ctx
+func (_m *FakePublicDashboardService) DisableExpired(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsEnabledByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardService) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -151,6 +199,32 @@ func (_m *FakePublicDashboardService) Find(ctx context.Context, uid string) (*mo
 	return r0, r1
 }
 
+// FindAccessLogs provides a mock function with given fields: ctx, orgId, uid, dashboardUid
+func (_m *FakePublicDashboardService) FindAccessLogs(ctx context.Context, orgId int64, uid string, dashboardUid string) ([]*models.PublicDashboardAccessLog, error) {
+	ret := _m.Called(ctx, orgId, uid, dashboardUid)
+
+	var r0 []*models.PublicDashboardAccessLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) ([]*models.PublicDashboardAccessLog, error)); ok {
+		return rf(ctx, orgId, uid, dashboardUid)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) []*models.PublicDashboardAccessLog); ok {
+		r0 = rf(ctx, orgId, uid, dashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PublicDashboardAccessLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, orgId, uid, dashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAllWithPagination provides a mock function with given fields: ctx, query
 func (_m *FakePublicDashboardService) FindAllWithPagination(ctx context.Context, query *models.PublicDashboardListQuery) (*models.PublicDashboardListResponseWithPagination, error) {
 	ret := _m.Called(ctx, query)
@@ -451,6 +525,20 @@ func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context,
 	return r0, r1
 }
 
+// LogAccess provides a mock function with given fields: ctx, accessToken, panelId, remoteAddr
+func (_m *FakePublicDashboardService) LogAccess(ctx context.Context, accessToken string, panelId int64, remoteAddr string) error {
+	ret := _m.Called(ctx, accessToken, panelId, remoteAddr)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) error); ok {
+		r0 = rf(ctx, accessToken, panelId, remoteAddr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewPublicDashboardAccessToken provides a mock function with given fields: ctx
 func (_m *FakePublicDashboardService) NewPublicDashboardAccessToken(ctx context.Context) (string, error) {
 	ret := _m.Called(ctx)
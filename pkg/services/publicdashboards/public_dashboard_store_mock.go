@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+
+	time "time"
 )
 
 // FakePublicDashboardStore is an autogenerated mock type for the Store type
@@ -40,6 +42,20 @@ func (_m *FakePublicDashboardStore) Create(ctx context.Context, cmd models.SaveP
 	return r0, r1
 }
 
+// CreateAccessLog provides a mock function with given fields: ctx, entry
+func (_m *FakePublicDashboardStore) CreateAccessLog(ctx context.Context, entry *models.PublicDashboardAccessLog) error {
+	ret := _m.Called(ctx, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboardAccessLog) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, uid
 func (_m *FakePublicDashboardStore) Delete(ctx context.Context, uid string) (int64, error) {
 	ret := _m.Called(ctx, uid)
@@ -64,6 +80,54 @@ func (_m *FakePublicDashboardStore) Delete(ctx context.Context, uid string) (int
 	return r0, r1
 }
 
+// DeleteStaleAccessLogs provides a mock function with given fields: ctx, olderThan, rowLimit
+func (_m *FakePublicDashboardStore) DeleteStaleAccessLogs(ctx context.Context, olderThan time.Time, rowLimit int64) (int64, error) {
+	ret := _m.Called(ctx, olderThan, rowLimit)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int64) (int64, error)); ok {
+		return rf(ctx, olderThan, rowLimit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int64) int64); ok {
+		r0 = rf(ctx, olderThan, rowLimit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int64) error); ok {
+		r1 = rf(ctx, olderThan, rowLimit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DisableExpired provides a mock function with given fields: ctx, now
+func (_m *FakePublicDashboardStore) DisableExpired(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsEnabledByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -138,6 +202,32 @@ func (_m *FakePublicDashboardStore) Find(ctx context.Context, uid string) (*mode
 	return r0, r1
 }
 
+// FindAccessLogs provides a mock function with given fields: ctx, uid, limit
+func (_m *FakePublicDashboardStore) FindAccessLogs(ctx context.Context, uid string, limit int) ([]*models.PublicDashboardAccessLog, error) {
+	ret := _m.Called(ctx, uid, limit)
+
+	var r0 []*models.PublicDashboardAccessLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*models.PublicDashboardAccessLog, error)); ok {
+		return rf(ctx, uid, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*models.PublicDashboardAccessLog); ok {
+		r0 = rf(ctx, uid, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PublicDashboardAccessLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, uid, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAllWithPagination provides a mock function with given fields: ctx, query
 func (_m *FakePublicDashboardStore) FindAllWithPagination(ctx context.Context, query *models.PublicDashboardListQuery) (*models.PublicDashboardListResponseWithPagination, error) {
 	ret := _m.Called(ctx, query)
@@ -318,6 +408,30 @@ func (_m *FakePublicDashboardStore) GetOrgIdByAccessToken(ctx context.Context, a
 	return r0, r1
 }
 
+// IncrementViewCount provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardStore) IncrementViewCount(ctx context.Context, accessToken string) (bool, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, accessToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, cmd
 func (_m *FakePublicDashboardStore) Update(ctx context.Context, cmd models.SavePublicDashboardCommand) (int64, error) {
 	ret := _m.Called(ctx, cmd)
@@ -2,6 +2,7 @@ package publicdashboards
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -37,6 +38,11 @@ type Service interface {
 
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
+
+	LogAccess(ctx context.Context, accessToken string, panelId int64, remoteAddr string) error
+	FindAccessLogs(ctx context.Context, orgId int64, uid string, dashboardUid string) ([]*PublicDashboardAccessLog, error)
+	DeleteStaleAccessLogs(ctx context.Context) (int64, error)
+	DisableExpired(ctx context.Context) (int64, error)
 }
 
 // ServiceWrapper these methods have different behavior between OSS and Enterprise. The latter would call the OSS service first
@@ -63,4 +69,9 @@ type Store interface {
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
 	GetMetrics(ctx context.Context) (*Metrics, error)
+	IncrementViewCount(ctx context.Context, accessToken string) (bool, error)
+	DisableExpired(ctx context.Context, now time.Time) (int64, error)
+	CreateAccessLog(ctx context.Context, entry *PublicDashboardAccessLog) error
+	FindAccessLogs(ctx context.Context, uid string, limit int) ([]*PublicDashboardAccessLog, error)
+	DeleteStaleAccessLogs(ctx context.Context, olderThan time.Time, rowLimit int64) (int64, error)
 }
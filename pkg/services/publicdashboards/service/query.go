@@ -110,6 +110,7 @@ func (pd *PublicDashboardServiceImpl) GetMetricRequest(ctx context.Context, dash
 	}
 
 	metricReqDTO, err := pd.buildMetricRequest(
+		ctx,
 		dashboard,
 		publicDashboard,
 		panelId,
@@ -154,7 +155,7 @@ func (pd *PublicDashboardServiceImpl) GetQueryDataResponse(ctx context.Context,
 }
 
 // buildMetricRequest merges public dashboard parameters with dashboard and returns a metrics request to be sent to query backend
-func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
+func (pd *PublicDashboardServiceImpl) buildMetricRequest(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
 	// group queries by panel
 	queriesByPanel := groupQueriesByPanelId(dashboard.Data)
 	queries, ok := queriesByPanel[panelId]
@@ -164,12 +165,18 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.D
 
 	ts := buildTimeSettings(dashboard, reqDTO, publicDashboard)
 
+	variables, err := resolveTemplateVariables(getTemplateVariables(dashboard.Data), reqDTO.Variables, pd.queryVariableOptions(ctx, dashboard, ts), false)
+	if err != nil {
+		return dtos.MetricRequest{}, err
+	}
+
 	// determine safe resolution to query data at
 	safeInterval, safeResolution := pd.getSafeIntervalAndMaxDataPoints(reqDTO, ts)
 	for i := range queries {
 		queries[i].Set("intervalMs", safeInterval)
 		queries[i].Set("maxDataPoints", safeResolution)
 		queries[i].Set("queryCachingTTL", reqDTO.QueryCachingTTL)
+		interpolateQuery(queries[i], variables)
 	}
 
 	return dtos.MetricRequest{
@@ -179,6 +186,41 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.D
 	}, nil
 }
 
+// queryVariableOptions returns a loader of the options of query variables, that runs their query through their data
+// source on behalf of the viewer. Viewers can select the values returned by the query and the saved values. If the
+// query fails, viewers can only select the saved values.
+func (pd *PublicDashboardServiceImpl) queryVariableOptions(ctx context.Context, dashboard *dashboards.Dashboard, ts models.TimeSettings) optionsLoader {
+	return func(variable *templateVariable, resolved map[string]variableValue) {
+		values, err := pd.runVariableQuery(ctx, dashboard, variable, resolved, ts)
+		if err != nil {
+			pd.log.Warn("Failed to run the query of a template variable, only its saved value can be selected", "dashboardUid", dashboard.UID, "variable", variable.Name, "error", err)
+		}
+		variable.Options = appendUnique(values, variable.Current...)
+	}
+}
+
+func (pd *PublicDashboardServiceImpl) runVariableQuery(ctx context.Context, dashboard *dashboards.Dashboard, variable *templateVariable, resolved map[string]variableValue, ts models.TimeSettings) ([]string, error) {
+	query := variableDataQuery(variable)
+	interpolateQuery(query, resolved)
+
+	res, err := pd.QueryDataService.QueryData(ctx, buildAnonymousUser(ctx, dashboard), false, dtos.MetricRequest{
+		From:    ts.From,
+		To:      ts.To,
+		Queries: []*simplejson.Json{query},
+	})
+	if err != nil {
+		return nil, err
+	}
+	r, ok := res.Responses[variableQueryRefID]
+	if !ok {
+		return nil, nil
+	}
+	if r.Error != nil {
+		return nil, r.Error
+	}
+	return filterVariableValues(variableQueryValues(r.Frames), variable.Regex)
+}
+
 // buildAnonymousUser creates a user with permissions to read from all datasources used in the dashboard
 func buildAnonymousUser(ctx context.Context, dashboard *dashboards.Dashboard) *user.SignedInUser {
 	datasourceUids := getUniqueDashboardDatasourceUids(dashboard.Data)
@@ -241,6 +283,18 @@ func getUniqueDashboardDatasourceUids(dashboard *simplejson.Json) []string {
 		}
 	}
 
+	// the queries of query variables are run to get their options
+	for _, varObj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(varObj)
+		if variable.Get("type").MustString() != variableTypeQuery {
+			continue
+		}
+		if uid := getDataSourceUidFromJson(variable); uid != "" && !exists[uid] {
+			datasourceUids = append(datasourceUids, uid)
+			exists[uid] = true
+		}
+	}
+
 	return datasourceUids
 }
 
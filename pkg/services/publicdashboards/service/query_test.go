@@ -31,6 +31,7 @@ import (
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 	"github.com/grafana/grafana/pkg/util"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -737,6 +738,45 @@ func TestGetQueryDataResponse(t *testing.T) {
 		resp, _ := service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdashDto.AccessToken)
 		require.NotNil(t, resp)
 	})
+
+	t.Run("Returns query data for the last view allowed by max views", func(t *testing.T) {
+		customPanels := []interface{}{
+			map[string]interface{}{
+				"id": 1,
+				"datasource": map[string]interface{}{
+					"uid": "ds1",
+				},
+				"targets": []interface{}{map[string]interface{}{
+					"datasource": map[string]interface{}{"uid": "ds1"},
+					"refId":      "A",
+				}},
+			}}
+
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashWithMaxViews", 1, 0, "", true, []map[string]interface{}{}, customPanels)
+		isEnabled := true
+		maxViews := int64(1)
+		dto := &SavePublicDashboardDTO{
+			DashboardUid: dashboard.UID,
+			UserId:       7,
+			OrgID:        dashboard.OrgID,
+			PublicDashboard: &PublicDashboardDTO{
+				IsEnabled: &isEnabled,
+				MaxViews:  &maxViews,
+			},
+		}
+		pubdashDto, err := service.Create(context.Background(), SignedInUser, dto)
+		require.NoError(t, err)
+
+		_, err = service.GetPublicDashboardForView(context.Background(), pubdashDto.AccessToken)
+		require.NoError(t, err)
+
+		resp, err := service.GetQueryDataResponse(context.Background(), true, publicDashboardQueryDTO, 1, pubdashDto.AccessToken)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+
+		_, err = service.GetPublicDashboardForView(context.Background(), pubdashDto.AccessToken)
+		require.ErrorIs(t, err, ErrPublicDashboardViewLimitReached)
+	})
 }
 
 func TestFindAnnotations(t *testing.T) {
@@ -746,7 +786,7 @@ func TestFindAnnotations(t *testing.T) {
 		sqlStore := sqlstore.InitTestDB(t)
 		config := setting.NewCfg()
 		tagService := tagimpl.ProvideService(sqlStore, sqlStore.Cfg)
-		annotationsRepo := annotationsimpl.ProvideService(sqlStore, config, featuremgmt.WithFeatures(), tagService)
+		annotationsRepo := annotationsimpl.ProvideService(sqlStore, config, featuremgmt.WithFeatures(), tagService, prometheus.NewRegistry())
 		fakeStore := FakePublicDashboardStore{}
 		service := &PublicDashboardServiceImpl{
 			log:             log.New("test.logger"),
@@ -1258,6 +1298,7 @@ func TestBuildMetricRequest(t *testing.T) {
 
 	t.Run("extracts queries from provided dashboard", func(t *testing.T) {
 		reqDTO, err := service.buildMetricRequest(
+			context.Background(),
 			publicDashboard,
 			publicDashboardPD,
 			1,
@@ -1308,6 +1349,7 @@ func TestBuildMetricRequest(t *testing.T) {
 
 	t.Run("returns an error when panel missing", func(t *testing.T) {
 		_, err := service.buildMetricRequest(
+			context.Background(),
 			publicDashboard,
 			publicDashboardPD,
 			49,
@@ -1346,6 +1388,7 @@ func TestBuildMetricRequest(t *testing.T) {
 		publicDashboard := insertTestDashboard(t, dashboardStore, "testDashWithHiddenQuery", 1, 0, "", true, []map[string]interface{}{}, customPanels)
 
 		reqDTO, err := service.buildMetricRequest(
+			context.Background(),
 			publicDashboard,
 			publicDashboardPD,
 			1,
@@ -1369,6 +1412,20 @@ func TestBuildMetricRequest(t *testing.T) {
 			reqDTO.Queries[0],
 		)
 	})
+
+	t.Run("returns an error when a template variable value is not allowed", func(t *testing.T) {
+		queryDTO := publicDashboardQueryDTO
+		queryDTO.Variables = map[string][]string{"job": {"anything"}}
+
+		_, err := service.buildMetricRequest(
+			context.Background(),
+			publicDashboard,
+			publicDashboardPD,
+			1,
+			queryDTO,
+		)
+		require.ErrorIs(t, err, ErrInvalidTemplateVariable)
+	})
 }
 
 func TestBuildAnonymousUser(t *testing.T) {
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
@@ -39,6 +42,15 @@ type PublicDashboardServiceImpl struct {
 
 var LogPrefix = "publicdashboards.service"
 
+const (
+	// accessLogLimit is the max number of access log entries returned for a public dashboard
+	accessLogLimit = 1000
+	// accessLogMaxAge is the age after which access log entries are deleted
+	accessLogMaxAge = 30 * 24 * time.Hour
+	// accessLogRowLimit is the max number of access log entries kept for all public dashboards
+	accessLogRowLimit = 500000
+)
+
 // Gives us compile time error if the service does not adhere to the contract of
 // the interface
 var _ publicdashboards.Service = (*PublicDashboardServiceImpl)(nil)
@@ -71,6 +83,15 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 		return nil, err
 	}
 
+	// The max views only limit the views of the dashboard, so that the panel queries of the last view succeed
+	incremented, err := pd.store.IncrementViewCount(ctx, accessToken)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("GetPublicDashboardForView: failed to increment view count: %w", err)
+	}
+	if !incremented {
+		return nil, ErrPublicDashboardViewLimitReached.Errorf("GetPublicDashboardForView: Public dashboard reached its max views accessToken: %s", accessToken)
+	}
+
 	meta := dtos.DashboardMeta{
 		Slug:                   dash.Slug,
 		Type:                   dashboards.DashTypeDB,
@@ -88,7 +109,15 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 	}
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
+	// the options of query variables are loaded for the default time range of the public dashboard
+	variables := getTemplateVariables(dash.Data)
+	ts := buildTimeSettings(dash, PublicDashboardQueryDTO{}, pubdash)
+	if _, err := resolveTemplateVariables(variables, nil, pd.queryVariableOptions(ctx, dash, ts), true); err != nil {
+		return nil, err
+	}
+
 	sanitizeData(dash.Data)
+	sanitizeTemplateVariables(dash.Data, variables)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
 }
@@ -145,6 +174,10 @@ func (pd *PublicDashboardServiceImpl) FindEnabledPublicDashboardAndDashboardByAc
 		return nil, nil, ErrPublicDashboardNotEnabled.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard is not enabled accessToken: %s", accessToken)
 	}
 
+	if pubdash.IsExpired(time.Now()) {
+		return nil, nil, ErrPublicDashboardExpired.Errorf("FindEnabledPublicDashboardAndDashboardByAccessToken: Public dashboard expired accessToken: %s", accessToken)
+	}
+
 	return pubdash, dash, err
 }
 
@@ -328,6 +361,80 @@ func (pd *PublicDashboardServiceImpl) ExistsEnabledByAccessToken(ctx context.Con
 	return pd.store.ExistsEnabledByAccessToken(ctx, accessToken)
 }
 
+// LogAccess Adds an entry to the access log of the public dashboard with the access token, for a query of the
+// panel, or for a view of the dashboard if panelId is 0. The address of the viewer is hashed with the secret key,
+// so it can't be recovered from the log.
+func (pd *PublicDashboardServiceImpl) LogAccess(ctx context.Context, accessToken string, panelId int64, remoteAddr string) error {
+	pubdash, err := pd.store.FindByAccessToken(ctx, accessToken)
+	if err != nil {
+		return ErrInternalServerError.Errorf("LogAccess: failed to find a public dashboard: %w", err)
+	}
+	if pubdash == nil {
+		return nil
+	}
+
+	entry := &PublicDashboardAccessLog{
+		PublicDashboardUid: pubdash.Uid,
+		OrgId:              pubdash.OrgId,
+		PanelId:            panelId,
+		IpHash:             hashRemoteAddr(pd.cfg.SecretKey, remoteAddr),
+		Created:            time.Now(),
+	}
+	if err := pd.store.CreateAccessLog(ctx, entry); err != nil {
+		return ErrInternalServerError.Errorf("LogAccess: failed to create access log entry: %w", err)
+	}
+
+	return nil
+}
+
+// FindAccessLogs Returns the most recent entries of the access log of a public dashboard
+func (pd *PublicDashboardServiceImpl) FindAccessLogs(ctx context.Context, orgId int64, uid string, dashboardUid string) ([]*PublicDashboardAccessLog, error) {
+	existingPubdash, err := pd.store.Find(ctx, uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindAccessLogs: failed to find public dashboard by uid: %s: %w", uid, err)
+	}
+	if existingPubdash == nil || existingPubdash.OrgId != orgId {
+		return nil, ErrPublicDashboardNotFound.Errorf("FindAccessLogs: public dashboard not found by uid: %s", uid)
+	}
+
+	// validate the public dashboard belongs to the dashboard
+	if existingPubdash.DashboardUid != dashboardUid {
+		return nil, ErrInvalidUid.Errorf("FindAccessLogs: the public dashboard does not belong to the dashboard")
+	}
+
+	entries, err := pd.store.FindAccessLogs(ctx, uid, accessLogLimit)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindAccessLogs: failed to find access log of public dashboard by uid: %s: %w", uid, err)
+	}
+
+	return entries, nil
+}
+
+// DeleteStaleAccessLogs Deletes the access log entries older than the max age, and the oldest entries over the
+// row limit
+func (pd *PublicDashboardServiceImpl) DeleteStaleAccessLogs(ctx context.Context) (int64, error) {
+	deletedRows, err := pd.store.DeleteStaleAccessLogs(ctx, time.Now().Add(-accessLogMaxAge), accessLogRowLimit)
+	if err != nil {
+		return 0, ErrInternalServerError.Errorf("DeleteStaleAccessLogs: failed to delete stale access log entries: %w", err)
+	}
+
+	return deletedRows, nil
+}
+
+// DisableExpired Disables the public dashboards that expired
+func (pd *PublicDashboardServiceImpl) DisableExpired(ctx context.Context) (int64, error) {
+	affectedRows, err := pd.store.DisableExpired(ctx, time.Now())
+	if err != nil {
+		return 0, ErrInternalServerError.Errorf("DisableExpired: failed to disable expired public dashboards: %w", err)
+	}
+
+	if affectedRows > 0 {
+		pd.log.Info("Disabled expired public dashboards", "count", affectedRows)
+	}
+
+	return affectedRows, nil
+}
+
 func (pd *PublicDashboardServiceImpl) GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error) {
 	return pd.store.GetOrgIdByAccessToken(ctx, accessToken)
 }
@@ -478,6 +585,11 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 
 	now := time.Now()
 
+	var maxViews int64
+	if dto.PublicDashboard.MaxViews != nil {
+		maxViews = *dto.PublicDashboard.MaxViews
+	}
+
 	return &PublicDashboard{
 		Uid:                  uid,
 		DashboardUid:         dto.DashboardUid,
@@ -492,6 +604,8 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            now,
 		AccessToken:          accessToken,
+		ExpiresAt:            expiresAtOrNil(dto.PublicDashboard.ExpiresAt),
+		MaxViews:             maxViews,
 	}, nil
 }
 
@@ -506,6 +620,16 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		share = pd.Share
 	}
 
+	expiresAt := pd.ExpiresAt
+	if pubdashDTO.ExpiresAt != nil {
+		expiresAt = expiresAtOrNil(pubdashDTO.ExpiresAt)
+	}
+
+	maxViews := pd.MaxViews
+	if pubdashDTO.MaxViews != nil {
+		maxViews = *pubdashDTO.MaxViews
+	}
+
 	return &PublicDashboard{
 		Uid:                  pd.Uid,
 		IsEnabled:            isEnabled,
@@ -513,11 +637,29 @@ func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard)
 		TimeSelectionEnabled: timeSelectionEnabled,
 		TimeSettings:         pd.TimeSettings,
 		Share:                share,
+		ExpiresAt:            expiresAt,
+		MaxViews:             maxViews,
+		ViewCount:            pd.ViewCount,
 		UpdatedBy:            dto.UserId,
 		UpdatedAt:            time.Now(),
 	}
 }
 
+// expiresAtOrNil returns nil for the zero time, which is used to remove the expiration
+func expiresAtOrNil(expiresAt *time.Time) *time.Time {
+	if expiresAt == nil || expiresAt.IsZero() {
+		return nil
+	}
+	return expiresAt
+}
+
+// hashRemoteAddr returns the hex encoded HMAC-SHA256 of the address, keyed with the secret key
+func hashRemoteAddr(secretKey string, remoteAddr string) string {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(remoteAddr))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func returnValueOrDefault(value *bool, defaultValue bool) bool {
 	if value != nil {
 		return *value
@@ -389,6 +389,7 @@ func TestGetPublicDashboardForView(t *testing.T) {
 
 			fakeStore.On("FindByAccessToken", mock.Anything, mock.Anything).Return(test.StoreResp.pd, test.StoreResp.err)
 			fakeStore.On("FindDashboard", mock.Anything, mock.Anything, mock.Anything).Return(test.StoreResp.d, test.StoreResp.err)
+			fakeStore.On("IncrementViewCount", mock.Anything, test.AccessToken).Return(true, nil)
 
 			dashboardFullWithMeta, err := service.GetPublicDashboardForView(context.Background(), test.AccessToken)
 			if test.ErrResp != nil {
@@ -555,6 +556,39 @@ func TestGetEnabledPublicDashboard(t *testing.T) {
 			ErrResp:  ErrPublicDashboardNotFound,
 			DashResp: nil,
 		},
+		{
+			Name:        "returns ErrPublicDashboardExpired when expired",
+			AccessToken: "abc123",
+			StoreResp: &storeResp{
+				pd:  &PublicDashboard{AccessToken: "abcdToken", IsEnabled: true, ExpiresAt: util.Pointer(time.Now().Add(-time.Minute))},
+				d:   &dashboards.Dashboard{UID: "mydashboard"},
+				err: nil,
+			},
+			ErrResp:  ErrPublicDashboardExpired,
+			DashResp: nil,
+		},
+		{
+			Name:        "returns a dashboard when not expired yet",
+			AccessToken: "abc123",
+			StoreResp: &storeResp{
+				pd:  &PublicDashboard{AccessToken: "abcdToken", IsEnabled: true, ExpiresAt: util.Pointer(time.Now().Add(time.Hour))},
+				d:   &dashboards.Dashboard{UID: "mydashboard", Data: dashboardData},
+				err: nil,
+			},
+			ErrResp:  nil,
+			DashResp: &dashboards.Dashboard{UID: "mydashboard", Data: dashboardData},
+		},
+		{
+			Name:        "returns a dashboard when max views is reached, since only views are limited",
+			AccessToken: "abc123",
+			StoreResp: &storeResp{
+				pd:  &PublicDashboard{AccessToken: "abcdToken", IsEnabled: true, MaxViews: 5, ViewCount: 5},
+				d:   &dashboards.Dashboard{UID: "mydashboard", Data: dashboardData},
+				err: nil,
+			},
+			ErrResp:  nil,
+			DashResp: &dashboards.Dashboard{UID: "mydashboard", Data: dashboardData},
+		},
 	}
 
 	for _, test := range testCases {
@@ -1759,6 +1793,129 @@ func TestDeleteByDashboard(t *testing.T) {
 	})
 }
 
+func TestLogAccess(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.SecretKey = "secret"
+
+	t.Run("adds an entry with a hash of the address", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		pd := &PublicDashboardServiceImpl{store: store, cfg: cfg}
+		pubdash := &PublicDashboard{Uid: "2", OrgId: 1, AccessToken: "abc123"}
+		store.On("FindByAccessToken", mock.Anything, "abc123").Return(pubdash, nil)
+		store.On("CreateAccessLog", mock.Anything, mock.MatchedBy(func(entry *PublicDashboardAccessLog) bool {
+			return entry.PublicDashboardUid == "2" && entry.OrgId == 1 && entry.PanelId == 3 &&
+				entry.IpHash == hashRemoteAddr("secret", "127.0.0.1") && !entry.Created.IsZero()
+		})).Return(nil)
+
+		err := pd.LogAccess(context.Background(), "abc123", 3, "127.0.0.1")
+		require.NoError(t, err)
+	})
+
+	t.Run("does nothing when the public dashboard doesn't exist", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		pd := &PublicDashboardServiceImpl{store: store, cfg: cfg}
+		store.On("FindByAccessToken", mock.Anything, "abc123").Return(nil, nil)
+
+		err := pd.LogAccess(context.Background(), "abc123", 0, "127.0.0.1")
+		require.NoError(t, err)
+	})
+
+	t.Run("hash depends on the address and the secret key", func(t *testing.T) {
+		hash := hashRemoteAddr("secret", "127.0.0.1")
+		assert.Len(t, hash, 64)
+		assert.NotContains(t, hash, "127.0.0.1")
+		assert.Equal(t, hash, hashRemoteAddr("secret", "127.0.0.1"))
+		assert.NotEqual(t, hash, hashRemoteAddr("secret", "127.0.0.2"))
+		assert.NotEqual(t, hash, hashRemoteAddr("other", "127.0.0.1"))
+	})
+}
+
+func TestDeleteStaleAccessLogs(t *testing.T) {
+	store := NewFakePublicDashboardStore(t)
+	pd := &PublicDashboardServiceImpl{store: store}
+	store.On("DeleteStaleAccessLogs", mock.Anything, mock.MatchedBy(func(olderThan time.Time) bool {
+		return olderThan.Before(time.Now().Add(-accessLogMaxAge + time.Minute))
+	}), int64(accessLogRowLimit)).Return(int64(5), nil)
+
+	deleted, err := pd.DeleteStaleAccessLogs(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, deleted)
+}
+
+func TestFindAccessLogs(t *testing.T) {
+	pubdash := &PublicDashboard{Uid: "2", OrgId: 1, DashboardUid: "1"}
+
+	t.Run("returns the access log", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		pd := &PublicDashboardServiceImpl{store: store}
+		entries := []*PublicDashboardAccessLog{{PublicDashboardUid: "2", IpHash: "hash"}}
+		store.On("Find", mock.Anything, "2").Return(pubdash, nil)
+		store.On("FindAccessLogs", mock.Anything, "2", accessLogLimit).Return(entries, nil)
+
+		res, err := pd.FindAccessLogs(context.Background(), 1, "2", "1")
+		require.NoError(t, err)
+		assert.Equal(t, entries, res)
+	})
+
+	t.Run("returns not found for a public dashboard of another org", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		pd := &PublicDashboardServiceImpl{store: store}
+		store.On("Find", mock.Anything, "2").Return(pubdash, nil)
+
+		_, err := pd.FindAccessLogs(context.Background(), 2, "2", "1")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+
+	t.Run("returns invalid uid when the public dashboard belongs to another dashboard", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		pd := &PublicDashboardServiceImpl{store: store}
+		store.On("Find", mock.Anything, "2").Return(pubdash, nil)
+
+		_, err := pd.FindAccessLogs(context.Background(), 1, "2", "other")
+		require.ErrorIs(t, err, ErrInvalidUid)
+	})
+}
+
+func TestDisableExpired(t *testing.T) {
+	t.Run("disables expired public dashboards", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		pd := &PublicDashboardServiceImpl{store: store, log: log.New("test.logger")}
+		store.On("DisableExpired", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(2), nil)
+
+		affected, err := pd.DisableExpired(context.Background())
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, affected)
+	})
+
+	t.Run("returns an internal server error when the store fails", func(t *testing.T) {
+		store := NewFakePublicDashboardStore(t)
+		pd := &PublicDashboardServiceImpl{store: store, log: log.New("test.logger")}
+		store.On("DisableExpired", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("db error"))
+
+		_, err := pd.DisableExpired(context.Background())
+		require.ErrorIs(t, err, ErrInternalServerError)
+	})
+}
+
+func TestNewUpdatePublicDashboardExpiration(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	existing := &PublicDashboard{Uid: "2", ExpiresAt: &expiresAt, MaxViews: 10, ViewCount: 4}
+
+	t.Run("keeps the expiration and max views when not set", func(t *testing.T) {
+		pubdash := newUpdatePublicDashboard(&SavePublicDashboardDTO{PublicDashboard: &PublicDashboardDTO{}}, existing)
+		assert.Equal(t, &expiresAt, pubdash.ExpiresAt)
+		assert.EqualValues(t, 10, pubdash.MaxViews)
+		assert.EqualValues(t, 4, pubdash.ViewCount)
+	})
+
+	t.Run("removes the expiration and max views with zero values", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{PublicDashboard: &PublicDashboardDTO{ExpiresAt: &time.Time{}, MaxViews: util.Pointer(int64(0))}}
+		pubdash := newUpdatePublicDashboard(dto, existing)
+		assert.Nil(t, pubdash.ExpiresAt)
+		assert.EqualValues(t, 0, pubdash.MaxViews)
+	})
+}
+
 func TestGenerateAccessToken(t *testing.T) {
 	accessToken, err := GenerateAccessToken()
 
@@ -0,0 +1,527 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const (
+	variableTypeConstant = "constant"
+	variableTypeCustom   = "custom"
+	variableTypeQuery    = "query"
+
+	// allValue is the value the frontend uses when "All" is selected
+	allValue = "$__all"
+
+	// variableQueryRefID is the refId of the data query of a query variable
+	variableQueryRefID = "variable"
+)
+
+// variableRegex matches $var, [[var]], [[var:format]], ${var}, ${var.fieldPath} and ${var:format}, the same way the
+// frontend template service does
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?:\.([^:^\}]+))?(?::([^\}]+))?\}`)
+
+// templateVariable is a template variable of the dashboard along with the values that viewers are allowed to select
+type templateVariable struct {
+	Name       string
+	Type       string
+	Multi      bool
+	IncludeAll bool
+	AllValue   string
+	// Index is the position of the variable in the dashboard. Queries of variables can use the variables before them.
+	Index int
+	// Options are the values viewers can select, in order. The options of query variables are only set once they
+	// are loaded by running their query.
+	Options []string
+	// Current are the values saved with the dashboard, used when the viewer doesn't select any
+	Current []string
+	// Query is the query of a query variable, either a string or the data query of the data source
+	Query any
+	// Datasource is the data source of a query variable
+	Datasource any
+	// Regex filters and extracts the options from the values returned by the query of a query variable
+	Regex string
+}
+
+// optionsLoader sets the options of a query variable by running its query, with the values of the variables before
+// it in the dashboard
+type optionsLoader func(variable *templateVariable, resolved map[string]variableValue)
+
+// variableValue is the value of a template variable to substitute in queries
+type variableValue struct {
+	variable *templateVariable
+	values   []string
+	// raw is set when "All" is selected and the variable has a custom all value, which is never formatted
+	raw *string
+}
+
+// getTemplateVariables returns the template variables of the dashboard, by name
+func getTemplateVariables(dashboard *simplejson.Json) map[string]*templateVariable {
+	variables := make(map[string]*templateVariable)
+	for i, varObj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(varObj)
+		name := v.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+
+		variable := &templateVariable{
+			Name:       name,
+			Type:       v.Get("type").MustString(),
+			Multi:      v.Get("multi").MustBool(),
+			IncludeAll: v.Get("includeAll").MustBool(),
+			AllValue:   v.Get("allValue").MustString(),
+			Index:      i,
+			Current:    optionValues(v.GetPath("current", "value").Interface()),
+		}
+
+		switch variable.Type {
+		case variableTypeConstant:
+			variable.Options = []string{v.Get("query").MustString()}
+			variable.Current = variable.Options
+		case variableTypeCustom:
+			variable.Options = parseCustomVariableQuery(v.Get("query").MustString())
+		case variableTypeQuery:
+			// The options saved with the dashboard are not used, as they can be outdated or missing when the
+			// variable is refreshed by the frontend. The options are loaded by running the query server side.
+			variable.Query = v.Get("query").Interface()
+			variable.Datasource = v.Get("datasource").Interface()
+			variable.Regex = v.Get("regex").MustString()
+		}
+
+		variables[name] = variable
+	}
+	return variables
+}
+
+// resolveTemplateVariables returns the values of the template variables of the dashboard. Values requested by the
+// viewer are only accepted for constant, custom and query variables, and only if they are among the allowed options.
+// The values saved with the dashboard are used for the variables the viewer didn't request.
+//
+// The options of query variables are loaded in the order of the dashboard, when the viewer requests values of the
+// variable or when "All" is selected, or for all query variables if loadAll is set.
+func resolveTemplateVariables(variables map[string]*templateVariable, requested map[string][]string, loadOptions optionsLoader, loadAll bool) (map[string]variableValue, error) {
+	for name := range requested {
+		if _, ok := variables[name]; !ok {
+			return nil, models.ErrInvalidTemplateVariable.Errorf("resolveTemplateVariables: unknown template variable %s", name)
+		}
+	}
+
+	resolved := make(map[string]variableValue, len(variables))
+	for _, variable := range sortedVariables(variables) {
+		name := variable.Name
+		values, ok := requested[name]
+		requestedValues := ok && len(values) > 0
+		if !requestedValues {
+			values = variable.Current
+		}
+
+		if variable.Type == variableTypeQuery && (loadAll || requestedValues || isAllValue(values)) {
+			loadOptions(variable, resolved)
+		}
+
+		if requestedValues {
+			if err := variable.validate(values); err != nil {
+				return nil, err
+			}
+		}
+
+		if isAllValue(values) && variable.IncludeAll {
+			if variable.AllValue != "" {
+				raw := variable.AllValue
+				resolved[name] = variableValue{variable: variable, raw: &raw}
+				continue
+			}
+			values = variable.Options
+		}
+		resolved[name] = variableValue{variable: variable, values: values}
+	}
+	return resolved, nil
+}
+
+// validate checks that the values can be selected by a viewer
+func (v *templateVariable) validate(values []string) error {
+	switch v.Type {
+	case variableTypeConstant, variableTypeCustom, variableTypeQuery:
+	default:
+		return models.ErrInvalidTemplateVariable.Errorf("validate: values of %s variable %s can't be selected", v.Type, v.Name)
+	}
+
+	if len(values) > 1 && !v.Multi {
+		return models.ErrInvalidTemplateVariable.Errorf("validate: variable %s doesn't allow multiple values", v.Name)
+	}
+
+	for _, value := range values {
+		if value == allValue {
+			if !v.IncludeAll || len(values) > 1 {
+				return models.ErrInvalidTemplateVariable.Errorf("validate: variable %s doesn't allow selecting all values", v.Name)
+			}
+			continue
+		}
+		if !contains(v.Options, value) {
+			return models.ErrInvalidTemplateVariable.Errorf("validate: value is not an option of variable %s", v.Name)
+		}
+	}
+	return nil
+}
+
+// sortedVariables returns the variables in the order of the dashboard
+func sortedVariables(variables map[string]*templateVariable) []*templateVariable {
+	sorted := make([]*templateVariable, 0, len(variables))
+	for _, v := range variables {
+		sorted = append(sorted, v)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+	return sorted
+}
+
+func isAllValue(values []string) bool {
+	return len(values) == 1 && values[0] == allValue
+}
+
+// variableDataQuery returns the data query that runs the query of a query variable. Data sources with variable
+// support save the data query as the variable query. Other variable queries are strings, which are sent as the
+// query of the data source, or as the raw SQL of SQL data sources.
+func variableDataQuery(variable *templateVariable) *simplejson.Json {
+	query := simplejson.New()
+	switch q := variable.Query.(type) {
+	case map[string]any:
+		for key, value := range q {
+			query.Set(key, value)
+		}
+	case string:
+		if defaultVariableFormat(simplejson.NewFromAny(variable.Datasource).Get("type").MustString()) == "sqlstring" {
+			query.Set("rawSql", q)
+			query.Set("format", "table")
+		} else {
+			query.Set("query", q)
+		}
+	}
+	query.Set("refId", variableQueryRefID)
+	query.Set("datasource", variable.Datasource)
+	return query
+}
+
+// variableQueryValues returns the values returned by the query of a query variable, from the field named value or
+// text, or else from the first field, the way the frontend reads the values of data frames
+func variableQueryValues(frames data.Frames) []string {
+	var values []string
+	for _, frame := range frames {
+		if len(frame.Fields) == 0 {
+			continue
+		}
+		field := frame.Fields[0]
+		if f, _ := frame.FieldByName("value"); f != nil {
+			field = f
+		} else if f, _ := frame.FieldByName("text"); f != nil {
+			field = f
+		}
+		for i := 0; i < field.Len(); i++ {
+			if v, ok := field.ConcreteAt(i); ok {
+				values = appendUnique(values, fmt.Sprint(v))
+			}
+		}
+	}
+	return values
+}
+
+// filterVariableValues applies the regex of a query variable to the values returned by its query, the way the
+// frontend does. Values that don't match are dropped, and the first capture group, or the group named value or text,
+// is used when the regex has one.
+func filterVariableValues(values []string, regex string) ([]string, error) {
+	if regex == "" {
+		return values, nil
+	}
+	re, err := variableRegexp(regex)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []string
+	for _, value := range values {
+		match := re.FindStringSubmatch(value)
+		if match == nil {
+			continue
+		}
+		v := match[0]
+		if i := re.SubexpIndex("value"); i > 0 && match[i] != "" {
+			v = match[i]
+		} else if i := re.SubexpIndex("text"); i > 0 && match[i] != "" {
+			v = match[i]
+		} else if len(match) > 1 && match[1] != "" {
+			v = match[1]
+		}
+		filtered = appendUnique(filtered, v)
+	}
+	return filtered, nil
+}
+
+// variableRegexp compiles a regex of the frontend, which is either written as /pattern/flags or matches the whole value
+func variableRegexp(regex string) (*regexp.Regexp, error) {
+	if !strings.HasPrefix(regex, "/") {
+		return regexp.Compile("^" + regex + "$")
+	}
+	end := strings.LastIndex(regex, "/")
+	if end == 0 {
+		return nil, fmt.Errorf("invalid regex %s", regex)
+	}
+	pattern := regex[1:end]
+	var flags string
+	for _, flag := range regex[end+1:] {
+		if strings.ContainsRune("ims", flag) {
+			flags += string(flag)
+		}
+	}
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// interpolateQuery substitutes the template variables in all the string fields of a query. Variables that are not
+// template variables of the dashboard, like $__interval, are left for the data source to handle.
+func interpolateQuery(query *simplejson.Json, variables map[string]variableValue) {
+	if len(variables) == 0 {
+		return
+	}
+	datasourceType := query.Get("datasource").Get("type").MustString()
+
+	var interpolate func(value any) any
+	interpolate = func(value any) any {
+		switch v := value.(type) {
+		case string:
+			return interpolateString(v, variables, datasourceType)
+		case map[string]any:
+			for key, field := range v {
+				if key == "datasource" {
+					continue
+				}
+				v[key] = interpolate(field)
+			}
+		case []any:
+			for i := range v {
+				v[i] = interpolate(v[i])
+			}
+		}
+		return value
+	}
+	interpolate(query.Interface())
+}
+
+func interpolateString(target string, variables map[string]variableValue, datasourceType string) string {
+	return variableRegex.ReplaceAllStringFunc(target, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name := firstNonEmpty(groups[1], groups[2], groups[4])
+		format := firstNonEmpty(groups[3], groups[6])
+
+		value, ok := variables[name]
+		// field paths are only used with data links, which are not evaluated in queries
+		if !ok || groups[5] != "" {
+			return match
+		}
+		if value.raw != nil {
+			return *value.raw
+		}
+		return formatVariableValue(value, format, datasourceType)
+	})
+}
+
+// formatVariableValue formats the values of a variable the way the frontend does. Without an explicit format, the
+// values of single value variables are not formatted, and the values of other variables are formatted the way
+// the data source expects them.
+func formatVariableValue(value variableValue, format string, datasourceType string) string {
+	values := value.values
+	if format == "" {
+		if !value.variable.Multi && !value.variable.IncludeAll {
+			return strings.Join(values, ",")
+		}
+		format = defaultVariableFormat(datasourceType)
+	}
+
+	switch format {
+	case "raw", "csv", "text":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "distributed":
+		if len(values) == 0 {
+			return ""
+		}
+		distributed := make([]string, 0, len(values))
+		distributed = append(distributed, values[0])
+		for _, v := range values[1:] {
+			distributed = append(distributed, value.variable.Name+"="+v)
+		}
+		return strings.Join(distributed, ",")
+	case "regex":
+		escaped := mapValues(values, regexp.QuoteMeta)
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "lucene":
+		if len(values) == 1 {
+			return luceneEscape(values[0])
+		}
+		quoted := mapValues(values, func(v string) string { return `"` + luceneEscape(v) + `"` })
+		return "(" + strings.Join(quoted, " OR ") + ")"
+	case "json":
+		var b []byte
+		if len(values) == 1 {
+			b, _ = json.Marshal(values[0])
+		} else {
+			b, _ = json.Marshal(values)
+		}
+		return string(b)
+	case "singlequote":
+		return strings.Join(mapValues(values, func(v string) string {
+			return "'" + strings.ReplaceAll(v, "'", `\'`) + "'"
+		}), ",")
+	case "doublequote":
+		return strings.Join(mapValues(values, func(v string) string {
+			return `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
+		}), ",")
+	case "sqlstring":
+		return strings.Join(mapValues(values, func(v string) string {
+			return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		}), ",")
+	default:
+		// glob is also the format of unknown formats
+		if len(values) == 1 {
+			return values[0]
+		}
+		return "{" + strings.Join(values, ",") + "}"
+	}
+}
+
+// defaultVariableFormat returns the format the frontend uses for the values of multi value variables of a data source
+func defaultVariableFormat(datasourceType string) string {
+	switch datasourceType {
+	case "prometheus", "loki", "influxdb":
+		return "regex"
+	case "mysql", "postgres", "grafana-postgresql-datasource", "mssql", "sqlite":
+		return "sqlstring"
+	case "elasticsearch":
+		return "lucene"
+	default:
+		return "glob"
+	}
+}
+
+// parseCustomVariableQuery returns the values of a custom variable. The query is a comma separated list of values,
+// where commas can be escaped with a backslash, and where each value can be a "text : value" pair.
+func parseCustomVariableQuery(query string) []string {
+	var values []string
+	for _, match := range customVariableRegex.FindAllString(query, -1) {
+		option := strings.TrimSpace(strings.ReplaceAll(match, `\,`, ","))
+		if textAndValue := strings.SplitN(option, " : ", 2); len(textAndValue) == 2 {
+			option = strings.TrimSpace(textAndValue[1])
+		}
+		values = appendUnique(values, option)
+	}
+	return values
+}
+
+var customVariableRegex = regexp.MustCompile(`(?:\\,|[^,])+`)
+
+// sanitizeTemplateVariables replaces the options of the query variables with the ones viewers are allowed to select,
+// which are loaded server side, and removes their queries, which are not shown to viewers
+func sanitizeTemplateVariables(dashboard *simplejson.Json, variables map[string]*templateVariable) {
+	for _, varObj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(varObj)
+		variable, ok := variables[v.Get("name").MustString()]
+		if !ok || variable.Type != variableTypeQuery {
+			continue
+		}
+
+		options := make([]any, 0, len(variable.Options))
+		for _, option := range variable.Options {
+			options = append(options, map[string]any{
+				"text":     option,
+				"value":    option,
+				"selected": contains(variable.Current, option),
+			})
+		}
+		v.Set("options", options)
+		v.Del("query")
+		v.Del("definition")
+		v.Set("refresh", 0)
+	}
+}
+
+// optionValues returns the values of a variable option, which can either be a string or a list of strings
+func optionValues(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	case []string:
+		return v
+	case nil:
+		return nil
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// luceneEscape escapes the special characters of the Lucene query syntax
+func luceneEscape(value string) string {
+	var sb strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(`+-=&|><!(){}[]^"~*?:\/`, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func appendUnique(values []string, newValues ...string) []string {
+	for _, v := range newValues {
+		if v != allValue && !contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func mapValues(values []string, fn func(string) string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		result = append(result, fn(v))
+	}
+	return result
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/query"
+)
+
+const dashboardWithTemplateVariables = `{
+  "templating": {
+    "list": [
+      {"name": "env", "type": "constant", "query": "prod"},
+      {
+        "name": "region",
+        "type": "custom",
+        "query": "Europe : eu\\,west, us, ap",
+        "multi": true,
+        "includeAll": true,
+        "current": {"text": "eu,west", "value": ["eu,west"]}
+      },
+      {
+        "name": "job",
+        "type": "query",
+        "query": "label_values(up, job)",
+        "definition": "label_values(up, job)",
+        "datasource": {"type": "prometheus", "uid": "prom"},
+        "refresh": 1,
+        "includeAll": true,
+        "allValue": ".*",
+        "current": {"text": "api", "value": "api"},
+        "options": [
+          {"text": "All", "value": "$__all"},
+          {"text": "api", "value": "api"},
+          {"text": "db", "value": "db"}
+        ]
+      },
+      {"name": "search", "type": "textbox", "query": "", "current": {"text": "foo", "value": "foo"}},
+      {
+        "name": "host",
+        "type": "query",
+        "query": {"query": "label_values(up{job=\"$job\"}, instance)", "refId": "PrometheusVariableQueryEditor-VariableQuery"},
+        "datasource": {"type": "prometheus", "uid": "prom"},
+        "regex": "/(?<value>web-\\d+):9100/",
+        "refresh": 2,
+        "current": {"text": "web-1", "value": "web-1"},
+        "options": []
+      }
+    ]
+  }
+}`
+
+// fakeOptionsLoader sets the options of query variables to the values returned by their query, and records the values
+// of the variables before them
+type fakeOptionsLoader struct {
+	results map[string][]string
+	loaded  map[string]map[string]variableValue
+}
+
+func newFakeOptionsLoader() *fakeOptionsLoader {
+	return &fakeOptionsLoader{
+		results: map[string][]string{"job": {"api", "db"}, "host": {"web-1", "web-2"}},
+		loaded:  map[string]map[string]variableValue{},
+	}
+}
+
+func (f *fakeOptionsLoader) load(variable *templateVariable, resolved map[string]variableValue) {
+	before := make(map[string]variableValue, len(resolved))
+	for name, value := range resolved {
+		before[name] = value
+	}
+	f.loaded[variable.Name] = before
+	variable.Options = appendUnique(f.results[variable.Name], variable.Current...)
+}
+
+func TestGetTemplateVariables(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	variables := getTemplateVariables(data)
+	require.Len(t, variables, 5)
+	assert.Equal(t, []string{"prod"}, variables["env"].Options)
+	assert.Equal(t, []string{"eu,west", "us", "ap"}, variables["region"].Options)
+	assert.Equal(t, []string{"eu,west"}, variables["region"].Current)
+	assert.Equal(t, []string{"foo"}, variables["search"].Current)
+
+	// the options of query variables are loaded by running their query
+	assert.Empty(t, variables["job"].Options)
+	assert.Equal(t, "label_values(up, job)", variables["job"].Query)
+	assert.Equal(t, 2, variables["job"].Index)
+	assert.Equal(t, []string{"web-1"}, variables["host"].Current)
+	assert.Equal(t, `/(?<value>web-\d+):9100/`, variables["host"].Regex)
+}
+
+func TestResolveTemplateVariables(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+	variables := getTemplateVariables(data)
+	loader := newFakeOptionsLoader()
+
+	t.Run("uses the saved values when no values are requested", func(t *testing.T) {
+		resolved, err := resolveTemplateVariables(variables, nil, loader.load, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod"}, resolved["env"].values)
+		assert.Equal(t, []string{"eu,west"}, resolved["region"].values)
+		assert.Equal(t, []string{"api"}, resolved["job"].values)
+		assert.Equal(t, []string{"foo"}, resolved["search"].values)
+		assert.Equal(t, []string{"web-1"}, resolved["host"].values)
+		// the queries of query variables are only run when their options are needed
+		assert.Empty(t, loader.loaded)
+	})
+
+	t.Run("uses the requested values when the query of a query variable returns them", func(t *testing.T) {
+		resolved, err := resolveTemplateVariables(variables, map[string][]string{
+			"region": {"us", "ap"},
+			"job":    {"db"},
+			"host":   {"web-2"},
+		}, loader.load, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"us", "ap"}, resolved["region"].values)
+		assert.Equal(t, []string{"db"}, resolved["job"].values)
+		assert.Equal(t, []string{"web-2"}, resolved["host"].values)
+		// queries can use the values of the variables before them
+		assert.Equal(t, []string{"db"}, loader.loaded["host"]["job"].values)
+		assert.NotContains(t, loader.loaded["job"], "host")
+	})
+
+	t.Run("loads the options of all query variables", func(t *testing.T) {
+		loader := newFakeOptionsLoader()
+		variables := getTemplateVariables(data)
+		_, err := resolveTemplateVariables(variables, nil, loader.load, true)
+		require.NoError(t, err)
+		assert.Len(t, loader.loaded, 2)
+		assert.Equal(t, []string{"web-1", "web-2"}, variables["host"].Options)
+	})
+
+	t.Run("expands all to the options, or uses the custom all value", func(t *testing.T) {
+		resolved, err := resolveTemplateVariables(variables, map[string][]string{
+			"region": {allValue},
+			"job":    {allValue},
+		}, loader.load, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"eu,west", "us", "ap"}, resolved["region"].values)
+		require.NotNil(t, resolved["job"].raw)
+		assert.Equal(t, ".*", *resolved["job"].raw)
+	})
+
+	testCases := []struct {
+		name      string
+		requested map[string][]string
+	}{
+		{name: "rejects values that are not options", requested: map[string][]string{"job": {`api"} or vector(1) or up{job="`}}},
+		{name: "rejects a different value of a constant", requested: map[string][]string{"env": {"dev"}}},
+		{name: "rejects multiple values of a single value variable", requested: map[string][]string{"job": {"api", "db"}}},
+		{name: "rejects all combined with other values", requested: map[string][]string{"region": {allValue, "us"}}},
+		{name: "rejects values of variables that can't be selected", requested: map[string][]string{"search": {"foo"}}},
+		{name: "rejects values that the query of a query variable doesn't return", requested: map[string][]string{"host": {"web-3"}}},
+		{name: "rejects unknown variables", requested: map[string][]string{"unknown": {"foo"}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := resolveTemplateVariables(variables, tc.requested, loader.load, false)
+			require.ErrorIs(t, err, ErrInvalidTemplateVariable)
+		})
+	}
+}
+
+func TestInterpolateQuery(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+	resolved, err := resolveTemplateVariables(getTemplateVariables(data), map[string][]string{"region": {"us", "ap"}}, newFakeOptionsLoader().load, false)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		datasourceType string
+		query          string
+		expected       string
+	}{
+		{name: "single value variable", datasourceType: "prometheus", query: `up{env="$env", job="${job}"}`, expected: `up{env="prod", job="api"}`},
+		{name: "multi value variable with the data source format", datasourceType: "prometheus", query: `up{region=~"$region"}`, expected: `up{region=~"(us|ap)"}`},
+		{name: "multi value variable with sql", datasourceType: "mysql", query: "SELECT * FROM t WHERE region IN ($region)", expected: "SELECT * FROM t WHERE region IN ('us','ap')"},
+		{name: "multi value variable with glob", datasourceType: "graphite", query: "servers.[[region]].cpu", expected: "servers.{us,ap}.cpu"},
+		{name: "explicit format", datasourceType: "prometheus", query: "${region:csv} ${region:pipe} [[region:json]]", expected: `us,ap us|ap ["us","ap"]`},
+		{name: "unknown and builtin variables are left", datasourceType: "prometheus", query: "rate(x[$__rate_interval]) $unknown ${job.name}", expected: "rate(x[$__rate_interval]) $unknown ${job.name}"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query := simplejson.NewFromAny(map[string]any{
+				"datasource": map[string]any{"type": tc.datasourceType, "uid": "$env"},
+				"expr":       tc.query,
+				"nested":     []any{map[string]any{"target": tc.query}},
+			})
+			interpolateQuery(query, resolved)
+			assert.Equal(t, tc.expected, query.Get("expr").MustString())
+			assert.Equal(t, tc.expected, query.Get("nested").GetIndex(0).Get("target").MustString())
+			assert.Equal(t, "$env", query.Get("datasource").Get("uid").MustString())
+		})
+	}
+
+	t.Run("custom all value is not formatted", func(t *testing.T) {
+		resolved, err := resolveTemplateVariables(getTemplateVariables(data), map[string][]string{"job": {allValue}}, newFakeOptionsLoader().load, false)
+		require.NoError(t, err)
+		query := simplejson.NewFromAny(map[string]any{"expr": `up{job=~"$job"}`})
+		interpolateQuery(query, resolved)
+		assert.Equal(t, `up{job=~".*"}`, query.Get("expr").MustString())
+	})
+
+	t.Run("regex and lucene values are escaped", func(t *testing.T) {
+		value := variableValue{variable: &templateVariable{Name: "v", Multi: true}, values: []string{"a.b", "c(d)"}}
+		assert.Equal(t, `(a\.b|c\(d\))`, formatVariableValue(value, "regex", ""))
+		assert.Equal(t, `("a.b" OR "c\(d\)")`, formatVariableValue(value, "lucene", ""))
+		assert.Equal(t, `'a.b','c(d)'`, formatVariableValue(variableValue{variable: value.variable, values: []string{"a.b", "c(d)"}}, "singlequote", ""))
+	})
+}
+
+func TestSanitizeTemplateVariables(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	variables := getTemplateVariables(data)
+	_, err = resolveTemplateVariables(variables, nil, newFakeOptionsLoader().load, true)
+	require.NoError(t, err)
+	sanitizeTemplateVariables(data, variables)
+
+	job := data.GetPath("templating", "list").GetIndex(2)
+	_, hasQuery := job.CheckGet("query")
+	assert.False(t, hasQuery)
+	_, hasDefinition := job.CheckGet("definition")
+	assert.False(t, hasDefinition)
+	assert.Equal(t, 0, job.Get("refresh").MustInt())
+	assert.Len(t, job.Get("options").MustArray(), 2)
+	assert.True(t, job.Get("options").GetIndex(0).Get("selected").MustBool())
+
+	// the options of query variables refreshed by the frontend are the ones loaded server side
+	host := data.GetPath("templating", "list").GetIndex(4)
+	assert.Equal(t, 0, host.Get("refresh").MustInt())
+	require.Len(t, host.Get("options").MustArray(), 2)
+	assert.Equal(t, "web-2", host.Get("options").GetIndex(1).Get("value").MustString())
+
+	// custom variables are left as they are
+	assert.Equal(t, "Europe : eu\\,west, us, ap", data.GetPath("templating", "list").GetIndex(1).Get("query").MustString())
+}
+
+func TestVariableDataQuery(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+	variables := getTemplateVariables(data)
+
+	query := variableDataQuery(variables["host"])
+	assert.Equal(t, `label_values(up{job="$job"}, instance)`, query.Get("query").MustString())
+	assert.Equal(t, variableQueryRefID, query.Get("refId").MustString())
+	assert.Equal(t, "prom", query.Get("datasource").Get("uid").MustString())
+
+	query = variableDataQuery(&templateVariable{Query: "SELECT name FROM hosts", Datasource: map[string]any{"type": "mysql", "uid": "db"}})
+	assert.Equal(t, "SELECT name FROM hosts", query.Get("rawSql").MustString())
+	assert.Equal(t, "table", query.Get("format").MustString())
+}
+
+func TestVariableQueryValues(t *testing.T) {
+	frames := data.Frames{
+		data.NewFrame("", data.NewField("text", nil, []string{"API", "DB"}), data.NewField("value", nil, []string{"api", "db"})),
+		data.NewFrame("", data.NewField("count", nil, []int64{1, 2})),
+	}
+	assert.Equal(t, []string{"api", "db", "1", "2"}, variableQueryValues(frames))
+}
+
+func TestFilterVariableValues(t *testing.T) {
+	testCases := []struct {
+		name     string
+		regex    string
+		expected []string
+	}{
+		{name: "no regex", regex: "", expected: []string{"web-1:9100", "web-2:9100", "db-1:9100"}},
+		{name: "matches the whole value", regex: "web.*", expected: []string{"web-1:9100", "web-2:9100"}},
+		{name: "first capture group", regex: "/(web-\\d+):.*/", expected: []string{"web-1", "web-2"}},
+		{name: "named value group", regex: "/(?<text>\\w+)-(?<value>\\d+)/", expected: []string{"1", "2"}},
+		{name: "flags", regex: "/^WEB/i", expected: []string{"web"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := filterVariableValues([]string{"web-1:9100", "web-2:9100", "db-1:9100"}, tc.regex)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestQueryVariableOptions(t *testing.T) {
+	dashboardData, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+	dashboard := &dashboards.Dashboard{UID: "dash", OrgID: 1, Data: dashboardData}
+	ts := TimeSettings{From: "1000", To: "2000"}
+	resolved := map[string]variableValue{"job": {variable: &templateVariable{Name: "job"}, values: []string{"db"}}}
+
+	t.Run("runs the query of the variable through its data source", func(t *testing.T) {
+		fakeQueryService := &query.FakeQueryService{}
+		fakeQueryService.On("QueryData", mock.Anything, mock.Anything, false, mock.MatchedBy(func(req dtos.MetricRequest) bool {
+			q := req.Queries[0]
+			return req.From == "1000" && req.To == "2000" &&
+				q.Get("query").MustString() == `label_values(up{job="db"}, instance)` &&
+				q.Get("datasource").Get("uid").MustString() == "prom"
+		})).Return(&backend.QueryDataResponse{Responses: backend.Responses{variableQueryRefID: {
+			Frames: data.Frames{data.NewFrame("", data.NewField("value", nil, []string{"web-3:9100", "db-1:9100"}))},
+		}}}, nil)
+		pd := &PublicDashboardServiceImpl{log: log.NewNopLogger(), QueryDataService: fakeQueryService}
+
+		variables := getTemplateVariables(dashboardData)
+		pd.queryVariableOptions(context.Background(), dashboard, ts)(variables["host"], resolved)
+		assert.Equal(t, []string{"web-3", "web-1"}, variables["host"].Options)
+	})
+
+	t.Run("only allows the saved value when the query fails", func(t *testing.T) {
+		fakeQueryService := &query.FakeQueryService{}
+		fakeQueryService.On("QueryData", mock.Anything, mock.Anything, false, mock.Anything).Return(nil, errors.New("data source unavailable"))
+		pd := &PublicDashboardServiceImpl{log: log.NewNopLogger(), QueryDataService: fakeQueryService}
+
+		variables := getTemplateVariables(dashboardData)
+		pd.queryVariableOptions(context.Background(), dashboard, ts)(variables["host"], resolved)
+		assert.Equal(t, []string{"web-1"}, variables["host"].Options)
+	})
+}
@@ -13,6 +13,10 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	if dto.PublicDashboard.MaxViews != nil && *dto.PublicDashboard.MaxViews < 0 {
+		return ErrInvalidMaxViews.Errorf("ValidateSavePublicDashboard: maxViews should not be negative")
+	}
+
 	return nil
 }
 
@@ -22,6 +22,14 @@ func TestValidatePublicDashboard(t *testing.T) {
 		err := ValidatePublicDashboard(dto)
 		require.Error(t, err)
 	})
+
+	t.Run("Returns error when maxViews is negative", func(t *testing.T) {
+		maxViews := int64(-1)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{MaxViews: &maxViews}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidMaxViews)
+	})
 }
 
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
@@ -17,9 +17,11 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/caching"
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/validations"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/grafanads"
@@ -43,6 +45,8 @@ func ProvideService(
 	pluginRequestValidator validations.PluginRequestValidator,
 	pluginClient plugins.Client,
 	pCtxProvider *plugincontext.Provider,
+	incrementalCache *caching.IncrementalQueryCache,
+	quotaService quota.Service,
 ) *ServiceImpl {
 	g := &ServiceImpl{
 		cfg:                    cfg,
@@ -51,6 +55,8 @@ func ProvideService(
 		pluginRequestValidator: pluginRequestValidator,
 		pluginClient:           pluginClient,
 		pCtxProvider:           pCtxProvider,
+		incrementalCache:       incrementalCache,
+		quotaService:           quotaService,
 		log:                    log.New("query_data"),
 		concurrentQueryLimit:   cfg.SectionWithEnvOverrides("query").Key("concurrent_query_limit").MustInt(runtime.NumCPU()),
 	}
@@ -74,6 +80,8 @@ type ServiceImpl struct {
 	pluginRequestValidator validations.PluginRequestValidator
 	pluginClient           plugins.Client
 	pCtxProvider           *plugincontext.Provider
+	incrementalCache       *caching.IncrementalQueryCache
+	quotaService           quota.Service
 	log                    log.Logger
 	concurrentQueryLimit   int
 }
@@ -85,7 +93,28 @@ func (s *ServiceImpl) Run(ctx context.Context) error {
 }
 
 // QueryData processes queries and returns query responses. It handles queries to single or mixed datasources, as well as expressions.
+// Requests over the rate-based query quotas of the user or its org fail with a quota.RateLimitedError.
 func (s *ServiceImpl) QueryData(ctx context.Context, user identity.Requester, skipDSCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	if err := s.checkRateQuota(ctx, user); err != nil {
+		return nil, err
+	}
+	return s.queryData(ctx, user, skipDSCache, reqDTO)
+}
+
+// checkRateQuota counts the request towards the rate-based query quotas of the user and its org.
+func (s *ServiceImpl) checkRateQuota(ctx context.Context, user identity.Requester) error {
+	if user == nil || user.IsNil() {
+		return nil
+	}
+	if userID, _ := identity.UserIdentifier(user.GetNamespacedID()); userID > 0 {
+		if err := s.quotaService.CheckRateReached(ctx, quota.RateTargetQueries, quota.UserScope, userID); err != nil {
+			return err
+		}
+	}
+	return s.quotaService.CheckRateReached(ctx, quota.RateTargetQueries, quota.OrgScope, user.GetOrgID())
+}
+
+func (s *ServiceImpl) queryData(ctx context.Context, user identity.Requester, skipDSCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error) {
 	// Parse the request into parsed queries grouped by datasource uid
 	parsedReq, err := s.parseMetricRequest(ctx, user, skipDSCache, reqDTO)
 	if err != nil {
@@ -145,7 +174,7 @@ func (s *ServiceImpl) executeConcurrentQueries(ctx context.Context, user identit
 			defer recoveryFn(subDTO.Queries)
 
 			ctxCopy := contexthandler.CopyWithReqContext(ctx)
-			subResp, err := s.QueryData(ctxCopy, user, skipDSCache, subDTO)
+			subResp, err := s.queryData(ctxCopy, user, skipDSCache, subDTO)
 			if err == nil {
 				reqCtx, header := contexthandler.FromContext(ctxCopy), http.Header{}
 				if reqCtx != nil {
@@ -267,6 +296,10 @@ func (s *ServiceImpl) handleQuerySingleDatasource(ctx context.Context, user iden
 		req.Queries = append(req.Queries, q.query)
 	}
 
+	// Reuse the cached time series for the parts of the time ranges that were already queried
+	if s.incrementalCache.Enabled() {
+		return s.incrementalCache.QueryData(ctx, req, s.pluginClient)
+	}
 	return s.pluginClient.QueryData(ctx, req)
 }
 
@@ -36,6 +36,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
 	pluginSettings "github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings/service"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	secretskvs "github.com/grafana/grafana/pkg/services/secrets/kvstore"
 	secretsmng "github.com/grafana/grafana/pkg/services/secrets/manager"
@@ -446,6 +448,47 @@ func TestQueryDataMultipleSources(t *testing.T) {
 
 		require.NoError(t, err)
 	})
+
+	t.Run("counts a mixed query once towards the rate quotas", func(t *testing.T) {
+		tc := setup(t)
+		tc.signedInUser.UserID = 2
+		reqDTO := metricRequestWithQueries(t, `{
+			"refId": "A",
+			"datasource": {
+				"uid": "ds1",
+				"type": "mysql"
+			}
+		}`, `{
+			"refId": "B",
+			"datasource": {
+				"uid": "ds2",
+				"type": "mysql"
+			}
+		}`)
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, reqDTO)
+		require.NoError(t, err)
+		require.Equal(t, []quota.Scope{quota.UserScope, quota.OrgScope}, tc.quotaService.checked)
+	})
+
+	t.Run("fails when the rate quota is reached", func(t *testing.T) {
+		tc := setup(t)
+		tc.quotaService.ExpectedRateError = quota.NewRateLimitedError(quota.RateTargetQueries, quota.OrgScope, 10*time.Second)
+		reqDTO := metricRequestWithQueries(t, `{
+			"refId": "A",
+			"datasource": {
+				"uid": "ds1",
+				"type": "mysql"
+			}
+		}`)
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, reqDTO)
+		var rateErr *quota.RateLimitedError
+		require.ErrorAs(t, err, &rateErr)
+		require.ErrorIs(t, err, quota.ErrRateLimited)
+		require.Equal(t, "10", rateErr.RetryAfterHeader())
+		require.Nil(t, tc.pluginContext.req)
+	})
 }
 
 func setup(t *testing.T) *testContext {
@@ -481,12 +524,14 @@ func setup(t *testing.T) *testContext {
 	)
 	exprService := expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, pc, pCtxProvider,
 		&featuremgmt.FeatureManager{}, nil, tracing.InitializeTracerForTest())
-	queryService := ProvideService(setting.NewCfg(), dc, exprService, rv, pc, pCtxProvider) // provider belonging to this package
+	quotaService := &fakeRateQuotaService{FakeQuotaService: quotatest.New(false, nil)}
+	queryService := ProvideService(setting.NewCfg(), dc, exprService, rv, pc, pCtxProvider, nil, quotaService) // provider belonging to this package
 	return &testContext{
 		pluginContext:          pc,
 		secretStore:            ss,
 		pluginRequestValidator: rv,
 		queryService:           queryService,
+		quotaService:           quotaService,
 		signedInUser:           &user.SignedInUser{OrgID: 1, Login: "login", Name: "name", Email: "email", OrgRole: roletype.RoleAdmin},
 	}
 }
@@ -496,9 +541,21 @@ type testContext struct {
 	secretStore            secretskvs.SecretsKVStore
 	pluginRequestValidator *fakePluginRequestValidator
 	queryService           *ServiceImpl // implementation belonging to this package
+	quotaService           *fakeRateQuotaService
 	signedInUser           *user.SignedInUser
 }
 
+// fakeRateQuotaService records the scopes of the rate-based quotas checked.
+type fakeRateQuotaService struct {
+	*quotatest.FakeQuotaService
+	checked []quota.Scope
+}
+
+func (f *fakeRateQuotaService) CheckRateReached(ctx context.Context, target quota.RateTarget, scope quota.Scope, id int64) error {
+	f.checked = append(f.checked, scope)
+	return f.FakeQuotaService.CheckRateReached(ctx, target, scope, id)
+}
+
 func metricRequestWithQueries(t *testing.T, rawQueries ...string) dtos.MetricRequest {
 	t.Helper()
 	queries := make([]*simplejson.Json, 0)
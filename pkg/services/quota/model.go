@@ -29,6 +29,8 @@ const (
 	GlobalScope Scope = "global"
 	OrgScope    Scope = "org"
 	UserScope   Scope = "user"
+	// TokenScope is the scope of a service account token. It's only used by rate-based quotas.
+	TokenScope Scope = "token"
 )
 
 func (s Scope) Validate() error {
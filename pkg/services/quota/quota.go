@@ -25,6 +25,9 @@ type Service interface {
 
 	// RegisterQuotaReporter registers a service UsageReporterFunc, targets and their default limits
 	RegisterQuotaReporter(e *NewUsageReporter) error
+	// CheckRateReached counts a request towards the rate-based quota of the target for the scope (organization, user)
+	// and returns a RateLimitedError if the quota of the current minute is reached.
+	CheckRateReached(ctx context.Context, target RateTarget, scope Scope, id int64) error
 }
 
 type UsageReporterFunc func(ctx context.Context, scopeParams *ScopeParameters) (*Map, error)
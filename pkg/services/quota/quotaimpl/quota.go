@@ -3,6 +3,7 @@ package quotaimpl
 import (
 	"context"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -40,6 +41,10 @@ func (s *serviceDisabled) RegisterQuotaReporter(e *quota.NewUsageReporter) error
 	return nil
 }
 
+func (s *serviceDisabled) CheckRateReached(ctx context.Context, target quota.RateTarget, scope quota.Scope, id int64) error {
+	return nil
+}
+
 type service struct {
 	store  store
 	Cfg    *setting.Cfg
@@ -51,6 +56,8 @@ type service struct {
 	defaultLimits *quota.Map
 
 	targetToSrv *quota.TargetToSrv
+
+	rates *rateCounter
 }
 
 func ProvideService(db db.DB, cfg *setting.Cfg) quota.Service {
@@ -62,6 +69,7 @@ func ProvideService(db db.DB, cfg *setting.Cfg) quota.Service {
 		reporters:     make(map[quota.TargetSrv]quota.UsageReporterFunc),
 		defaultLimits: &quota.Map{},
 		targetToSrv:   quota.NewTargetToSrv(),
+		rates:         newRateCounter(time.Now),
 	}
 
 	if s.IsDisabled() {
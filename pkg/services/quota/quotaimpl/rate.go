@@ -0,0 +1,80 @@
+package quotaimpl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/quota"
+)
+
+// CheckRateReached counts a request towards the rate-based quota of the target for the scope.
+// Requests over the quota are not counted, so that a client retrying too early doesn't extend its wait.
+func (s *service) CheckRateReached(ctx context.Context, target quota.RateTarget, scope quota.Scope, id int64) error {
+	limit := s.rateLimit(target, scope)
+	if limit < 0 {
+		return nil
+	}
+	if retryAfter, ok := s.rates.take(rateKey{target: target, scope: scope, id: id}, limit); !ok {
+		s.Logger.Debug("Rate quota reached", "target", target, "scope", scope, "id", id, "limit", limit)
+		return quota.NewRateLimitedError(target, scope, retryAfter)
+	}
+	return nil
+}
+
+// rateLimit returns the limit per minute of the target for the scope, or -1 if it's unlimited.
+func (s *service) rateLimit(target quota.RateTarget, scope quota.Scope) int64 {
+	rate := s.Cfg.Quota.Rate
+	switch {
+	case target == quota.RateTargetQueries && scope == quota.UserScope:
+		return rate.UserQueries
+	case target == quota.RateTargetQueries && scope == quota.OrgScope:
+		return rate.OrgQueries
+	case target == quota.RateTargetAlertRuleEvaluations && scope == quota.OrgScope:
+		return rate.OrgAlertRuleEvaluations
+	case target == quota.RateTargetAPIRequests && scope == quota.TokenScope:
+		return rate.TokenAPIRequests
+	default:
+		return -1
+	}
+}
+
+type rateKey struct {
+	target quota.RateTarget
+	scope  quota.Scope
+	id     int64
+}
+
+// rateCounter counts requests in fixed windows of a minute. Only the counts
+// of the current minute are kept.
+type rateCounter struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	window time.Time
+	counts map[rateKey]int64
+}
+
+func newRateCounter(now func() time.Time) *rateCounter {
+	return &rateCounter{now: now, counts: map[rateKey]int64{}}
+}
+
+// take counts a request if the count of the current minute is below the limit.
+// Otherwise it returns how long until the next minute starts.
+func (r *rateCounter) take(key rateKey, limit int64) (time.Duration, bool) {
+	now := r.now()
+	window := now.Truncate(time.Minute)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !window.Equal(r.window) {
+		r.window = window
+		r.counts = map[rateKey]int64{}
+	}
+	if r.counts[key] >= limit {
+		return window.Add(time.Minute).Sub(now), false
+	}
+	r.counts[key]++
+	return 0, true
+}
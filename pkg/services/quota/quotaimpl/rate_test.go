@@ -0,0 +1,47 @@
+package quotaimpl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestCheckRateReached(t *testing.T) {
+	now := time.Date(2023, 10, 10, 12, 0, 15, 0, time.UTC)
+	cfg := setting.NewCfg()
+	cfg.Quota.Rate = setting.RateQuota{UserQueries: 2, OrgQueries: -1, OrgAlertRuleEvaluations: -1, TokenAPIRequests: -1}
+	s := &service{Cfg: cfg, Logger: log.NewNopLogger(), rates: newRateCounter(func() time.Time { return now })}
+	ctx := context.Background()
+
+	t.Run("allows requests within the quota", func(t *testing.T) {
+		require.NoError(t, s.CheckRateReached(ctx, quota.RateTargetQueries, quota.UserScope, 1))
+		require.NoError(t, s.CheckRateReached(ctx, quota.RateTargetQueries, quota.UserScope, 1))
+		require.NoError(t, s.CheckRateReached(ctx, quota.RateTargetQueries, quota.UserScope, 2))
+	})
+
+	t.Run("rejects requests over the quota until the next minute", func(t *testing.T) {
+		err := s.CheckRateReached(ctx, quota.RateTargetQueries, quota.UserScope, 1)
+		var rateLimited *quota.RateLimitedError
+		require.True(t, errors.As(err, &rateLimited))
+		require.ErrorIs(t, err, quota.ErrRateLimited)
+		require.Equal(t, 45*time.Second, rateLimited.RetryAfter)
+		require.Equal(t, "45", rateLimited.RetryAfterHeader())
+
+		now = now.Add(45 * time.Second)
+		require.NoError(t, s.CheckRateReached(ctx, quota.RateTargetQueries, quota.UserScope, 1))
+	})
+
+	t.Run("ignores unlimited quotas", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			require.NoError(t, s.CheckRateReached(ctx, quota.RateTargetQueries, quota.OrgScope, 1))
+			require.NoError(t, s.CheckRateReached(ctx, quota.RateTargetAPIRequests, quota.TokenScope, 1))
+		}
+	})
+}
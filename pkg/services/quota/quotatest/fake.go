@@ -10,10 +10,22 @@ import (
 type FakeQuotaService struct {
 	reached bool
 	err     error
+
+	// ExpectedRateError is returned by CheckRateReached.
+	ExpectedRateError error
+	// RateChecks records the CheckRateReached calls.
+	RateChecks []RateCheck
+}
+
+// RateCheck is a call to CheckRateReached.
+type RateCheck struct {
+	Target quota.RateTarget
+	Scope  quota.Scope
+	ID     int64
 }
 
 func New(reached bool, err error) *FakeQuotaService {
-	return &FakeQuotaService{reached, err}
+	return &FakeQuotaService{reached: reached, err: err}
 }
 
 func (f *FakeQuotaService) GetQuotasByScope(ctx context.Context, scope quota.Scope, id int64) ([]quota.QuotaDTO, error) {
@@ -40,6 +52,11 @@ func (f *FakeQuotaService) RegisterQuotaReporter(e *quota.NewUsageReporter) erro
 	return f.err
 }
 
+func (f *FakeQuotaService) CheckRateReached(ctx context.Context, target quota.RateTarget, scope quota.Scope, id int64) error {
+	f.RateChecks = append(f.RateChecks, RateCheck{Target: target, Scope: scope, ID: id})
+	return f.ExpectedRateError
+}
+
 type FakeQuotaStore struct {
 	ExpectedError error
 }
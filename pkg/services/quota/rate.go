@@ -0,0 +1,54 @@
+package quota
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// ErrRateLimited is the base error of a RateLimitedError.
+var ErrRateLimited = errutil.TooManyRequests("quota.rate-limited", errutil.WithPublicMessage("Rate limit reached, try again later"))
+
+// RateTarget is the target of a rate-based quota, which limits how many times
+// something can happen per minute, unlike the quotas of stored objects.
+type RateTarget string
+
+const (
+	// RateTargetQueries counts the data source queries per user and per org.
+	RateTargetQueries RateTarget = "queries"
+	// RateTargetAlertRuleEvaluations counts the alert rule evaluations per org.
+	RateTargetAlertRuleEvaluations RateTarget = "alert_rule_evaluations"
+	// RateTargetAPIRequests counts the API requests per service account token.
+	RateTargetAPIRequests RateTarget = "api_requests"
+)
+
+// RateLimitedError is returned when a rate-based quota is reached.
+type RateLimitedError struct {
+	Target RateTarget
+	// RetryAfter is how long until the quota allows requests again.
+	RetryAfter time.Duration
+	err        error
+}
+
+func NewRateLimitedError(target RateTarget, scope Scope, retryAfter time.Duration) *RateLimitedError {
+	return &RateLimitedError{
+		Target:     target,
+		RetryAfter: retryAfter,
+		err:        ErrRateLimited.Errorf("%s %s rate quota reached, retry after %s", scope, target, retryAfter),
+	}
+}
+
+func (e *RateLimitedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfterHeader returns the value of the Retry-After header, in seconds.
+func (e *RateLimitedError) RetryAfterHeader() string {
+	return strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds())))
+}
@@ -37,8 +37,8 @@ const (
 	DocumentFieldUpdatedAt   = "updated_at"
 )
 
-func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
-	dashboardWriter, err := bluge.OpenWriter(bluge.InMemoryOnlyConfig())
+func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc, writerConfig bluge.Config) (*orgIndex, error) {
+	dashboardWriter, err := bluge.OpenWriter(writerConfig)
 	if err != nil {
 		return nil, fmt.Errorf("error opening writer: %v", err)
 	}
@@ -68,6 +68,8 @@ type buildSignal struct {
 
 type orgIndex struct {
 	writers map[indexType]*bluge.Writer
+	// dir is the directory of a persistent index, relative to the index path. Empty for in-memory indexes.
+	dir string
 }
 
 type indexType string
@@ -94,6 +96,7 @@ type searchIndex struct {
 	perOrgIndex             map[int64]*orgIndex
 	initializedOrgs         map[int64]bool
 	initialIndexingComplete bool
+	closed                  bool
 	initializationMutex     sync.RWMutex
 	eventStore              eventStore
 	logger                  log.Logger
@@ -194,22 +197,29 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 	partialUpdateTimer := time.NewTimer(partialUpdateInterval)
 	defer partialUpdateTimer.Stop()
 
-	var lastEventID int64
-	lastEvent, err := i.eventStore.GetLastEvent(initialSetupCtx)
-	if err != nil {
-		initialSetupSpan.End()
-		return err
-	}
-	if lastEvent != nil {
-		lastEventID = lastEvent.Id
+	// Indexes persisted on the last shutdown only need the events that happened since then.
+	lastEventID, restored := i.loadPersistedIndexes()
+	if !restored {
+		lastEvent, err := i.eventStore.GetLastEvent(initialSetupCtx)
+		if err != nil {
+			initialSetupSpan.End()
+			return err
+		}
+		if lastEvent != nil {
+			lastEventID = lastEvent.Id
+		}
 	}
 
-	err = i.buildInitialIndexes(initialSetupCtx, orgIDs)
+	err := i.buildInitialIndexes(initialSetupCtx, orgIDs)
 	if err != nil {
 		initialSetupSpan.End()
 		return err
 	}
 
+	if restored {
+		lastEventID = i.applyIndexUpdates(initialSetupCtx, lastEventID)
+	}
+
 	// This semaphore channel allows limiting concurrent async re-indexing routines to 1.
 	asyncReIndexSemaphore := make(chan struct{}, 1)
 
@@ -302,6 +312,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 			}
 			fullReIndexTimer.Reset(reIndexInterval)
 		case <-ctx.Done():
+			i.persistIndexes(lastEventID)
 			return ctx.Err()
 		}
 	}
@@ -311,6 +322,10 @@ func (i *searchIndex) buildInitialIndexes(ctx context.Context, orgIDs []int64) e
 	started := time.Now()
 	i.logger.Info("Start building in-memory indexes")
 	for _, orgID := range orgIDs {
+		if _, ok := i.getOrgIndex(orgID); ok {
+			// Index was loaded from disk.
+			continue
+		}
 		err := i.buildInitialIndex(ctx, orgID)
 		if err != nil {
 			return fmt.Errorf("can't build initial dashboard search index for org %d: %w", orgID, err)
@@ -496,13 +511,16 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 		attribute.Int("dashboardCount", len(dashboards)),
 	))
 
-	index, err := initOrgIndex(dashboards, i.logger, dashboardExtender)
+	writerConfig, dir := i.newWriterConfig(orgID)
+	index, err := initOrgIndex(dashboards, i.logger, dashboardExtender, writerConfig)
 
 	initOrgIndexSpan.End()
 
 	if err != nil {
+		i.removeIndexDir(dir)
 		return 0, fmt.Errorf("error initializing index: %w", err)
 	}
+	index.dir = dir
 	orgSearchIndexTotalTime := time.Since(started)
 	orgSearchIndexBuildTime := orgSearchIndexTotalTime - orgSearchIndexLoadTime
 
@@ -514,10 +532,20 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 			"orgSearchDashboardCount", len(dashboards))...)
 
 	i.mu.Lock()
+	if i.closed {
+		// Indexes were persisted while this one was being built.
+		i.mu.Unlock()
+		for _, w := range index.writers {
+			_ = w.Close()
+		}
+		i.removeIndexDir(dir)
+		return 0, errors.New("search index is closed")
+	}
 	if oldIndex, ok := i.perOrgIndex[orgID]; ok {
 		for _, w := range oldIndex.writers {
 			_ = w.Close()
 		}
+		i.removeIndexDir(oldIndex.dir)
 	}
 	i.perOrgIndex[orgID] = index
 	i.mu.Unlock()
@@ -0,0 +1,208 @@
+package searchV2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/blugelabs/bluge"
+
+	"github.com/grafana/grafana/pkg/services/store"
+)
+
+// indexStateFileName is the name of the file describing the indexes left on disk by a clean shutdown.
+const indexStateFileName = "index-state.json"
+
+// persistedIndexState describes the indexes kept on disk, and the last entity event applied to them.
+type persistedIndexState struct {
+	LastEventID int64 `json:"lastEventId"`
+	// Persisted is when the indexes were persisted. Entity events older than their retention are
+	// deleted, so indexes persisted before that can't catch up and are built from scratch.
+	Persisted time.Time `json:"persisted"`
+	// Orgs maps org IDs to the directories of their dashboard indexes, relative to the index path.
+	Orgs map[int64]string `json:"orgs"`
+}
+
+// newWriterConfig returns the configuration of a new dashboard index writer for an organization.
+// For persistent indexes it also returns the directory of the index, relative to the index path.
+func (i *searchIndex) newWriterConfig(orgID int64) (bluge.Config, string) {
+	if !i.settings.PersistentIndex {
+		return bluge.InMemoryOnlyConfig(), ""
+	}
+	// Every build of an index gets its own directory, so a rebuild never touches the index currently in use.
+	dir := filepath.Join(strconv.FormatInt(orgID, 10), strconv.FormatInt(time.Now().UnixNano(), 10))
+	return bluge.DefaultConfig(filepath.Join(i.settings.IndexPath, dir)), dir
+}
+
+// removeIndexDir removes the directory of a persistent index, if any.
+func (i *searchIndex) removeIndexDir(dir string) {
+	if dir == "" {
+		return
+	}
+	if err := os.RemoveAll(filepath.Join(i.settings.IndexPath, dir)); err != nil {
+		i.logger.Warn("Can't remove search index directory", "dir", dir, "error", err)
+	}
+}
+
+// loadPersistedIndexes opens the indexes left on disk by the previous clean shutdown. It returns the ID
+// of the last entity event applied to them, and false if there was nothing to load.
+//
+// The state file is removed once read, so after a crash the indexes are never trusted and are built from scratch.
+func (i *searchIndex) loadPersistedIndexes() (int64, bool) {
+	if !i.settings.PersistentIndex {
+		return 0, false
+	}
+
+	state, err := readIndexState(i.settings.IndexPath)
+	if err != nil {
+		i.logger.Warn("Can't read persisted search index state, indexes will be built from scratch", "error", err)
+	}
+	if state == nil {
+		i.removeUnusedIndexDirs(nil)
+		return 0, false
+	}
+	if age := time.Since(state.Persisted); age >= store.EntityEventsRetention {
+		i.logger.Info("Persisted search indexes are older than the retention of entity events, they will be built from scratch", "persisted", state.Persisted, "retention", store.EntityEventsRetention)
+		i.removeUnusedIndexDirs(nil)
+		return 0, false
+	}
+
+	started := time.Now()
+	loaded := make(map[int64]string, len(state.Orgs))
+	for orgID, dir := range state.Orgs {
+		writer, err := bluge.OpenWriter(bluge.DefaultConfig(filepath.Join(i.settings.IndexPath, dir)))
+		if err != nil {
+			i.logger.Warn("Can't open persisted search index, it will be built from scratch", "orgId", orgID, "error", err)
+			continue
+		}
+		i.mu.Lock()
+		i.perOrgIndex[orgID] = &orgIndex{
+			writers: map[indexType]*bluge.Writer{
+				indexTypeDashboard: writer,
+			},
+			dir: dir,
+		}
+		i.mu.Unlock()
+
+		i.initializationMutex.Lock()
+		i.initializedOrgs[orgID] = true
+		i.initializationMutex.Unlock()
+		loaded[orgID] = dir
+	}
+	i.removeUnusedIndexDirs(loaded)
+
+	if len(loaded) == 0 {
+		return 0, false
+	}
+	i.logger.Info("Loaded persisted search indexes", "orgs", len(loaded), "lastEventId", state.LastEventID, "elapsed", time.Since(started))
+	return state.LastEventID, true
+}
+
+// persistIndexes closes all indexes and records them, together with the last applied entity event,
+// so they can be loaded on the next start. No index can be built or updated afterwards.
+func (i *searchIndex) persistIndexes(lastEventID int64) {
+	if !i.settings.PersistentIndex {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.closed = true
+
+	state := persistedIndexState{
+		LastEventID: lastEventID,
+		Persisted:   time.Now(),
+		Orgs:        make(map[int64]string, len(i.perOrgIndex)),
+	}
+	for orgID, index := range i.perOrgIndex {
+		var closeErr error
+		for _, w := range index.writers {
+			if err := w.Close(); err != nil {
+				closeErr = err
+			}
+		}
+		if closeErr != nil {
+			i.logger.Warn("Can't close search index, it will be built from scratch on the next start", "orgId", orgID, "error", closeErr)
+			continue
+		}
+		state.Orgs[orgID] = index.dir
+	}
+
+	if err := writeIndexState(i.settings.IndexPath, state); err != nil {
+		i.logger.Error("Can't persist search index state", "error", err)
+		return
+	}
+	i.logger.Info("Persisted search indexes", "orgs", len(state.Orgs), "lastEventId", lastEventID)
+}
+
+// removeUnusedIndexDirs removes index directories that are not in use, such as leftovers from interrupted builds.
+func (i *searchIndex) removeUnusedIndexDirs(inUse map[int64]string) {
+	orgDirs, err := os.ReadDir(i.settings.IndexPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			i.logger.Warn("Can't list search index directory", "error", err)
+		}
+		return
+	}
+	used := make(map[string]struct{}, len(inUse))
+	for _, dir := range inUse {
+		used[dir] = struct{}{}
+	}
+	for _, orgDir := range orgDirs {
+		if !orgDir.IsDir() {
+			continue
+		}
+		dirs, err := os.ReadDir(filepath.Join(i.settings.IndexPath, orgDir.Name()))
+		if err != nil {
+			i.logger.Warn("Can't list search index directory", "error", err)
+			continue
+		}
+		for _, d := range dirs {
+			dir := filepath.Join(orgDir.Name(), d.Name())
+			if _, ok := used[dir]; !ok {
+				i.removeIndexDir(dir)
+			}
+		}
+	}
+}
+
+// readIndexState reads and removes the index state file. It returns nil if there is no state file.
+func readIndexState(path string) (*persistedIndexState, error) {
+	file := filepath.Join(path, indexStateFileName)
+	b, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := os.Remove(file); err != nil {
+		return nil, fmt.Errorf("can't remove index state file: %w", err)
+	}
+
+	var state persistedIndexState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, fmt.Errorf("can't decode index state: %w", err)
+	}
+	return &state, nil
+}
+
+// writeIndexState atomically writes the index state file.
+func writeIndexState(path string, state persistedIndexState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path, 0750); err != nil {
+		return err
+	}
+	tmp := filepath.Join(path, indexStateFileName+".tmp")
+	if err := os.WriteFile(tmp, b, 0640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(path, indexStateFileName))
+}
@@ -0,0 +1,222 @@
+package searchV2
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/store"
+	"github.com/grafana/grafana/pkg/services/store/entity"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// countingDashboardLoader returns dashboards filtered by UID and counts the loads of whole organizations.
+type countingDashboardLoader struct {
+	mu         sync.Mutex
+	dashboards []dashboard
+	fullLoads  int
+}
+
+func (l *countingDashboardLoader) LoadDashboards(_ context.Context, _ int64, dashboardUID string) ([]dashboard, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if dashboardUID == "" {
+		l.fullLoads++
+		return l.dashboards, nil
+	}
+	for _, d := range l.dashboards {
+		if d.uid == dashboardUID {
+			return []dashboard{d}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (l *countingDashboardLoader) add(d dashboard) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dashboards = append(l.dashboards, d)
+}
+
+func (l *countingDashboardLoader) loads() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fullLoads
+}
+
+type fakeEventStore struct {
+	mu     sync.Mutex
+	events []*store.EntityEvent
+}
+
+func (s *fakeEventStore) GetLastEvent(_ context.Context) (*store.EntityEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) == 0 {
+		return nil, nil
+	}
+	return s.events[len(s.events)-1], nil
+}
+
+func (s *fakeEventStore) GetAllEventsAfter(_ context.Context, id int64) ([]*store.EntityEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res []*store.EntityEvent
+	for _, e := range s.events {
+		if e.Id > id {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+func (s *fakeEventStore) add(e *store.EntityEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, e)
+}
+
+func newPersistentTestIndex(loader dashboardLoader, events eventStore, path string) *searchIndex {
+	return newSearchIndex(loader, events, &NoopDocumentExtender{}, func(ctx context.Context, folderId int64) (string, error) { return "x", nil },
+		tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{
+			FullReindexInterval: time.Hour,
+			IndexUpdateInterval: time.Hour,
+			PersistentIndex:     true,
+			IndexPath:           path,
+		})
+}
+
+// runTestIndex runs the index until it is ready and returns a function that stops it.
+func runTestIndex(t *testing.T, index *searchIndex) func() {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- index.run(ctx, []int64{testOrgID}, make(chan struct{}))
+	}()
+	require.Eventually(t, func() bool {
+		return index.isInitialized(ctx, testOrgID).IsReady
+	}, 5*time.Second, 10*time.Millisecond)
+	return func() {
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	}
+}
+
+func requireIndexed(t *testing.T, index *searchIndex, uid string, expected bool) {
+	t.Helper()
+	orgIdx, ok := index.getOrgIndex(testOrgID)
+	require.True(t, ok)
+	_, found, err := getDashboardLocation(orgIdx, uid)
+	require.NoError(t, err)
+	require.Equal(t, expected, found)
+}
+
+func TestPersistentIndex(t *testing.T) {
+	t.Run("index is reused after a clean shutdown and events since then are applied", func(t *testing.T) {
+		path := t.TempDir()
+		loader := &countingDashboardLoader{dashboards: []dashboard{
+			{id: 1, uid: "1", summary: &entity.EntitySummary{Name: "test"}},
+		}}
+		events := &fakeEventStore{}
+		events.add(&store.EntityEvent{Id: 1, EventType: store.EntityEventTypeCreate, EntityId: store.CreateDatabaseEntityId("1", testOrgID, store.EntityTypeDashboard)})
+
+		index := newPersistentTestIndex(loader, events, path)
+		stop := runTestIndex(t, index)
+		requireIndexed(t, index, "1", true)
+		stop()
+		require.Equal(t, 1, loader.loads())
+		require.FileExists(t, filepath.Join(path, indexStateFileName))
+
+		// A dashboard is created while Grafana is down.
+		loader.add(dashboard{id: 2, uid: "2", summary: &entity.EntitySummary{Name: "boom"}})
+		events.add(&store.EntityEvent{Id: 2, EventType: store.EntityEventTypeCreate, EntityId: store.CreateDatabaseEntityId("2", testOrgID, store.EntityTypeDashboard)})
+
+		index = newPersistentTestIndex(loader, events, path)
+		stop = runTestIndex(t, index)
+		defer stop()
+		require.Equal(t, 1, loader.loads(), "org should not be indexed from scratch")
+		requireIndexed(t, index, "1", true)
+		requireIndexed(t, index, "2", true)
+		require.NoFileExists(t, filepath.Join(path, indexStateFileName))
+	})
+
+	t.Run("index is built from scratch without a clean shutdown", func(t *testing.T) {
+		path := t.TempDir()
+		loader := &countingDashboardLoader{dashboards: []dashboard{
+			{id: 1, uid: "1", summary: &entity.EntitySummary{Name: "test"}},
+		}}
+		index := newPersistentTestIndex(loader, &fakeEventStore{}, path)
+		_, err := index.buildOrgIndex(context.Background(), testOrgID)
+		require.NoError(t, err)
+		orgIdx, _ := index.getOrgIndex(testOrgID)
+		require.DirExists(t, filepath.Join(path, orgIdx.dir))
+
+		// Simulate a crash: the index is left on disk, but no state is recorded.
+		index = newPersistentTestIndex(loader, &fakeEventStore{}, path)
+		_, restored := index.loadPersistedIndexes()
+		require.False(t, restored)
+		require.NoDirExists(t, filepath.Join(path, orgIdx.dir))
+	})
+
+	t.Run("rebuilding an org index removes the previous one", func(t *testing.T) {
+		path := t.TempDir()
+		index := newPersistentTestIndex(&countingDashboardLoader{dashboards: testDashboards}, &fakeEventStore{}, path)
+		_, err := index.buildOrgIndex(context.Background(), testOrgID)
+		require.NoError(t, err)
+		first, _ := index.getOrgIndex(testOrgID)
+
+		_, err = index.buildOrgIndex(context.Background(), testOrgID)
+		require.NoError(t, err)
+		second, _ := index.getOrgIndex(testOrgID)
+		require.NotEqual(t, first.dir, second.dir)
+		require.NoDirExists(t, filepath.Join(path, first.dir))
+		require.DirExists(t, filepath.Join(path, second.dir))
+
+		index.persistIndexes(0)
+		_, err = index.buildOrgIndex(context.Background(), testOrgID)
+		require.Error(t, err, "indexes can't be built once persisted")
+	})
+
+	t.Run("index is built from scratch when it was persisted before the retention of entity events", func(t *testing.T) {
+		path := t.TempDir()
+		loader := &countingDashboardLoader{dashboards: []dashboard{
+			{id: 1, uid: "1", summary: &entity.EntitySummary{Name: "test"}},
+		}}
+		index := newPersistentTestIndex(loader, &fakeEventStore{}, path)
+		_, err := index.buildOrgIndex(context.Background(), testOrgID)
+		require.NoError(t, err)
+		orgIdx, _ := index.getOrgIndex(testOrgID)
+		index.persistIndexes(0)
+
+		// The events since the index was persisted have been deleted.
+		state, err := readIndexState(path)
+		require.NoError(t, err)
+		state.Persisted = state.Persisted.Add(-store.EntityEventsRetention)
+		require.NoError(t, writeIndexState(path, *state))
+
+		index = newPersistentTestIndex(loader, &fakeEventStore{}, path)
+		_, restored := index.loadPersistedIndexes()
+		require.False(t, restored)
+		require.NoDirExists(t, filepath.Join(path, orgIdx.dir))
+	})
+
+	t.Run("corrupted state is ignored", func(t *testing.T) {
+		path := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(path, indexStateFileName), []byte("{"), 0600))
+		index := newPersistentTestIndex(&countingDashboardLoader{}, &fakeEventStore{}, path)
+		_, restored := index.loadPersistedIndexes()
+		require.False(t, restored)
+
+		_, err := os.Stat(filepath.Join(path, indexStateFileName))
+		require.True(t, errors.Is(err, os.ErrNotExist))
+	})
+}
@@ -96,4 +96,43 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add expires_at column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "expires_at",
+		Type:     DB_DateTime,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add max_views column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "max_views",
+		Type:     DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}))
+
+	mg.AddMigration("add view_count column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "view_count",
+		Type:     DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}))
+
+	var dashboardPublicAccessLogV1 = Table{
+		Name: "dashboard_public_access_log",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "panel_id", Type: DB_BigInt, Nullable: false},
+			{Name: "ip_hash", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"public_dashboard_uid", "created"}},
+			{Cols: []string{"created"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public access log table v1", NewAddTableMigration(dashboardPublicAccessLogV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicAccessLogV1)
 }
@@ -0,0 +1,48 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDashboardTrashMigrations(mg *Migrator) {
+	dashboardTrashV1 := Table{
+		Name: "dashboard_trash",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 189, Nullable: false},
+			{Name: "is_folder", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "folder_uid", Type: DB_NVarchar, Length: 40, Nullable: true},
+			{Name: "deleted_by", Type: DB_BigInt, Nullable: false},
+			{Name: "deleted", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "deleted"}},
+			{Cols: []string{"deleted"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_trash table v1", NewAddTableMigration(dashboardTrashV1))
+	mg.AddMigration("add unique index dashboard_trash.org_id_uid", NewAddIndexMigration(dashboardTrashV1, dashboardTrashV1.Indices[0]))
+	mg.AddMigration("add index dashboard_trash.org_id_deleted", NewAddIndexMigration(dashboardTrashV1, dashboardTrashV1.Indices[1]))
+	mg.AddMigration("add index dashboard_trash.deleted", NewAddIndexMigration(dashboardTrashV1, dashboardTrashV1.Indices[2]))
+
+	// the rows deleted with a dashboard or folder are kept one per row, as a folder
+	// with all its dashboards and versions can be larger than a single value allows
+	dashboardTrashItemV1 := Table{
+		Name: "dashboard_trash_item",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "trash_id", Type: DB_BigInt, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "data", Type: DB_MediumText, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"trash_id"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_trash_item table v1", NewAddTableMigration(dashboardTrashItemV1))
+	mg.AddMigration("add index dashboard_trash_item.trash_id", NewAddIndexMigration(dashboardTrashItemV1, dashboardTrashItemV1.Indices[0]))
+}
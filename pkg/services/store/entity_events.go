@@ -42,6 +42,9 @@ func CreateDatabaseEntityId(internalId any, orgId int64, entityType EntityType)
 	return fmt.Sprintf("database/%d/%s/%s", orgId, entityType, internalIdAsString)
 }
 
+// EntityEventsRetention is how long entity events are kept before they are deleted.
+const EntityEventsRetention = 24 * time.Hour
+
 type EntityEvent struct {
 	Id        int64
 	EventType EntityEventType
@@ -132,7 +135,7 @@ func (e *entityEventService) Run(ctx context.Context) error {
 		select {
 		case <-clean.C:
 			go func() {
-				err := e.deleteEventsOlderThan(context.Background(), 24*time.Hour)
+				err := e.deleteEventsOlderThan(context.Background(), EntityEventsRetention)
 				if err != nil {
 					e.log.Info("Failed to delete old entity events", "error", err)
 				}
@@ -9,15 +9,17 @@ import (
 )
 
 type SignedInUser struct {
-	UserID           int64 `xorm:"user_id"`
-	OrgID            int64 `xorm:"org_id"`
-	OrgName          string
-	OrgRole          roletype.RoleType
-	Login            string
-	Name             string
-	Email            string
-	AuthenticatedBy  string
-	ApiKeyID         int64 `xorm:"api_key_id"`
+	UserID          int64 `xorm:"user_id"`
+	OrgID           int64 `xorm:"org_id"`
+	OrgName         string
+	OrgRole         roletype.RoleType
+	Login           string
+	Name            string
+	Email           string
+	AuthenticatedBy string
+	ApiKeyID        int64 `xorm:"api_key_id"`
+	// TokenID is the ID of the service account token the user authenticated with, if any.
+	TokenID          int64 `xorm:"-"`
 	IsServiceAccount bool  `xorm:"is_service_account"`
 	IsGrafanaAdmin   bool
 	IsAnonymous      bool
@@ -268,6 +268,8 @@ type Cfg struct {
 
 	// Dashboards
 	DefaultHomeDashboardPath string
+	// DashboardTrashRetention is how long deleted dashboards and folders are kept in the trash. 0 deletes them permanently.
+	DashboardTrashRetention time.Duration
 
 	// Auth
 	LoginCookieName              string
@@ -385,6 +387,7 @@ type Cfg struct {
 	AlertingAnnotationCleanupSetting   AnnotationCleanupSettings
 	DashboardAnnotationCleanupSettings AnnotationCleanupSettings
 	APIAnnotationCleanupSettings       AnnotationCleanupSettings
+	AnnotationStore                    AnnotationStoreSettings
 
 	// GrafanaJavascriptAgent config
 	GrafanaJavascriptAgent GrafanaJavascriptAgent
@@ -396,6 +399,8 @@ type Cfg struct {
 	SqlDatasourceMaxOpenConnsDefault    int
 	SqlDatasourceMaxIdleConnsDefault    int
 	SqlDatasourceMaxConnLifetimeDefault int
+	// SQLiteDatasourceAllowedDirectories are the directories of the database files the SQLite data source can open.
+	SQLiteDatasourceAllowedDirectories []string
 
 	// Snapshots
 	SnapshotEnabled       bool
@@ -524,6 +529,9 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	QueryCaching QueryCachingSettings
+	QueryLimits  QueryLimitsSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -751,6 +759,17 @@ func (cfg *Cfg) readAnnotationSettings() error {
 	cfg.DashboardAnnotationCleanupSettings = newAnnotationCleanupSettings(dashboardAnnotation, "max_age")
 	cfg.APIAnnotationCleanupSettings = newAnnotationCleanupSettings(apiIAnnotation, "max_age")
 
+	cfg.AnnotationStore = AnnotationStoreSettings{
+		Backend:               section.Key("store").MustString("sql"),
+		LokiSources:           util.SplitString(section.Key("loki_sources").MustString("api")),
+		LokiRemoteURL:         section.Key("loki_remote_url").MustString(""),
+		LokiReadURL:           section.Key("loki_remote_read_url").MustString(""),
+		LokiWriteURL:          section.Key("loki_remote_write_url").MustString(""),
+		LokiTenantID:          section.Key("loki_tenant_id").MustString(""),
+		LokiBasicAuthUsername: section.Key("loki_basic_auth_username").MustString(""),
+		LokiBasicAuthPassword: section.Key("loki_basic_auth_password").MustString(""),
+	}
+
 	return nil
 }
 
@@ -764,6 +783,23 @@ type AnnotationCleanupSettings struct {
 	MaxCount int64
 }
 
+// AnnotationStoreSettings configures where annotations are stored.
+type AnnotationStoreSettings struct {
+	// Backend is either "sql", "loki" or "composite".
+	Backend string
+	// LokiSources are the sources of the annotations that are written to Loki
+	// when the backend is "composite", any of "alert", "dashboard" and "api".
+	LokiSources   []string
+	LokiRemoteURL string
+	LokiReadURL   string
+	LokiWriteURL  string
+	LokiTenantID  string
+	// LokiBasicAuthUsername and LokiBasicAuthPassword are used for basic auth
+	// if one of them is set.
+	LokiBasicAuthUsername string
+	LokiBasicAuthPassword string
+}
+
 func EnvKey(sectionName string, keyName string) string {
 	sN := strings.ToUpper(strings.ReplaceAll(sectionName, ".", "_"))
 	sN = strings.ReplaceAll(sN, "-", "_")
@@ -1077,6 +1113,11 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	trashRetention, err := gtime.ParseDuration(valueAsString(dashboards, "trash_retention", "30d"))
+	if err != nil {
+		return err
+	}
+	cfg.DashboardTrashRetention = trashRetention
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
@@ -1189,7 +1230,9 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	cfg.readSqlDataSourceSettings()
 
 	cfg.Storage = readStorageSettings(iniFile)
-	cfg.Search = readSearchSettings(iniFile)
+	cfg.Search = readSearchSettings(iniFile, cfg.DataPath)
+	cfg.QueryCaching = readQueryCachingSettings(iniFile)
+	cfg.QueryLimits = readQueryLimitsSettings(iniFile)
 
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
 	if err != nil {
@@ -1965,6 +2008,10 @@ func (cfg *Cfg) readSqlDataSourceSettings() {
 	cfg.SqlDatasourceMaxOpenConnsDefault = sqlDatasources.Key("max_open_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxIdleConnsDefault = sqlDatasources.Key("max_idle_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxConnLifetimeDefault = sqlDatasources.Key("max_conn_lifetime_default").MustInt(14400)
+	cfg.SQLiteDatasourceAllowedDirectories = nil
+	for _, dir := range util.SplitString(sqlDatasources.Key("sqlite_allowed_directories").String()) {
+		cfg.SQLiteDatasourceAllowedDirectories = append(cfg.SQLiteDatasourceAllowedDirectories, makeAbsolute(dir, cfg.HomePath))
+	}
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
@@ -0,0 +1,40 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// QueryCachingSettings configures the caching of data source query and resource results in the remote cache.
+type QueryCachingSettings struct {
+	Enabled bool
+	// TTL is how long query results are cached, unless the data source sets its own.
+	TTL time.Duration
+	// ResourceTTL is how long resource results are cached, unless the data source sets its own.
+	ResourceTTL time.Duration
+	// MaxTTL caps the TTLs set by data sources. Zero disables the cap.
+	MaxTTL time.Duration
+	// MaxValueSize is the size in bytes of the largest result that is cached. Zero disables the limit.
+	MaxValueSize int
+	// IncrementalQueries makes the query service reuse cached time series for the overlapping part of
+	// the time range of a query, so that only the rest of the time range is queried from the data source.
+	IncrementalQueries bool
+	// IncrementalOverlap is how much of the end of the cached time series is queried again, as the most
+	// recent data points may not be complete yet.
+	IncrementalOverlap time.Duration
+}
+
+func readQueryCachingSettings(iniFile *ini.File) QueryCachingSettings {
+	section := iniFile.Section("caching")
+	return QueryCachingSettings{
+		Enabled:      section.Key("enabled").MustBool(false),
+		TTL:          section.Key("ttl").MustDuration(time.Minute),
+		ResourceTTL:  section.Key("resource_ttl").MustDuration(5 * time.Minute),
+		MaxTTL:       section.Key("max_ttl").MustDuration(0),
+		MaxValueSize: section.Key("max_value_mb").MustInt(1) * 1024 * 1024,
+
+		IncrementalQueries: section.Key("incremental_queries").MustBool(false),
+		IncrementalOverlap: section.Key("incremental_overlap").MustDuration(10 * time.Minute),
+	}
+}
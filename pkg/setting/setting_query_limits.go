@@ -0,0 +1,33 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// QueryLimitsSettings configures the limits of the plugin client that protect data sources from too many queries.
+type QueryLimitsSettings struct {
+	// MaxConcurrentPerDataSource is the number of queries that can run at once against a data source. Zero disables the limit.
+	MaxConcurrentPerDataSource int
+	// MaxConcurrentPerOrg is the number of queries that can run at once against the data sources of an org. Zero disables the limit.
+	MaxConcurrentPerOrg int
+	// QueueTimeout is how long a query waits for a slot before it's rejected.
+	QueueTimeout time.Duration
+	// CircuitBreakerTimeouts is the number of consecutive timeouts of a data source that make further
+	// queries fail fast. Zero disables the circuit breaker.
+	CircuitBreakerTimeouts int
+	// CircuitBreakerOpenDuration is how long queries fail fast before a query is tried again.
+	CircuitBreakerOpenDuration time.Duration
+}
+
+func readQueryLimitsSettings(iniFile *ini.File) QueryLimitsSettings {
+	section := iniFile.Section("query")
+	return QueryLimitsSettings{
+		MaxConcurrentPerDataSource: section.Key("max_concurrent_queries_per_datasource").MustInt(0),
+		MaxConcurrentPerOrg:        section.Key("max_concurrent_queries_per_org").MustInt(0),
+		QueueTimeout:               section.Key("queue_timeout").MustDuration(10 * time.Second),
+		CircuitBreakerTimeouts:     section.Key("circuit_breaker_timeouts").MustInt(0),
+		CircuitBreakerOpenDuration: section.Key("circuit_breaker_open_duration").MustDuration(30 * time.Second),
+	}
+}
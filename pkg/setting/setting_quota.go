@@ -24,11 +24,21 @@ type GlobalQuota struct {
 	Correlations int64 `target:"correlations"`
 }
 
+// RateQuota limits how many times something can happen per minute. A limit of -1 is unlimited.
+type RateQuota struct {
+	UserQueries             int64
+	OrgQueries              int64
+	OrgAlertRuleEvaluations int64
+	// TokenAPIRequests limits the API requests of each service account token.
+	TokenAPIRequests int64
+}
+
 type QuotaSettings struct {
 	Enabled bool
 	Org     OrgQuota
 	User    UserQuota
 	Global  GlobalQuota
+	Rate    RateQuota
 }
 
 func (cfg *Cfg) readQuotaSettings() {
@@ -68,4 +78,12 @@ func (cfg *Cfg) readQuotaSettings() {
 		AlertRule:    alertGlobalQuota,
 		Correlations: quota.Key("global_correlations").MustInt64(-1),
 	}
+
+	// Rate limits
+	cfg.Quota.Rate = RateQuota{
+		UserQueries:             quota.Key("user_queries_per_minute").MustInt64(-1),
+		OrgQueries:              quota.Key("org_queries_per_minute").MustInt64(-1),
+		OrgAlertRuleEvaluations: quota.Key("org_alert_rule_evaluations_per_minute").MustInt64(-1),
+		TokenAPIRequests:        quota.Key("token_api_requests_per_minute").MustInt64(-1),
+	}
 }
@@ -10,14 +10,20 @@ type SearchSettings struct {
 	FullReindexInterval       time.Duration
 	IndexUpdateInterval       time.Duration
 	DashboardLoadingBatchSize int
+	// PersistentIndex keeps the search index on local disk, so it can be reused after a restart.
+	PersistentIndex bool
+	// IndexPath is the directory where the search index is kept when PersistentIndex is enabled.
+	IndexPath string
 }
 
-func readSearchSettings(iniFile *ini.File) SearchSettings {
+func readSearchSettings(iniFile *ini.File, dataPath string) SearchSettings {
 	s := SearchSettings{}
 
 	searchSection := iniFile.Section("search")
 	s.DashboardLoadingBatchSize = searchSection.Key("dashboard_loading_batch_size").MustInt(200)
 	s.FullReindexInterval = searchSection.Key("full_reindex_interval").MustDuration(5 * time.Minute)
 	s.IndexUpdateInterval = searchSection.Key("index_update_interval").MustDuration(10 * time.Second)
+	s.PersistentIndex = searchSection.Key("persistent_index").MustBool(false)
+	s.IndexPath = makeAbsolute(searchSection.Key("index_path").MustString("search"), dataPath)
 	return s
 }
@@ -61,6 +61,7 @@ const (
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
 	DefaultRuleEvaluationInterval = SchedulerBaseInterval * 6 // == 60 seconds
 	stateHistoryDefaultEnabled    = true
+	recordingRulesDefaultTimeout  = 10 * time.Second
 )
 
 type UnifiedAlertingSettings struct {
@@ -96,8 +97,11 @@ type UnifiedAlertingSettings struct {
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	StateHistory                  UnifiedAlertingStateHistorySettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
+	RecordingRules                RecordingRuleSettings
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency int
+	// JitterStrategy controls how evaluations of rules with the same interval are spread across the interval.
+	JitterStrategy string
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -109,6 +113,16 @@ type RemoteAlertmanagerSettings struct {
 	Password string
 }
 
+// RecordingRuleSettings contains the configuration of the target
+// that results of recording rules are written to.
+type RecordingRuleSettings struct {
+	Enabled           bool
+	URL               string
+	BasicAuthUsername string
+	BasicAuthPassword string
+	Timeout           time.Duration
+}
+
 type UnifiedAlertingScreenshotSettings struct {
 	Capture                    bool
 	CaptureTimeout             time.Duration
@@ -131,9 +145,14 @@ type UnifiedAlertingStateHistorySettings struct {
 	// if one of them is set.
 	LokiBasicAuthPassword string
 	LokiBasicAuthUsername string
-	MultiPrimary          string
-	MultiSecondaries      []string
-	ExternalLabels        map[string]string
+	// PrometheusRemoteWriteURL is the remote write endpoint that the prometheus backend writes to.
+	PrometheusRemoteWriteURL    string
+	PrometheusBasicAuthUsername string
+	PrometheusBasicAuthPassword string
+	PrometheusMetricName        string
+	MultiPrimary                string
+	MultiSecondaries            []string
+	ExternalLabels              map[string]string
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -356,6 +375,19 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.RemoteAlertmanager = uaCfgRemoteAM
 
+	recordingRules := iniFile.Section("recording_rules")
+	uaCfgRecordingRules := RecordingRuleSettings{
+		Enabled:           recordingRules.Key("enabled").MustBool(false),
+		URL:               recordingRules.Key("url").MustString(""),
+		BasicAuthUsername: recordingRules.Key("basic_auth_username").MustString(""),
+		BasicAuthPassword: recordingRules.Key("basic_auth_password").MustString(""),
+		Timeout:           recordingRules.Key("timeout").MustDuration(recordingRulesDefaultTimeout),
+	}
+	if uaCfgRecordingRules.Enabled && uaCfgRecordingRules.URL == "" {
+		return errors.New("setting 'url' in section 'recording_rules' is required when recording rules are enabled")
+	}
+	uaCfg.RecordingRules = uaCfgRecordingRules
+
 	screenshots := iniFile.Section("unified_alerting.screenshots")
 	uaCfgScreenshots := uaCfg.Screenshots
 
@@ -383,22 +415,28 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	stateHistory := iniFile.Section("unified_alerting.state_history")
 	stateHistoryLabels := iniFile.Section("unified_alerting.state_history.external_labels")
 	uaCfgStateHistory := UnifiedAlertingStateHistorySettings{
-		Enabled:               stateHistory.Key("enabled").MustBool(stateHistoryDefaultEnabled),
-		Backend:               stateHistory.Key("backend").MustString("annotations"),
-		LokiRemoteURL:         stateHistory.Key("loki_remote_url").MustString(""),
-		LokiReadURL:           stateHistory.Key("loki_remote_read_url").MustString(""),
-		LokiWriteURL:          stateHistory.Key("loki_remote_write_url").MustString(""),
-		LokiTenantID:          stateHistory.Key("loki_tenant_id").MustString(""),
-		LokiBasicAuthUsername: stateHistory.Key("loki_basic_auth_username").MustString(""),
-		LokiBasicAuthPassword: stateHistory.Key("loki_basic_auth_password").MustString(""),
-		MultiPrimary:          stateHistory.Key("primary").MustString(""),
-		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
-		ExternalLabels:        stateHistoryLabels.KeysHash(),
+		Enabled:                     stateHistory.Key("enabled").MustBool(stateHistoryDefaultEnabled),
+		Backend:                     stateHistory.Key("backend").MustString("annotations"),
+		LokiRemoteURL:               stateHistory.Key("loki_remote_url").MustString(""),
+		LokiReadURL:                 stateHistory.Key("loki_remote_read_url").MustString(""),
+		LokiWriteURL:                stateHistory.Key("loki_remote_write_url").MustString(""),
+		LokiTenantID:                stateHistory.Key("loki_tenant_id").MustString(""),
+		LokiBasicAuthUsername:       stateHistory.Key("loki_basic_auth_username").MustString(""),
+		LokiBasicAuthPassword:       stateHistory.Key("loki_basic_auth_password").MustString(""),
+		PrometheusRemoteWriteURL:    stateHistory.Key("prometheus_remote_write_url").MustString(""),
+		PrometheusBasicAuthUsername: stateHistory.Key("prometheus_basic_auth_username").MustString(""),
+		PrometheusBasicAuthPassword: stateHistory.Key("prometheus_basic_auth_password").MustString(""),
+		PrometheusMetricName:        stateHistory.Key("prometheus_metric_name").MustString("GRAFANA_ALERTS"),
+		MultiPrimary:                stateHistory.Key("primary").MustString(""),
+		MultiSecondaries:            splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
+		ExternalLabels:              stateHistoryLabels.KeysHash(),
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
+	uaCfg.JitterStrategy = ua.Key("jitter_strategy").MustString("none")
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
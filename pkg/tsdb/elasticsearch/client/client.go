@@ -20,6 +20,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/tsdb/healthcheck"
 )
 
 // Used in logging to mark a stage
@@ -53,6 +54,10 @@ type Client interface {
 	GetConfiguredFields() ConfiguredFields
 	ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearchResponse, error)
 	MultiSearch() *MultiSearchRequestBuilder
+	OpenPointInTime(keepAlive string) (string, error)
+	ClosePointInTime(id string) error
+	GetClusterInfo() (*ClusterInfo, error)
+	GetFieldMapping(field string) (FieldMapping, error)
 }
 
 // NewClient creates a new elasticsearch client
@@ -149,10 +154,10 @@ func (c *baseClientImpl) executeRequest(method, uriPath, uriQuery string, body [
 	u.RawQuery = uriQuery
 
 	var req *http.Request
-	if method == http.MethodPost {
-		req, err = http.NewRequestWithContext(c.ctx, http.MethodPost, u.String(), bytes.NewBuffer(body))
-	} else {
+	if method == http.MethodGet {
 		req, err = http.NewRequestWithContext(c.ctx, http.MethodGet, u.String(), nil)
+	} else {
+		req, err = http.NewRequestWithContext(c.ctx, method, u.String(), bytes.NewBuffer(body))
 	}
 	if err != nil {
 		return nil, err
@@ -245,6 +250,14 @@ func (c *baseClientImpl) createMultiSearchRequests(searchRequests []*SearchReque
 			interval: searchReq.Interval,
 		}
 
+		// The indices of searches in a point in time are the indices of the point in time, and
+		// can't be set in the header.
+		if searchReq.PointInTime != nil {
+			mr.header = map[string]any{
+				"search_type": "query_then_fetch",
+			}
+		}
+
 		multiRequests = append(multiRequests, &mr)
 	}
 
@@ -270,3 +283,117 @@ func (c *baseClientImpl) getMultiSearchQueryParameters() string {
 func (c *baseClientImpl) MultiSearch() *MultiSearchRequestBuilder {
 	return NewMultiSearchRequestBuilder()
 }
+
+type pointInTimeResponse struct {
+	ID    string         `json:"id"`
+	Error map[string]any `json:"error"`
+}
+
+// OpenPointInTime opens a point in time of the indices of the client, kept alive for the
+// keepAlive duration, and returns its ID. Searches in the point in time see the documents
+// of the indices at the time it was opened.
+func (c *baseClientImpl) OpenPointInTime(keepAlive string) (string, error) {
+	queryParams := url.Values{}
+	queryParams.Set("keep_alive", keepAlive)
+	queryParams.Set("ignore_unavailable", "true")
+
+	start := time.Now()
+	res, err := c.executeRequest(http.MethodPost, path.Join(strings.Join(c.indices, ","), "_pit"), queryParams.Encode(), nil)
+	if err != nil {
+		c.logger.Error("Error received from Elasticsearch", "error", err, "status", "error", "duration", time.Since(start), "stage", StageDatabaseRequest)
+		return "", err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+	c.logger.Debug("Opened point in time", "statusCode", res.StatusCode, "duration", time.Since(start), "stage", StageDatabaseRequest)
+
+	var pitRes pointInTimeResponse
+	if err := json.NewDecoder(res.Body).Decode(&pitRes); err != nil {
+		return "", fmt.Errorf("failed to decode the point in time response: %w", err)
+	}
+	if res.StatusCode >= http.StatusBadRequest || pitRes.ID == "" {
+		return "", fmt.Errorf("failed to open point in time: %s", errorReason(res.StatusCode, pitRes.Error))
+	}
+	return pitRes.ID, nil
+}
+
+// ClosePointInTime closes the point in time with the ID before it expires.
+func (c *baseClientImpl) ClosePointInTime(id string) error {
+	body, err := json.Marshal(map[string]string{"id": id})
+	if err != nil {
+		return err
+	}
+
+	res, err := c.executeRequest(http.MethodDelete, "_pit", "", body)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode >= http.StatusBadRequest && res.StatusCode != http.StatusNotFound {
+		var pitRes pointInTimeResponse
+		_ = json.NewDecoder(res.Body).Decode(&pitRes)
+		return fmt.Errorf("failed to close point in time: %s", errorReason(res.StatusCode, pitRes.Error))
+	}
+	return nil
+}
+
+// GetClusterInfo returns the information of the cluster, like its distribution and version.
+func (c *baseClientImpl) GetClusterInfo() (*ClusterInfo, error) {
+	var info ClusterInfo
+	if err := c.get("", "", &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetFieldMapping returns the mapping of the field in the indices of the client. Indices that
+// don't exist are left out.
+func (c *baseClientImpl) GetFieldMapping(field string) (FieldMapping, error) {
+	queryParams := url.Values{}
+	queryParams.Set("ignore_unavailable", "true")
+	queryParams.Set("allow_no_indices", "true")
+
+	var mapping FieldMapping
+	if err := c.get(path.Join(strings.Join(c.indices, ","), "_mapping/field", field), queryParams.Encode(), &mapping); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// get sends a GET request and decodes the JSON response into v.
+func (c *baseClientImpl) get(uriPath, uriQuery string, v any) error {
+	res, err := c.executeRequest(http.MethodGet, uriPath, uriQuery, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if err := healthcheck.CheckResponse(res); err != nil {
+		return err
+	}
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+// errorReason returns the reason of an Elasticsearch error, or the status code of the response
+// if it has no reason.
+func errorReason(statusCode int, esError map[string]any) string {
+	if reason, ok := esError["reason"].(string); ok && reason != "" {
+		return reason
+	}
+	return fmt.Sprintf("unexpected status code %d", statusCode)
+}
@@ -217,6 +217,84 @@ func TestClient_Index(t *testing.T) {
 	}
 }
 
+func TestClient_PointInTime(t *testing.T) {
+	type receivedRequest struct {
+		method string
+		path   string
+		query  string
+		body   string
+	}
+	var requests []receivedRequest
+
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		buf, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, receivedRequest{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, body: string(buf)})
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/logs-2018.05.15/_pit":
+			_, err = rw.Write([]byte(`{"id": "pit-1"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/_msearch":
+			_, err = rw.Write([]byte(`{"responses": [{"hits": {"hits": []}, "pit_id": "pit-2", "status": 200}]}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/_pit":
+			_, err = rw.Write([]byte(`{"succeeded": true, "num_freed": 1}`))
+		default:
+			rw.WriteHeader(http.StatusBadRequest)
+			_, err = rw.Write([]byte(`{"error": {"reason": "unexpected request"}, "status": 400}`))
+		}
+		require.NoError(t, err)
+	}))
+	t.Cleanup(ts.Close)
+
+	ds := DatasourceInfo{
+		URL:              ts.URL,
+		HTTPClient:       ts.Client(),
+		Database:         "[logs-]YYYY.MM.DD",
+		ConfiguredFields: ConfiguredFields{TimeField: "@timestamp"},
+		Interval:         "Daily",
+	}
+	timeRange := backend.TimeRange{
+		From: time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC),
+		To:   time.Date(2018, 5, 15, 17, 55, 0, 0, time.UTC),
+	}
+	c, err := NewClient(context.Background(), &ds, timeRange, log.New("test", "test"), tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+
+	id, err := c.OpenPointInTime("5m")
+	require.NoError(t, err)
+	require.Equal(t, "pit-1", id)
+	assert.Equal(t, "ignore_unavailable=true&keep_alive=5m", requests[0].query)
+
+	msb := c.MultiSearch()
+	msb.Search(15*time.Second).PointInTime(id, "5m").Size(10)
+	ms, err := msb.Build()
+	require.NoError(t, err)
+	res, err := c.ExecuteMultisearch(ms)
+	require.NoError(t, err)
+	require.Equal(t, "pit-2", res.Responses[0].PitID)
+
+	requestBody := bytes.NewBufferString(requests[1].body)
+	headerBytes, err := requestBody.ReadBytes('\n')
+	require.NoError(t, err)
+	jHeader, err := simplejson.NewJson(headerBytes)
+	require.NoError(t, err)
+	jBody, err := simplejson.NewJson(requestBody.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"search_type": "query_then_fetch"}, jHeader.MustMap())
+	assert.Equal(t, "pit-1", jBody.GetPath("pit", "id").MustString())
+	assert.Equal(t, "5m", jBody.GetPath("pit", "keep_alive").MustString())
+
+	require.NoError(t, c.ClosePointInTime("pit-2"))
+	assert.JSONEq(t, `{"id": "pit-2"}`, requests[2].body)
+
+	ds.Database = "metrics"
+	ds.Interval = ""
+	c, err = NewClient(context.Background(), &ds, timeRange, log.New("test", "test"), tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	_, err = c.OpenPointInTime("5m")
+	require.EqualError(t, err, "failed to open point in time: unexpected request")
+}
+
 func createMultisearchForTest(t *testing.T, c Client) (*MultiSearchRequest, error) {
 	t.Helper()
 
@@ -13,9 +13,33 @@ type SearchRequest struct {
 	Sort        map[string]interface{}
 	Query       *Query
 	Aggs        AggArray
+	PointInTime *PointInTime
 	CustomProps map[string]interface{}
 }
 
+// PointInTime represents the point in time of a search request
+type PointInTime struct {
+	ID        string `json:"id"`
+	KeepAlive string `json:"keep_alive,omitempty"`
+}
+
+// ClusterInfo represents the information of a cluster
+type ClusterInfo struct {
+	Version struct {
+		Number       string `json:"number"`
+		Distribution string `json:"distribution"`
+	} `json:"version"`
+}
+
+// FieldMapping represents the mapping of fields, by index and field
+type FieldMapping map[string]struct {
+	Mappings map[string]struct {
+		Mapping map[string]struct {
+			Type string `json:"type"`
+		} `json:"mapping"`
+	} `json:"mappings"`
+}
+
 // MarshalJSON returns the JSON encoding of the request.
 func (r *SearchRequest) MarshalJSON() ([]byte, error) {
 	root := make(map[string]interface{})
@@ -29,6 +53,10 @@ func (r *SearchRequest) MarshalJSON() ([]byte, error) {
 		root[key] = value
 	}
 
+	if r.PointInTime != nil {
+		root["pit"] = r.PointInTime
+	}
+
 	root["query"] = r.Query
 
 	if len(r.Aggs) > 0 {
@@ -48,6 +76,7 @@ type SearchResponse struct {
 	Error        map[string]interface{} `json:"error"`
 	Aggregations map[string]interface{} `json:"aggregations"`
 	Hits         *SearchResponseHits    `json:"hits"`
+	PitID        string                 `json:"pit_id"`
 }
 
 // MultiSearchRequest represents a multi search request
@@ -242,6 +271,32 @@ type TermsAggregation struct {
 	Missing     *string                `json:"missing,omitempty"`
 }
 
+// CompositeAggregation represents a composite aggregation
+type CompositeAggregation struct {
+	Size    int                           `json:"size"`
+	Sources []*CompositeAggregationSource `json:"sources"`
+	After   map[string]interface{}        `json:"after,omitempty"`
+}
+
+// CompositeAggregationSource represents a terms source of a composite aggregation
+type CompositeAggregationSource struct {
+	Name  string
+	Field string
+}
+
+// MarshalJSON returns the JSON encoding of the composite aggregation source
+func (s *CompositeAggregationSource) MarshalJSON() ([]byte, error) {
+	root := map[string]interface{}{
+		s.Name: map[string]interface{}{
+			"terms": map[string]interface{}{
+				"field": s.Field,
+			},
+		},
+	}
+
+	return json.Marshal(root)
+}
+
 // NestedAggregation represents a nested aggregation
 type NestedAggregation struct {
 	Path string `json:"path"`
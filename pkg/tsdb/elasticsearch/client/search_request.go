@@ -21,6 +21,7 @@ type SearchRequestBuilder struct {
 	sort         map[string]any
 	queryBuilder *QueryBuilder
 	aggBuilders  []AggBuilder
+	pointInTime  *PointInTime
 	customProps  map[string]any
 }
 
@@ -42,6 +43,7 @@ func (b *SearchRequestBuilder) Build() (*SearchRequest, error) {
 		Interval:    b.interval,
 		Size:        b.size,
 		Sort:        b.sort,
+		PointInTime: b.pointInTime,
 		CustomProps: b.customProps,
 	}
 
@@ -138,6 +140,12 @@ func (b *SearchRequestBuilder) AddSearchAfter(value any) *SearchRequestBuilder {
 	return b
 }
 
+// PointInTime sets the point in time the search request searches in, and extends its keep alive
+func (b *SearchRequestBuilder) PointInTime(id string, keepAlive string) *SearchRequestBuilder {
+	b.pointInTime = &PointInTime{ID: id, KeepAlive: keepAlive}
+	return b
+}
+
 // Query creates and return a query builder
 func (b *SearchRequestBuilder) Query() *QueryBuilder {
 	if b.queryBuilder == nil {
@@ -298,6 +306,7 @@ type AggBuilder interface {
 	Histogram(key, field string, fn func(a *HistogramAgg, b AggBuilder)) AggBuilder
 	DateHistogram(key, field string, fn func(a *DateHistogramAgg, b AggBuilder)) AggBuilder
 	Terms(key, field string, fn func(a *TermsAggregation, b AggBuilder)) AggBuilder
+	Composite(key string, fn func(a *CompositeAggregation, b AggBuilder)) AggBuilder
 	Nested(key, path string, fn func(a *NestedAggregation, b AggBuilder)) AggBuilder
 	Filters(key string, fn func(a *FiltersAggregation, b AggBuilder)) AggBuilder
 	GeoHashGrid(key, field string, fn func(a *GeoHashGridAggregation, b AggBuilder)) AggBuilder
@@ -411,6 +420,26 @@ func (b *aggBuilderImpl) Terms(key, field string, fn func(a *TermsAggregation, b
 	return b
 }
 
+func (b *aggBuilderImpl) Composite(key string, fn func(a *CompositeAggregation, b AggBuilder)) AggBuilder {
+	innerAgg := &CompositeAggregation{
+		Sources: make([]*CompositeAggregationSource, 0),
+	}
+	aggDef := newAggDef(key, &aggContainer{
+		Type:        "composite",
+		Aggregation: innerAgg,
+	})
+
+	if fn != nil {
+		builder := newAggBuilder()
+		aggDef.builders = append(aggDef.builders, builder)
+		fn(innerAgg, builder)
+	}
+
+	b.aggDefs = append(b.aggDefs, aggDef)
+
+	return b
+}
+
 func (b *aggBuilderImpl) Nested(key, field string, fn func(a *NestedAggregation, b AggBuilder)) AggBuilder {
 	innerAgg := &NestedAggregation{
 		Path: field,
@@ -395,6 +395,37 @@ func TestSearchRequest(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("and adding composite agg with metric agg", func(t *testing.T) {
+		b := setup()
+		aggBuilder := b.Agg()
+		aggBuilder.Composite("1", func(a *CompositeAggregation, ib AggBuilder) {
+			a.Size = 100
+			a.Sources = append(a.Sources, &CompositeAggregationSource{Name: "@hostname", Field: "@hostname"}, &CompositeAggregationSource{Name: "@app", Field: "@app"})
+			a.After = map[string]any{"@hostname": "host-1", "@app": "app-1"}
+			ib.Metric("2", "avg", "@value", nil)
+		})
+
+		t.Run("When marshal to JSON should generate correct json", func(t *testing.T) {
+			sr, err := b.Build()
+			require.Nil(t, err)
+			body, err := json.Marshal(sr)
+			require.Nil(t, err)
+			json, err := simplejson.NewJson(body)
+			require.Nil(t, err)
+
+			compositeAgg := json.GetPath("aggs", "1", "composite")
+			require.Equal(t, 100, compositeAgg.Get("size").MustInt())
+			sources := compositeAgg.Get("sources").MustArray()
+			require.Len(t, sources, 2)
+			require.Equal(t, "@hostname", simplejson.NewFromAny(sources[0]).GetPath("@hostname", "terms", "field").MustString())
+			require.Equal(t, "@app", simplejson.NewFromAny(sources[1]).GetPath("@app", "terms", "field").MustString())
+			require.Equal(t, map[string]any{"@hostname": "host-1", "@app": "app-1"}, compositeAgg.Get("after").MustMap())
+
+			avgAgg := json.GetPath("aggs", "1", "aggs", "2")
+			require.Equal(t, "@value", avgAgg.GetPath("avg", "field").MustString())
+		})
+	})
 }
 
 func TestMultiSearchRequest(t *testing.T) {
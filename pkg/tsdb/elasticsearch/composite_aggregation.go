@@ -0,0 +1,165 @@
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+// defaultCompositeLimit is the default maximum number of buckets of a composite aggregation,
+// over all of its pages.
+const defaultCompositeLimit = 10000
+
+// compositeFields returns the fields of the terms sources of a composite aggregation: its field,
+// followed by the fields of its fields setting.
+func compositeFields(bucketAgg *BucketAgg) []string {
+	fields := []string{bucketAgg.Field}
+	seen := map[string]bool{bucketAgg.Field: true}
+	for _, value := range bucketAgg.Settings.Get("fields").MustArray() {
+		field, ok := value.(string)
+		if !ok || field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// compositePageSize returns the number of buckets in each page of a composite aggregation.
+func compositePageSize(bucketAgg *BucketAgg) int {
+	if size, err := bucketAgg.Settings.Get("size").Int(); err == nil && size > 0 {
+		return size
+	}
+	return stringToIntWithDefaultValue(bucketAgg.Settings.Get("size").MustString(), defaultSize)
+}
+
+// compositeLimit returns the maximum number of buckets of a composite aggregation.
+func compositeLimit(bucketAgg *BucketAgg) int {
+	if limit, err := bucketAgg.Settings.Get("limit").Int(); err == nil && limit > 0 {
+		return limit
+	}
+	return stringToIntWithDefaultValue(bucketAgg.Settings.Get("limit").MustString(), defaultCompositeLimit)
+}
+
+// compositePages holds the buckets of all pages of the composite aggregation of a query.
+type compositePages struct {
+	query     *Query
+	bucketAgg *BucketAgg
+	// agg is the aggregation in the response of the query, with the buckets of all pages
+	agg map[string]any
+	// response is the index of the response of the query in the multisearch response
+	response      int
+	lastPageCount int
+}
+
+func (p *compositePages) buckets() []any {
+	buckets, _ := p.agg["buckets"].([]any)
+	return buckets
+}
+
+// hasMoreBuckets returns true if the last page was full and Elasticsearch returned the key to get
+// the buckets after it.
+func (p *compositePages) hasMoreBuckets() bool {
+	if _, ok := p.agg["after_key"].(map[string]any); !ok {
+		return false
+	}
+	return p.lastPageCount >= compositePageSize(p.bucketAgg)
+}
+
+// fetchCompositePages requests the pages after the first page of the composite aggregations of
+// the queries, and appends their buckets to the responses of the queries, up to the limit of the
+// aggregations. It returns the queries whose aggregation had more buckets than its limit.
+func (e *elasticsearchDataQuery) fetchCompositePages(queries []*Query, responses []*es.SearchResponse, from, to int64) ([]*Query, error) {
+	var pages []*compositePages
+	for i, q := range queries {
+		if i >= len(responses) || len(q.BucketAggs) == 0 || q.BucketAggs[0].Type != compositeType || responses[i].Error != nil {
+			continue
+		}
+		agg, ok := responses[i].Aggregations[q.BucketAggs[0].ID].(map[string]any)
+		if !ok {
+			continue
+		}
+		p := &compositePages{query: q, bucketAgg: q.BucketAggs[0], agg: agg, response: i}
+		p.lastPageCount = len(p.buckets())
+		pages = append(pages, p)
+	}
+
+	for {
+		ms := e.client.MultiSearch()
+		var pending []*compositePages
+		for _, p := range pages {
+			if !p.hasMoreBuckets() || len(p.buckets()) >= compositeLimit(p.bucketAgg) {
+				continue
+			}
+			p.query.CompositeAfterKey = p.agg["after_key"].(map[string]any)
+			if err := e.processQuery(p.query, ms, from, to); err != nil {
+				return nil, err
+			}
+			pending = append(pending, p)
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		req, err := ms.Build()
+		if err != nil {
+			return nil, err
+		}
+		e.logger.Debug("Requesting next pages of composite aggregations", "queriesLength", len(pending))
+		res, err := e.client.ExecuteMultisearch(req)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, p := range pending {
+			if i >= len(res.Responses) {
+				return nil, fmt.Errorf("missing response of the next page of the composite aggregation of query %s", p.query.RefID)
+			}
+			pageRes := res.Responses[i]
+			pageAgg, ok := pageRes.Aggregations[p.bucketAgg.ID].(map[string]any)
+			if pageRes.Error != nil || !ok {
+				// The error of the page is the error of the query
+				responses[p.response] = pageRes
+				delete(p.agg, "after_key")
+				continue
+			}
+			pageBuckets, _ := pageAgg["buckets"].([]any)
+			p.agg["buckets"] = append(p.buckets(), pageBuckets...)
+			p.agg["after_key"] = pageAgg["after_key"]
+			p.lastPageCount = len(pageBuckets)
+		}
+	}
+
+	var truncated []*Query
+	for _, p := range pages {
+		limit := compositeLimit(p.bucketAgg)
+		buckets := p.buckets()
+		if len(buckets) > limit || (len(buckets) == limit && p.hasMoreBuckets()) {
+			p.agg["buckets"] = buckets[:limit]
+			truncated = append(truncated, p.query)
+		}
+	}
+	return truncated, nil
+}
+
+// addCompositeLimitNotices warns in the frames of the queries whose composite aggregation had
+// more buckets than its limit that buckets are missing.
+func addCompositeLimitNotices(result *backend.QueryDataResponse, truncated []*Query) {
+	for _, q := range truncated {
+		res, ok := result.Responses[q.RefID]
+		if !ok {
+			continue
+		}
+		notice := data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     fmt.Sprintf("The composite aggregation returned its first %d buckets. Increase its limit to return more buckets.", compositeLimit(q.BucketAggs[0])),
+		}
+		for _, frame := range res.Frames {
+			frame.AppendNotices(notice)
+		}
+	}
+}
@@ -74,7 +74,19 @@ func (e *elasticsearchDataQuery) execute() (*backend.QueryDataResponse, error) {
 		return &backend.QueryDataResponse{}, err
 	}
 
-	return parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.logger, e.tracer)
+	e.closeLastPages(queries, res.Responses)
+
+	truncated, err := e.fetchCompositePages(queries, res.Responses, from, to)
+	if err != nil {
+		return &backend.QueryDataResponse{}, err
+	}
+
+	result, err := parseResponse(e.ctx, res.Responses, queries, e.client.GetConfiguredFields(), e.logger, e.tracer)
+	if err != nil {
+		return result, err
+	}
+	addCompositeLimitNotices(result, truncated)
+	return result, nil
 }
 
 func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilder, from, to int64) error {
@@ -84,6 +96,16 @@ func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchReques
 		return err
 	}
 
+	if isPaginatedQuery(q) {
+		page, err := e.queryPage(q, from, to)
+		if err != nil {
+			return err
+		}
+		q.Page = page
+		// All pages of a query search the time range of its first page
+		from, to = page.From, page.To
+	}
+
 	defaultTimeField := e.client.GetConfiguredFields().TimeField
 	b := ms.Search(q.Interval)
 	b.Size(0)
@@ -103,6 +125,44 @@ func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchReques
 	return nil
 }
 
+// queryPage returns the page of a paginated query: the page of its continuation token, or the first
+// page in a new point in time.
+func (e *elasticsearchDataQuery) queryPage(q *Query, from, to int64) (*queryPage, error) {
+	settings := q.Metrics[0].Settings
+	if token := settings.Get(continuationTokenSetting).MustString(); token != "" {
+		return decodeContinuationToken(token)
+	}
+
+	keepAlive := settings.Get(pointInTimeKeepAliveSetting).MustString()
+	pitID, err := e.client.OpenPointInTime(keepAlive)
+	if err != nil {
+		return nil, err
+	}
+	return &queryPage{PitID: pitID, KeepAlive: keepAlive, From: from, To: to}, nil
+}
+
+// closeLastPages closes the points in time of paginated queries that have no page after their
+// response, instead of keeping them open until they expire.
+func (e *elasticsearchDataQuery) closeLastPages(queries []*Query, responses []*es.SearchResponse) {
+	for i, q := range queries {
+		if q.Page == nil || i >= len(responses) || nextPage(q, responses[i]) != nil {
+			continue
+		}
+		if err := e.client.ClosePointInTime(pointInTimeID(q, responses[i])); err != nil {
+			e.logger.Warn("Failed to close point in time", "error", err, "refId", q.RefID)
+		}
+	}
+}
+
+// addPage sets the point in time of the search of a paginated query, and the sort values of the
+// last document of the previous page, to search the documents after it.
+func addPage(q *Query, b *es.SearchRequestBuilder) {
+	b.PointInTime(q.Page.PitID, q.Page.KeepAlive)
+	for _, value := range q.Page.SearchAfter {
+		b.AddSearchAfter(value)
+	}
+}
+
 func setFloatPath(settings *simplejson.Json, path ...string) {
 	if stringValue, err := settings.GetPath(path...).String(); err == nil {
 		if value, err := strconv.ParseFloat(stringValue, 64); err == nil {
@@ -263,6 +323,20 @@ func addTermsAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, metrics []*Metr
 	return aggBuilder
 }
 
+func addCompositeAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, afterKey map[string]any) es.AggBuilder {
+	aggBuilder.Composite(bucketAgg.ID, func(a *es.CompositeAggregation, b es.AggBuilder) {
+		a.Size = compositePageSize(bucketAgg)
+		for _, field := range compositeFields(bucketAgg) {
+			a.Sources = append(a.Sources, &es.CompositeAggregationSource{Name: field, Field: field})
+		}
+		a.After = afterKey
+
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
 func addNestedAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
 	aggBuilder.Nested(bucketAgg.ID, bucketAgg.Field, func(a *es.NestedAggregation, b es.AggBuilder) {
 		aggBuilder = b
@@ -322,6 +396,18 @@ func isQueryWithError(query *Query) error {
 			return fmt.Errorf("invalid query, missing metrics and aggregations")
 		}
 	}
+	for i, bucketAgg := range query.BucketAggs {
+		if bucketAgg.Type != compositeType {
+			continue
+		}
+		// Elasticsearch only supports composite aggregations as top level aggregations
+		if i > 0 {
+			return fmt.Errorf("invalid query, composite aggregation must be the first bucket aggregation")
+		}
+		if bucketAgg.Field == "" {
+			return fmt.Errorf("invalid query, missing field of composite aggregation")
+		}
+	}
 	return nil
 }
 
@@ -357,11 +443,19 @@ func processLogsQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defa
 	b.Size(stringToIntWithDefaultValue(metric.Settings.Get("limit").MustString(), defaultSize))
 	b.AddHighlight()
 
-	// This is currently used only for log context query to get
-	// log lines before and after the selected log line
-	searchAfter := metric.Settings.Get("searchAfter").MustArray()
-	for _, value := range searchAfter {
-		b.AddSearchAfter(value)
+	if q.Page != nil {
+		addPage(q, b)
+		// The date histogram of the logs is returned with their first page
+		if len(q.Page.SearchAfter) > 0 {
+			return
+		}
+	} else {
+		// This is currently used only for log context query to get
+		// log lines before and after the selected log line
+		searchAfter := metric.Settings.Get("searchAfter").MustArray()
+		for _, value := range searchAfter {
+			b.AddSearchAfter(value)
+		}
 	}
 
 	// For log query, we add a date histogram aggregation
@@ -387,6 +481,10 @@ func processDocumentQuery(q *Query, b *es.SearchRequestBuilder, from, to int64,
 	b.Sort(es.SortOrderDesc, "_doc", "")
 	b.AddDocValueField(defaultTimeField)
 	b.Size(stringToIntWithDefaultValue(metric.Settings.Get("size").MustString(), defaultSize))
+
+	if q.Page != nil {
+		addPage(q, b)
+	}
 }
 
 func processTimeSeriesQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defaultTimeField string) {
@@ -406,6 +504,8 @@ func processTimeSeriesQuery(q *Query, b *es.SearchRequestBuilder, from, to int64
 			aggBuilder = addFiltersAgg(aggBuilder, bucketAgg)
 		case termsType:
 			aggBuilder = addTermsAgg(aggBuilder, bucketAgg, q.Metrics)
+		case compositeType:
+			aggBuilder = addCompositeAgg(aggBuilder, bucketAgg, q.CompositeAfterKey)
 		case geohashGridType:
 			aggBuilder = addGeoHashGridAgg(aggBuilder, bucketAgg)
 		case nestedType:
@@ -3,10 +3,12 @@ package elasticsearch
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -399,6 +401,104 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, dateHistogramAgg.Aggregation.Aggregation.(*es.DateHistogramAgg).Field, "@timestamp")
 		})
 
+		t.Run("With composite agg", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{ "type": "composite", "id": "2", "field": "host", "settings": { "fields": ["customer", "host"], "size": "100" } },
+					{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			compositeAgg := sr.Aggs[0]
+			require.Equal(t, compositeAgg.Key, "2")
+			require.Equal(t, compositeAgg.Aggregation.Type, "composite")
+			cAgg := compositeAgg.Aggregation.Aggregation.(*es.CompositeAggregation)
+			require.Equal(t, cAgg.Size, 100)
+			require.Equal(t, []*es.CompositeAggregationSource{{Name: "host", Field: "host"}, {Name: "customer", Field: "customer"}}, cAgg.Sources)
+			require.Nil(t, cAgg.After)
+
+			dateHistogramAgg := compositeAgg.Aggregation.Aggs[0]
+			require.Equal(t, dateHistogramAgg.Key, "3")
+		})
+
+		t.Run("With composite agg after another bucket agg should return error", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [
+					{ "type": "date_histogram", "field": "@timestamp", "id": "3" },
+					{ "type": "composite", "id": "2", "field": "host" }
+				],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.Error(t, err)
+			require.Empty(t, c.multisearchRequests)
+		})
+
+		t.Run("With composite agg should request the pages after the first page", func(t *testing.T) {
+			c := newFakeClient()
+			newResponse := func(afterKey string, keys ...string) *es.MultiSearchResponse {
+				buckets := []any{}
+				for _, key := range keys {
+					buckets = append(buckets, map[string]any{"key": map[string]any{"host": key}, "doc_count": float64(1)})
+				}
+				return &es.MultiSearchResponse{Responses: []*es.SearchResponse{{
+					Aggregations: map[string]any{"2": map[string]any{"buckets": buckets, "after_key": map[string]any{"host": afterKey}}},
+				}}}
+			}
+			c.multiSearchResponses = []*es.MultiSearchResponse{
+				newResponse("b", "a", "b"),
+				newResponse("d", "c", "d"),
+				newResponse("e", "e"),
+			}
+			res, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [{ "type": "composite", "id": "2", "field": "host", "settings": { "size": "2" } }],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			require.Len(t, c.multisearchRequests, 3)
+			require.Nil(t, c.multisearchRequests[0].Requests[0].Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation).After)
+			require.Equal(t, map[string]any{"host": "b"}, c.multisearchRequests[1].Requests[0].Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation).After)
+			require.Equal(t, map[string]any{"host": "d"}, c.multisearchRequests[2].Requests[0].Aggs[0].Aggregation.Aggregation.(*es.CompositeAggregation).After)
+
+			frames := res.Responses[""].Frames
+			require.Len(t, frames, 1)
+			require.Equal(t, 5, frames[0].Rows())
+			require.True(t, frames[0].Meta == nil || len(frames[0].Meta.Notices) == 0)
+		})
+
+		t.Run("With composite agg should stop paging at its limit", func(t *testing.T) {
+			c := newFakeClient()
+			newResponse := func(afterKey string, keys ...string) *es.MultiSearchResponse {
+				buckets := []any{}
+				for _, key := range keys {
+					buckets = append(buckets, map[string]any{"key": map[string]any{"host": key}, "doc_count": float64(1)})
+				}
+				return &es.MultiSearchResponse{Responses: []*es.SearchResponse{{
+					Aggregations: map[string]any{"2": map[string]any{"buckets": buckets, "after_key": map[string]any{"host": afterKey}}},
+				}}}
+			}
+			c.multiSearchResponses = []*es.MultiSearchResponse{
+				newResponse("b", "a", "b"),
+				newResponse("d", "c", "d"),
+			}
+			res, err := executeElasticsearchDataQuery(c, `{
+				"bucketAggs": [{ "type": "composite", "id": "2", "field": "host", "settings": { "size": "2", "limit": "3" } }],
+				"metrics": [{"type": "count", "id": "1" }]
+			}`, from, to)
+			require.NoError(t, err)
+			require.Len(t, c.multisearchRequests, 2)
+
+			frames := res.Responses[""].Frames
+			require.Len(t, frames, 1)
+			require.Equal(t, 3, frames[0].Rows())
+			require.Len(t, frames[0].Meta.Notices, 1)
+			require.Equal(t, data.NoticeSeverityWarning, frames[0].Meta.Notices[0].Severity)
+		})
+
 		t.Run("With raw document metric size", func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
@@ -1426,6 +1526,77 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, secondSearchAfter, "2")
 		})
 
+		t.Run("With paginated log query should search in a new point in time", func(t *testing.T) {
+			c := newFakeClient()
+			c.pointInTimeID = "pit-1"
+			_, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "logs", "id": "1", "settings": { "limit": "1000", "pointInTimeKeepAlive": "5m" }}]
+			}`, from, to)
+			require.NoError(t, err)
+			require.Equal(t, []string{"5m"}, c.openedKeepAlives)
+
+			sr := c.multisearchRequests[0].Requests[0]
+			require.Equal(t, &es.PointInTime{ID: "pit-1", KeepAlive: "5m"}, sr.PointInTime)
+			require.Nil(t, sr.CustomProps["search_after"])
+			require.Equal(t, sr.Aggs[0].Key, "1")
+		})
+
+		t.Run("With continuation token should search after the previous page in its point in time", func(t *testing.T) {
+			c := newFakeClient()
+			token, err := (&queryPage{PitID: "pit-2", KeepAlive: "5m", SearchAfter: []any{1675869055830, 4}, From: fromMs - 1000, To: toMs - 1000}).encode()
+			require.NoError(t, err)
+			_, err = executeElasticsearchDataQuery(c, fmt.Sprintf(`{
+				"metrics": [{ "type": "raw_data", "id": "1", "settings": { "size": "1000", "continuationToken": %q }}]
+			}`, token), from, to)
+			require.NoError(t, err)
+			require.Empty(t, c.openedKeepAlives)
+
+			sr := c.multisearchRequests[0].Requests[0]
+			require.Equal(t, &es.PointInTime{ID: "pit-2", KeepAlive: "5m"}, sr.PointInTime)
+			require.Equal(t, []any{float64(1675869055830), float64(4)}, sr.CustomProps["search_after"])
+			rangeFilter := sr.Query.Bool.Filters[0].(*es.RangeFilter)
+			require.Equal(t, fromMs-1000, rangeFilter.Gte)
+			require.Equal(t, toMs-1000, rangeFilter.Lte)
+		})
+
+		t.Run("With continuation token of log query should not return the date histogram", func(t *testing.T) {
+			c := newFakeClient()
+			token, err := (&queryPage{PitID: "pit-2", KeepAlive: "5m", SearchAfter: []any{1675869055830, 4}, From: fromMs, To: toMs}).encode()
+			require.NoError(t, err)
+			_, err = executeElasticsearchDataQuery(c, fmt.Sprintf(`{
+				"metrics": [{ "type": "logs", "id": "1", "settings": { "continuationToken": %q }}]
+			}`, token), from, to)
+			require.NoError(t, err)
+
+			sr := c.multisearchRequests[0].Requests[0]
+			require.Equal(t, &es.PointInTime{ID: "pit-2", KeepAlive: "5m"}, sr.PointInTime)
+			require.Empty(t, sr.Aggs)
+		})
+
+		t.Run("With invalid continuation token should return error", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "logs", "id": "1", "settings": { "continuationToken": "foo" }}]
+			}`, from, to)
+			require.Error(t, err)
+		})
+
+		t.Run("With paginated query should close the point in time after the last page", func(t *testing.T) {
+			c := newFakeClient()
+			c.pointInTimeID = "pit-1"
+			c.multiSearchResponse = &es.MultiSearchResponse{
+				Responses: []*es.SearchResponse{{
+					Hits:  &es.SearchResponseHits{Hits: []map[string]any{{"_id": "1", "sort": []any{float64(1), float64(2)}}}},
+					PitID: "pit-2",
+				}},
+			}
+			_, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "raw_data", "id": "1", "settings": { "size": "2", "pointInTimeKeepAlive": "5m" }}]
+			}`, from, to)
+			require.NoError(t, err)
+			require.Equal(t, []string{"pit-2"}, c.closedPointInTimes)
+		})
+
 		t.Run("With invalid query should return error", (func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
@@ -1801,9 +1972,14 @@ func TestSettingsCasting(t *testing.T) {
 type fakeClient struct {
 	configuredFields    es.ConfiguredFields
 	multiSearchResponse *es.MultiSearchResponse
-	multiSearchError    error
-	builder             *es.MultiSearchRequestBuilder
-	multisearchRequests []*es.MultiSearchRequest
+	// multiSearchResponses are returned in order before multiSearchResponse
+	multiSearchResponses []*es.MultiSearchResponse
+	multiSearchError     error
+	builder              *es.MultiSearchRequestBuilder
+	multisearchRequests  []*es.MultiSearchRequest
+	pointInTimeID        string
+	openedKeepAlives     []string
+	closedPointInTimes   []string
 }
 
 func newFakeClient() *fakeClient {
@@ -1826,6 +2002,11 @@ func (c *fakeClient) GetConfiguredFields() es.ConfiguredFields {
 
 func (c *fakeClient) ExecuteMultisearch(r *es.MultiSearchRequest) (*es.MultiSearchResponse, error) {
 	c.multisearchRequests = append(c.multisearchRequests, r)
+	if len(c.multiSearchResponses) > 0 {
+		res := c.multiSearchResponses[0]
+		c.multiSearchResponses = c.multiSearchResponses[1:]
+		return res, c.multiSearchError
+	}
 	return c.multiSearchResponse, c.multiSearchError
 }
 
@@ -1834,6 +2015,24 @@ func (c *fakeClient) MultiSearch() *es.MultiSearchRequestBuilder {
 	return c.builder
 }
 
+func (c *fakeClient) OpenPointInTime(keepAlive string) (string, error) {
+	c.openedKeepAlives = append(c.openedKeepAlives, keepAlive)
+	return c.pointInTimeID, nil
+}
+
+func (c *fakeClient) ClosePointInTime(id string) error {
+	c.closedPointInTimes = append(c.closedPointInTimes, id)
+	return nil
+}
+
+func (c *fakeClient) GetClusterInfo() (*es.ClusterInfo, error) {
+	return &es.ClusterInfo{}, nil
+}
+
+func (c *fakeClient) GetFieldMapping(field string) (es.FieldMapping, error) {
+	return es.FieldMapping{}, nil
+}
+
 func newDataQuery(body string) (backend.QueryDataRequest, error) {
 	return backend.QueryDataRequest{
 		Queries: []backend.DataQuery{
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -27,6 +28,9 @@ import (
 
 var eslog = log.New("tsdb.elasticsearch")
 
+// nextPageResourcePath is the resource path returning the next page of a paginated logs or raw data query.
+const nextPageResourcePath = "next-page"
+
 type Service struct {
 	httpClientProvider httpclient.Provider
 	im                 instancemgmt.InstanceManager
@@ -190,6 +194,10 @@ func (s *Service) getDSInfo(ctx context.Context, pluginCtx backend.PluginContext
 
 func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
 	logger := eslog.FromContext(ctx)
+	if req.Path == nextPageResourcePath {
+		return s.handleNextPage(ctx, req, sender, logger)
+	}
+
 	// allowed paths for resource calls:
 	// - empty string for fetching db version
 	// - ?/_mapping for fetching index mapping
@@ -269,3 +277,90 @@ func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceReq
 		Body:    body,
 	})
 }
+
+type nextPageRequest struct {
+	Query             json.RawMessage `json:"query"`
+	ContinuationToken string          `json:"continuationToken"`
+}
+
+// handleNextPage runs a paginated logs or raw data query for the page of a continuation token, in
+// the time range of the first page, and sends the data response of the query.
+func (s *Service) handleNextPage(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender, logger log.Logger) error {
+	if req.Method != http.MethodPost {
+		return sendResourceError(sender, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+	}
+
+	var pageReq nextPageRequest
+	if err := json.Unmarshal(req.Body, &pageReq); err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, fmt.Errorf("failed to parse the request: %w", err))
+	}
+	page, err := decodeContinuationToken(pageReq.ContinuationToken)
+	if err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, err)
+	}
+
+	model, err := simplejson.NewJson(pageReq.Query)
+	if err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, fmt.Errorf("failed to parse the query: %w", err))
+	}
+	metrics := model.Get("metrics").MustArray()
+	if len(metrics) == 0 {
+		return sendResourceError(sender, http.StatusBadRequest, errors.New("the query has no metrics"))
+	}
+	metric := simplejson.NewFromAny(metrics[0])
+	if metricType := metric.Get("type").MustString(); metricType != logsType && metricType != rawDataType {
+		return sendResourceError(sender, http.StatusBadRequest, fmt.Errorf("%s queries can't be paginated", metricType))
+	}
+	metric.SetPath([]string{"settings", continuationTokenSetting}, pageReq.ContinuationToken)
+	queryJSON, err := model.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		logger.Error("Failed to get data source info", "error", err)
+		return err
+	}
+
+	refID := model.Get("refId").MustString()
+	query := backend.DataQuery{
+		RefID: refID,
+		JSON:  queryJSON,
+		TimeRange: backend.TimeRange{
+			From: time.UnixMilli(page.From),
+			To:   time.UnixMilli(page.To),
+		},
+	}
+	res, err := queryData(ctx, []backend.DataQuery{query}, dsInfo, logger, s.tracer)
+	if err != nil {
+		return err
+	}
+
+	dataRes := res.Responses[refID]
+	status := http.StatusOK
+	if dataRes.Error != nil {
+		status = http.StatusBadRequest
+	}
+	body, err := json.Marshal(dataRes)
+	if err != nil {
+		return err
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  status,
+		Headers: map[string][]string{"content-type": {"application/json"}},
+		Body:    body,
+	})
+}
+
+func sendResourceError(sender backend.CallResourceResponseSender, status int, err error) error {
+	body, marshalErr := json.Marshal(map[string]string{"message": err.Error()})
+	if marshalErr != nil {
+		return marshalErr
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  status,
+		Headers: map[string][]string{"content-type": {"application/json"}},
+		Body:    body,
+	})
+}
@@ -1,14 +1,22 @@
 package elasticsearch
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 )
 
 type datasourceInfo struct {
@@ -74,3 +82,92 @@ func TestNewInstanceSettings(t *testing.T) {
 		})
 	})
 }
+
+type fakeCallResourceSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (s *fakeCallResourceSender) Send(res *backend.CallResourceResponse) error {
+	s.response = res
+	return nil
+}
+
+func TestCallResourceNextPage(t *testing.T) {
+	var searchBody *simplejson.Json
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_msearch":
+			reader := bufio.NewReader(r.Body)
+			_, err := reader.ReadBytes('\n')
+			require.NoError(t, err)
+			body, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			searchBody, err = simplejson.NewJson(body)
+			require.NoError(t, err)
+			_, _ = w.Write([]byte(`{"responses": [{"hits": {"hits": [
+				{"_id": "2", "_source": {"@timestamp": "2023-02-08T15:10:54.835Z", "line": "second"}, "sort": [1675869054835, 7]}
+			]}, "pit_id": "pit-2", "status": 200}]}`))
+		case "/_pit":
+			_, _ = w.Write([]byte(`{"succeeded": true, "num_freed": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	callResource := func(t *testing.T, body string) *backend.CallResourceResponse {
+		t.Helper()
+		service := ProvideService(httpclient.NewProvider(), tracing.InitializeTracerForTest())
+		sender := &fakeCallResourceSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL, JSONData: []byte(`{"timeField": "@timestamp", "index": "logs"}`)},
+			},
+			Path:   nextPageResourcePath,
+			Method: http.MethodPost,
+			Body:   []byte(body),
+		}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+
+	t.Run("should return the page of the continuation token", func(t *testing.T) {
+		token, err := (&queryPage{PitID: "pit-1", KeepAlive: "5m", SearchAfter: []any{1675869055830, 4}, From: 1675868000000, To: 1675870000000}).encode()
+		require.NoError(t, err)
+
+		res := callResource(t, fmt.Sprintf(`{
+			"query": {"refId": "A", "metrics": [{"type": "logs", "id": "1", "settings": {"limit": "2"}}]},
+			"continuationToken": %q
+		}`, token))
+		require.Equal(t, http.StatusOK, res.Status)
+
+		assert.Equal(t, "pit-1", searchBody.GetPath("pit", "id").MustString())
+		assert.Equal(t, []any{json.Number("1675869055830"), json.Number("4")}, searchBody.Get("search_after").MustArray())
+		assert.Equal(t, json.Number("1675868000000"), searchBody.GetPath("query", "bool", "filter", "range", "@timestamp", "gte").Interface())
+
+		var dataRes backend.DataResponse
+		require.NoError(t, json.Unmarshal(res.Body, &dataRes))
+		require.NoError(t, dataRes.Error)
+		require.Len(t, dataRes.Frames, 1)
+		require.Equal(t, 1, dataRes.Frames[0].Rows())
+	})
+
+	t.Run("should fail for invalid continuation tokens", func(t *testing.T) {
+		res := callResource(t, `{
+			"query": {"refId": "A", "metrics": [{"type": "logs", "id": "1"}]},
+			"continuationToken": "foo"
+		}`)
+		require.Equal(t, http.StatusBadRequest, res.Status)
+	})
+
+	t.Run("should fail for queries that can't be paginated", func(t *testing.T) {
+		token, err := (&queryPage{PitID: "pit-1", KeepAlive: "5m", SearchAfter: []any{1}, From: 1, To: 2}).encode()
+		require.NoError(t, err)
+		res := callResource(t, fmt.Sprintf(`{
+			"query": {"refId": "A", "metrics": [{"type": "count", "id": "1"}], "bucketAggs": [{"type": "date_histogram", "id": "2"}]},
+			"continuationToken": %q
+		}`, token))
+		require.Equal(t, http.StatusBadRequest, res.Status)
+	})
+}
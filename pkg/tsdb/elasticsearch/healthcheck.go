@@ -0,0 +1,84 @@
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+	"github.com/grafana/grafana/pkg/tsdb/healthcheck"
+)
+
+// healthCheckTimeRange is the time range used to resolve the indices of dynamic index patterns.
+const healthCheckTimeRange = 24 * time.Hour
+
+// CheckHealth checks that Elasticsearch can be reached with the configured credentials, and that the
+// index pattern of the data source resolves to at least one index with the configured time field.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := s.logger.FromContext(ctx)
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return healthcheck.Result(logger, "error getting datasource info", err), nil
+	}
+	if dsInfo.Database == "" {
+		return healthcheck.Result(logger, "invalid index pattern", errors.New("no index name or pattern is configured")), nil
+	}
+
+	now := time.Now()
+	client, err := es.NewClient(ctx, dsInfo, backend.TimeRange{From: now.Add(-healthCheckTimeRange), To: now}, logger, s.tracer)
+	if err != nil {
+		return healthcheck.Result(logger, "invalid index pattern", err), nil
+	}
+
+	version, err := getVersion(client)
+	if err != nil {
+		return healthcheck.Result(logger, "error connecting to Elasticsearch", err), nil
+	}
+	if err := checkTimeField(client, dsInfo.ConfiguredFields.TimeField); err != nil {
+		return healthcheck.Result(logger, fmt.Sprintf("error checking index pattern %q", dsInfo.Database), err), nil
+	}
+
+	return healthcheck.Result(logger, fmt.Sprintf("Data source is working. %s", version), nil), nil
+}
+
+// getVersion returns a description of the distribution and version of the cluster.
+func getVersion(client es.Client) (string, error) {
+	info, err := client.GetClusterInfo()
+	if err != nil {
+		return "", err
+	}
+	if info.Version.Number == "" {
+		return "", errors.New("invalid response: no version found")
+	}
+
+	distribution := "Elasticsearch"
+	if info.Version.Distribution == "opensearch" {
+		distribution = "OpenSearch"
+	}
+	return fmt.Sprintf("%s version: %s", distribution, info.Version.Number), nil
+}
+
+// checkTimeField checks that at least one of the indices of the client exists and maps the time field as a date.
+func checkTimeField(client es.Client, timeField string) error {
+	mapping, err := client.GetFieldMapping(timeField)
+	if err != nil {
+		return err
+	}
+	if len(mapping) == 0 {
+		return errors.New("no matching index found")
+	}
+
+	for _, index := range mapping {
+		for _, field := range index.Mappings {
+			for _, m := range field.Mapping {
+				if m.Type == "date" || m.Type == "date_nanos" {
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("no date field named %q found", timeField)
+}
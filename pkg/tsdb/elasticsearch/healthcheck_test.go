@@ -0,0 +1,107 @@
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+const clusterInfo = `{"name": "es", "version": {"number": "8.11.0", "build_flavor": "default"}, "tagline": "You Know, for Search"}`
+
+func TestCheckHealth(t *testing.T) {
+	checkHealth := func(t *testing.T, jsonData string, handler http.HandlerFunc) *backend.CheckHealthResult {
+		t.Helper()
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		service := ProvideService(httpclient.NewProvider(), tracing.InitializeTracerForTest())
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL, JSONData: []byte(jsonData)},
+			},
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should succeed when the index pattern has the time field", func(t *testing.T) {
+		res := checkHealth(t, `{"timeField": "@timestamp", "index": "logs-*"}`, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				_, _ = w.Write([]byte(clusterInfo))
+			case "/logs-*/_mapping/field/@timestamp":
+				assert.Equal(t, "true", r.URL.Query().Get("ignore_unavailable"))
+				_, _ = w.Write([]byte(`{"logs-1": {"mappings": {"@timestamp": {"full_name": "@timestamp", "mapping": {"@timestamp": {"type": "date"}}}}}}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+		require.Equal(t, "Data source is working. Elasticsearch version: 8.11.0", res.Message)
+	})
+
+	t.Run("should resolve dynamic index patterns", func(t *testing.T) {
+		today := time.Now().UTC().Format("2006.01.02")
+		res := checkHealth(t, `{"timeField": "@timestamp", "index": "[logs-]YYYY.MM.DD", "interval": "Daily"}`, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/" {
+				_, _ = w.Write([]byte(`{"version": {"number": "2.11.0", "distribution": "opensearch"}}`))
+				return
+			}
+			assert.Contains(t, r.URL.Path, "logs-"+today)
+			assert.True(t, strings.HasSuffix(r.URL.Path, "/_mapping/field/@timestamp"))
+			_, _ = w.Write([]byte(`{"logs-` + today + `": {"mappings": {"@timestamp": {"mapping": {"@timestamp": {"type": "date_nanos"}}}}}}`))
+		})
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+		require.Equal(t, "Data source is working. OpenSearch version: 2.11.0", res.Message)
+	})
+
+	t.Run("should fail when no index matches", func(t *testing.T) {
+		res := checkHealth(t, `{"timeField": "@timestamp", "index": "logs-*"}`, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/" {
+				_, _ = w.Write([]byte(clusterInfo))
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		})
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "no matching index found")
+	})
+
+	t.Run("should fail when the time field is not a date", func(t *testing.T) {
+		res := checkHealth(t, `{"timeField": "@timestamp", "index": "logs-*"}`, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/" {
+				_, _ = w.Write([]byte(clusterInfo))
+				return
+			}
+			_, _ = w.Write([]byte(`{"logs-1": {"mappings": {"@timestamp": {"mapping": {"@timestamp": {"type": "keyword"}}}}}}`))
+		})
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, `no date field named "@timestamp" found`)
+	})
+
+	t.Run("should fail when no index is configured", func(t *testing.T) {
+		res := checkHealth(t, `{"timeField": "@timestamp"}`, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(clusterInfo))
+		})
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "no index name or pattern is configured")
+	})
+
+	t.Run("should fail when authentication fails", func(t *testing.T) {
+		res := checkHealth(t, `{"timeField": "@timestamp", "index": "logs-*"}`, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "authentication failed")
+	})
+}
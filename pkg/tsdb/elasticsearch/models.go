@@ -16,6 +16,11 @@ type Query struct {
 	IntervalMs    int64
 	RefID         string
 	MaxDataPoints int64
+	// Page is the page of the results returned for paginated logs and raw data queries
+	Page *queryPage
+	// CompositeAfterKey is the key of the last bucket of the previous page of the composite
+	// aggregation of the query
+	CompositeAfterKey map[string]any
 }
 
 // BucketAgg represents a bucket aggregation of the time series query model of the datasource
@@ -0,0 +1,129 @@
+package elasticsearch
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+const (
+	// pointInTimeKeepAliveSetting is the metric setting of logs and raw data queries enabling
+	// pagination, with the duration the point in time of their pages is kept alive between pages.
+	pointInTimeKeepAliveSetting = "pointInTimeKeepAlive"
+	// continuationTokenSetting is the metric setting of logs and raw data queries with the
+	// continuation token of the page to return.
+	continuationTokenSetting = "continuationToken"
+	// continuationTokenMeta is the key of the continuation token of the next page in the custom
+	// metadata of the frames of paginated queries.
+	continuationTokenMeta = "continuationToken"
+)
+
+// queryPage is a page of the results of a paginated logs or raw data query. All pages of a query
+// search the same time range in the same point in time, so that documents indexed while paging
+// don't shift the results. Clients get the page as an opaque continuation token.
+type queryPage struct {
+	PitID     string `json:"pitId"`
+	KeepAlive string `json:"keepAlive"`
+	// SearchAfter holds the sort values of the last document of the previous page.
+	SearchAfter []any `json:"searchAfter,omitempty"`
+	From        int64 `json:"from"`
+	To          int64 `json:"to"`
+}
+
+func (p *queryPage) encode() (string, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeContinuationToken(token string) (*queryPage, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid continuation token: %w", err)
+	}
+	page := &queryPage{}
+	if err := json.Unmarshal(b, page); err != nil {
+		return nil, fmt.Errorf("invalid continuation token: %w", err)
+	}
+	if page.PitID == "" || len(page.SearchAfter) == 0 {
+		return nil, fmt.Errorf("invalid continuation token: missing point in time or sort values")
+	}
+	return page, nil
+}
+
+func isPaginatedQuery(query *Query) bool {
+	if !isLogsQuery(query) && !isRawDataQuery(query) {
+		return false
+	}
+	settings := query.Metrics[0].Settings
+	return settings.Get(pointInTimeKeepAliveSetting).MustString() != "" || settings.Get(continuationTokenSetting).MustString() != ""
+}
+
+// pageSize returns the number of documents in the pages of a paginated query.
+func pageSize(query *Query) int {
+	if isLogsQuery(query) {
+		return stringToIntWithDefaultValue(query.Metrics[0].Settings.Get("limit").MustString(), defaultSize)
+	}
+	return stringToIntWithDefaultValue(query.Metrics[0].Settings.Get("size").MustString(), defaultSize)
+}
+
+// pointInTimeID returns the ID of the point in time of a paginated query after its response.
+// Elasticsearch may change the ID of a point in time in each response.
+func pointInTimeID(query *Query, res *es.SearchResponse) string {
+	if res.PitID != "" {
+		return res.PitID
+	}
+	return query.Page.PitID
+}
+
+// nextPage returns the page after the response of a paginated query, or nil if the response is
+// its last page.
+func nextPage(query *Query, res *es.SearchResponse) *queryPage {
+	if query.Page == nil || res.Error != nil || res.Hits == nil {
+		return nil
+	}
+	hits := res.Hits.Hits
+	if len(hits) == 0 || len(hits) < pageSize(query) {
+		return nil
+	}
+	searchAfter, ok := hits[len(hits)-1]["sort"].([]any)
+	if !ok || len(searchAfter) == 0 {
+		return nil
+	}
+	return &queryPage{
+		PitID:       pointInTimeID(query, res),
+		KeepAlive:   query.Page.KeepAlive,
+		SearchAfter: searchAfter,
+		From:        query.Page.From,
+		To:          query.Page.To,
+	}
+}
+
+// setContinuationTokenMeta adds the continuation token of the next page of a paginated query to
+// the custom metadata of the frame.
+func setContinuationTokenMeta(frame *data.Frame, page *queryPage) error {
+	if page == nil {
+		return nil
+	}
+	token, err := page.encode()
+	if err != nil {
+		return err
+	}
+
+	if frame.Meta == nil {
+		frame.Meta = &data.FrameMeta{}
+	}
+	custom, ok := frame.Meta.Custom.(map[string]any)
+	if !ok {
+		custom = map[string]any{}
+		frame.Meta.Custom = custom
+	}
+	custom[continuationTokenMeta] = token
+	return nil
+}
@@ -0,0 +1,81 @@
+package elasticsearch
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	es "github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
+)
+
+func TestContinuationToken(t *testing.T) {
+	t.Run("Should decode encoded pages", func(t *testing.T) {
+		page := &queryPage{PitID: "pit-1", KeepAlive: "1m", SearchAfter: []any{float64(1675869055830), float64(4)}, From: 1, To: 2}
+		token, err := page.encode()
+		require.NoError(t, err)
+
+		decoded, err := decodeContinuationToken(token)
+		require.NoError(t, err)
+		require.Equal(t, page, decoded)
+	})
+
+	t.Run("Should fail to decode invalid tokens", func(t *testing.T) {
+		_, err := decodeContinuationToken("not a token")
+		require.Error(t, err)
+
+		token, err := (&queryPage{PitID: "pit-1", KeepAlive: "1m"}).encode()
+		require.NoError(t, err)
+		_, err = decodeContinuationToken(token)
+		require.Error(t, err)
+	})
+}
+
+func TestNextPage(t *testing.T) {
+	newQuery := func(metricType string, settings map[string]any) *Query {
+		return &Query{
+			Metrics: []*MetricAgg{{Type: metricType, Settings: simplejson.NewFromAny(settings)}},
+			Page:    &queryPage{PitID: "pit-1", KeepAlive: "1m", From: 1, To: 2},
+		}
+	}
+	newResponse := func(pitID string, hits int) *es.SearchResponse {
+		res := &es.SearchResponse{Hits: &es.SearchResponseHits{}, PitID: pitID}
+		for i := 0; i < hits; i++ {
+			res.Hits.Hits = append(res.Hits.Hits, map[string]any{"sort": []any{float64(100 - i), float64(i)}})
+		}
+		return res
+	}
+
+	t.Run("Should return the page after the last document of full pages", func(t *testing.T) {
+		page := nextPage(newQuery(logsType, map[string]any{"limit": "2"}), newResponse("pit-2", 2))
+		require.Equal(t, &queryPage{PitID: "pit-2", KeepAlive: "1m", SearchAfter: []any{float64(99), float64(1)}, From: 1, To: 2}, page)
+
+		page = nextPage(newQuery(rawDataType, map[string]any{"size": "3"}), newResponse("", 3))
+		require.Equal(t, &queryPage{PitID: "pit-1", KeepAlive: "1m", SearchAfter: []any{float64(98), float64(2)}, From: 1, To: 2}, page)
+	})
+
+	t.Run("Should return no page after the last page", func(t *testing.T) {
+		require.Nil(t, nextPage(newQuery(logsType, map[string]any{"limit": "2"}), newResponse("pit-2", 1)))
+		require.Nil(t, nextPage(newQuery(logsType, map[string]any{"limit": "2"}), newResponse("pit-2", 0)))
+	})
+
+	t.Run("Should return no page for queries without pagination", func(t *testing.T) {
+		query := newQuery(logsType, map[string]any{"limit": "2"})
+		query.Page = nil
+		require.Nil(t, nextPage(query, newResponse("pit-2", 2)))
+	})
+
+	t.Run("Should add the continuation token to the custom metadata of frames", func(t *testing.T) {
+		frame := data.NewFrame("")
+		setLogsCustomMeta(frame, map[string]bool{"foo": true}, 2)
+		page := &queryPage{PitID: "pit-1", KeepAlive: "1m", SearchAfter: []any{float64(1)}}
+		require.NoError(t, setContinuationTokenMeta(frame, page))
+
+		custom := frame.Meta.Custom.(map[string]any)
+		require.Equal(t, []string{"foo"}, custom["searchWords"])
+		decoded, err := decodeContinuationToken(custom[continuationTokenMeta].(string))
+		require.NoError(t, err)
+		require.Equal(t, page, decoded)
+	})
+}
@@ -36,6 +36,7 @@ const (
 	histogramType   = "histogram"
 	filtersType     = "filters"
 	termsType       = "terms"
+	compositeType   = "composite"
 	geohashGridType = "geohash_grid"
 	//  Document types
 	rawDocumentType = "raw_document"
@@ -203,6 +204,9 @@ func processLogsResponse(res *es.SearchResponse, target *Query, configuredFields
 	frame := data.NewFrame("", fields...)
 	setPreferredVisType(frame, data.VisTypeLogs)
 	setLogsCustomMeta(frame, searchWords, stringToIntWithDefaultValue(target.Metrics[0].Settings.Get("limit").MustString(), defaultSize))
+	if err := setContinuationTokenMeta(frame, nextPage(target, res)); err != nil {
+		return err
+	}
 	frames = append(frames, frame)
 	queryRes.Frames = frames
 
@@ -244,6 +248,9 @@ func processRawDataResponse(res *es.SearchResponse, target *Query, configuredFie
 
 	frames := data.Frames{}
 	frame := data.NewFrame("", fields...)
+	if err := setContinuationTokenMeta(frame, nextPage(target, res)); err != nil {
+		return err
+	}
 
 	frames = append(frames, frame)
 	queryRes.Frames = frames
@@ -424,7 +431,16 @@ func processBuckets(aggs map[string]interface{}, target *Query,
 					newProps[k] = v
 				}
 
-				if key, err := bucket.Get("key").String(); err == nil {
+				if aggDef.Type == compositeType {
+					// The key of composite buckets has the values of each of their sources
+					for _, key := range getBucketKeys(aggDef, bucket) {
+						if value, err := key.value.String(); err == nil {
+							newProps[key.name] = value
+						} else if value, err := key.value.Int64(); err == nil {
+							newProps[key.name] = strconv.FormatInt(value, 10)
+						}
+					}
+				} else if key, err := bucket.Get("key").String(); err == nil {
 					newProps[aggDef.Field] = key
 				} else if key, err := bucket.Get("key").Int64(); err == nil {
 					newProps[aggDef.Field] = strconv.FormatInt(key, 10)
@@ -738,7 +754,8 @@ func processAggregationDocs(esAgg *simplejson.Json, aggDef *BucketAgg, target *Q
 		bucket := simplejson.NewFromAny(v)
 		var values []interface{}
 
-		found := false
+		keys := getBucketKeys(aggDef, bucket)
+		found := make(map[string]bool, len(keys))
 		for _, field := range fields {
 			for _, propKey := range propKeys {
 				if field.Name == propKey {
@@ -746,12 +763,15 @@ func processAggregationDocs(esAgg *simplejson.Json, aggDef *BucketAgg, target *Q
 					field.Append(&value)
 				}
 			}
-			if field.Name == aggDef.Field {
-				found = true
-				if key, err := bucket.Get("key").String(); err == nil {
+			for _, bucketKey := range keys {
+				if field.Name != bucketKey.name {
+					continue
+				}
+				found[bucketKey.name] = true
+				if key, err := bucketKey.value.String(); err == nil {
 					field.Append(&key)
 				} else {
-					f, err := bucket.Get("key").Float64()
+					f, err := bucketKey.value.Float64()
 					if err != nil {
 						return fmt.Errorf("error appending bucket key to existing field with name %s: %w", field.Name, err)
 					}
@@ -760,17 +780,20 @@ func processAggregationDocs(esAgg *simplejson.Json, aggDef *BucketAgg, target *Q
 			}
 		}
 
-		if !found {
+		for _, bucketKey := range keys {
+			if found[bucketKey.name] {
+				continue
+			}
 			var aggDefField *data.Field
-			if key, err := bucket.Get("key").String(); err == nil {
-				aggDefField = extractDataField(aggDef.Field, &key)
+			if key, err := bucketKey.value.String(); err == nil {
+				aggDefField = extractDataField(bucketKey.name, &key)
 				aggDefField.Append(&key)
 			} else {
-				f, err := bucket.Get("key").Float64()
+				f, err := bucketKey.value.Float64()
 				if err != nil {
-					return fmt.Errorf("error appending bucket key to new field with name %s: %w", aggDef.Field, err)
+					return fmt.Errorf("error appending bucket key to new field with name %s: %w", bucketKey.name, err)
 				}
-				aggDefField = extractDataField(aggDef.Field, &f)
+				aggDefField = extractDataField(bucketKey.name, &f)
 				aggDefField.Append(&f)
 			}
 			fields = append(fields, aggDefField)
@@ -803,6 +826,26 @@ func processAggregationDocs(esAgg *simplejson.Json, aggDef *BucketAgg, target *Q
 	return nil
 }
 
+type bucketKey struct {
+	name  string
+	value *simplejson.Json
+}
+
+// getBucketKeys returns the keys of a bucket with the names of their fields. Composite buckets
+// have a key for each source of their aggregation, other buckets have a single key.
+func getBucketKeys(aggDef *BucketAgg, bucket *simplejson.Json) []bucketKey {
+	if aggDef.Type != compositeType {
+		return []bucketKey{{name: aggDef.Field, value: bucket.Get("key")}}
+	}
+
+	fields := compositeFields(aggDef)
+	keys := make([]bucketKey, 0, len(fields))
+	for _, field := range fields {
+		keys = append(keys, bucketKey{name: field, value: bucket.GetPath("key", field)})
+	}
+	return keys
+}
+
 func extractDataField(name string, v interface{}) *data.Field {
 	var field *data.Field
 	switch v.(type) {
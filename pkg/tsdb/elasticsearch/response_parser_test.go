@@ -1242,6 +1242,104 @@ func TestProcessBuckets(t *testing.T) {
 			requireFloatAt(t, 369.0, f3, 0)
 			requireFloatAt(t, 200.0, f3, 1)
 		})
+
+		t.Run("Composite agg without date histogram", func(t *testing.T) {
+			query := []byte(`
+	[
+		{
+		  "refId": "A",
+		  "metrics": [
+			{ "type": "avg", "id": "1", "field": "@value" },
+			{ "type": "count", "id": "3" }
+		  ],
+		  "bucketAggs": [{ "id": "2", "type": "composite", "field": "host", "settings": { "fields": ["customer"] } }]
+		}
+	]
+	`)
+
+			response := []byte(`
+	{
+		"responses": [
+		  {
+			"aggregations": {
+			  "2": {
+				"after_key": { "host": "server-2", "customer": "b" },
+				"buckets": [
+				  { "1": { "value": 1000 }, "key": { "host": "server-1", "customer": "a" }, "doc_count": 369 },
+				  { "1": { "value": 2000 }, "key": { "host": "server-2", "customer": "b" }, "doc_count": 200 }
+				]
+			  }
+			}
+		  }
+		]
+	}
+	`)
+
+			result, err := queryDataTest(query, response)
+			require.NoError(t, err)
+
+			require.Len(t, result.response.Responses, 1)
+			frames := result.response.Responses["A"].Frames
+			require.Len(t, frames, 1)
+
+			frame1 := frames[0]
+			requireFrameLength(t, frame1, 2)
+			require.Len(t, frame1.Fields, 4)
+
+			require.Equal(t, "host", frame1.Fields[0].Name)
+			requireStringAt(t, "server-1", frame1.Fields[0], 0)
+			requireStringAt(t, "server-2", frame1.Fields[0], 1)
+
+			require.Equal(t, "customer", frame1.Fields[1].Name)
+			requireStringAt(t, "a", frame1.Fields[1], 0)
+			requireStringAt(t, "b", frame1.Fields[1], 1)
+
+			requireFloatAt(t, 1000.0, frame1.Fields[2], 0)
+			requireFloatAt(t, 2000.0, frame1.Fields[2], 1)
+
+			requireFloatAt(t, 369.0, frame1.Fields[3], 0)
+			requireFloatAt(t, 200.0, frame1.Fields[3], 1)
+		})
+
+		t.Run("Composite agg with date histogram", func(t *testing.T) {
+			targets := map[string]string{
+				"A": `{
+					"metrics": [{ "type": "count", "id": "1" }],
+					"bucketAggs": [
+						{ "type": "composite", "field": "host", "id": "2", "settings": { "fields": ["customer"] } },
+						{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+					]
+				}`,
+			}
+			response := `{
+				"responses": [{
+					"aggregations": {
+						"2": {
+							"buckets": [
+								{
+									"key": { "host": "server-1", "customer": 1 },
+									"3": { "buckets": [{ "doc_count": 1, "key": 1000 }, { "doc_count": 3, "key": 2000 }] }
+								},
+								{
+									"key": { "host": "server-2", "customer": 2 },
+									"3": { "buckets": [{ "doc_count": 2, "key": 1000 }, { "doc_count": 8, "key": 2000 }] }
+								}
+							]
+						}
+					}
+				}]
+			}`
+			result, err := parseTestResponse(targets, response)
+			require.NoError(t, err)
+
+			frames := result.Responses["A"].Frames
+			require.Len(t, frames, 2)
+			require.Equal(t, data.Labels{"host": "server-1", "customer": "1"}, frames[0].Fields[1].Labels)
+			require.Equal(t, "1 server-1", frames[0].Fields[1].Config.DisplayNameFromDS)
+			requireNumberValue(t, 3, frames[0], 1)
+			require.Equal(t, data.Labels{"host": "server-2", "customer": "2"}, frames[1].Fields[1].Labels)
+			requireNumberValue(t, 8, frames[1], 1)
+		})
 	})
 
 	t.Run("Top metrics", func(t *testing.T) {
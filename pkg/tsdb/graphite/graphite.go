@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/patrickmn/go-cache"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 
@@ -52,6 +53,8 @@ type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
 	Id         int64
+	// resourceCache holds the responses of resource calls
+	resourceCache *cache.Cache
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -67,9 +70,10 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 		}
 
 		model := datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
-			Id:         settings.ID,
+			HTTPClient:    client,
+			URL:           settings.URL,
+			Id:            settings.ID,
+			resourceCache: cache.New(resourceCacheTTL, 5*time.Minute),
 		}
 
 		return model, nil
@@ -0,0 +1,44 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/tsdb/healthcheck"
+)
+
+// CheckHealth checks that Graphite can be reached with the configured credentials by finding the top level metrics,
+// and reports the version of Graphite when the server exposes it.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return healthcheck.Result(logger, "error getting datasource info", err), nil
+	}
+
+	body, err := healthcheck.Get(ctx, logger, dsInfo.HTTPClient, dsInfo.URL, "metrics/find", url.Values{"query": []string{"*"}})
+	if err != nil {
+		return healthcheck.Result(logger, "error finding metrics", err), nil
+	}
+	var metrics []any
+	if err := json.Unmarshal(body, &metrics); err != nil {
+		return healthcheck.Result(logger, "error finding metrics", fmt.Errorf("invalid response: %w", err)), nil
+	}
+
+	// The version endpoint is not available in all Graphite compatible servers, so it is not required.
+	message := "Data source is working"
+	if version, err := healthcheck.Get(ctx, logger, dsInfo.HTTPClient, dsInfo.URL, "version", nil); err == nil {
+		// Servers that answer any path with a page or a JSON document don't have a version to report.
+		if v := strings.TrimSpace(string(version)); v != "" && !strings.ContainsAny(v, "<{") {
+			message = fmt.Sprintf("Data source is working. Graphite version: %s", v)
+		}
+	} else {
+		logger.Debug("Failed to get Graphite version", "error", err)
+	}
+	return healthcheck.Result(logger, message, nil), nil
+}
@@ -0,0 +1,76 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestCheckHealth(t *testing.T) {
+	checkHealth := func(t *testing.T, handler http.HandlerFunc) *backend.CheckHealthResult {
+		t.Helper()
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		service := ProvideService(httpclient.NewProvider(), tracing.InitializeTracerForTest())
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL},
+			},
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should report the version when metrics are found", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/metrics/find":
+				assert.Equal(t, "*", r.URL.Query().Get("query"))
+				_, _ = w.Write([]byte(`[{"text": "carbon", "id": "carbon", "leaf": 0, "expandable": 1}]`))
+			case "/version":
+				_, _ = w.Write([]byte("1.1.10\n"))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+		require.Equal(t, "Data source is working. Graphite version: 1.1.10", res.Message)
+	})
+
+	t.Run("should succeed when the version is not available", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/metrics/find" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		})
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+		require.Equal(t, "Data source is working", res.Message)
+	})
+
+	t.Run("should fail when authentication fails", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "authentication failed")
+	})
+
+	t.Run("should fail when the response is not a list of metrics", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`<html></html>`))
+		})
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "invalid response")
+	})
+}
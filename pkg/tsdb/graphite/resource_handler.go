@@ -0,0 +1,175 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/patrickmn/go-cache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// resourceCacheTTL is the duration the successful responses of resource calls are cached for.
+const resourceCacheTTL = time.Minute
+
+// resourcePaths are the Graphite API endpoints that can be called as resources of the data source.
+var resourcePaths = map[string]bool{
+	"metrics/find":             true,
+	"tags/autoComplete/tags":   true,
+	"tags/autoComplete/values": true,
+	"functions":                true,
+}
+
+// Graphite 1.1.7 returns `Infinity` as the default value of some function parameters, which is not valid JSON.
+// See https://github.com/graphite-project/graphite-web/issues/2609
+var functionsInfinityRegexp = regexp.MustCompile(`"default": ?Infinity`)
+
+// CallResource calls the Graphite API endpoints used to find metrics, autocomplete tags and list functions,
+// with the credentials of the data source, so that they can be used where the data source proxy is not
+// available. Successful responses are cached per data source.
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+	if !resourcePaths[req.Path] {
+		return sendResourceError(sender, http.StatusNotFound, fmt.Errorf("invalid resource path: %s", req.Path))
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodPost {
+		return sendResourceError(sender, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+	}
+
+	params, err := resourceParams(req)
+	if err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, err)
+	}
+
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	// Encode sorts the parameters by name, so equal requests have the same key.
+	cacheKey := req.Method + " " + req.Path + "?" + params.Encode()
+	if res, found := dsInfo.resourceCache.Get(cacheKey); found {
+		return sender.Send(res.(*backend.CallResourceResponse))
+	}
+
+	res, err := s.resourceRequest(ctx, logger, dsInfo, req, params)
+	if err != nil {
+		return err
+	}
+	if res.Status/100 == 2 {
+		dsInfo.resourceCache.Set(cacheKey, res, cache.DefaultExpiration)
+	}
+	return sender.Send(res)
+}
+
+// resourceParams returns the parameters of a resource call, from its query string and, for POST requests,
+// from its form encoded body.
+func resourceParams(req *backend.CallResourceRequest) (url.Values, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the resource URL: %w", err)
+	}
+	params := u.Query()
+	if req.Method != http.MethodPost || len(req.Body) == 0 {
+		return params, nil
+	}
+
+	form, err := url.ParseQuery(string(req.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the request body: %w", err)
+	}
+	for name, values := range form {
+		params[name] = append(params[name], values...)
+	}
+	return params, nil
+}
+
+func (s *Service) resourceRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, req *backend.CallResourceRequest, params url.Values) (*backend.CallResourceResponse, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, req.Path)
+
+	var graphiteReq *http.Request
+	if req.Method == http.MethodPost {
+		graphiteReq, err = http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(params.Encode()))
+		if err == nil {
+			graphiteReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		u.RawQuery = params.Encode()
+		graphiteReq, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	}
+	if err != nil {
+		logger.Info("Failed to create request", "error", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	ctx, span := s.tracer.Start(ctx, "graphite resource")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("path", req.Path),
+		attribute.Int64("datasource_id", dsInfo.Id),
+		attribute.Int64("org_id", req.PluginContext.OrgID),
+	)
+	s.tracer.Inject(ctx, graphiteReq.Header, span)
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if res != nil {
+		span.SetAttributes(attribute.Int("graphite.response.code", res.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode/100 != 2 {
+		logger.Info("Resource request failed", "path", req.Path, "status", res.Status)
+	} else if req.Path == "functions" {
+		body = functionsInfinityRegexp.ReplaceAll(body, []byte(`"default": 1e9999`))
+	}
+
+	contentType := res.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	return &backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: map[string][]string{"content-type": {contentType}},
+		Body:    body,
+	}, nil
+}
+
+func sendResourceError(sender backend.CallResourceResponseSender, status int, err error) error {
+	body, marshalErr := json.Marshal(map[string]string{"message": err.Error()})
+	if marshalErr != nil {
+		return marshalErr
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  status,
+		Headers: map[string][]string{"content-type": {"application/json"}},
+		Body:    body,
+	})
+}
@@ -0,0 +1,164 @@
+package graphite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+type fakeCallResourceSender struct {
+	responses []*backend.CallResourceResponse
+}
+
+func (s *fakeCallResourceSender) Send(res *backend.CallResourceResponse) error {
+	s.responses = append(s.responses, res)
+	return nil
+}
+
+func TestCallResource(t *testing.T) {
+	newService := func(t *testing.T, handler http.HandlerFunc) (*Service, backend.PluginContext) {
+		t.Helper()
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		service := ProvideService(httpclient.NewProvider(), tracing.InitializeTracerForTest())
+		pluginCtx := backend.PluginContext{
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				ID:               1,
+				URL:              srv.URL,
+				BasicAuthEnabled: true,
+				BasicAuthUser:    "user",
+				DecryptedSecureJSONData: map[string]string{
+					"basicAuthPassword": "password",
+				},
+			},
+		}
+		return service, pluginCtx
+	}
+	callResource := func(t *testing.T, service *Service, req *backend.CallResourceRequest) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeCallResourceSender{}
+		require.NoError(t, service.CallResource(context.Background(), req, sender))
+		require.Len(t, sender.responses, 1)
+		return sender.responses[0]
+	}
+
+	t.Run("should find metrics with the credentials of the data source", func(t *testing.T) {
+		service, pluginCtx := newService(t, func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "user", user)
+			assert.Equal(t, "password", password)
+			assert.Equal(t, "/metrics/find", r.URL.Path)
+			assert.Equal(t, http.MethodPost, r.Method)
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "prod.*", r.PostForm.Get("query"))
+			assert.Equal(t, "-1h", r.PostForm.Get("from"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"text": "servers", "expandable": 1}]`))
+		})
+
+		res := callResource(t, service, &backend.CallResourceRequest{
+			PluginContext: pluginCtx,
+			Path:          "metrics/find",
+			URL:           "metrics/find?from=-1h",
+			Method:        http.MethodPost,
+			Body:          []byte("query=prod.*"),
+		})
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `[{"text": "servers", "expandable": 1}]`, string(res.Body))
+	})
+
+	t.Run("should autocomplete tags", func(t *testing.T) {
+		service, pluginCtx := newService(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/tags/autoComplete/values", r.URL.Path)
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, []string{"name=cpu", "host=a"}, r.URL.Query()["expr"])
+			assert.Equal(t, "dc", r.URL.Query().Get("tag"))
+			_, _ = w.Write([]byte(`["eu", "us"]`))
+		})
+
+		res := callResource(t, service, &backend.CallResourceRequest{
+			PluginContext: pluginCtx,
+			Path:          "tags/autoComplete/values",
+			URL:           "tags/autoComplete/values?expr=name%3Dcpu&expr=host%3Da&tag=dc",
+			Method:        http.MethodGet,
+		})
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, `["eu", "us"]`, string(res.Body))
+	})
+
+	t.Run("should return valid JSON functions", func(t *testing.T) {
+		service, pluginCtx := newService(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"linearRegression": {"params": [{"name": "n", "default": Infinity}]}}`))
+		})
+
+		res := callResource(t, service, &backend.CallResourceRequest{
+			PluginContext: pluginCtx,
+			Path:          "functions",
+			URL:           "functions",
+			Method:        http.MethodGet,
+		})
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, `{"linearRegression": {"params": [{"name": "n", "default": 1e9999}]}}`, string(res.Body))
+	})
+
+	t.Run("should cache successful responses", func(t *testing.T) {
+		requests := 0
+		service, pluginCtx := newService(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Query().Get("tagPrefix") == "bad" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(`["dc", "host"]`))
+		})
+
+		for _, tagPrefix := range []string{"", "", "d", "bad", "bad"} {
+			res := callResource(t, service, &backend.CallResourceRequest{
+				PluginContext: pluginCtx,
+				Path:          "tags/autoComplete/tags",
+				URL:           "tags/autoComplete/tags?tagPrefix=" + tagPrefix,
+				Method:        http.MethodGet,
+			})
+			if tagPrefix == "bad" {
+				require.Equal(t, http.StatusInternalServerError, res.Status)
+			} else {
+				require.Equal(t, http.StatusOK, res.Status)
+			}
+		}
+		require.Equal(t, 4, requests)
+	})
+
+	t.Run("should not call other endpoints", func(t *testing.T) {
+		service, pluginCtx := newService(t, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			t.Errorf("unexpected request to %s: %s", r.URL.Path, body)
+		})
+
+		res := callResource(t, service, &backend.CallResourceRequest{
+			PluginContext: pluginCtx,
+			Path:          "render",
+			URL:           "render?target=foo",
+			Method:        http.MethodGet,
+		})
+		require.Equal(t, http.StatusNotFound, res.Status)
+
+		res = callResource(t, service, &backend.CallResourceRequest{
+			PluginContext: pluginCtx,
+			Path:          "functions",
+			URL:           "functions",
+			Method:        http.MethodDelete,
+		})
+		require.Equal(t, http.StatusMethodNotAllowed, res.Status)
+	})
+}
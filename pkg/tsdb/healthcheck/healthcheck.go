@@ -0,0 +1,78 @@
+// Package healthcheck has the helpers shared by the health checks of the core data sources.
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// ErrAuthFailed is returned when the data source rejects the credentials of a request.
+var ErrAuthFailed = errors.New("authentication failed, check the data source credentials")
+
+// Get sends a GET request for the path and query relative to the URL of a data source, and returns
+// the body of the response if it is successful.
+func Get(ctx context.Context, logger log.Logger, client *http.Client, dsURL string, p string, query url.Values) ([]byte, error) {
+	u, err := url.Parse(dsURL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, p)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if err := CheckResponse(res); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(res.Body)
+}
+
+// CheckResponse returns an error wrapping ErrAuthFailed if the data source rejected the credentials,
+// or an error with the status of the response if the response is not successful.
+func CheckResponse(res *http.Response) error {
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("%w (status: %s)", ErrAuthFailed, res.Status)
+	}
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("request failed, status: %s", res.Status)
+	}
+	return nil
+}
+
+// Result returns a successful result with the message if err is nil, or else logs err and returns
+// a failed result with the message followed by err.
+func Result(logger log.Logger, message string, err error) *backend.CheckHealthResult {
+	if err == nil {
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusOk,
+			Message: message,
+		}
+	}
+
+	logger.Warn("Health check failed", "error", err)
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusError,
+		Message: fmt.Sprintf("%s: %s", message, err.Error()),
+	}
+}
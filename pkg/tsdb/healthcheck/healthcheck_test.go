@@ -0,0 +1,59 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestGet(t *testing.T) {
+	get := func(t *testing.T, handler http.HandlerFunc, p string, query url.Values) ([]byte, error) {
+		t.Helper()
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+		return Get(context.Background(), log.NewNopLogger(), srv.Client(), srv.URL+"/prefix/", p, query)
+	}
+
+	t.Run("should return the body of successful responses", func(t *testing.T) {
+		body, err := get(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/prefix/api/version", r.URL.Path)
+			assert.Equal(t, "*", r.URL.Query().Get("query"))
+			_, _ = w.Write([]byte("1.0"))
+		}, "api/version", url.Values{"query": []string{"*"}})
+		require.NoError(t, err)
+		require.Equal(t, "1.0", string(body))
+	})
+
+	t.Run("should fail when authentication fails", func(t *testing.T) {
+		for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+			_, err := get(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+			}, "api/version", nil)
+			require.ErrorIs(t, err, ErrAuthFailed)
+		}
+	})
+
+	t.Run("should fail when the response is not successful", func(t *testing.T) {
+		_, err := get(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}, "api/version", nil)
+		require.EqualError(t, err, "request failed, status: 500 Internal Server Error")
+	})
+}
+
+func TestResult(t *testing.T) {
+	res := Result(log.NewNopLogger(), "Data source is working", nil)
+	require.Equal(t, &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Data source is working"}, res)
+
+	res = Result(log.NewNopLogger(), "error connecting", errors.New("timeout"))
+	require.Equal(t, &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: "error connecting: timeout"}, res)
+}
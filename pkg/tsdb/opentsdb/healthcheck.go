@@ -0,0 +1,49 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/tsdb/healthcheck"
+)
+
+// versionResponse is the response of the OpenTSDB version endpoint, available since OpenTSDB 2.0.
+type versionResponse struct {
+	Version string `json:"version"`
+}
+
+// CheckHealth checks that the OpenTSDB HTTP API can be reached with the configured credentials,
+// and reports the version of OpenTSDB.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return healthcheck.Result(logger, "error getting datasource info", err), nil
+	}
+
+	version, err := getVersion(ctx, logger, dsInfo)
+	if err != nil {
+		return healthcheck.Result(logger, "error getting OpenTSDB version", err), nil
+	}
+	return healthcheck.Result(logger, fmt.Sprintf("Data source is working. OpenTSDB version: %s", version), nil), nil
+}
+
+func getVersion(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo) (string, error) {
+	body, err := healthcheck.Get(ctx, logger, dsInfo.HTTPClient, dsInfo.URL, "api/version", nil)
+	if err != nil {
+		return "", err
+	}
+	var version versionResponse
+	if err := json.Unmarshal(body, &version); err != nil {
+		return "", fmt.Errorf("invalid response: %w", err)
+	}
+	if version.Version == "" {
+		return "", errors.New("invalid response: no version found")
+	}
+	return version.Version, nil
+}
@@ -0,0 +1,55 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+)
+
+func TestCheckHealth(t *testing.T) {
+	checkHealth := func(t *testing.T, handler http.HandlerFunc) *backend.CheckHealthResult {
+		t.Helper()
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		service := ProvideService(httpclient.NewProvider())
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL},
+			},
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should report the version", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/version", r.URL.Path)
+			_, _ = w.Write([]byte(`{"short_revision": "", "version": "2.4.0", "host": "localhost"}`))
+		})
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+		require.Equal(t, "Data source is working. OpenTSDB version: 2.4.0", res.Message)
+	})
+
+	t.Run("should fail when authentication fails", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "authentication failed")
+	})
+
+	t.Run("should fail when the server is not OpenTSDB", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{}`))
+		})
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "no version found")
+	})
+}
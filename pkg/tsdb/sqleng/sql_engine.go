@@ -46,6 +46,12 @@ type SqlQueryResultTransformer interface {
 	GetConverterList() []sqlutil.StringConverter
 }
 
+// SqlQueryResultConverters is implemented by the query result transformers that convert the rows with
+// converters, in addition to the string converters of GetConverterList.
+type SqlQueryResultConverters interface {
+	GetConverters() []sqlutil.Converter
+}
+
 var sqlIntervalCalculator = intervalv2.NewCalculator()
 
 // NewXormEngine is an xorm.Engine factory, that can be stubbed by tests.
@@ -308,7 +314,15 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 
 	// Convert row.Rows to dataframe
 	stringConverters := e.queryResultTransformer.GetConverterList()
-	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
+	converters := sqlutil.ToConverters(stringConverters...)
+	if t, ok := e.queryResultTransformer.(SqlQueryResultConverters); ok {
+		converters = append(converters, t.GetConverters()...)
+	}
+	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, converters...)
+	if err == nil {
+		// Dynamic converters don't return the errors of the rows.
+		err = rows.Err()
+	}
 	if err != nil {
 		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
 		return
@@ -0,0 +1,225 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+	"github.com/mattn/go-sqlite3"
+	"xorm.io/core"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+var logger = log.New("tsdb.sqlite")
+
+// driverName is the name of the driver of the data source, whose connections can only read the tables
+// of their database file.
+const driverName = "sqlite3-datasource"
+
+// sqliteRecursive is the authorizer action code of recursive common table expressions,
+// which isn't exported by the driver.
+const sqliteRecursive = 33
+
+// ErrPathNotAllowed is returned when the database file of a data source isn't in the allowed directories.
+var ErrPathNotAllowed = errors.New("the database file is not in a directory allowed by the sqlite_allowed_directories setting")
+
+type Service struct {
+	im instancemgmt.InstanceManager
+}
+
+func ProvideService(cfg *setting.Cfg) *Service {
+	return &Service{
+		im: datasource.NewInstanceManager(newInstanceSettings(cfg)),
+	}
+}
+
+func newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFactoryFunc {
+	return func(ctx context.Context, settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		jsonData := sqleng.JsonData{
+			MaxOpenConns:    cfg.SqlDatasourceMaxOpenConnsDefault,
+			MaxIdleConns:    cfg.SqlDatasourceMaxIdleConnsDefault,
+			ConnMaxLifetime: cfg.SqlDatasourceMaxConnLifetimeDefault,
+		}
+
+		err := json.Unmarshal(settings.JSONData, &jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+
+		database := jsonData.Database
+		if database == "" {
+			database = settings.Database
+		}
+
+		path, err := resolveDatabasePath(database, cfg.SQLiteDatasourceAllowedDirectories)
+		if err != nil {
+			return nil, err
+		}
+
+		registerDriver()
+
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData: jsonData,
+			Database: path,
+			ID:       settings.ID,
+			Updated:  settings.Updated,
+			UID:      settings.UID,
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DriverName:       driverName,
+			ConnectionString: connectionString(path),
+			DSInfo:           dsInfo,
+			TimeColumnNames:  []string{"time", "time_sec"},
+			// The declared types of the columns keep the case used in the CREATE TABLE statements.
+			MetricColumnTypes: []string{"TEXT", "text", "VARCHAR", "varchar", "CHAR", "char"},
+			RowLimit:          cfg.DataProxyRowLimit,
+		}
+
+		return sqleng.NewQueryDataHandler(cfg, config, &sqliteQueryResultTransformer{}, newSQLiteMacroEngine(), logger)
+	}
+}
+
+// resolveDatabasePath returns the absolute path of the database file, without symbolic links, and
+// checks it's in one of the allowed directories. Relative paths are relative to the first directory.
+func resolveDatabasePath(database string, allowedDirectories []string) (string, error) {
+	if database == "" {
+		return "", errors.New("no database file path specified")
+	}
+	if len(allowedDirectories) == 0 {
+		return "", ErrPathNotAllowed
+	}
+	if !filepath.IsAbs(database) {
+		database = filepath.Join(allowedDirectories[0], database)
+	}
+
+	path, err := filepath.EvalSymlinks(database)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the database file path: %w", err)
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !fileInfo.Mode().IsRegular() {
+		return "", fmt.Errorf("the database file path %s is not a file", database)
+	}
+
+	for _, dir := range allowedDirectories {
+		dir, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			continue
+		}
+		dir, err = filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, path); err == nil && filepath.IsLocal(rel) {
+			return path, nil
+		}
+	}
+	return "", ErrPathNotAllowed
+}
+
+// connectionString returns the URI opening the database file read-only, so that queries can't
+// change the database.
+func connectionString(path string) string {
+	uri := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return uri.String() + "?mode=ro&_query_only=true"
+}
+
+// registerDriver registers the driver of the data source, once. Its connections can't attach
+// other databases, which would read files outside of the allowed directories, or change pragmas.
+func registerDriver() {
+	sqleng.XormDriverMu.Lock()
+	defer sqleng.XormDriverMu.Unlock()
+
+	if core.QueryDriver(driverName) != nil {
+		return
+	}
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			conn.SetLimit(sqlite3.SQLITE_LIMIT_ATTACHED, 0)
+			conn.RegisterAuthorizer(authorizeRead)
+			return nil
+		},
+	})
+	// The connection strings are parsed like the ones of the sqlite3 driver.
+	core.RegisterDriver(driverName, core.QueryDriver("sqlite3"))
+}
+
+// authorizeRead only authorizes the statements reading the tables.
+func authorizeRead(action int, _, _, _ string) int {
+	switch action {
+	case sqlite3.SQLITE_SELECT, sqlite3.SQLITE_READ, sqlite3.SQLITE_FUNCTION, sqliteRecursive:
+		return sqlite3.SQLITE_OK
+	default:
+		return sqlite3.SQLITE_DENY
+	}
+}
+
+func (s *Service) getDataSourceHandler(ctx context.Context, pluginCtx backend.PluginContext) (*sqleng.DataSourceHandler, error) {
+	i, err := s.im.Get(ctx, pluginCtx)
+	if err != nil {
+		return nil, err
+	}
+	instance := i.(*sqleng.DataSourceHandler)
+	return instance, nil
+}
+
+// CheckHealth checks that the database file can be opened.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
+	if err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: err.Error()}, nil
+	}
+
+	if err := dsHandler.Ping(); err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: dsHandler.TransformQueryError(logger, err).Error()}, nil
+	}
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Database Connection OK"}, nil
+}
+
+func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	dsHandler, err := s.getDataSourceHandler(ctx, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	return dsHandler.QueryData(ctx, req)
+}
+
+type sqliteQueryResultTransformer struct{}
+
+func (t *sqliteQueryResultTransformer) TransformQueryError(_ log.Logger, err error) error {
+	var driverErr sqlite3.Error
+	if errors.As(err, &driverErr) && driverErr.Code == sqlite3.ErrReadonly {
+		return fmt.Errorf("the database is read-only: %w", err)
+	}
+	return err
+}
+
+func (t *sqliteQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
+	return nil
+}
+
+// GetConverters returns a dynamic converter, finding the types of the fields from the values of the
+// rows since the type of SQLite values doesn't depend on the type of their column.
+func (t *sqliteQueryResultTransformer) GetConverters() []sqlutil.Converter {
+	return []sqlutil.Converter{{Name: "dynamic", Dynamic: true}}
+}
@@ -0,0 +1,166 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSQLite(t *testing.T) {
+	dir := t.TempDir()
+	createTestDatabase(t, filepath.Join(dir, "metrics.db"))
+
+	cfg := setting.NewCfg()
+	cfg.DataProxyRowLimit = 1000
+	cfg.SQLiteDatasourceAllowedDirectories = []string{dir}
+	s := ProvideService(cfg)
+
+	var id int64
+	pluginContext := func(database string) backend.PluginContext {
+		// Instances are cached by the ID of their data source.
+		id++
+		return backend.PluginContext{
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				ID:       id,
+				UID:      database,
+				JSONData: json.RawMessage(`{"database": "` + database + `"}`),
+			},
+		}
+	}
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := func(format, rawSQL string) *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			PluginContext: pluginContext("metrics.db"),
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				JSON:      json.RawMessage(`{"format": "` + format + `", "rawSql": ` + string(mustMarshal(t, rawSQL)) + `}`),
+				TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+			}},
+		}
+	}
+
+	t.Run("returns time series grouped by time", func(t *testing.T) {
+		resp, err := s.QueryData(context.Background(), query("time_series",
+			"SELECT $__timeGroupAlias(time, '10m'), host AS metric, avg(value) AS value FROM metrics WHERE $__timeFilter(time) GROUP BY 1, 2 ORDER BY 1"))
+		require.NoError(t, err)
+		res := resp.Responses["A"]
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, data.FieldTypeTime, frame.Fields[0].Type())
+		require.Equal(t, from.Unix(), frame.Fields[0].At(0).(time.Time).Unix())
+		require.Equal(t, from.Add(10*time.Minute).Unix(), frame.Fields[0].At(1).(time.Time).Unix())
+		require.Equal(t, "a", frame.Fields[1].Name)
+		require.Equal(t, 2.0, *frame.Fields[1].At(0).(*float64))
+	})
+
+	t.Run("returns tables with the types of the values", func(t *testing.T) {
+		resp, err := s.QueryData(context.Background(), query("table", "SELECT time, host, value FROM metrics ORDER BY time, host LIMIT 1"))
+		require.NoError(t, err)
+		res := resp.Responses["A"]
+		require.NoError(t, res.Error)
+		frame := res.Frames[0]
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		require.Equal(t, data.FieldTypeNullableString, frame.Fields[1].Type())
+		require.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[2].Type())
+	})
+
+	t.Run("fails for queries writing to the database", func(t *testing.T) {
+		resp, err := s.QueryData(context.Background(), query("table", "DELETE FROM metrics"))
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+
+		resp, err = s.QueryData(context.Background(), query("table", "SELECT count(*) AS count FROM metrics"))
+		require.NoError(t, err)
+		require.Equal(t, 4.0, *resp.Responses["A"].Frames[0].Fields[0].At(0).(*float64))
+	})
+
+	t.Run("fails for queries attaching other databases", func(t *testing.T) {
+		outside := t.TempDir()
+		createTestDatabase(t, filepath.Join(outside, "other.db"))
+
+		resp, err := s.QueryData(context.Background(), query("table",
+			"ATTACH '"+filepath.Join(outside, "other.db")+"' AS other; SELECT host FROM other.metrics"))
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+
+		resp, err = s.QueryData(context.Background(), query("table", "PRAGMA query_only = 0"))
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+	})
+
+	t.Run("checks the health of the data source", func(t *testing.T) {
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginContext(filepath.Join(dir, "metrics.db"))})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+	})
+
+	t.Run("fails for files outside of the allowed directories", func(t *testing.T) {
+		outside := t.TempDir()
+		createTestDatabase(t, filepath.Join(outside, "metrics.db"))
+		require.NoError(t, os.Symlink(filepath.Join(outside, "metrics.db"), filepath.Join(dir, "link.db")))
+
+		relative, err := filepath.Rel(dir, filepath.Join(outside, "metrics.db"))
+		require.NoError(t, err)
+
+		for _, database := range []string{filepath.Join(outside, "metrics.db"), relative, "link.db"} {
+			res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginContext(database)})
+			require.NoError(t, err)
+			require.Equal(t, backend.HealthStatusError, res.Status, database)
+		}
+	})
+}
+
+func TestResolveDatabasePath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metrics.db")
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+
+	_, err := resolveDatabasePath(path, nil)
+	require.ErrorIs(t, err, ErrPathNotAllowed)
+
+	resolved, err := resolveDatabasePath("metrics.db", []string{dir})
+	require.NoError(t, err)
+	expected, err := filepath.EvalSymlinks(path)
+	require.NoError(t, err)
+	require.Equal(t, expected, resolved)
+
+	_, err = resolveDatabasePath(dir, []string{dir})
+	require.Error(t, err)
+	_, err = resolveDatabasePath("missing.db", []string{dir})
+	require.Error(t, err)
+}
+
+func createTestDatabase(t *testing.T, path string) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	_, err = db.Exec(`CREATE TABLE metrics (time TIMESTAMP, host TEXT, value REAL);
+		INSERT INTO metrics VALUES
+			('2023-01-01 00:00:00', 'a', 1), ('2023-01-01 00:05:00', 'a', 3),
+			('2023-01-01 00:10:00', 'a', 5), ('2023-01-01 02:00:00', 'a', 7);`)
+	require.NoError(t, err)
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
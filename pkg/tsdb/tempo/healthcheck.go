@@ -0,0 +1,48 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/tsdb/healthcheck"
+)
+
+type buildInfoResponse struct {
+	Version string `json:"version"`
+}
+
+// CheckHealth checks that the Tempo API can be reached with the configured credentials,
+// and reports the version of Tempo when the server exposes it.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	ctxLogger := s.logger.FromContext(ctx)
+	dsInfo, err := s.getDSInfo(ctx, req.PluginContext)
+	if err != nil {
+		return healthcheck.Result(ctxLogger, "error getting datasource info", err), nil
+	}
+
+	body, err := healthcheck.Get(ctx, ctxLogger, dsInfo.HTTPClient, dsInfo.URL, "api/echo", nil)
+	if err != nil {
+		return healthcheck.Result(ctxLogger, "error connecting to Tempo", err), nil
+	}
+	if strings.TrimSpace(string(body)) != "echo" {
+		return healthcheck.Result(ctxLogger, "error connecting to Tempo", errors.New("unexpected response from the echo endpoint")), nil
+	}
+
+	// The build info endpoint is not available in older versions of Tempo, so it is not required.
+	message := "Data source is working"
+	body, err = healthcheck.Get(ctx, ctxLogger, dsInfo.HTTPClient, dsInfo.URL, "api/status/buildinfo", nil)
+	if err == nil {
+		var info buildInfoResponse
+		if err := json.Unmarshal(body, &info); err == nil && info.Version != "" {
+			message = fmt.Sprintf("Data source is working. Tempo version: %s", info.Version)
+		}
+	} else {
+		ctxLogger.Debug("Failed to get Tempo build info", "error", err, "function", logEntrypoint())
+	}
+	return healthcheck.Result(ctxLogger, message, nil), nil
+}
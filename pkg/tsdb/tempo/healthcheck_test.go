@@ -0,0 +1,73 @@
+package tempo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+)
+
+func TestCheckHealth(t *testing.T) {
+	checkHealth := func(t *testing.T, handler http.HandlerFunc) *backend.CheckHealthResult {
+		t.Helper()
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+
+		service := ProvideService(httpclient.NewProvider())
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL},
+			},
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should report the version", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/echo":
+				_, _ = w.Write([]byte("echo"))
+			case "/api/status/buildinfo":
+				_, _ = w.Write([]byte(`{"version": "2.3.1", "revision": "abc", "branch": "HEAD"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+		require.Equal(t, "Data source is working. Tempo version: 2.3.1", res.Message)
+	})
+
+	t.Run("should succeed when the build info is not available", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/echo" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte("echo"))
+		})
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+		require.Equal(t, "Data source is working", res.Message)
+	})
+
+	t.Run("should fail when authentication fails", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "authentication failed")
+	})
+
+	t.Run("should fail when the server is not Tempo", func(t *testing.T) {
+		res := checkHealth(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("<html></html>"))
+		})
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Contains(t, res.Message, "unexpected response")
+	})
+}
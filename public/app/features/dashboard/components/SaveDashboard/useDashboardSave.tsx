@@ -65,7 +65,11 @@ export const useDashboardSave = (isCopy = false) => {
 
         // important that these happen before location redirect below
         appEvents.publish(new DashboardSavedEvent());
-        notifyApp.success('Dashboard saved');
+        if (result.warning) {
+          notifyApp.warning('Dashboard saved', result.warning);
+        } else {
+          notifyApp.success('Dashboard saved');
+        }
 
         //Update local storage dashboard to handle things like last used datasource
         updateDashboardUidLastUsedDatasource(result.uid);
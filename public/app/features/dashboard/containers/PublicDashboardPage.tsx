@@ -17,6 +17,7 @@ import { DashboardLoading } from '../components/DashboardLoading/DashboardLoadin
 import { PublicDashboardFooter } from '../components/PublicDashboard/PublicDashboardsFooter';
 import { useGetPublicDashboardConfig } from '../components/PublicDashboard/usePublicDashboardConfig';
 import { PublicDashboardNotAvailable } from '../components/PublicDashboardNotAvailable/PublicDashboardNotAvailable';
+import { SubMenu } from '../components/SubMenu/SubMenu';
 import { DashboardGrid } from '../dashgrid/DashboardGrid';
 import { getTimeSrv } from '../services/TimeSrv';
 import { DashboardModel } from '../state';
@@ -114,6 +115,9 @@ const PublicDashboardPage = (props: Props) => {
       <Toolbar dashboard={dashboard} />
       {dashboardState.initError && <DashboardFailed initError={dashboardState.initError} />}
       <div className={styles.gridContainer}>
+        <section aria-label={e2eSelectors.pages.Dashboard.SubMenu.submenu}>
+          <SubMenu dashboard={dashboard} annotations={[]} links={[]} />
+        </section>
         <DashboardGrid dashboard={dashboard} isEditable={false} viewPanel={null} editPanel={null} hidePanelMenus />
       </div>
       <PublicDashboardFooter />
@@ -5,6 +5,8 @@ import { SemVer } from 'semver';
 
 import {
   DataFrame,
+  DataFrameJSON,
+  dataFrameFromJSON,
   DataLink,
   DataQueryRequest,
   DataQueryResponse,
@@ -1035,6 +1037,22 @@ export class ElasticDatasource
     return freshDatabaseVersion;
   }
 
+  /**
+   * Returns the next page of a paginated logs or raw data query, using the continuation token
+   * in the custom metadata of the frame of its previous page.
+   */
+  async getNextPage(
+    query: ElasticsearchQuery,
+    continuationToken: string,
+    scopedVars: ScopedVars = {}
+  ): Promise<DataFrame[]> {
+    const response = await this.postResource<{ frames?: DataFrameJSON[] }>('next-page', {
+      query: this.applyTemplateVariables(query, scopedVars),
+      continuationToken,
+    });
+    return (response.frames ?? []).map((frame) => dataFrameFromJSON(frame));
+  }
+
   private makeLogContextDataRequest = (row: LogRowModel, options?: LogRowContextOptions) => {
     const direction = options?.direction || LogRowContextQueryDirection.Backward;
     const logQuery: Logs = {
@@ -26,6 +26,9 @@ export { Elasticsearch as ElasticsearchQuery } from './dataquery.gen';
 type ExtendedLogsSettings = SchemaLogs['settings'] & {
   searchAfter?: unknown[];
   sortDirection?: 'asc' | 'desc';
+  // Enables pagination, with the duration the point in time of the pages is kept alive, e.g. `5m`
+  pointInTimeKeepAlive?: string;
+  continuationToken?: string;
 };
 
 export interface Logs extends SchemaLogs {
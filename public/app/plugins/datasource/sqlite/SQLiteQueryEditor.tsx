@@ -0,0 +1,14 @@
+import React from 'react';
+
+import { QueryEditorProps } from '@grafana/data';
+import { SqlQueryEditor } from 'app/features/plugins/sql/components/QueryEditor';
+import { SQLOptions, SQLQuery } from 'app/features/plugins/sql/types';
+
+import { SQLiteDatasource } from './datasource';
+
+// SQLite databases have no datasets, like PostgreSQL data sources which are configured with a database.
+const queryHeaderProps = { isPostgresInstance: true };
+
+export function SQLiteQueryEditor(props: QueryEditorProps<SQLiteDatasource, SQLQuery, SQLOptions>) {
+  return <SqlQueryEditor {...props} queryHeaderProps={queryHeaderProps} />;
+}
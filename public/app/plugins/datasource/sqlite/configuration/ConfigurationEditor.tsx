@@ -0,0 +1,80 @@
+import React from 'react';
+
+import { DataSourcePluginOptionsEditorProps, onUpdateDatasourceJsonDataOption } from '@grafana/data';
+import { ConfigSection, ConfigSubSection, DataSourceDescription, Stack } from '@grafana/experimental';
+import { Field, Icon, Input, Label, Tooltip } from '@grafana/ui';
+import { ConnectionLimits } from 'app/features/plugins/sql/components/configuration/ConnectionLimits';
+import { Divider } from 'app/features/plugins/sql/components/configuration/Divider';
+
+import { SQLiteOptions } from '../types';
+
+export const SQLiteConfigEditor = (props: DataSourcePluginOptionsEditorProps<SQLiteOptions>) => {
+  const { options, onOptionsChange } = props;
+  const jsonData = options.jsonData;
+
+  const WIDTH_LONG = 40;
+
+  return (
+    <>
+      <DataSourceDescription
+        dataSourceName="SQLite"
+        docsLink="https://grafana.com/docs/grafana/latest/datasources/sqlite/"
+        hasRequiredFields={true}
+      />
+
+      <Divider />
+
+      <ConfigSection title="Database">
+        <Field
+          label="Database file path"
+          description="The database file must be in a directory allowed by the sqlite_allowed_directories setting of Grafana. Relative paths are relative to the first allowed directory. The file is opened read-only."
+          required
+        >
+          <Input
+            width={WIDTH_LONG}
+            name="database"
+            value={jsonData.database || ''}
+            placeholder="metrics.db"
+            onChange={onUpdateDatasourceJsonDataOption(props, 'database')}
+          />
+        </Field>
+      </ConfigSection>
+
+      <Divider />
+
+      <ConfigSection title="Additional settings">
+        <ConfigSubSection title="SQLite Options">
+          <Field
+            label={
+              <Label>
+                <Stack gap={0.5}>
+                  <span>Min time interval</span>
+                  <Tooltip
+                    content={
+                      <span>
+                        A lower limit for the auto group by time interval. Recommended to be set to write frequency, for
+                        example
+                        <code>1m</code> if your data is written every minute.
+                      </span>
+                    }
+                  >
+                    <Icon name="info-circle" size="sm" />
+                  </Tooltip>
+                </Stack>
+              </Label>
+            }
+          >
+            <Input
+              placeholder="1m"
+              value={jsonData.timeInterval || ''}
+              onChange={onUpdateDatasourceJsonDataOption(props, 'timeInterval')}
+              width={WIDTH_LONG}
+            />
+          </Field>
+        </ConfigSubSection>
+
+        <ConnectionLimits options={options} onOptionsChange={onOptionsChange} />
+      </ConfigSection>
+    </>
+  );
+};
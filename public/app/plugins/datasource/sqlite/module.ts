@@ -0,0 +1,13 @@
+import { DataSourcePlugin } from '@grafana/data';
+import { SQLQuery } from 'app/features/plugins/sql/types';
+
+import { CheatSheet } from './CheatSheet';
+import { SQLiteQueryEditor } from './SQLiteQueryEditor';
+import { SQLiteConfigEditor } from './configuration/ConfigurationEditor';
+import { SQLiteDatasource } from './datasource';
+import { SQLiteOptions } from './types';
+
+export const plugin = new DataSourcePlugin<SQLiteDatasource, SQLQuery, SQLiteOptions>(SQLiteDatasource)
+  .setQueryEditor(SQLiteQueryEditor)
+  .setQueryEditorHelp(CheatSheet)
+  .setConfigEditor(SQLiteConfigEditor);
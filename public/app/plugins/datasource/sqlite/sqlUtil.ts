@@ -0,0 +1,64 @@
+import { isEmpty } from 'lodash';
+
+import { RAQBFieldTypes, SQLQuery } from 'app/features/plugins/sql/types';
+import { createSelectClause, haveColumns } from 'app/features/plugins/sql/utils/sql.utils';
+
+// getFieldConfig returns the field config of a column from its declared type, following the rules of
+// the type affinity of SQLite columns.
+export function getFieldConfig(type: string): { raqbFieldType: RAQBFieldTypes; icon: string } {
+  const declaredType = type.toUpperCase();
+  if (declaredType === 'BOOLEAN') {
+    return { raqbFieldType: 'boolean', icon: 'toggle-off' };
+  }
+  if (['DATE', 'DATETIME', 'TIMESTAMP'].includes(declaredType)) {
+    return { raqbFieldType: 'datetime', icon: 'clock-nine' };
+  }
+  if (declaredType.includes('INT')) {
+    return { raqbFieldType: 'number', icon: 'calculator-alt' };
+  }
+  if (['CHAR', 'CLOB', 'TEXT'].some((t) => declaredType.includes(t))) {
+    return { raqbFieldType: 'text', icon: 'text' };
+  }
+  if (['REAL', 'FLOA', 'DOUB', 'NUMERIC', 'DECIMAL'].some((t) => declaredType.includes(t))) {
+    return { raqbFieldType: 'number', icon: 'calculator-alt' };
+  }
+  return { raqbFieldType: 'text', icon: 'text' };
+}
+
+export function toRawSql({ sql, table }: SQLQuery): string {
+  let rawQuery = '';
+
+  // Return early with empty string if there is no sql column
+  if (!sql || !haveColumns(sql.columns)) {
+    return rawQuery;
+  }
+
+  rawQuery += createSelectClause(sql.columns);
+
+  if (table) {
+    rawQuery += `FROM ${table} `;
+  }
+
+  if (sql.whereString) {
+    rawQuery += `WHERE ${sql.whereString} `;
+  }
+
+  if (sql.groupBy?.[0]?.property.name) {
+    const groupBy = sql.groupBy.map((g) => g.property.name).filter((g) => !isEmpty(g));
+    rawQuery += `GROUP BY ${groupBy.join(', ')} `;
+  }
+
+  if (sql.orderBy?.property.name) {
+    rawQuery += `ORDER BY ${sql.orderBy.property.name} `;
+  }
+
+  if (sql.orderBy?.property.name && sql.orderByDirection) {
+    rawQuery += `${sql.orderByDirection} `;
+  }
+
+  // Altough LIMIT 0 doesn't make sense, it is still possible to have LIMIT 0
+  if (sql.limit !== undefined && sql.limit >= 0) {
+    rawQuery += `LIMIT ${sql.limit} `;
+  }
+  return rawQuery;
+}
@@ -0,0 +1,7 @@
+export function showTables() {
+  return `SELECT name AS "table" FROM sqlite_schema WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`;
+}
+
+export function getSchema(table?: string) {
+  return `SELECT name AS "column", type FROM pragma_table_info('${(table ?? '').replace(/'/g, "''")}')`;
+}
@@ -15,6 +15,7 @@ export interface SaveDashboardResponseDTO {
   uid: string;
   url: string;
   version: number;
+  warning?: string;
 }
 
 export interface DashboardMeta {